@@ -145,8 +145,8 @@ type Wallet interface {
 	// This is needed for backwards compatibility on a network where validators
 	SignHash(account Account, hash []byte) ([]byte, error)
 
-	// SignBLS generates a BLS signature over the provided data with a direct or composite hasher
-	SignBLS(account Account, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error)
+	// SignBLS generates a BLS signature on the given curve over the provided data with a direct or composite hasher
+	SignBLS(account Account, curve bls.BLSCryptoSelector, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error)
 
 	GetPublicKey(account Account) (*ecdsa.PublicKey, error)
 
@@ -279,7 +279,7 @@ func (api *ExternalSigner) Decrypt(account accounts.Account, c, s1, s2 []byte) (
 	return nil, accounts.ErrNotSupported
 }
 
-func (api *ExternalSigner) SignBLS(account accounts.Account, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error) {
+func (api *ExternalSigner) SignBLS(account accounts.Account, curve bls.BLSCryptoSelector, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error) {
 	return bls.SerializedSignature{}, accounts.ErrNotSupported
 }
 
@@ -24,8 +24,6 @@ import (
 	"crypto/ecdsa"
 	crand "crypto/rand"
 	"errors"
-	bn256 "github.com/mapprotocol/atlas/helper/bls"
-	"github.com/mapprotocol/atlas/params"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -527,7 +525,9 @@ func (ks *KeyStore) Decrypt(a accounts.Account, c, s1, s2 []byte) ([]byte, error
 	return eciesKey.Decrypt(c, s1, s2)
 }
 
-func (ks *KeyStore) SignBLS(a accounts.Account, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
+// SignBLS signs msg on the given curve with the BLS key derived from the ECDSA
+// key of the unlocked account a.
+func (ks *KeyStore) SignBLS(a accounts.Account, curve blscrypto.BLSCryptoSelector, msg []byte, extraData []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
 	// Look up the key to sign with and abort if it cannot be found
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
@@ -537,52 +537,11 @@ func (ks *KeyStore) SignBLS(a accounts.Account, msg []byte, extraData []byte, us
 		return blscrypto.SerializedSignature{}, ErrLocked
 	}
 
-	privateKeyBytes, err := blscrypto.CryptoType().ECDSAToBLS(unlockedKey.PrivateKey)
+	privateKeyBytes, err := curve.ECDSAToBLS(unlockedKey.PrivateKey)
 	if err != nil {
 		return blscrypto.SerializedSignature{}, err
 	}
-
-	///////////////////////
-	blskey, err := bn256.DeserializePrivateKey(privateKeyBytes)
-	if err != nil {
-		return blscrypto.SerializedSignature{}, err
-	}
-	//pubkey, err := bn256.PrivateToPublic(privateKeyBytes)
-	//if err != nil {
-	//	return blscrypto.SerializedSignature{}, err
-	//}
-	//pk, err := bn256.UnmarshalPk(pubkey)
-	var sign *bn256.UnsafeSignature
-	if params.IsBN256Fork(fork, cur) {
-		sign, err = bn256.UnsafeSign2(blskey, msg)
-	} else {
-		sign, err = bn256.UnsafeSign(blskey, msg)
-	}
-
-	if err != nil {
-		return blscrypto.SerializedSignature{}, err
-	}
-	signature := blscrypto.SerializedSignature{}
-	copy(signature[:], sign.Marshal())
-	return signature, nil
-
-	//privateKey, err := bls.DeserializePrivateKey(privateKeyBytes)
-	//if err != nil {
-	//	return blscrypto.SerializedSignature{}, err
-	//}
-	//defer privateKey.Destroy()
-
-	//signature, err := privateKey.SignMessage(msg, extraData, useComposite, cip22)
-	//if err != nil {
-	//	return blscrypto.SerializedSignature{}, err
-	//}
-	//defer signature.Destroy()
-	//signatureBytes, err := signature.Serialize()
-	//if err != nil {
-	//	return blscrypto.SerializedSignature{}, err
-	//}
-	//
-	//return blscrypto.SerializedSignatureFromBytes(signatureBytes)
+	return curve.Sign(privateKeyBytes, msg, extraData, useComposite, cip22, fork, cur)
 }
 
 // GetPublicKey Retrieve the ECDSA public key for a given account.
@@ -41,6 +41,7 @@ import (
 	"github.com/mapprotocol/atlas/accounts/keystore"
 	"github.com/mapprotocol/atlas/accounts/scwallet"
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/chains/interfaces"
 	"github.com/mapprotocol/atlas/consensus/misc"
 	"github.com/mapprotocol/atlas/core"
@@ -645,6 +646,8 @@ type AccountResult struct {
 	Nonce        hexutil.Uint64  `json:"nonce"`
 	StorageHash  common.Hash     `json:"storageHash"`
 	StorageProof []StorageResult `json:"storageProof"`
+	// POWStorageProof is only set by GetPOWStorageProof
+	POWStorageProof []POWStorageResult `json:"powStorageProof,omitempty"`
 }
 
 type StorageResult struct {
@@ -653,13 +656,35 @@ type StorageResult struct {
 	Proof []string     `json:"proof"`
 }
 
+// POWStorageResult is the proof of a byte array written through SetPOWState.
+type POWStorageResult struct {
+	Key   string        `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+	Proof []string      `json:"proof"`
+}
+
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
 func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	return getProof(state, address, storageKeys, nil)
+}
+
+// GetPOWStorageProof returns the Merkle-proof for a given account and the byte
+// arrays stored under the given keys through SetPOWState, such as the headers
+// the Ethereum header store keeps under ethereum.HeaderDbKey. The value of a
+// byte array is the leaf of its proof as it is.
+func (s *PublicBlockChainAPI) GetPOWStorageProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return getProof(state, address, nil, storageKeys)
+}
 
+func getProof(state *state.StateDB, address common.Address, storageKeys, powStorageKeys []string) (*AccountResult, error) {
 	storageTrie := state.StorageTrie(address)
 	storageHash := types.EmptyRootHash
 	codeHash := state.GetCodeHash(address)
@@ -685,6 +710,18 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 			storageProof[i] = StorageResult{key, &hexutil.Big{}, []string{}}
 		}
 	}
+	var powStorageProof []POWStorageResult
+	for _, key := range powStorageKeys {
+		if storageTrie == nil {
+			powStorageProof = append(powStorageProof, POWStorageResult{key, hexutil.Bytes{}, []string{}})
+			continue
+		}
+		proof, storageError := state.GetStorageProof(address, common.HexToHash(key))
+		if storageError != nil {
+			return nil, storageError
+		}
+		powStorageProof = append(powStorageProof, POWStorageResult{key, state.GetPOWState(address, common.HexToHash(key)), toHexSlice(proof)})
+	}
 
 	// create the accountProof
 	accountProof, proofErr := state.GetProof(address)
@@ -693,13 +730,14 @@ func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Addre
 	}
 
 	return &AccountResult{
-		Address:      address,
-		AccountProof: toHexSlice(accountProof),
-		Balance:      (*hexutil.Big)(state.GetBalance(address)),
-		CodeHash:     codeHash,
-		Nonce:        hexutil.Uint64(state.GetNonce(address)),
-		StorageHash:  storageHash,
-		StorageProof: storageProof,
+		Address:         address,
+		AccountProof:    toHexSlice(accountProof),
+		Balance:         (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:        codeHash,
+		Nonce:           hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:     storageHash,
+		StorageProof:    storageProof,
+		POWStorageProof: powStorageProof,
 	}, state.Error()
 }
 
@@ -973,8 +1011,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	} else {
 		feeCap = common.Big0
 	}
-	// Recap the highest gas limit with account's available balance.
-	if feeCap.BitLen() != 0 {
+	// Recap the highest gas limit with account's available balance. The gas of
+	// a fee currency is not paid from it, the state transition checks that one.
+	if feeCap.BitLen() != 0 && args.FeeCurrency == nil {
 		state, _, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 		if err != nil {
 			return 0, err
@@ -1239,6 +1278,7 @@ type RPCTransaction struct {
 	GasPrice         *hexutil.Big      `json:"gasPrice"`
 	GasFeeCap        *hexutil.Big      `json:"maxFeePerGas,omitempty"`
 	GasTipCap        *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	FeeCurrency      *common.Address   `json:"feeCurrency,omitempty"`
 	Hash             common.Hash       `json:"hash"`
 	Input            hexutil.Bytes     `json:"input"`
 	Nonce            hexutil.Uint64    `json:"nonce"`
@@ -1297,6 +1337,16 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		} else {
 			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
 		}
+	case types.FeeCurrencyTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		result.FeeCurrency = tx.FeeCurrency()
+		// The base fee is in MAP, the gas price paid in the fee currency
+		// depends on its exchange rate at the block
+		result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
 	}
 	return result
 }
@@ -1790,9 +1840,25 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Tra
 	return &SignTransactionResult{data, signed}, nil
 }
 
-func (s *PublicTransactionPoolAPI) GetRelayers(ctx context.Context, blockNr rpc.BlockNumber) error {
-
-	return nil
+// GetRelayers returns the relayers allowed to write headers of the chain at
+// the given block, before the relayer set fork that is the relayer set by
+// setRelayer.
+func (s *PublicTransactionPoolAPI) GetRelayers(ctx context.Context, chainType uint64, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	statedb, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if !s.b.ChainConfig().IsRelayerSet(header.Number) {
+		if relayer := vm.ReadRelayer(statedb); relayer != (common.Address{}) {
+			return []common.Address{relayer}, nil
+		}
+		return []common.Address{}, nil
+	}
+	relayers, _, err := vm.ReadRelayers(statedb, chains.ChainType(chainType))
+	if err != nil {
+		return nil, err
+	}
+	return relayers, nil
 }
 
 // PendingTransactions returns the transactions that are in the transaction pool
@@ -2057,17 +2123,42 @@ func NewPublicHeaderStoreAPI(b Backend) *PublicHeaderStoreAPI {
 }
 
 func (p *PublicHeaderStoreAPI) LatestState() (*state.StateDB, error) {
-	bn := rpc.LatestBlockNumber
-	statedb, _, err := p.b.StateAndHeaderByNumberOrHash(context.Background(), rpc.BlockNumberOrHash{BlockNumber: &bn})
+	return p.stateAt(context.Background(), nil)
+}
+
+// stateAt returns the state at the given block, the latest one if it is nil.
+func (p *PublicHeaderStoreAPI) stateAt(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*state.StateDB, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, _, err := p.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
 	if err != nil {
 		return nil, err
 	}
 	if statedb == nil {
-		return nil, errors.New("failed to get state by latest number")
+		return nil, errors.New("failed to get state by block number or hash")
 	}
 	return statedb, nil
 }
 
+// headerReader returns the header store of the chain if it keeps full headers.
+func (p *PublicHeaderStoreAPI) headerReader(chainID uint64) (interfaces.IHeaderReader, error) {
+	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID))
+	if err != nil {
+		return nil, err
+	}
+	hs, err := interfaces.HeaderStoreFactory(group)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := hs.(interfaces.IHeaderReader)
+	if !ok {
+		return nil, fmt.Errorf("header store of chain %d does not keep full headers", chainID)
+	}
+	return reader, nil
+}
+
 func (p *PublicHeaderStoreAPI) CurrentHeaderNumber(chainID uint64) (uint64, error) {
 	//return new(ethereum.Validate).GetCurrentHeaderNumber(chains.ChainType(chainID))
 	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID))
@@ -2133,3 +2224,116 @@ func (p *PublicHeaderStoreAPI) CurrentNumberAndHash(chainID uint64) (map[string]
 	}
 	return nh, nil
 }
+
+// GetHeaderByNumber returns the canonical header of the chain with the given
+// number, as stored at the given atlas block.
+func (p *PublicHeaderStoreAPI) GetHeaderByNumber(ctx context.Context, chainID uint64, number uint64, blockNrOrHash *rpc.BlockNumberOrHash) (*ethereum.Header, error) {
+	hs, err := p.headerReader(chainID)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := p.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	// the ring slot may still hold the header of an older number
+	header := hs.GetHeaderByNumber(number, statedb)
+	if header == nil || header.Number.Uint64() != number {
+		return nil, nil
+	}
+	return header, nil
+}
+
+// GetHeaderByHash returns the canonical header of the chain with the given
+// hash and number, as stored at the given atlas block.
+func (p *PublicHeaderStoreAPI) GetHeaderByHash(ctx context.Context, chainID uint64, hash common.Hash, number uint64, blockNrOrHash *rpc.BlockNumberOrHash) (*ethereum.Header, error) {
+	hs, err := p.headerReader(chainID)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := p.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return hs.GetHeaderByHash(hash, number, statedb), nil
+}
+
+// GetTotalDifficulty returns the total difficulty of the canonical header of
+// the chain with the given hash and number.
+func (p *PublicHeaderStoreAPI) GetTotalDifficulty(ctx context.Context, chainID uint64, hash common.Hash, number uint64, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	hs, err := p.headerReader(chainID)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := p.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if hs.GetHeaderByHash(hash, number, statedb) == nil {
+		return nil, nil
+	}
+	return (*hexutil.Big)(hs.GetTd(hash, number, statedb)), nil
+}
+
+// maxCanonicalRange caps the number of hashes returned by GetCanonicalRange.
+const maxCanonicalRange = 1024
+
+// GetCanonicalRange returns the canonical hashes of the chain in [from, to],
+// numbers without a canonical header are skipped.
+func (p *PublicHeaderStoreAPI) GetCanonicalRange(ctx context.Context, chainID uint64, from, to uint64, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range, from %d is greater than to %d", from, to)
+	}
+	if to-from >= maxCanonicalRange {
+		return nil, fmt.Errorf("range of %d headers exceeds the limit of %d", to-from+1, maxCanonicalRange)
+	}
+	hs, err := p.headerReader(chainID)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := p.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	nhs := make([]map[string]interface{}, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		hash := hs.ReadCanonicalHash(number, statedb)
+		if hash == (common.Hash{}) {
+			continue
+		}
+		nhs = append(nhs, map[string]interface{}{
+			"number": number,
+			"hash":   hash,
+		})
+	}
+	return nhs, nil
+}
+
+// GetRelayer returns the relayers allowed to write headers of the chain at
+// the given atlas block, before the relayer set fork that is the relayer set
+// by setRelayer.
+func (p *PublicHeaderStoreAPI) GetRelayer(ctx context.Context, chainID uint64, blockNrOrHash *rpc.BlockNumberOrHash) ([]common.Address, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, header, err := p.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, errors.New("failed to get state by block number or hash")
+	}
+	if !p.b.ChainConfig().IsRelayerSet(header.Number) {
+		if relayer := vm.ReadRelayer(statedb); relayer != (common.Address{}) {
+			return []common.Address{relayer}, nil
+		}
+		return []common.Address{}, nil
+	}
+	relayers, _, err := vm.ReadRelayers(statedb, chains.ChainType(chainID))
+	if err != nil {
+		return nil, err
+	}
+	return relayers, nil
+}
@@ -423,6 +423,8 @@ type storageMap map[common.Hash]storageEntry
 type storageEntry struct {
 	Key   *common.Hash `json:"key"`
 	Value common.Hash  `json:"value"`
+	// POWValue is set instead of Value for byte arrays written through SetPOWState
+	POWValue hexutil.Bytes `json:"powValue,omitempty"`
 }
 
 // StorageRangeAt returns the storage at the given block height and transaction index.
@@ -440,18 +442,25 @@ func (api *PrivateDebugAPI) StorageRangeAt(blockHash common.Hash, txIndex int, c
 	if st == nil {
 		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
 	}
-	return storageRangeAt(st, keyStart, maxResult)
+	return storageRangeAt(st, keyStart, maxResult, statedb.IsPOWStorageAccount(contractAddress))
 }
 
-func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeResult, error) {
+// storageRangeAt returns the storage of st from start on, pow tells whether the
+// account of st is written through SetPOWState.
+func storageRangeAt(st state.Trie, start []byte, maxResult int, pow bool) (StorageRangeResult, error) {
 	it := trie.NewIterator(st.NodeIterator(start))
 	result := StorageRangeResult{Storage: storageMap{}}
 	for i := 0; i < maxResult && it.Next(); i++ {
-		_, content, _, err := rlp.Split(it.Value)
-		if err != nil {
-			return StorageRangeResult{}, err
+		var e storageEntry
+		if pow {
+			e.POWValue = common.CopyBytes(it.Value)
+		} else {
+			_, content, _, err := rlp.Split(it.Value)
+			if err != nil {
+				return StorageRangeResult{}, err
+			}
+			e.Value = common.BytesToHash(content)
 		}
-		e := storageEntry{Value: common.BytesToHash(content)}
 		if preimage := st.GetKey(it.Key); preimage != nil {
 			preimage := common.BytesToHash(preimage)
 			e.Key = &preimage
@@ -364,3 +364,11 @@ func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *types.Block, re
 func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (chain.Message, vm.BlockContext, *state.StateDB, error) {
 	return b.eth.stateAtTransaction(block, txIndex, reexec)
 }
+
+func (b *EthAPIBackend) NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner {
+	return b.eth.blockchain.NewEVMRunner(header, state)
+}
+
+func (b *EthAPIBackend) TraceSystemCalls(statedb *state.StateDB, tracer vm.Tracer) (stop func()) {
+	return b.eth.blockchain.TraceSystemCalls(statedb, tracer)
+}
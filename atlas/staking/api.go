@@ -0,0 +1,297 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package staking implements the RPC API reading the validators and their votes
+// from the Validators, Election and Accounts contracts.
+package staking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/contracts/accounts"
+	"github.com/mapprotocol/atlas/contracts/election"
+	"github.com/mapprotocol/atlas/contracts/validators"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+)
+
+var errStateNotFound = errors.New("failed to get state by block number or hash")
+
+// Backend interface provides the chain access the staking API needs, the
+// contracts are queried through an EVMRunner at the historical state.
+type Backend interface {
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
+	NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner
+}
+
+// Validator is a registered validator with the votes it received. Score,
+// commission and slash multiplier are fixidity fractions scaled by 1e24.
+type Validator struct {
+	Account             common.Address   `json:"account"`
+	Signer              common.Address   `json:"signer"`
+	Eligible            bool             `json:"eligible"`
+	Score               *hexutil.Big     `json:"score"`
+	Commission          *hexutil.Big     `json:"commission"`
+	NextCommission      *hexutil.Big     `json:"nextCommission"`
+	NextCommissionBlock *hexutil.Big     `json:"nextCommissionBlock"`
+	SlashMultiplier     *hexutil.Big     `json:"slashMultiplier"`
+	PendingVotes        *hexutil.Big     `json:"pendingVotes"` // Votes activated at the next epoch
+	ActiveVotes         *hexutil.Big     `json:"activeVotes"`
+	TotalVotes          *hexutil.Big     `json:"totalVotes"`
+	PendingVoters       []common.Address `json:"pendingVoters"`
+}
+
+// Staking is the validator set registered at a block.
+type Staking struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	TotalVotes *hexutil.Big   `json:"totalVotes"`
+	Validators []*Validator   `json:"validators"`
+}
+
+// Vote is the vote of an account for a validator.
+type Vote struct {
+	Validator common.Address `json:"validator"`
+	Pending   *hexutil.Big   `json:"pending"`
+	Active    *hexutil.Big   `json:"active"`
+}
+
+// Votes is the votes of an account at a block.
+type Votes struct {
+	Number  hexutil.Uint64 `json:"number"`
+	Hash    common.Hash    `json:"hash"`
+	Account common.Address `json:"account"`
+	Votes   []*Vote        `json:"votes"`
+}
+
+// ValidatorVotes is the total votes of an eligible validator.
+type ValidatorVotes struct {
+	Validator common.Address `json:"validator"`
+	Votes     *hexutil.Big   `json:"votes"`
+}
+
+// EligibleValidators is the eligible validators at a block, sorted by votes in
+// descending order.
+type EligibleValidators struct {
+	Number     hexutil.Uint64    `json:"number"`
+	Hash       common.Hash       `json:"hash"`
+	Validators []*ValidatorVotes `json:"validators"`
+}
+
+// API is the collection of staking APIs.
+type API struct {
+	b Backend
+}
+
+// NewAPI creates a new staking API.
+func NewAPI(b Backend) *API {
+	return &API{b: b}
+}
+
+// Staking returns the registered validators and their votes at the given block.
+func (api *API) Staking(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*Staking, error) {
+	header, vmRunner, err := api.runnerAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	totalVotes, err := election.GetTotalVotes(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("total votes: %w", err)
+	}
+	registered, err := validators.RetrieveRegisteredValidators(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("registered validators: %w", err)
+	}
+	staking := &Staking{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		Hash:       header.Hash(),
+		TotalVotes: (*hexutil.Big)(totalVotes),
+		Validators: make([]*Validator, 0, len(registered)),
+	}
+	for _, account := range registered {
+		validator, err := validatorAt(vmRunner, account)
+		if err != nil {
+			return nil, err
+		}
+		staking.Validators = append(staking.Validators, validator)
+	}
+	return staking, nil
+}
+
+// StakingValidator returns the validator, given by its account or any of its
+// signers, at the given block.
+func (api *API) StakingValidator(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*Validator, error) {
+	_, vmRunner, err := api.runnerAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	account, err := accounts.GetSignerToAccountMethod(vmRunner, address)
+	if err != nil {
+		return nil, fmt.Errorf("account of %s: %w", address.Hex(), err)
+	}
+	return validatorAt(vmRunner, account)
+}
+
+// StakingVotes returns the pending and active votes of the account, given by
+// itself or any of its signers, at the given block.
+func (api *API) StakingVotes(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*Votes, error) {
+	header, vmRunner, err := api.runnerAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	account, err := accounts.GetSignerToAccountMethod(vmRunner, address)
+	if err != nil {
+		return nil, fmt.Errorf("account of %s: %w", address.Hex(), err)
+	}
+	votedFor, err := election.GetValidatorsVotedForByAccount(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("validators voted for: %w", err)
+	}
+	votes := &Votes{
+		Number:  hexutil.Uint64(header.Number.Uint64()),
+		Hash:    header.Hash(),
+		Account: account,
+		Votes:   make([]*Vote, 0, len(votedFor)),
+	}
+	for _, validator := range votedFor {
+		pending, err := election.GetPendingVotesForValidatorByAccount(vmRunner, validator, account)
+		if err != nil {
+			return nil, fmt.Errorf("pending votes for %s: %w", validator.Hex(), err)
+		}
+		active, err := election.GetActiveVotesForValidatorByAccount(vmRunner, validator, account)
+		if err != nil {
+			return nil, fmt.Errorf("active votes for %s: %w", validator.Hex(), err)
+		}
+		votes.Votes = append(votes.Votes, &Vote{
+			Validator: validator,
+			Pending:   (*hexutil.Big)(pending),
+			Active:    (*hexutil.Big)(active),
+		})
+	}
+	return votes, nil
+}
+
+// StakingEligibleValidators returns the eligible validators and their total
+// votes at the given block.
+func (api *API) StakingEligibleValidators(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*EligibleValidators, error) {
+	header, vmRunner, err := api.runnerAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	accounts, values, err := election.GetTotalVotesForEligibleValidators(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("eligible validators: %w", err)
+	}
+	eligible := &EligibleValidators{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		Hash:       header.Hash(),
+		Validators: make([]*ValidatorVotes, 0, len(accounts)),
+	}
+	for i, account := range accounts {
+		eligible.Validators = append(eligible.Validators, &ValidatorVotes{
+			Validator: account,
+			Votes:     (*hexutil.Big)(values[i]),
+		})
+	}
+	return eligible, nil
+}
+
+// StakingTopValidators returns the n eligible validators with the most votes at
+// the given block.
+func (api *API) StakingTopValidators(ctx context.Context, n hexutil.Uint64, blockNrOrHash rpc.BlockNumberOrHash) ([]common.Address, error) {
+	_, vmRunner, err := api.runnerAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	top, err := validators.GetTopValidators(vmRunner, new(big.Int).SetUint64(uint64(n)))
+	if err != nil {
+		return nil, fmt.Errorf("top validators: %w", err)
+	}
+	return top, nil
+}
+
+// validatorAt reads the validator account from the contracts.
+func validatorAt(vmRunner vm.EVMRunner, account common.Address) (*Validator, error) {
+	data, err := validators.GetValidator(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("validator %s: %w", account.Hex(), err)
+	}
+	eligible, err := election.GetValidatorEligibility(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("eligibility of %s: %w", account.Hex(), err)
+	}
+	pending, err := election.GetPendingVotesForValidator(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("pending votes for %s: %w", account.Hex(), err)
+	}
+	active, err := election.GetActiveVotesForValidator(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("active votes for %s: %w", account.Hex(), err)
+	}
+	total, err := election.GetTotalVotesForValidator(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("total votes for %s: %w", account.Hex(), err)
+	}
+	voters, err := election.GetPendingVotersForValidator(vmRunner, account)
+	if err != nil {
+		return nil, fmt.Errorf("pending voters for %s: %w", account.Hex(), err)
+	}
+	return &Validator{
+		Account:             account,
+		Signer:              data.Signer,
+		Eligible:            eligible,
+		Score:               (*hexutil.Big)(data.Score),
+		Commission:          (*hexutil.Big)(data.Commission),
+		NextCommission:      (*hexutil.Big)(data.NextCommission),
+		NextCommissionBlock: (*hexutil.Big)(data.NextCommissionBlock),
+		SlashMultiplier:     (*hexutil.Big)(data.SlashMultiplier),
+		PendingVotes:        (*hexutil.Big)(pending),
+		ActiveVotes:         (*hexutil.Big)(active),
+		TotalVotes:          (*hexutil.Big)(total),
+		PendingVoters:       voters,
+	}, nil
+}
+
+func (api *API) runnerAt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, vm.EVMRunner, error) {
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, nil, errStateNotFound
+	}
+	return header, api.b.NewEVMRunner(header, statedb), nil
+}
+
+// APIs return the collection of RPC services the staking package offers.
+func APIs(backend Backend) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "atlas",
+			Version:   "1.0",
+			Service:   NewAPI(backend),
+			Public:    true,
+		},
+	}
+}
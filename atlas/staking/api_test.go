@@ -0,0 +1,222 @@
+package staking
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	validatorA = common.HexToAddress("0x0a1")
+	validatorB = common.HexToAddress("0x0a2")
+	signerA    = common.HexToAddress("0x5a1")
+	signerB    = common.HexToAddress("0x5a2")
+	voter      = common.HexToAddress("0x0b1")
+	voteSigner = common.HexToAddress("0x5b1")
+)
+
+type validatorsMock struct {
+	testutil.ContractMock
+}
+
+func (m *validatorsMock) GetRegisteredValidators() []common.Address {
+	return []common.Address{validatorA, validatorB}
+}
+
+func (m *validatorsMock) GetValidator(account common.Address) ([]byte, []byte, []byte, *big.Int, common.Address, *big.Int, *big.Int, *big.Int, *big.Int, *big.Int) {
+	signer := signerA
+	if account == validatorB {
+		signer = signerB
+	}
+	return []byte{}, make([]byte, blscrypto.PUBLICKEYBYTES), make([]byte, blscrypto.G1PUBLICKEYBYTES),
+		new(big.Int).Set(params.Fixidity1), signer, big.NewInt(10), big.NewInt(20), big.NewInt(1000),
+		new(big.Int).Set(params.Fixidity1), big.NewInt(0)
+}
+
+func (m *validatorsMock) GetTopValidators(n *big.Int) []common.Address {
+	return []common.Address{validatorA, validatorB}[:n.Int64()]
+}
+
+type electionMock struct {
+	testutil.ContractMock
+}
+
+func (em *electionMock) GetTotalVotes() *big.Int { return big.NewInt(700) }
+func (em *electionMock) GetValidatorEligibility(validator common.Address) bool {
+	return validator == validatorA
+}
+func (em *electionMock) GetPendingVotesForValidator(validator common.Address) *big.Int {
+	return big.NewInt(100)
+}
+func (em *electionMock) GetActiveVotesForValidator(validator common.Address) *big.Int {
+	return big.NewInt(250)
+}
+func (em *electionMock) GetTotalVotesForValidator(validator common.Address) *big.Int {
+	return big.NewInt(350)
+}
+func (em *electionMock) GetTotalVotesForEligibleValidators() ([]common.Address, []*big.Int) {
+	return []common.Address{validatorA}, []*big.Int{big.NewInt(350)}
+}
+func (em *electionMock) GetPendingVotersForValidator(validator common.Address) []common.Address {
+	return []common.Address{voter}
+}
+func (em *electionMock) GetValidatorsVotedForByAccount(account common.Address) []common.Address {
+	if account != voter {
+		return nil
+	}
+	return []common.Address{validatorA}
+}
+func (em *electionMock) GetPendingVotesForValidatorByAccount(validator, account common.Address) *big.Int {
+	return big.NewInt(100)
+}
+func (em *electionMock) GetActiveVotesForValidatorByAccount(validator, account common.Address) *big.Int {
+	return big.NewInt(50)
+}
+
+type accountsMock struct {
+	testutil.ContractMock
+}
+
+func (am *accountsMock) SignerToAccount(signer common.Address) common.Address {
+	switch signer {
+	case signerA:
+		return validatorA
+	case signerB:
+		return validatorB
+	case voteSigner:
+		return voter
+	}
+	return signer
+}
+
+type testBackend struct {
+	statedb *state.StateDB
+	header  *types.Header
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	return &testBackend{statedb: statedb, header: &types.Header{Number: big.NewInt(1)}}
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if number, ok := blockNrOrHash.Number(); ok && number.Int64() != b.header.Number.Int64() {
+		return nil, nil, nil
+	}
+	return b.statedb, b.header, nil
+}
+
+func (b *testBackend) NewEVMRunner(*types.Header, types.StateDB) vm.EVMRunner {
+	runner := testutil.NewMockEVMRunner()
+	registry := testutil.NewRegistryMock()
+	runner.RegisterContract(params.RegistrySmartContractAddress, registry)
+
+	validators := &validatorsMock{}
+	validators.ContractMock = testutil.NewContractMock(abis.Validators, validators)
+	election := &electionMock{}
+	election.ContractMock = testutil.NewContractMock(abis.Elections, election)
+	accounts := &accountsMock{}
+	accounts.ContractMock = testutil.NewContractMock(abis.Accounts, accounts)
+
+	for i, contract := range []struct {
+		id   common.Hash
+		mock testutil.Contract
+	}{
+		{params.ValidatorsRegistryId, validators},
+		{params.ElectionRegistryId, election},
+		{params.AccountsId, accounts},
+	} {
+		address := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		registry.AddContract(contract.id, address)
+		runner.RegisterContract(address, contract.mock)
+	}
+	return runner
+}
+
+var latest = rpc.BlockNumberOrHashWithNumber(1)
+
+func TestStaking(t *testing.T) {
+	g := NewGomegaWithT(t)
+	api := NewAPI(newTestBackend(t))
+
+	staking, err := api.Staking(context.Background(), latest)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(staking.TotalVotes.ToInt()).To(Equal(big.NewInt(700)))
+	g.Expect(staking.Validators).To(HaveLen(2))
+
+	a, b := staking.Validators[0], staking.Validators[1]
+	g.Expect(a.Account).To(Equal(validatorA))
+	g.Expect(a.Signer).To(Equal(signerA))
+	g.Expect(a.Eligible).To(BeTrue())
+	g.Expect(a.Score.ToInt()).To(Equal(params.Fixidity1))
+	g.Expect(a.Commission.ToInt()).To(Equal(big.NewInt(10)))
+	g.Expect(a.NextCommission.ToInt()).To(Equal(big.NewInt(20)))
+	g.Expect(a.NextCommissionBlock.ToInt()).To(Equal(big.NewInt(1000)))
+	g.Expect(a.PendingVotes.ToInt()).To(Equal(big.NewInt(100)))
+	g.Expect(a.ActiveVotes.ToInt()).To(Equal(big.NewInt(250)))
+	g.Expect(a.TotalVotes.ToInt()).To(Equal(big.NewInt(350)))
+	g.Expect(a.PendingVoters).To(Equal([]common.Address{voter}))
+	g.Expect(b.Account).To(Equal(validatorB))
+	g.Expect(b.Signer).To(Equal(signerB))
+	g.Expect(b.Eligible).To(BeFalse())
+
+	_, err = api.Staking(context.Background(), rpc.BlockNumberOrHashWithNumber(2))
+	g.Expect(err).To(Equal(errStateNotFound))
+}
+
+func TestStakingValidator(t *testing.T) {
+	api := NewAPI(newTestBackend(t))
+
+	for _, address := range []common.Address{validatorB, signerB} {
+		g := NewGomegaWithT(t)
+		validator, err := api.StakingValidator(context.Background(), address, latest)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(validator.Account).To(Equal(validatorB))
+		g.Expect(validator.Signer).To(Equal(signerB))
+	}
+}
+
+func TestStakingVotes(t *testing.T) {
+	api := NewAPI(newTestBackend(t))
+
+	for _, address := range []common.Address{voter, voteSigner} {
+		g := NewGomegaWithT(t)
+		votes, err := api.StakingVotes(context.Background(), address, latest)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(votes.Account).To(Equal(voter))
+		g.Expect(votes.Votes).To(HaveLen(1))
+		g.Expect(votes.Votes[0].Validator).To(Equal(validatorA))
+		g.Expect(votes.Votes[0].Pending.ToInt()).To(Equal(big.NewInt(100)))
+		g.Expect(votes.Votes[0].Active.ToInt()).To(Equal(big.NewInt(50)))
+	}
+}
+
+func TestStakingEligibleValidators(t *testing.T) {
+	g := NewGomegaWithT(t)
+	api := NewAPI(newTestBackend(t))
+
+	eligible, err := api.StakingEligibleValidators(context.Background(), latest)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(eligible.Validators).To(HaveLen(1))
+	g.Expect(eligible.Validators[0].Validator).To(Equal(validatorA))
+	g.Expect(eligible.Validators[0].Votes.ToInt()).To(Equal(big.NewInt(350)))
+
+	top, err := api.StakingTopValidators(context.Background(), 1, latest)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(top).To(Equal([]common.Address{validatorA}))
+}
@@ -0,0 +1,281 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package supply implements the RPC API accounting for the MAP supply and the
+// MAP minted as epoch rewards.
+package supply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/accounts/abi"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/epoch_rewards"
+	"github.com/mapprotocol/atlas/contracts/gold_token"
+	"github.com/mapprotocol/atlas/contracts/locked_gold"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	validatorPaymentEvent = "ValidatorEpochPaymentDistributed"
+	voterRewardsEvent     = "EpochRewardsDistributedToVoters"
+)
+
+var errStateNotFound = errors.New("failed to get state by block number or hash")
+
+// Backend interface provides the chain access the supply API needs, the
+// contracts are queried through an EVMRunner at the historical state.
+type Backend interface {
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	ChainConfig() *params.ChainConfig
+	NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner
+}
+
+// Supply is the MAP supply at a block.
+type Supply struct {
+	Number      hexutil.Uint64 `json:"number"`
+	Hash        common.Hash    `json:"hash"`
+	Epoch       hexutil.Uint64 `json:"epoch"`
+	TotalSupply *hexutil.Big   `json:"totalSupply"`
+	Locked      *hexutil.Big   `json:"locked"`   // MAP locked in the LockedGold contract
+	Voting      *hexutil.Big   `json:"voting"`   // Part of the locked MAP used for votes
+	Unlocked    *hexutil.Big   `json:"unlocked"` // MAP not locked
+}
+
+// EpochMint is the MAP minted at the last block of an epoch and where it went.
+// Validator and Voter are summed from the reward logs of the block.
+type EpochMint struct {
+	Total             *hexutil.Big   `json:"total"` // Increase of the total supply over the last block
+	Validator         *hexutil.Big   `json:"validator"`
+	Voter             *hexutil.Big   `json:"voter"`
+	Community         *hexutil.Big   `json:"community"`
+	Maintainer        *hexutil.Big   `json:"maintainer"`
+	CommunityPartner  common.Address `json:"communityPartner"`
+	MaintainerAddress common.Address `json:"maintainerAddress"`
+}
+
+// EpochSupply is the MAP supply at the last block of an epoch and the MAP minted
+// at that block.
+type EpochSupply struct {
+	*Supply
+	Mint *EpochMint `json:"mint"`
+}
+
+// API is the collection of supply accounting APIs.
+type API struct {
+	b Backend
+}
+
+// NewAPI creates a new supply API.
+func NewAPI(b Backend) *API {
+	return &API{b: b}
+}
+
+// Supply returns the total, locked and unlocked MAP at the given block.
+func (api *API) Supply(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*Supply, error) {
+	statedb, header, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.supplyAt(header, statedb)
+}
+
+// EpochSupply returns the supply at the last block of the given epoch and the
+// MAP minted as rewards at that block.
+func (api *API) EpochSupply(ctx context.Context, epoch hexutil.Uint64) (*EpochSupply, error) {
+	if epoch == 0 {
+		return nil, errors.New("epoch 0 has no rewards")
+	}
+	number := istanbul.GetEpochLastBlockNumber(uint64(epoch), api.epochSize())
+	statedb, header, err := api.stateAndHeader(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+	if err != nil {
+		return nil, err
+	}
+	supply, err := api.supplyAt(header, statedb)
+	if err != nil {
+		return nil, err
+	}
+	parentState, parent, err := api.stateAndHeader(ctx, rpc.BlockNumberOrHashWithHash(header.ParentHash, true))
+	if err != nil {
+		return nil, err
+	}
+	mint, err := api.epochMint(ctx, header, parent, parentState, supply.TotalSupply.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	return &EpochSupply{Supply: supply, Mint: mint}, nil
+}
+
+// supplyAt reads the supply from the contracts at the state of header.
+func (api *API) supplyAt(header *types.Header, statedb *state.StateDB) (*Supply, error) {
+	vmRunner := api.b.NewEVMRunner(header, statedb)
+	totalSupply, err := gold_token.GetTotalSupply(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("total supply: %w", err)
+	}
+	locked, err := locked_gold.GetTotalLockedGold(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("total locked: %w", err)
+	}
+	nonvoting, err := locked_gold.GetNonvotingLockedGold(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("nonvoting locked: %w", err)
+	}
+	return &Supply{
+		Number:      hexutil.Uint64(header.Number.Uint64()),
+		Hash:        header.Hash(),
+		Epoch:       hexutil.Uint64(istanbul.GetEpochNumber(header.Number.Uint64(), api.epochSize())),
+		TotalSupply: (*hexutil.Big)(totalSupply),
+		Locked:      (*hexutil.Big)(locked),
+		Voting:      (*hexutil.Big)(new(big.Int).Sub(locked, nonvoting)),
+		Unlocked:    (*hexutil.Big)(new(big.Int).Sub(totalSupply, locked)),
+	}, nil
+}
+
+// epochMint splits the MAP minted at the last block of an epoch the way the
+// rewards are distributed, reading the targets at the state of its parent.
+func (api *API) epochMint(ctx context.Context, header, parent *types.Header, parentState *state.StateDB, totalSupply *big.Int) (*EpochMint, error) {
+	vmRunner := api.b.NewEVMRunner(parent, parentState)
+	parentSupply, err := gold_token.GetTotalSupply(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("parent total supply: %w", err)
+	}
+	mint := &EpochMint{
+		Total:      (*hexutil.Big)(new(big.Int).Sub(totalSupply, parentSupply)),
+		Community:  new(hexutil.Big),
+		Maintainer: new(hexutil.Big),
+	}
+	if mint.CommunityPartner, err = epoch_rewards.GetCommunityPartnerAddress(vmRunner); err != nil {
+		return nil, fmt.Errorf("community partner: %w", err)
+	}
+	if mint.MaintainerAddress, err = epoch_rewards.GetMgrMaintainerAddress(vmRunner); err != nil {
+		return nil, fmt.Errorf("maintainer: %w", err)
+	}
+	validator, voter, err := api.rewardLogs(ctx, header, vmRunner)
+	if err != nil {
+		return nil, err
+	}
+	mint.Validator, mint.Voter = (*hexutil.Big)(validator), (*hexutil.Big)(voter)
+	// No rewards are paid up to the reward block
+	if enableRewardBlock := api.b.ChainConfig().EnableRewardBlock; enableRewardBlock != nil && header.Number.Cmp(enableRewardBlock) <= 0 {
+		return mint, nil
+	}
+	_, communityReward, maintainerReward, err := epoch_rewards.CalculateTargetEpochRewards(vmRunner)
+	if err != nil {
+		return nil, fmt.Errorf("target epoch rewards: %w", err)
+	}
+	if mint.CommunityPartner != params.ZeroAddress {
+		mint.Community = (*hexutil.Big)(communityReward)
+	}
+	if mint.MaintainerAddress != params.ZeroAddress {
+		mint.Maintainer = (*hexutil.Big)(maintainerReward)
+	}
+	return mint, nil
+}
+
+// rewardLogs sums the validator payments and the voter rewards logged by the
+// Validators and Election contracts in the block receipt of header, the
+// contracts are looked up in the registry through vmRunner.
+func (api *API) rewardLogs(ctx context.Context, header *types.Header, vmRunner vm.EVMRunner) (*big.Int, *big.Int, error) {
+	validatorsAddress, err := contracts.GetRegisteredAddress(vmRunner, params.ValidatorsRegistryId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("validators contract: %w", err)
+	}
+	electionAddress, err := contracts.GetRegisteredAddress(vmRunner, params.ElectionRegistryId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("election contract: %w", err)
+	}
+	receipts, err := api.b.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		hash      = header.Hash()
+		validator = new(big.Int)
+		voter     = new(big.Int)
+	)
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			// Only the logs of the block receipt are emitted by the reward distribution
+			if log.TxHash != hash || len(log.Topics) == 0 {
+				continue
+			}
+			var (
+				contract *abi.ABI
+				total    *big.Int
+				event    string
+			)
+			switch {
+			case log.Address == validatorsAddress && log.Topics[0] == abis.Validators.Events[validatorPaymentEvent].ID:
+				contract, total, event = abis.Validators, validator, validatorPaymentEvent
+			case log.Address == electionAddress && log.Topics[0] == abis.Elections.Events[voterRewardsEvent].ID:
+				contract, total, event = abis.Elections, voter, voterRewardsEvent
+			default:
+				continue
+			}
+			values, err := contract.Unpack(event, log.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s log: %w", event, err)
+			}
+			value, ok := values[0].(*big.Int)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s log: unexpected value %v", event, values[0])
+			}
+			total.Add(total, value)
+		}
+	}
+	return validator, voter, nil
+}
+
+func (api *API) stateAndHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, nil, errStateNotFound
+	}
+	return statedb, header, nil
+}
+
+func (api *API) epochSize() uint64 {
+	return api.b.ChainConfig().Istanbul.Epoch
+}
+
+// APIs return the collection of RPC services the supply package offers.
+func APIs(backend Backend) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "atlas",
+			Version:   "1.0",
+			Service:   NewAPI(backend),
+			Public:    true,
+		},
+	}
+}
@@ -0,0 +1,227 @@
+package supply
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mapprotocol/atlas/accounts/abi"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	communityPartner  = common.HexToAddress("0x0c0")
+	maintainer        = common.HexToAddress("0x0a1")
+	validatorsAddress = common.HexToAddress("0x2000")
+	electionAddress   = common.HexToAddress("0x2001")
+)
+
+type goldTokenMock struct {
+	testutil.ContractMock
+	totalSupply *big.Int
+}
+
+func (gm *goldTokenMock) TotalSupply() *big.Int { return gm.totalSupply }
+
+type lockedGoldMock struct {
+	testutil.ContractMock
+	locked, nonvoting *big.Int
+}
+
+func (lm *lockedGoldMock) GetTotalLockedGold() *big.Int     { return lm.locked }
+func (lm *lockedGoldMock) GetNonvotingLockedGold() *big.Int { return lm.nonvoting }
+
+type epochRewardsMock struct {
+	testutil.ContractMock
+}
+
+func (em *epochRewardsMock) CalculateTargetEpochRewards() (*big.Int, *big.Int, *big.Int) {
+	return big.NewInt(600), big.NewInt(300), big.NewInt(100)
+}
+func (em *epochRewardsMock) CommunityPartner() common.Address        { return communityPartner }
+func (em *epochRewardsMock) GetMgrMaintainerAddress() common.Address { return maintainer }
+
+// testBackend serves a chain of headers, one per entry of supplies holding the
+// total supply at that block.
+type testBackend struct {
+	config   *params.ChainConfig
+	db       ethdb.Database
+	statedb  *state.StateDB
+	headers  []*types.Header
+	supplies []*big.Int
+	receipts map[common.Hash]types.Receipts
+}
+
+func newTestBackend(t *testing.T, supplies []*big.Int) *testBackend {
+	db := rawdb.NewMemoryDatabase()
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	b := &testBackend{config: params.IstanbulTestChainConfig, db: db, statedb: statedb, supplies: supplies, receipts: make(map[common.Hash]types.Receipts)}
+	var parent common.Hash
+	for i := range supplies {
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parent}
+		b.headers = append(b.headers, header)
+		parent = header.Hash()
+	}
+	return b
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		if int(number) < 0 || int(number) >= len(b.headers) {
+			return nil, nil, nil
+		}
+		return b.statedb, b.headers[number], nil
+	}
+	hash, _ := blockNrOrHash.Hash()
+	for _, header := range b.headers {
+		if header.Hash() == hash {
+			return b.statedb, header, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.config }
+
+func (b *testBackend) NewEVMRunner(header *types.Header, _ types.StateDB) vm.EVMRunner {
+	runner := testutil.NewMockEVMRunner()
+	registry := testutil.NewRegistryMock()
+	runner.RegisterContract(params.RegistrySmartContractAddress, registry)
+
+	goldToken := &goldTokenMock{totalSupply: b.supplies[header.Number.Uint64()]}
+	goldToken.ContractMock = testutil.NewContractMock(abis.GoldToken, goldToken)
+	lockedGold := &lockedGoldMock{locked: big.NewInt(400), nonvoting: big.NewInt(150)}
+	lockedGold.ContractMock = testutil.NewContractMock(abis.LockedGold, lockedGold)
+	epochRewards := &epochRewardsMock{}
+	epochRewards.ContractMock = testutil.NewContractMock(abis.EpochRewards, epochRewards)
+
+	for i, contract := range []struct {
+		id   common.Hash
+		mock testutil.Contract
+	}{
+		{params.GoldTokenRegistryId, goldToken},
+		{params.LockedGoldRegistryId, lockedGold},
+		{params.EpochRewardsRegistryId, epochRewards},
+	} {
+		address := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		registry.AddContract(contract.id, address)
+		runner.RegisterContract(address, contract.mock)
+	}
+	// The reward logs are only matched against the registered addresses
+	registry.AddContract(params.ValidatorsRegistryId, validatorsAddress)
+	registry.AddContract(params.ElectionRegistryId, electionAddress)
+	return runner
+}
+
+// rewardLog returns a log of the reward event of contract for validator,
+// emitted in the transaction txHash.
+func rewardLog(t *testing.T, address common.Address, contract *abi.ABI, event string, validator common.Address, value int64, txHash common.Hash) *types.Log {
+	data, err := contract.Events[event].Inputs.NonIndexed().Pack(big.NewInt(value))
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", event, err)
+	}
+	return &types.Log{
+		Address: address,
+		Topics:  []common.Hash{contract.Events[event].ID, validator.Hash()},
+		Data:    data,
+		TxHash:  txHash,
+	}
+}
+
+func testSupplies(epochSize uint64) []*big.Int {
+	supplies := make([]*big.Int, epochSize+1)
+	for i := range supplies {
+		supplies[i] = big.NewInt(1000)
+	}
+	supplies[epochSize] = big.NewInt(2000)
+	return supplies
+}
+
+func TestSupply(t *testing.T) {
+	g := NewGomegaWithT(t)
+	api := NewAPI(newTestBackend(t, []*big.Int{big.NewInt(1000)}))
+
+	supply, err := api.Supply(context.Background(), rpc.BlockNumberOrHashWithNumber(0))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(supply.TotalSupply.ToInt()).To(Equal(big.NewInt(1000)))
+	g.Expect(supply.Locked.ToInt()).To(Equal(big.NewInt(400)))
+	g.Expect(supply.Voting.ToInt()).To(Equal(big.NewInt(250)))
+	g.Expect(supply.Unlocked.ToInt()).To(Equal(big.NewInt(600)))
+
+	_, err = api.Supply(context.Background(), rpc.BlockNumberOrHashWithNumber(1))
+	g.Expect(err).To(Equal(errStateNotFound))
+}
+
+func TestEpochSupply(t *testing.T) {
+	epochSize := params.IstanbulTestChainConfig.Istanbul.Epoch
+
+	t.Run("should split the mint without reward logs", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		api := NewAPI(newTestBackend(t, testSupplies(epochSize)))
+
+		epoch, err := api.EpochSupply(context.Background(), 1)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(epoch.Number).To(Equal(hexutil.Uint64(epochSize)))
+		g.Expect(epoch.Epoch).To(Equal(hexutil.Uint64(1)))
+		g.Expect(epoch.Mint.Total.ToInt()).To(Equal(big.NewInt(1000)))
+		g.Expect(epoch.Mint.Community.ToInt()).To(Equal(big.NewInt(300)))
+		g.Expect(epoch.Mint.Maintainer.ToInt()).To(Equal(big.NewInt(100)))
+		g.Expect(epoch.Mint.CommunityPartner).To(Equal(communityPartner))
+		g.Expect(epoch.Mint.MaintainerAddress).To(Equal(maintainer))
+		g.Expect(epoch.Mint.Validator.ToInt()).To(Equal(big.NewInt(0)))
+		g.Expect(epoch.Mint.Voter.ToInt()).To(Equal(big.NewInt(0)))
+	})
+
+	t.Run("should sum the validator and voter rewards logged by the block", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		backend := newTestBackend(t, testSupplies(epochSize))
+		hash := backend.headers[epochSize].Hash()
+		validator1, validator2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+		backend.receipts[hash] = types.Receipts{
+			// a transaction log of the same event is not a reward
+			{Logs: []*types.Log{rewardLog(t, validatorsAddress, abis.Validators, validatorPaymentEvent, validator1, 1000, common.Hash{0x01})}},
+			{Logs: []*types.Log{
+				rewardLog(t, validatorsAddress, abis.Validators, validatorPaymentEvent, validator1, 100, hash),
+				rewardLog(t, electionAddress, abis.Elections, voterRewardsEvent, validator1, 200, hash),
+				rewardLog(t, validatorsAddress, abis.Validators, validatorPaymentEvent, validator2, 100, hash),
+				rewardLog(t, electionAddress, abis.Elections, voterRewardsEvent, validator2, 200, hash),
+				// an event of another contract is skipped
+				rewardLog(t, common.HexToAddress("0x3000"), abis.Validators, validatorPaymentEvent, validator2, 1000, hash),
+			}},
+		}
+		api := NewAPI(backend)
+
+		epoch, err := api.EpochSupply(context.Background(), 1)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(epoch.Mint.Validator.ToInt()).To(Equal(big.NewInt(200)))
+		g.Expect(epoch.Mint.Voter.ToInt()).To(Equal(big.NewInt(400)))
+	})
+
+	t.Run("should fail for epoch 0 and unknown epochs", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		api := NewAPI(newTestBackend(t, testSupplies(epochSize)))
+
+		_, err := api.EpochSupply(context.Background(), 0)
+		g.Expect(err).To(HaveOccurred())
+		_, err = api.EpochSupply(context.Background(), 2)
+		g.Expect(err).To(Equal(errStateNotFound))
+	})
+}
@@ -38,6 +38,7 @@ import (
 
 	"github.com/mapprotocol/atlas/apis/atlasapi"
 	"github.com/mapprotocol/atlas/consensus"
+	"github.com/mapprotocol/atlas/contracts/random"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/abstract"
 	"github.com/mapprotocol/atlas/core/rawdb"
@@ -72,6 +73,8 @@ type Backend interface {
 	ChainDb() ethdb.Database
 	StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, checkLive bool) (*state.StateDB, error)
 	StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (chain.Message, vm.BlockContext, *state.StateDB, error)
+	NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner
+	TraceSystemCalls(statedb *state.StateDB, tracer vm.Tracer) (stop func())
 }
 
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
@@ -108,6 +111,25 @@ func (context *chainContext) GetHeader(hash common.Hash, number uint64) *types.H
 	return header
 }
 
+func (context *chainContext) Config() *params.ChainConfig {
+	return context.api.backend.ChainConfig()
+}
+
+func (context *chainContext) CurrentHeader() *types.Header {
+	header, _ := context.api.backend.HeaderByNumber(context.ctx, rpc.LatestBlockNumber)
+	return header
+}
+
+func (context *chainContext) GetHeaderByNumber(number uint64) *types.Header {
+	header, _ := context.api.backend.HeaderByNumber(context.ctx, rpc.BlockNumber(number))
+	return header
+}
+
+func (context *chainContext) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, _ := context.api.backend.HeaderByHash(context.ctx, hash)
+	return header
+}
+
 // chainContext construts the context reader which is used by the evm for reading
 // the necessary chain context.
 func (api *API) chainContext(ctx context.Context) abstract.ChainContext {
@@ -162,6 +184,9 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// SystemCalls adds the calls the consensus engine makes outside of the
+	// transactions to the block traces, traced with Tracer
+	SystemCalls bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -183,8 +208,9 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
-	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
-	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+	Result     interface{} `json:"result,omitempty"`     // Trace results produced by the tracer
+	Error      string      `json:"error,omitempty"`      // Trace failure produced by the tracer
+	SystemCall string      `json:"systemCall,omitempty"` // Phase of the block the system call was made in, empty for transactions
 }
 
 // blockTraceTask represents a single block trace task when an entire chain is
@@ -564,6 +590,25 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if err != nil {
 		return nil, err
 	}
+	blockHash := block.Hash()
+
+	// Trace the system calls made on the state replaying the block, the
+	// transactions are traced on copies of it
+	var systemTracer *systemCallTracer
+	if config != nil && config.SystemCalls {
+		if config.Tracer == nil {
+			return nil, errors.New("tracing system calls requires a tracer")
+		}
+		systemTracer = newSystemCallTracer(func() (resultTracer, error) {
+			return newTracer(*config.Tracer, &Context{BlockHash: blockHash})
+		})
+		defer api.backend.TraceSystemCalls(statedb, systemTracer)()
+
+		systemTracer.phase = systemCallRandomness
+		if err := api.applyRandomness(block, statedb); err != nil {
+			return nil, err
+		}
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
 		signer  = types.MakeSigner(api.backend.ChainConfig(), block.Number())
@@ -578,7 +623,6 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		threads = len(txs)
 	}
 	blockCtx := chain.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
-	blockHash := block.Hash()
 	for th := 0; th < threads; th++ {
 		pend.Add(1)
 		go func() {
@@ -625,9 +669,37 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if failed != nil {
 		return nil, failed
 	}
+	if systemTracer != nil {
+		systemTracer.phase = systemCallFinalize
+		// The traced block is already in the chain, nothing it derives may be kept
+		if engine, ok := api.backend.Engine().(consensus.Istanbul); ok {
+			engine.TraceFinalize(&chainContext{api: api, ctx: ctx}, types.CopyHeader(block.Header()), statedb, txs)
+		} else {
+			api.backend.Engine().Finalize(&chainContext{api: api, ctx: ctx}, types.CopyHeader(block.Header()), statedb, txs)
+		}
+		results = append(results, systemTracer.results...)
+	}
 	return results, nil
 }
 
+// applyRandomness reveals and commits the randomness of block like the state
+// processor does before applying its transactions.
+func (api *API) applyRandomness(block *types.Block, statedb *state.StateDB) error {
+	vmRunner := api.backend.NewEVMRunner(block.Header(), statedb)
+	if !random.IsRunning(vmRunner) {
+		return nil
+	}
+	author, err := api.backend.Engine().Author(block.Header())
+	if err != nil {
+		return err
+	}
+	if err := random.RevealAndCommit(vmRunner, block.Randomness().Revealed, block.Randomness().Committed, author); err != nil {
+		return err
+	}
+	statedb.IntermediateRoot(true)
+	return nil
+}
+
 // standardTraceBlockToFile configures a new tracer which uses standard JSON output,
 // and traces either a full block or an individual transaction. The return value will
 // be one filename per transaction traced.
@@ -861,8 +933,8 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = New(*config.Tracer, txctx); err != nil {
+		// Constuct the native or JavaScript tracer to execute with
+		if tracer, err = newTracer(*config.Tracer, txctx); err != nil {
 			return nil, err
 		}
 		// Handle timeouts and RPC cancellations
@@ -870,7 +942,7 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 		go func() {
 			<-deadlineCtx.Done()
 			if deadlineCtx.Err() == context.DeadlineExceeded {
-				tracer.(*Tracer).Stop(errors.New("execution timeout"))
+				tracer.(resultTracer).Stop(errors.New("execution timeout"))
 			}
 		}()
 		defer cancel()
@@ -907,7 +979,7 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 			StructLogs:  atlasapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
-	case *Tracer:
+	case resultTracer:
 		return tracer.GetResult()
 
 	default:
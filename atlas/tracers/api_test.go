@@ -175,6 +175,14 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 	return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
 
+func (b *testBackend) NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner {
+	return b.chain.NewEVMRunner(header, state)
+}
+
+func (b *testBackend) TraceSystemCalls(statedb *state.StateDB, tracer vm.Tracer) (stop func()) {
+	return b.chain.TraceSystemCalls(statedb, tracer)
+}
+
 type Account struct {
 	key  *ecdsa.PrivateKey
 	addr common.Address
@@ -0,0 +1,54 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mapprotocol/atlas/core/vm"
+)
+
+// resultTracer is a tracer that returns its result as JSON, either one of the
+// JavaScript tracers or a native one.
+type resultTracer interface {
+	vm.Tracer
+	GetResult() (json.RawMessage, error)
+	Stop(err error)
+}
+
+// nativeTracers contains the tracers implemented in Go by name, they take
+// precedence over the JavaScript tracers of the same name.
+var nativeTracers = map[string]func(ctx *Context) resultTracer{
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+}
+
+// newTracer returns the native tracer of the given name or else the
+// JavaScript tracer given by name or by code.
+func newTracer(code string, ctx *Context) (resultTracer, error) {
+	if ctor, ok := nativeTracers[code]; ok {
+		return ctor(ctx), nil
+	}
+	return New(code, ctx)
+}
+
+func bytesToHex(s []byte) string {
+	return "0x" + common.Bytes2Hex(s)
+}
+
+func bigToHex(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return hexutil.EncodeBig(n)
+}
+
+func uintToHex(n uint64) string {
+	return hexutil.EncodeUint64(n)
+}
+
+func addrToHex(a common.Address) string {
+	return a.Hex()
+}
@@ -0,0 +1,147 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mapprotocol/atlas/core/chain"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	nativeTestCaller = common.HexToAddress("0x00000000000000000000000000000000000000ca")
+	nativeTestCallee = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	nativeTestInner  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// newNativeTestEVM returns an EVM where nativeTestCallee loads its storage
+// slot 1 and then calls nativeTestInner. Like the calls of the consensus
+// engine the transaction context has no gas price.
+func newNativeTestEVM(tracer vm.Tracer) *vm.EVM {
+	alloc := chain.GenesisAlloc{
+		nativeTestCaller: {Balance: big.NewInt(100)},
+		nativeTestCallee: {
+			Code:    hexutil.MustDecode("0x6001545060006000600060006000600060bb5af100"),
+			Storage: map[common.Hash]common.Hash{common.BigToHash(big.NewInt(1)): common.BigToHash(big.NewInt(7))},
+		},
+		nativeTestInner: {Code: []byte{0x00}},
+	}
+	_, statedb := MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+	context := vm.BlockContext{
+		CanTransfer: chain.CanTransfer,
+		Transfer:    vmcontext.TobinTransfer,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(5),
+		Difficulty:  big.NewInt(1),
+		GasLimit:    10000000,
+	}
+	return vm.NewEVM(context, vm.TxContext{}, statedb, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+}
+
+func TestNativeCallTracer(t *testing.T) {
+	tracer, err := newTracer("callTracer", new(Context))
+	if err != nil {
+		t.Fatal(err)
+	}
+	evm := newNativeTestEVM(tracer)
+	if _, _, err := evm.Call(vm.AccountRef(nativeTestCaller), nativeTestCallee, nil, 100000, big.NewInt(10)); err != nil {
+		t.Fatalf("failed to execute call: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var frame callFrame
+	if err := json.Unmarshal(res, &frame); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if frame.Type != "CALL" || frame.To != nativeTestCallee.Hex() || frame.Value != "0xa" {
+		t.Errorf("top call = %+v", frame)
+	}
+	if len(frame.Calls) != 1 || frame.Calls[0].To != nativeTestInner.Hex() {
+		t.Fatalf("inner calls = %+v, want one call to %x", frame.Calls, nativeTestInner)
+	}
+}
+
+func TestNativePrestateTracer(t *testing.T) {
+	tracer, err := newTracer("prestateTracer", new(Context))
+	if err != nil {
+		t.Fatal(err)
+	}
+	evm := newNativeTestEVM(tracer)
+	if _, _, err := evm.Call(vm.AccountRef(nativeTestCaller), nativeTestCallee, nil, 100000, big.NewInt(10)); err != nil {
+		t.Fatalf("failed to execute call: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var pre prestate
+	if err := json.Unmarshal(res, &pre); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	// The balances are the ones before the value transfer
+	if acc := pre[nativeTestCaller]; acc == nil || acc.Balance != "0x64" {
+		t.Errorf("caller prestate = %+v, want balance 0x64", acc)
+	}
+	if acc := pre[nativeTestCallee]; acc == nil || acc.Balance != "0x0" {
+		t.Errorf("callee prestate = %+v, want balance 0x0", acc)
+	} else if slot := acc.Storage[common.BigToHash(big.NewInt(1))]; slot != common.BigToHash(big.NewInt(7)) {
+		t.Errorf("callee slot 1 = %x, want 7", slot)
+	}
+	if _, ok := pre[nativeTestInner]; !ok {
+		t.Errorf("inner contract missing from prestate")
+	}
+}
+
+func TestSystemCallTracer(t *testing.T) {
+	tracer := newSystemCallTracer(func() (resultTracer, error) {
+		return newTracer("callTracer", new(Context))
+	})
+	evm := newNativeTestEVM(tracer)
+
+	tracer.phase = systemCallRandomness
+	if _, _, err := evm.Call(vm.AccountRef(nativeTestCaller), nativeTestCallee, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("failed to execute call: %v", err)
+	}
+	// Static calls are left out of the traces
+	if _, _, err := evm.StaticCall(vm.AccountRef(nativeTestCaller), nativeTestInner, nil, 100000); err != nil {
+		t.Fatalf("failed to execute static call: %v", err)
+	}
+	tracer.phase = systemCallFinalize
+	if _, _, err := evm.Call(vm.AccountRef(nativeTestCaller), nativeTestInner, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("failed to execute call: %v", err)
+	}
+
+	if len(tracer.results) != 2 {
+		t.Fatalf("results = %d, want 2", len(tracer.results))
+	}
+	for i, want := range []struct {
+		phase string
+		to    common.Address
+		calls int
+	}{
+		{systemCallRandomness, nativeTestCallee, 1},
+		{systemCallFinalize, nativeTestInner, 0},
+	} {
+		res := tracer.results[i]
+		if res.SystemCall != want.phase || res.Error != "" {
+			t.Errorf("result %d: phase %q error %q, want phase %q", i, res.SystemCall, res.Error, want.phase)
+			continue
+		}
+		var frame callFrame
+		if err := json.Unmarshal(res.Result.(json.RawMessage), &frame); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+		if frame.To != want.to.Hex() || len(frame.Calls) != want.calls {
+			t.Errorf("result %d = %+v, want call to %x with %d inner calls", i, frame, want.to, want.calls)
+		}
+	}
+}
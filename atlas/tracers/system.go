@@ -0,0 +1,86 @@
+package tracers
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/core/vm"
+)
+
+const (
+	// systemCallRandomness is the phase of the randomness the block reveals
+	// and commits before its transactions are applied.
+	systemCallRandomness = "randomness"
+	// systemCallFinalize is the phase of the calls the consensus engine makes
+	// when finalizing the block, such as the epoch rewards distribution.
+	systemCallFinalize = "finalize"
+)
+
+// systemCallTracer traces every call the consensus engine makes through its
+// EVM runners with a new tracer and collects the results as synthetic
+// transaction traces. Static calls are left out as they can't change the
+// state.
+type systemCallTracer struct {
+	newTracer func() (resultTracer, error)
+	phase     string
+
+	current resultTracer // Tracer of the call in progress, nil in between calls
+	results []*txTraceResult
+}
+
+func newSystemCallTracer(newTracer func() (resultTracer, error)) *systemCallTracer {
+	return &systemCallTracer{newTracer: newTracer}
+}
+
+// CaptureStart implements the vm.Tracer interface, it starts the trace of a
+// system call.
+func (t *systemCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	tracer, err := t.newTracer()
+	if err != nil {
+		t.results = append(t.results, &txTraceResult{SystemCall: t.phase, Error: err.Error()})
+		return
+	}
+	t.current = tracer
+	t.current.CaptureStart(env, from, to, create, input, gas, value)
+}
+
+// CaptureEnd implements the vm.Tracer interface, it collects the trace of the
+// system call.
+func (t *systemCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	if t.current == nil {
+		return
+	}
+	t.current.CaptureEnd(output, gasUsed, d, err)
+	if res, err := t.current.GetResult(); err != nil {
+		t.results = append(t.results, &txTraceResult{SystemCall: t.phase, Error: err.Error()})
+	} else {
+		t.results = append(t.results, &txTraceResult{SystemCall: t.phase, Result: res})
+	}
+	t.current = nil
+}
+
+func (t *systemCallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.current != nil {
+		t.current.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *systemCallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.current != nil {
+		t.current.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *systemCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.current != nil {
+		t.current.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *systemCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.current != nil {
+		t.current.CaptureExit(output, gasUsed, err)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/mapprotocol/atlas/core/state/snapshot"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
 	"github.com/mapprotocol/atlas/params"
 )
 
@@ -176,12 +177,13 @@ func TestPrestateTracerCreate2(t *testing.T) {
 	}
 	context := vm.BlockContext{
 		CanTransfer: chain.CanTransfer,
-		Transfer:    chain.Transfer,
+		Transfer:    vmcontext.TobinTransfer,
 		Coinbase:    common.Address{},
 		BlockNumber: new(big.Int).SetUint64(8000000),
 		Time:        new(big.Int).SetUint64(5),
 		Difficulty:  big.NewInt(0x30000),
 		GasLimit:    uint64(6000000),
+		BaseFee:     big.NewInt(1),
 	}
 	alloc := chain.GenesisAlloc{}
 
@@ -269,7 +271,7 @@ func testCallTracer(tracer string, dirPath string, t *testing.T) {
 			}
 			context := vm.BlockContext{
 				CanTransfer: chain.CanTransfer,
-				Transfer:    chain.Transfer,
+				Transfer:    vmcontext.TobinTransfer,
 				Coinbase:    test.Context.Miner,
 				BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
 				Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
@@ -358,7 +360,7 @@ func BenchmarkTransactionTrace(b *testing.B) {
 	}
 	context := vm.BlockContext{
 		CanTransfer: chain.CanTransfer,
-		Transfer:    chain.Transfer,
+		Transfer:    vmcontext.TobinTransfer,
 		Coinbase:    common.Address{},
 		BlockNumber: new(big.Int).SetUint64(uint64(5)),
 		Time:        new(big.Int).SetUint64(uint64(5)),
@@ -456,7 +458,7 @@ func benchTracer(tracerName string, test *callTracerTest, b *testing.B) {
 	}
 	context := vm.BlockContext{
 		CanTransfer: chain.CanTransfer,
-		Transfer:    chain.Transfer,
+		Transfer:    vmcontext.TobinTransfer,
 		Coinbase:    test.Context.Miner,
 		BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
 		Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
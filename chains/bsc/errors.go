@@ -0,0 +1,23 @@
+package bsc
+
+import "errors"
+
+var (
+	errUnknownAncestor             = errors.New("unknown ancestor")
+	errOlderBlockTime              = errors.New("timestamp older than parent")
+	errFutureBlock                 = errors.New("block in the future")
+	errInvalidNumber               = errors.New("invalid block number")
+	errMissingVanity               = errors.New("extra-data 32 byte vanity prefix missing")
+	errMissingSignature            = errors.New("extra-data 65 byte signature suffix missing")
+	errInvalidCheckpointValidators = errors.New("invalid validator list on checkpoint block")
+	errInvalidTurnLength           = errors.New("invalid turn length on checkpoint block")
+	errExtraValidators             = errors.New("non-checkpoint block contains extra validator list")
+	errInvalidUncleHash            = errors.New("non empty uncle hash")
+	errInvalidDifficulty           = errors.New("invalid difficulty")
+	errWrongDifficulty             = errors.New("wrong difficulty")
+	errCoinBaseMisMatch            = errors.New("coinbase do not match with signature")
+	errUnauthorizedValidator       = errors.New("unauthorized validator")
+	errRecentlySigned              = errors.New("recently signed")
+	errUnknownValidators           = errors.New("validator set of the epoch is unknown")
+	errNotCheckpoint               = errors.New("header store must be reset with an epoch checkpoint")
+)
@@ -0,0 +1,245 @@
+package bsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+)
+
+// recordEnv names the BSC mainnet RPC endpoint TestRecordMainnetHeaders records
+// the fixtures from, the recorder is skipped when it is not set.
+const recordEnv = "BSC_MAINNET_RPC"
+
+// mainnetFixtures are ranges of BSC mainnet headers recorded into testdata, each
+// starts at a checkpoint the header store can be reset with and runs past the
+// point where the next set takes over, so the replay covers the checkpoint
+// hand-over. The ranges after Bohr run over the turns of the previous set.
+var mainnetFixtures = []struct {
+	file  string
+	first uint64
+	count uint64
+}{
+	{file: "bsc_mainnet_28000000.json", first: 28_000_000, count: 2*defaultEpochLength + 30},  // before Luban
+	{file: "bsc_mainnet_30000000.json", first: 30_000_000, count: 2*defaultEpochLength + 30},  // Luban extra-data with BLS keys and vote attestations
+	{file: "bsc_mainnet_33000000.json", first: 33_000_000, count: 2*defaultEpochLength + 30},  // Hertz, BaseFee in the header but not in the seal
+	{file: "bsc_mainnet_40000000.json", first: 40_000_000, count: 2*defaultEpochLength + 30},  // Cancun, the post-London fields are sealed
+	{file: "bsc_mainnet_43000000.json", first: 43_000_000, count: 2*defaultEpochLength + 200}, // Bohr, checkpoints carry the turn length
+	{file: "bsc_mainnet_48000000.json", first: 48_000_000, count: 2*defaultEpochLength + 200}, // Pascal, the requests hash is sealed
+	{file: "bsc_mainnet_50000000.json", first: 50_000_000, count: 2*lorentzEpochLength + 200}, // Lorentz epochs
+	{file: "bsc_mainnet_60000000.json", first: 60_000_000, count: 2*maxwellEpochLength + 200}, // Maxwell epochs
+}
+
+// rpcHeader is a header as returned by eth_getBlockByNumber.
+type rpcHeader struct {
+	ParentHash  common.Hash         `json:"parentHash"`
+	UncleHash   common.Hash         `json:"sha3Uncles"`
+	Coinbase    common.Address      `json:"miner"`
+	Root        common.Hash         `json:"stateRoot"`
+	TxHash      common.Hash         `json:"transactionsRoot"`
+	ReceiptHash common.Hash         `json:"receiptsRoot"`
+	Bloom       ethtypes.Bloom      `json:"logsBloom"`
+	Difficulty  *hexutil.Big        `json:"difficulty"`
+	Number      *hexutil.Big        `json:"number"`
+	GasLimit    hexutil.Uint64      `json:"gasLimit"`
+	GasUsed     hexutil.Uint64      `json:"gasUsed"`
+	Time        hexutil.Uint64      `json:"timestamp"`
+	Extra       hexutil.Bytes       `json:"extraData"`
+	MixDigest   common.Hash         `json:"mixHash"`
+	Nonce       ethtypes.BlockNonce `json:"nonce"`
+	BaseFee     *hexutil.Big        `json:"baseFeePerGas"`
+
+	WithdrawalsHash  *common.Hash    `json:"withdrawalsRoot"`
+	BlobGasUsed      *hexutil.Uint64 `json:"blobGasUsed"`
+	ExcessBlobGas    *hexutil.Uint64 `json:"excessBlobGas"`
+	ParentBeaconRoot *common.Hash    `json:"parentBeaconBlockRoot"`
+	RequestsHash     *common.Hash    `json:"requestsHash"`
+
+	Hash common.Hash `json:"hash"`
+}
+
+func (h *rpcHeader) header() *ethereum.Header {
+	header := &ethereum.Header{
+		ParentHash:  h.ParentHash,
+		UncleHash:   h.UncleHash,
+		Coinbase:    h.Coinbase,
+		Root:        h.Root,
+		TxHash:      h.TxHash,
+		ReceiptHash: h.ReceiptHash,
+		Bloom:       h.Bloom,
+		Difficulty:  h.Difficulty.ToInt(),
+		Number:      h.Number.ToInt(),
+		GasLimit:    uint64(h.GasLimit),
+		GasUsed:     uint64(h.GasUsed),
+		Time:        uint64(h.Time),
+		Extra:       h.Extra,
+		MixDigest:   h.MixDigest,
+		Nonce:       h.Nonce,
+	}
+	if h.BaseFee != nil {
+		header.BaseFee = h.BaseFee.ToInt()
+	}
+	header.WithdrawalsHash = h.WithdrawalsHash
+	if h.BlobGasUsed != nil {
+		blobGasUsed := uint64(*h.BlobGasUsed)
+		header.BlobGasUsed = &blobGasUsed
+	}
+	if h.ExcessBlobGas != nil {
+		excessBlobGas := uint64(*h.ExcessBlobGas)
+		header.ExcessBlobGas = &excessBlobGas
+	}
+	header.ParentBeaconRoot = h.ParentBeaconRoot
+	header.RequestsHash = h.RequestsHash
+	return header
+}
+
+// loadMainnetHeaders reads a recorded fixture and checks that every header
+// hashes to the hash the node reported, so a decoding mismatch can't pass as a
+// validation result.
+func loadMainnetHeaders(t *testing.T, file string) []*ethereum.Header {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	if os.IsNotExist(err) {
+		t.Skipf("fixture %s not recorded, run TestRecordMainnetHeaders with %s set", file, recordEnv)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded []*rpcHeader
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		t.Fatalf("decode %s: %v", file, err)
+	}
+	headers := make([]*ethereum.Header, 0, len(recorded))
+	for _, r := range recorded {
+		header := r.header()
+		if header.Hash() != r.Hash {
+			t.Fatalf("%s: header #%d hashes to %x, recorded %x", file, header.Number, header.Hash(), r.Hash)
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+func TestValidateMainnetHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		chainType chains.ChainType
+		at        int                           // index of the mutated header, the batch ends there
+		mutate    func(header *ethereum.Header) // every field is covered by the seal
+		wantErr   error
+	}{
+		{
+			name:      "recorded chain",
+			chainType: chains.ChainTypeBSC,
+		},
+		{
+			name:      "replayed on testnet",
+			chainType: chains.ChainTypeBSCTest,
+			mutate:    func(*ethereum.Header) {},
+			wantErr:   errCoinBaseMisMatch,
+		},
+		{
+			name:      "forged seal",
+			chainType: chains.ChainTypeBSC,
+			at:        5,
+			mutate: func(header *ethereum.Header) {
+				header.Extra[len(header.Extra)-extraSeal+10] ^= 0xff
+			},
+			wantErr: errCoinBaseMisMatch,
+		},
+		{
+			name:      "difficulty flipped",
+			chainType: chains.ChainTypeBSC,
+			at:        7,
+			mutate: func(header *ethereum.Header) {
+				if header.Difficulty.Cmp(diffInTurn) == 0 {
+					header.Difficulty = diffNoTurn
+				} else {
+					header.Difficulty = diffInTurn
+				}
+			},
+			wantErr: errCoinBaseMisMatch,
+		},
+	}
+
+	for _, fixture := range mainnetFixtures {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/%s", fixture.file, tt.name), func(t *testing.T) {
+				headers := loadMainnetHeaders(t, fixture.file)
+				db := getStateDB()
+				checkpoint, err := rlp.EncodeToBytes(headers[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := NewHeaderStore().ResetHeaderStore(db, checkpoint, headers[0].Difficulty); err != nil {
+					t.Fatal(err)
+				}
+
+				chain := headers[1:]
+				if tt.mutate != nil {
+					chain = chain[:tt.at+1]
+					tt.mutate(chain[tt.at])
+				}
+				enc, err := rlp.EncodeToBytes(chain)
+				if err != nil {
+					t.Fatal(err)
+				}
+				idx, err := new(Validate).ValidateHeaderChain(db, enc, tt.chainType)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ValidateHeaderChain() error = %v at %d, wantErr %v", err, idx, tt.wantErr)
+				}
+				if idx != tt.at {
+					t.Fatalf("ValidateHeaderChain() index = %d, want %d", idx, tt.at)
+				}
+			})
+		}
+	}
+}
+
+// TestRecordMainnetHeaders records the mainnet fixtures from the node given by
+// BSC_MAINNET_RPC into testdata.
+func TestRecordMainnetHeaders(t *testing.T) {
+	url := os.Getenv(recordEnv)
+	if url == "" {
+		t.Skipf("%s not set", recordEnv)
+	}
+	client, err := rpc.Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, fixture := range mainnetFixtures {
+		recorded := make([]json.RawMessage, 0, fixture.count)
+		for n := fixture.first; n < fixture.first+fixture.count; n++ {
+			var header json.RawMessage
+			if err := client.CallContext(context.Background(), &header, "eth_getBlockByNumber", hexutil.EncodeBig(new(big.Int).SetUint64(n)), false); err != nil {
+				t.Fatalf("header #%d: %v", n, err)
+			}
+			recorded = append(recorded, header)
+		}
+		data, err := json.MarshalIndent(recorded, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join("testdata", fixture.file), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package bsc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// HeaderStore keeps BSC headers in the ethereum header store layout under
+// chains.BSCHeaderStoreAddress. Parlia uses the same difficulty based fork
+// choice, so insertion and reorg handling are shared with ethereum, only the
+// reset point is restricted to epoch checkpoints, which carry the validator set.
+type HeaderStore struct {
+	*ethereum.HeaderStore
+}
+
+func NewHeaderStore() *HeaderStore {
+	return &HeaderStore{
+		HeaderStore: ethereum.NewHeaderStoreAt(chains.BSCHeaderStoreAddress),
+	}
+}
+
+func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, bscHeader []byte, td *big.Int) error {
+	var header ethereum.Header
+	if err := rlp.DecodeBytes(bscHeader, &header); err != nil {
+		log.Error("rlp decode bsc header failed.", "err", err)
+		return chains.ErrRLPDecode
+	}
+	// A multiple of maxwellEpochLength is a checkpoint whatever fork the header
+	// is in, and the epoch length of the following blocks is read from it.
+	if header.Number == nil || header.Number.Uint64()%maxwellEpochLength != 0 {
+		return errNotCheckpoint
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		return errMissingVanity
+	}
+	return hs.HeaderStore.ResetHeaderStore(state, bscHeader, td)
+}
@@ -0,0 +1,244 @@
+package bsc
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+)
+
+const (
+	extraVanity          = 32 // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal            = 65 // Fixed number of extra-data suffix bytes reserved for signer seal
+	validatorNumberSize  = 1  // Luban: number of validators in a checkpoint extra-data
+	validatorBytesLength = common.AddressLength
+	blsPublicKeyLength   = 48
+	lubanValidatorLength = validatorBytesLength + blsPublicKeyLength
+	turnLengthSize       = 1 // Bohr: turn length following the validators of a checkpoint
+
+	defaultEpochLength = 200  // Number of blocks after which the validator set is read from the checkpoint
+	lorentzEpochLength = 500  // Epoch length from Lorentz on
+	maxwellEpochLength = 1000 // Epoch length from Maxwell on, a multiple of the earlier lengths
+
+	defaultTurnLength = 1  // Number of consecutive blocks a validator seals before Bohr
+	maxTurnLength     = 64 // Upper bound of the turn length read from a checkpoint
+)
+
+var (
+	diffInTurn = big.NewInt(2) // Block difficulty for in-turn signatures
+	diffNoTurn = big.NewInt(1) // Block difficulty for out-of-turn signatures
+)
+
+// parliaConfig holds the per-network forks that change the header layout and
+// the consensus rules.
+type parliaConfig struct {
+	ChainID     *big.Int
+	LubanBlock  *big.Int // checkpoint extra-data carries BLS keys from this block on
+	BohrTime    *uint64  // checkpoint extra-data carries the turn length from this time on
+	LorentzTime *uint64  // epochs are lorentzEpochLength blocks long from this time on
+	MaxwellTime *uint64  // epochs are maxwellEpochLength blocks long from this time on
+}
+
+var chainType2ParliaConfig = map[chains.ChainType]*parliaConfig{
+	chains.ChainTypeBSC: {
+		ChainID:     big.NewInt(56),
+		LubanBlock:  big.NewInt(29_020_050),
+		BohrTime:    newUint64(1727317200),
+		LorentzTime: newUint64(1745903100),
+		MaxwellTime: newUint64(1751250600),
+	},
+	chains.ChainTypeBSCTest: {
+		ChainID:     big.NewInt(97),
+		LubanBlock:  big.NewInt(29_295_050),
+		BohrTime:    newUint64(1724116996),
+		LorentzTime: newUint64(1744097580),
+		MaxwellTime: newUint64(1748243100),
+	},
+}
+
+func newUint64(v uint64) *uint64 {
+	return &v
+}
+
+func getParliaConfig(chain chains.ChainType) (*parliaConfig, error) {
+	cfg, ok := chainType2ParliaConfig[chain]
+	if !ok {
+		return nil, chains.ErrNotSupportChain
+	}
+	return cfg, nil
+}
+
+func (c *parliaConfig) isLuban(num *big.Int) bool {
+	return c.LubanBlock != nil && num.Cmp(c.LubanBlock) >= 0
+}
+
+func (c *parliaConfig) isBohr(time uint64) bool {
+	return isTimestampForked(c.BohrTime, time)
+}
+
+// epochLength returns the epoch length of the fork the given header time is in.
+func (c *parliaConfig) epochLength(time uint64) uint64 {
+	switch {
+	case isTimestampForked(c.MaxwellTime, time):
+		return maxwellEpochLength
+	case isTimestampForked(c.LorentzTime, time):
+		return lorentzEpochLength
+	}
+	return defaultEpochLength
+}
+
+func isTimestampForked(fork *uint64, time uint64) bool {
+	return fork != nil && *fork <= time
+}
+
+// sealHash returns the hash of a block prior to it being sealed, the chain id
+// is part of the preimage to prevent replaying signatures across networks.
+func sealHash(header *ethereum.Header, chainID *big.Int) (hash common.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	enc := []interface{}{
+		chainID,
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra[:len(header.Extra)-extraSeal],
+		header.MixDigest,
+		header.Nonce,
+	}
+	// Since Cancun BSC seals the post-London fields too, earlier headers that
+	// carry a BaseFee are sealed without it, as in Parlia's encodeSigHeader.
+	// The requests hash follows them since Pascal.
+	if header.ParentBeaconRoot != nil {
+		enc = append(enc, header.BaseFee, header.WithdrawalsHash, header.BlobGasUsed, header.ExcessBlobGas, header.ParentBeaconRoot)
+		if header.RequestsHash != nil {
+			enc = append(enc, header.RequestsHash)
+		}
+	}
+	_ = rlp.Encode(hasher, enc)
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+// ecrecover extracts the address of the validator that sealed the header.
+func ecrecover(header *ethereum.Header, chainID *big.Int) (common.Address, error) {
+	if len(header.Extra) < extraSeal {
+		return common.Address{}, errMissingSignature
+	}
+	signature := header.Extra[len(header.Extra)-extraSeal:]
+
+	pubkey, err := crypto.Ecrecover(sealHash(header, chainID).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return signer, nil
+}
+
+// parseValidators reads the validator set carried by an epoch checkpoint.
+func parseValidators(header *ethereum.Header, cfg *parliaConfig) ([]common.Address, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return nil, errMissingVanity
+	}
+	data := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+
+	var validators []common.Address
+	if !cfg.isLuban(header.Number) {
+		if len(data) == 0 || len(data)%validatorBytesLength != 0 {
+			return nil, errInvalidCheckpointValidators
+		}
+		for i := 0; i < len(data); i += validatorBytesLength {
+			validators = append(validators, common.BytesToAddress(data[i:i+validatorBytesLength]))
+		}
+	} else {
+		if len(data) < validatorNumberSize {
+			return nil, errInvalidCheckpointValidators
+		}
+		num := int(data[0])
+		if num == 0 || len(data) < validatorNumberSize+num*lubanValidatorLength {
+			return nil, errInvalidCheckpointValidators
+		}
+		for i := 0; i < num; i++ {
+			start := validatorNumberSize + i*lubanValidatorLength
+			validators = append(validators, common.BytesToAddress(data[start:start+validatorBytesLength]))
+		}
+	}
+	sort.Sort(validatorsAscending(validators))
+	return validators, nil
+}
+
+// parseTurnLength reads the number of consecutive blocks each validator of the
+// checkpoint seals, it follows the validators since Bohr.
+func parseTurnLength(header *ethereum.Header, cfg *parliaConfig) (uint64, error) {
+	if !cfg.isLuban(header.Number) || !cfg.isBohr(header.Time) {
+		return defaultTurnLength, nil
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		return 0, errMissingVanity
+	}
+	data := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+	if len(data) < validatorNumberSize {
+		return 0, errInvalidCheckpointValidators
+	}
+	pos := validatorNumberSize + int(data[0])*lubanValidatorLength
+	if len(data) < pos+turnLengthSize {
+		return 0, errInvalidTurnLength
+	}
+	turnLength := uint64(data[pos])
+	if turnLength == 0 || turnLength > maxTurnLength {
+		return 0, errInvalidTurnLength
+	}
+	return turnLength, nil
+}
+
+// validatorSet is the validator set read from a checkpoint.
+type validatorSet struct {
+	validators []common.Address
+	turnLength uint64
+}
+
+func parseCheckpoint(header *ethereum.Header, cfg *parliaConfig) (*validatorSet, error) {
+	validators, err := parseValidators(header, cfg)
+	if err != nil {
+		return nil, err
+	}
+	turnLength, err := parseTurnLength(header, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &validatorSet{validators: validators, turnLength: turnLength}, nil
+}
+
+// checkLen returns the number of recent blocks in which a validator may seal at
+// most turnLength-1 blocks, it is also the number of blocks of the new epoch the
+// set stays in charge of before the next set takes over.
+func (s *validatorSet) checkLen() uint64 {
+	return uint64(len(s.validators)/2+1)*s.turnLength - 1
+}
+
+// inturn reports whether the validator is in turn to seal the block.
+func (s *validatorSet) inturn(number uint64, validator common.Address) bool {
+	return s.validators[number/s.turnLength%uint64(len(s.validators))] == validator
+}
+
+// validatorsAscending implements the sort interface to allow sorting a list of addresses
+type validatorsAscending []common.Address
+
+func (s validatorsAscending) Len() int           { return len(s) }
+func (s validatorsAscending) Less(i, j int) bool { return bytes.Compare(s[i][:], s[j][:]) < 0 }
+func (s validatorsAscending) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
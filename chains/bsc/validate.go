@@ -0,0 +1,264 @@
+package bsc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+const (
+	allowedFutureBlockTimeSeconds = int64(15)
+)
+
+type Validate struct{}
+
+func (v *Validate) ValidateHeaderChain(db types.StateDB, headers []byte, chainType chains.ChainType) (int, error) {
+	cfg, err := getParliaConfig(chainType)
+	if err != nil {
+		return 0, err
+	}
+
+	var chain []*ethereum.Header
+	if err := rlp.DecodeBytes(headers, &chain); err != nil {
+		log.Error("rlp decode bsc headers failed.", "err", err)
+		return 0, chains.ErrRLPDecode
+	}
+
+	chainLength := len(chain)
+	if chainLength == 0 {
+		return 0, errors.New("headers cannot be empty")
+	}
+	for i := 0; i < chainLength; i++ {
+		if chain[i].Number == nil || chain[i].Difficulty == nil {
+			return 0, errors.New("invalid header number or difficulty is nil")
+		}
+		if i == 0 {
+			continue
+		}
+		// Do a sanity check that the provided chain is actually ordered and linked
+		if chain[i].Number.Uint64() != chain[i-1].Number.Uint64()+1 || chain[i].ParentHash != chain[i-1].Hash() {
+			return 0, fmt.Errorf("non contiguous insert: item %d is #%d, item %d is #%d (parent [%x..])",
+				i-1, chain[i-1].Number, i, chain[i].Number, chain[i].ParentHash[:4])
+		}
+	}
+
+	hs := NewHeaderStore()
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	currentNumber := hs.CurrentNumber()
+	firstNumber := chain[0].Number.Uint64()
+	if firstNumber > currentNumber+1 {
+		return 0, fmt.Errorf("non contiguous insert, current number: %d, first number: %d", currentNumber, firstNumber)
+	}
+	if currentNumber >= ethereum.MaxHeaderLimit && firstNumber <= currentNumber-ethereum.MaxHeaderLimit+1 {
+		return 0, fmt.Errorf("obsolete block, current number: %d, first number: %d", currentNumber, firstNumber)
+	}
+
+	reader := &chainReader{hs: hs, db: db, batch: chain}
+	unixNow := time.Now().Unix()
+	for i, header := range chain {
+		parent := reader.getHeader(header.ParentHash, header.Number.Uint64()-1)
+		if parent == nil {
+			return i, errUnknownAncestor
+		}
+		epoch, err := reader.epochLength(header.Number.Uint64(), cfg)
+		if err != nil {
+			return i, err
+		}
+		if err := v.verifyHeader(header, parent, epoch, unixNow, cfg); err != nil {
+			return i, err
+		}
+		if err := v.verifySeal(reader, header, epoch, cfg); err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+func (v *Validate) verifyHeader(header, parent *ethereum.Header, epoch uint64, unixNow int64, cfg *parliaConfig) error {
+	number := header.Number.Uint64()
+	if header.Time > uint64(unixNow+allowedFutureBlockTimeSeconds) {
+		return errFutureBlock
+	}
+	if header.Time < parent.Time {
+		return errOlderBlockTime
+	}
+	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(big.NewInt(1)) != 0 {
+		return errInvalidNumber
+	}
+	if len(header.Extra) < extraVanity {
+		return errMissingVanity
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		return errMissingSignature
+	}
+	if number%epoch == 0 {
+		if _, err := parseCheckpoint(header, cfg); err != nil {
+			return err
+		}
+	} else if !cfg.isLuban(header.Number) && len(header.Extra) != extraVanity+extraSeal {
+		// Luban headers carry the vote attestation here, older ones nothing
+		return errExtraValidators
+	}
+	if header.UncleHash != ethtypes.EmptyUncleHash {
+		return errInvalidUncleHash
+	}
+	if header.Difficulty.Cmp(diffInTurn) != 0 && header.Difficulty.Cmp(diffNoTurn) != 0 {
+		return errInvalidDifficulty
+	}
+
+	// Verify that the gas limit is <= 2^63-1
+	maxGas := uint64(0x7fffffffffffffff)
+	if header.GasLimit > maxGas {
+		return fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, maxGas)
+	}
+	// Verify that the gasUsed is <= gasLimit
+	if header.GasUsed > header.GasLimit {
+		return fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, header.GasLimit)
+	}
+	return nil
+}
+
+// verifySeal checks that the header is signed by its coinbase, that the coinbase
+// belongs to the validator set in charge of the block and that it did not seal
+// a full turn within the recent blocks.
+func (v *Validate) verifySeal(reader *chainReader, header *ethereum.Header, epoch uint64, cfg *parliaConfig) error {
+	signer, err := ecrecover(header, cfg.ChainID)
+	if err != nil {
+		return err
+	}
+	if signer != header.Coinbase {
+		return errCoinBaseMisMatch
+	}
+
+	set, err := reader.validatorsAt(header.Number.Uint64(), epoch, cfg)
+	if err != nil {
+		return err
+	}
+	offset := -1
+	for i, val := range set.validators {
+		if val == signer {
+			offset = i
+			break
+		}
+	}
+	if offset < 0 {
+		return errUnauthorizedValidator
+	}
+
+	// Validators may only seal turnLength blocks in checkLen blocks, which is
+	// once in len(validators)/2+1 blocks before Bohr
+	var sealed uint64
+	ancestor := header
+	for seen := uint64(0); seen < set.checkLen(); seen++ {
+		if ancestor = reader.getHeader(ancestor.ParentHash, ancestor.Number.Uint64()-1); ancestor == nil {
+			break
+		}
+		if ancestor.Coinbase == signer {
+			sealed++
+		}
+	}
+	if sealed >= set.turnLength {
+		return errRecentlySigned
+	}
+
+	inturn := set.inturn(header.Number.Uint64(), signer)
+	if inturn && header.Difficulty.Cmp(diffInTurn) != 0 {
+		return errWrongDifficulty
+	}
+	if !inturn && header.Difficulty.Cmp(diffNoTurn) != 0 {
+		return errWrongDifficulty
+	}
+	return nil
+}
+
+// chainReader looks up headers in the batch being validated first and falls
+// back to the header store.
+type chainReader struct {
+	hs    *HeaderStore
+	db    types.StateDB
+	batch []*ethereum.Header
+}
+
+func (r *chainReader) getHeader(hash common.Hash, number uint64) *ethereum.Header {
+	if idx, ok := r.batchIndex(number); ok && r.batch[idx].Hash() == hash {
+		return r.batch[idx]
+	}
+	return r.hs.GetHeader(hash, number, r.db)
+}
+
+func (r *chainReader) getCheckpoint(number uint64) *ethereum.Header {
+	if idx, ok := r.batchIndex(number); ok {
+		return r.batch[idx]
+	}
+	return r.hs.GetHeaderByNumber(number, r.db)
+}
+
+func (r *chainReader) batchIndex(number uint64) (int, bool) {
+	first := r.batch[0].Number.Uint64()
+	if number < first || number-first >= uint64(len(r.batch)) {
+		return 0, false
+	}
+	return int(number - first), true
+}
+
+// epochLength returns the epoch length in force for the given block. The length
+// only changes at multiples of maxwellEpochLength, which are checkpoints under
+// every length, and follows the fork the last of them before the block is in.
+func (r *chainReader) epochLength(number uint64, cfg *parliaConfig) (uint64, error) {
+	parent := number - 1
+	header := r.getCheckpoint(parent - parent%maxwellEpochLength)
+	if header == nil {
+		return 0, errUnknownValidators
+	}
+	return cfg.epochLength(header.Time), nil
+}
+
+// validatorsAt returns the validator set that signs the given block. The set read
+// from a checkpoint only takes over once the previous set has sealed checkLen
+// blocks of the new epoch, before that the previous set stays in charge. Right
+// after a reset the previous set is unknown and the checkpoint set is used.
+func (r *chainReader) validatorsAt(number, epoch uint64, cfg *parliaConfig) (*validatorSet, error) {
+	parent := number - 1
+	checkpoint := parent - parent%epoch
+
+	header := r.getCheckpoint(checkpoint)
+	if header == nil {
+		return nil, errUnknownValidators
+	}
+	current, err := parseCheckpoint(header, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == 0 {
+		return current, nil
+	}
+
+	previousEpoch, err := r.epochLength(checkpoint, cfg)
+	if err != nil || checkpoint < previousEpoch {
+		return current, nil
+	}
+	header = r.getCheckpoint(checkpoint - previousEpoch)
+	if header == nil {
+		return current, nil
+	}
+	previous, err := parseCheckpoint(header, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if parent-checkpoint >= previous.checkLen() {
+		return current, nil
+	}
+	return previous, nil
+}
@@ -0,0 +1,592 @@
+package bsc
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+)
+
+var testChainType = chains.ChainTypeBSCTest
+
+func getStateDB() *state.StateDB {
+	finalDb := rawdb.NewMemoryDatabase()
+	finalState, _ := state.New(common.Hash{}, state.NewDatabase(finalDb), nil)
+	return finalState
+}
+
+func testKey(seed string) *ecdsa.PrivateKey {
+	key, _ := crypto.ToECDSA(crypto.Keccak256([]byte(seed)))
+	return key
+}
+
+// testValidators returns the keys of a validator set ordered like parlia orders
+// the addresses.
+func testValidators(seeds ...string) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var addrs []common.Address
+	for _, s := range seeds {
+		key := testKey(s)
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(validatorsAscending(addrs))
+	sorted := make([]*ecdsa.PrivateKey, 0, len(addrs))
+	for _, a := range addrs {
+		sorted = append(sorted, keys[a])
+	}
+	return sorted, addrs
+}
+
+type testBlock struct {
+	signer     *ecdsa.PrivateKey
+	coinbase   *common.Address // defaults to the signer address
+	difficulty *big.Int        // defaults to the parlia in-turn rule
+	validators []common.Address
+	turnLength uint8 // written with the validators since Bohr, defaults to 1
+}
+
+// makeHeaders seals a chain on top of parent, the validators field of a block
+// is written into its extra-data and must be set exactly on checkpoints. The
+// set passed in is in charge of the first block, with the turn length of the
+// parent when it is a checkpoint.
+func makeHeaders(t *testing.T, parent *ethereum.Header, set []common.Address, blocks []testBlock) []*ethereum.Header {
+	cfg, _ := getParliaConfig(testChainType)
+	headers := make([]*ethereum.Header, 0, len(blocks))
+	current := &validatorSet{validators: set, turnLength: defaultTurnLength}
+	if checkpoint, err := parseCheckpoint(parent, cfg); err == nil {
+		current.turnLength = checkpoint.turnLength
+	}
+	previous := current
+	var takeOver uint64
+	for _, b := range blocks {
+		number := new(big.Int).Add(parent.Number, big.NewInt(1))
+		signerAddr := crypto.PubkeyToAddress(b.signer.PublicKey)
+		header := &ethereum.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  ethtypes.EmptyUncleHash,
+			Coinbase:   signerAddr,
+			Number:     number,
+			GasLimit:   30_000_000,
+			Time:       parent.Time + 3,
+			Extra:      make([]byte, extraVanity),
+		}
+		if b.coinbase != nil {
+			header.Coinbase = *b.coinbase
+		}
+		if b.validators != nil && cfg.isLuban(number) {
+			header.Extra = append(header.Extra, byte(len(b.validators)))
+			for _, v := range b.validators {
+				header.Extra = append(header.Extra, v.Bytes()...)
+				header.Extra = append(header.Extra, make([]byte, blsPublicKeyLength)...)
+			}
+			if cfg.isBohr(header.Time) {
+				turnLength := b.turnLength
+				if turnLength == 0 {
+					turnLength = defaultTurnLength
+				}
+				header.Extra = append(header.Extra, turnLength)
+			}
+		} else {
+			for _, v := range b.validators {
+				header.Extra = append(header.Extra, v.Bytes()...)
+			}
+		}
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+
+		active := current
+		if number.Uint64() < takeOver {
+			active = previous
+		}
+		header.Difficulty = diffNoTurn
+		if active.inturn(number.Uint64(), signerAddr) {
+			header.Difficulty = diffInTurn
+		}
+		if b.validators != nil {
+			next := &validatorSet{validators: b.validators, turnLength: defaultTurnLength}
+			if b.turnLength != 0 && cfg.isLuban(number) && cfg.isBohr(header.Time) {
+				next.turnLength = uint64(b.turnLength)
+			}
+			takeOver = number.Uint64() + current.checkLen() + 1
+			previous, current = current, next
+		}
+		if b.difficulty != nil {
+			header.Difficulty = b.difficulty
+		}
+		sig, err := crypto.Sign(sealHash(header, cfg.ChainID).Bytes(), b.signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+// inTurn returns the blocks of an in-turn sealed chain following number.
+func inTurn(keys []*ecdsa.PrivateKey, number uint64, n int) []testBlock {
+	return inTurnOf(keys, number, n, defaultTurnLength)
+}
+
+// inTurnOf returns the blocks of a chain following number in which every
+// validator seals turnLength blocks in a row.
+func inTurnOf(keys []*ecdsa.PrivateKey, number uint64, n int, turnLength uint64) []testBlock {
+	blocks := make([]testBlock, 0, n)
+	for i := 1; i <= n; i++ {
+		blocks = append(blocks, testBlock{signer: keys[(number+uint64(i))/turnLength%uint64(len(keys))]})
+	}
+	return blocks
+}
+
+// useParliaConfig replaces the config of testChainType for the test.
+func useParliaConfig(t *testing.T, cfg *parliaConfig) {
+	saved := chainType2ParliaConfig[testChainType]
+	chainType2ParliaConfig[testChainType] = cfg
+	t.Cleanup(func() { chainType2ParliaConfig[testChainType] = saved })
+}
+
+func resetStore(t *testing.T, validators []common.Address, keys []*ecdsa.PrivateKey) (*state.StateDB, *ethereum.Header) {
+	db := getStateDB()
+	genesisParent := &ethereum.Header{Number: big.NewInt(maxwellEpochLength - 1), Time: 1_600_000_000}
+	checkpoint := makeHeaders(t, genesisParent, validators, []testBlock{{signer: keys[0], validators: validators}})[0]
+	enc, err := rlp.EncodeToBytes(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewHeaderStore().ResetHeaderStore(db, enc, checkpoint.Difficulty); err != nil {
+		t.Fatal(err)
+	}
+	return db, checkpoint
+}
+
+func TestValidateHeaderChain(t *testing.T) {
+	keys, validators := testValidators("validator-1", "validator-2", "validator-3")
+	outsider := testKey("outsider")
+	_, nextValidators := testValidators("validator-1", "validator-2", "validator-4")
+	newcomer := testKey("validator-4")
+	wrongCoinbase := crypto.PubkeyToAddress(keys[1].PublicKey)
+
+	tests := []struct {
+		name    string
+		blocks  func(checkpoint *ethereum.Header) []testBlock
+		wantIdx int
+		wantErr error
+	}{
+		{
+			name: "in turn chain",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				return inTurn(keys, checkpoint.Number.Uint64(), 10)
+			},
+		},
+		{
+			name: "out of turn signer",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[1], blocks[2] = blocks[2], blocks[1]
+				return blocks
+			},
+		},
+		{
+			name: "unauthorized validator",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[2].signer = outsider
+				return blocks
+			},
+			wantIdx: 2,
+			wantErr: errUnauthorizedValidator,
+		},
+		{
+			name: "coinbase mismatch",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[0].coinbase = &wrongCoinbase
+				return blocks
+			},
+			wantIdx: 0,
+			wantErr: errCoinBaseMisMatch,
+		},
+		{
+			name: "wrong difficulty",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[1].difficulty = diffNoTurn
+				return blocks
+			},
+			wantIdx: 1,
+			wantErr: errWrongDifficulty,
+		},
+		{
+			name: "invalid difficulty",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[1].difficulty = big.NewInt(3)
+				return blocks
+			},
+			wantIdx: 1,
+			wantErr: errInvalidDifficulty,
+		},
+		{
+			name: "recently signed",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[2].signer = blocks[1].signer
+				return blocks
+			},
+			wantIdx: 2,
+			wantErr: errRecentlySigned,
+		},
+		{
+			name: "validators in non checkpoint",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				blocks := inTurn(keys, checkpoint.Number.Uint64(), 3)
+				blocks[1].validators = validators
+				return blocks
+			},
+			wantIdx: 1,
+			wantErr: errExtraValidators,
+		},
+		{
+			name: "new validator set after half of the old set sealed",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				number := checkpoint.Number.Uint64()
+				blocks := inTurn(keys, number, defaultEpochLength+1)
+				blocks[defaultEpochLength-1].validators = nextValidators
+				blocks = append(blocks, testBlock{signer: newcomer})
+				return blocks
+			},
+		},
+		{
+			name: "new validator seals too early",
+			blocks: func(checkpoint *ethereum.Header) []testBlock {
+				number := checkpoint.Number.Uint64()
+				blocks := inTurn(keys, number, defaultEpochLength)
+				blocks[defaultEpochLength-1].validators = nextValidators
+				blocks = append(blocks, testBlock{signer: newcomer})
+				return blocks
+			},
+			wantIdx: defaultEpochLength,
+			wantErr: errUnauthorizedValidator,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, checkpoint := resetStore(t, validators, keys)
+			headers := makeHeaders(t, checkpoint, validators, tt.blocks(checkpoint))
+			enc, err := rlp.EncodeToBytes(headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			idx, err := new(Validate).ValidateHeaderChain(db, enc, testChainType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateHeaderChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if idx != tt.wantIdx {
+				t.Fatalf("ValidateHeaderChain() index = %d, want %d", idx, tt.wantIdx)
+			}
+			if err != nil {
+				return
+			}
+
+			hs := NewHeaderStore()
+			if _, err := hs.InsertHeaders(db, enc); err != nil {
+				t.Fatal(err)
+			}
+			number, hash, err := hs.GetCurrentNumberAndHash(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := headers[len(headers)-1]
+			if number != last.Number.Uint64() || hash != last.Hash() {
+				t.Fatalf("current header mismatch, have #%d %x, want #%d %x", number, hash, last.Number, last.Hash())
+			}
+		})
+	}
+}
+
+func TestResetHeaderStore(t *testing.T) {
+	keys, validators := testValidators("validator-1", "validator-2", "validator-3")
+	for _, number := range []int64{2 * defaultEpochLength, defaultEpochLength - 1, lorentzEpochLength - 1} {
+		parent := &ethereum.Header{Number: big.NewInt(number), Time: 1_600_000_000}
+		blocks := inTurn(keys, parent.Number.Uint64(), 1)
+		blocks[0].validators = validators
+		header := makeHeaders(t, parent, validators, blocks)[0]
+		enc, _ := rlp.EncodeToBytes(header)
+
+		// Only multiples of every epoch length are checkpoints whatever the fork.
+		if err := NewHeaderStore().ResetHeaderStore(getStateDB(), enc, big.NewInt(1)); err != errNotCheckpoint {
+			t.Fatalf("#%d: expected %v, have %v", header.Number, errNotCheckpoint, err)
+		}
+	}
+
+	db, checkpoint := resetStore(t, validators, keys)
+	if _, _, err := ethereum.NewHeaderStore().GetCurrentNumberAndHash(db); err == nil {
+		t.Fatal("bsc reset must not touch the ethereum header store")
+	}
+	number, hash, err := NewHeaderStore().GetCurrentNumberAndHash(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != checkpoint.Number.Uint64() || hash != checkpoint.Hash() {
+		t.Fatalf("current header mismatch, have #%d %x, want #%d %x", number, hash, checkpoint.Number, checkpoint.Hash())
+	}
+}
+
+func TestParseValidators(t *testing.T) {
+	cfg, _ := getParliaConfig(testChainType)
+	_, validators := testValidators("validator-1", "validator-2", "validator-3")
+
+	legacy := &ethereum.Header{Number: new(big.Int).Sub(cfg.LubanBlock, big.NewInt(50))}
+	legacy.Extra = make([]byte, extraVanity)
+	for i := len(validators) - 1; i >= 0; i-- {
+		legacy.Extra = append(legacy.Extra, validators[i].Bytes()...)
+	}
+	legacy.Extra = append(legacy.Extra, make([]byte, extraSeal)...)
+
+	luban := &ethereum.Header{Number: new(big.Int).Add(cfg.LubanBlock, big.NewInt(150))}
+	luban.Extra = append(make([]byte, extraVanity), byte(len(validators)))
+	for _, v := range validators {
+		luban.Extra = append(luban.Extra, v.Bytes()...)
+		luban.Extra = append(luban.Extra, make([]byte, blsPublicKeyLength)...)
+	}
+	luban.Extra = append(luban.Extra, []byte{0xc0}...) // empty vote attestation
+	luban.Extra = append(luban.Extra, make([]byte, extraSeal)...)
+
+	for name, header := range map[string]*ethereum.Header{"legacy": legacy, "luban": luban} {
+		got, err := parseValidators(header, cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(got) != len(validators) {
+			t.Fatalf("%s: have %d validators, want %d", name, len(got), len(validators))
+		}
+		for i := range got {
+			if got[i] != validators[i] {
+				t.Fatalf("%s: validator %d mismatch, have %x, want %x", name, i, got[i], validators[i])
+			}
+		}
+	}
+}
+
+func TestSealHashPostLondonFields(t *testing.T) {
+	chainID := big.NewInt(56)
+	header := &ethereum.Header{
+		Difficulty: diffInTurn,
+		Number:     big.NewInt(40_000_000),
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	legacy := sealHash(header, chainID)
+
+	// Hertz headers carry a BaseFee but are sealed without it.
+	header.BaseFee = big.NewInt(0)
+	if hertz := sealHash(header, chainID); hertz != legacy {
+		t.Fatalf("hertz seal hash %x, want %x", hertz, legacy)
+	}
+
+	// Cancun headers seal every post-London field.
+	var blobGasUsed, excessBlobGas uint64
+	header.WithdrawalsHash = &common.Hash{}
+	header.BlobGasUsed = &blobGasUsed
+	header.ExcessBlobGas = &excessBlobGas
+	header.ParentBeaconRoot = &common.Hash{}
+	cancun := sealHash(header, chainID)
+	if cancun == legacy {
+		t.Fatal("cancun seal hash must cover the post-London fields")
+	}
+	header.BaseFee = big.NewInt(1)
+	if sealHash(header, chainID) == cancun {
+		t.Fatal("cancun seal hash must cover the base fee")
+	}
+
+	// Pascal headers seal the requests hash after them.
+	cancun = sealHash(header, chainID)
+	header.RequestsHash = &ethtypes.EmptyRootHash
+	if sealHash(header, chainID) == cancun {
+		t.Fatal("pascal seal hash must cover the requests hash")
+	}
+}
+
+func TestParseTurnLength(t *testing.T) {
+	cfg := &parliaConfig{ChainID: big.NewInt(97), LubanBlock: big.NewInt(0), BohrTime: newUint64(1_700_000_000)}
+	_, validators := testValidators("validator-1", "validator-2", "validator-3")
+	checkpoint := func(time uint64, turnLength ...byte) *ethereum.Header {
+		header := &ethereum.Header{Number: big.NewInt(1000), Time: time}
+		header.Extra = append(make([]byte, extraVanity), byte(len(validators)))
+		for _, v := range validators {
+			header.Extra = append(header.Extra, v.Bytes()...)
+			header.Extra = append(header.Extra, make([]byte, blsPublicKeyLength)...)
+		}
+		header.Extra = append(header.Extra, turnLength...)
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+		return header
+	}
+
+	tests := []struct {
+		name    string
+		header  *ethereum.Header
+		want    uint64
+		wantErr error
+	}{
+		{name: "before bohr", header: checkpoint(1_600_000_000), want: defaultTurnLength},
+		{name: "bohr", header: checkpoint(1_700_000_000, 4, 0xc0), want: 4},
+		{name: "bohr without turn length", header: checkpoint(1_700_000_000), wantErr: errInvalidTurnLength},
+		{name: "zero turn length", header: checkpoint(1_700_000_000, 0), wantErr: errInvalidTurnLength},
+		{name: "turn length too long", header: checkpoint(1_700_000_000, maxTurnLength+1), wantErr: errInvalidTurnLength},
+	}
+	for _, tt := range tests {
+		got, err := parseTurnLength(tt.header, cfg)
+		if err != tt.wantErr {
+			t.Fatalf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: turn length = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHeaderChainForks(t *testing.T) {
+	keys, validators := testValidators("validator-1", "validator-2", "validator-3")
+	nextKeys, nextValidators := testValidators("validator-4", "validator-5", "validator-6")
+	const (
+		reset      = maxwellEpochLength
+		resetTime  = 1_600_000_000
+		turnLength = 4
+	)
+	timeAt := func(number uint64) uint64 { return resetTime + 3*(number-reset) }
+	useParliaConfig(t, &parliaConfig{
+		ChainID:     big.NewInt(97),
+		LubanBlock:  big.NewInt(0),
+		BohrTime:    newUint64(0),
+		LorentzTime: newUint64(timeAt(1500)), // epochs of 500 blocks after block 2000
+		MaxwellTime: newUint64(timeAt(2600)), // epochs of 1000 blocks after block 3000
+	})
+	index := func(number uint64) int { return int(number - reset - 1) }
+
+	// chain returns the blocks following the reset up to last, sealed in turn
+	// with the set and its turn length on the checkpoint of every epoch.
+	chain := func(last uint64) []testBlock {
+		blocks := inTurnOf(keys, reset, int(last-reset), turnLength)
+		for _, number := range []uint64{1200, 1400, 1600, 1800, 2000, 2500, 3000, 4000} {
+			if number <= last {
+				blocks[index(number)].validators = validators
+				blocks[index(number)].turnLength = turnLength
+			}
+		}
+		return blocks
+	}
+	// handOver returns a chain on which the next set, with a turn length of 2,
+	// takes over from the block following from on.
+	handOver := func(from uint64) []testBlock {
+		blocks := chain(1220)
+		blocks[index(1200)].validators = nextValidators
+		blocks[index(1200)].turnLength = 2
+		for number := from + 1; number <= 1220; number++ {
+			blocks[index(number)].signer = nextKeys[number/2%uint64(len(nextKeys))]
+		}
+		return blocks
+	}
+
+	tests := []struct {
+		name    string
+		blocks  func() []testBlock
+		wantIdx int
+		wantErr error
+	}{
+		{
+			name:   "turns across the lorentz and maxwell epoch lengths",
+			blocks: func() []testBlock { return chain(4001) },
+		},
+		{
+			name: "seal past the turn",
+			blocks: func() []testBlock {
+				blocks := chain(1010)
+				blocks[index(1008)].signer = blocks[index(1007)].signer
+				return blocks
+			},
+			wantIdx: index(1008),
+			wantErr: errRecentlySigned,
+		},
+		{
+			name: "no validators on a checkpoint before lorentz epochs",
+			blocks: func() []testBlock {
+				blocks := chain(1800)
+				blocks[index(1800)].validators = nil
+				return blocks
+			},
+			wantIdx: index(1800),
+			wantErr: errInvalidCheckpointValidators,
+		},
+		{
+			name: "no validators on a lorentz checkpoint",
+			blocks: func() []testBlock {
+				blocks := chain(2500)
+				blocks[index(2500)].validators = nil
+				return blocks
+			},
+			wantIdx: index(2500),
+			wantErr: errInvalidCheckpointValidators,
+		},
+		{
+			name: "no validators on a maxwell checkpoint",
+			blocks: func() []testBlock {
+				blocks := chain(4000)
+				blocks[index(4000)].validators = nil
+				return blocks
+			},
+			wantIdx: index(4000),
+			wantErr: errInvalidCheckpointValidators,
+		},
+		{
+			name:   "next set takes over after the turns of the previous set",
+			blocks: func() []testBlock { return handOver(1207) },
+		},
+		{
+			name:    "next set seals too early",
+			blocks:  func() []testBlock { return handOver(1206) },
+			wantIdx: index(1207),
+			wantErr: errUnauthorizedValidator,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := getStateDB()
+			parent := &ethereum.Header{Number: big.NewInt(reset - 1), Time: timeAt(reset) - 3}
+			checkpoint := makeHeaders(t, parent, validators, []testBlock{{signer: keys[0], validators: validators, turnLength: turnLength}})[0]
+			enc, err := rlp.EncodeToBytes(checkpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := NewHeaderStore().ResetHeaderStore(db, enc, checkpoint.Difficulty); err != nil {
+				t.Fatal(err)
+			}
+
+			headers := makeHeaders(t, checkpoint, validators, tt.blocks())
+			if enc, err = rlp.EncodeToBytes(headers); err != nil {
+				t.Fatal(err)
+			}
+			idx, err := new(Validate).ValidateHeaderChain(db, enc, testChainType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateHeaderChain() error = %v at %d, wantErr %v", err, idx, tt.wantErr)
+			}
+			if idx != tt.wantIdx {
+				t.Fatalf("ValidateHeaderChain() index = %d, want %d", idx, tt.wantIdx)
+			}
+		})
+	}
+}
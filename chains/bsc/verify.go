@@ -0,0 +1,19 @@
+package bsc
+
+import (
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// Verify proves BSC receipts, which use the ethereum receipt trie, against the
+// receipt roots kept by the BSC header store.
+type Verify struct{}
+
+func (v *Verify) Verify(db types.StateDB, filter *chains.LogFilter, txProveBytes []byte) (logs []byte, err error) {
+	return ethereum.NewVerify(chains.BSCHeaderStoreAddress).Verify(db, filter, txProveBytes)
+}
+
+func (v *Verify) VerifyBatch(db types.StateDB, filter *chains.LogFilter, batchProveBytes []byte) (logs [][]byte, errs []error, err error) {
+	return ethereum.NewVerify(chains.BSCHeaderStoreAddress).VerifyBatch(db, filter, batchProveBytes)
+}
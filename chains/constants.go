@@ -1,6 +1,7 @@
 package chains
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,29 +18,37 @@ const (
 const (
 	ChainTypeETH     ChainType = 1
 	ChainTypeETHTest ChainType = 34434
+	ChainTypeBSC     ChainType = 56
+	ChainTypeBSCTest ChainType = 97
 )
 
 const (
 	ChainGroupMAP = 1000
 	ChainGroupETH = 1001
+	ChainGroupBSC = 1002
 )
 
+// ChainTypeList holds the atlas networks themselves, source chains are
+// appended by RegisterChainType.
 var ChainTypeList = []ChainType{
 	ChainTypeMAP,
 	ChainTypeMAPTest,
 	ChainTypeMAPDev,
-	ChainTypeETH,
-	ChainTypeETHTest,
 }
 
-var chainType2ChainGroup = map[ChainType]ChainGroup{
-	ChainTypeETH:     ChainGroupETH,
-	ChainTypeETHTest: ChainGroupETH,
-}
+var chainType2ChainGroup = make(map[ChainType]ChainGroup)
 
 var chainType2ChainID = map[ChainType]uint64{
 	ChainTypeETH:     params.MainNetChainID,
 	ChainTypeETHTest: params.TestNetChainID,
+	ChainTypeBSC:     params.MainNetChainID,
+	ChainTypeBSCTest: params.TestNetChainID,
+}
+
+// chainType2BeaconChainID holds the source chains whose post-merge blocks are
+// proven by the eth2 light client, keyed to the beacon network it must follow.
+var chainType2BeaconChainID = map[ChainType]uint64{
+	ChainTypeETH: 1,
 }
 
 var chainType2LondonBlock = map[ChainType]*big.Int{
@@ -49,6 +58,8 @@ var chainType2LondonBlock = map[ChainType]*big.Int{
 
 var (
 	EthereumHeaderStoreAddress = common.BytesToAddress([]byte("EthereumHeaderStoreAddress"))
+	BSCHeaderStoreAddress      = common.BytesToAddress([]byte("BSCHeaderStoreAddress"))
+	Eth2LightClientAddress     = common.BytesToAddress([]byte("Eth2LightClientAddress"))
 )
 
 type ChainType uint64
@@ -63,6 +74,17 @@ func IsSupportedChain(chain ChainType) bool {
 	return false
 }
 
+// RegisterChainType adds a source chain to ChainTypeList and binds it to the
+// group whose header store keeps its headers. It is meant to be called from
+// init and panics if the chain type is already registered.
+func RegisterChainType(chain ChainType, group ChainGroup) {
+	if IsSupportedChain(chain) {
+		panic(fmt.Sprintf("chain type %d registered twice", chain))
+	}
+	ChainTypeList = append(ChainTypeList, chain)
+	chainType2ChainGroup[chain] = group
+}
+
 func ChainType2ChainGroup(chain ChainType) (ChainGroup, error) {
 	group, ok := chainType2ChainGroup[chain]
 	if !ok {
@@ -79,6 +101,13 @@ func ChainType2ChainID(chain ChainType) (uint64, error) {
 	return chainID, nil
 }
 
+// ChainType2BeaconChainID returns the beacon network of the eth2 light client
+// for the chain, false if its receipts are only proven by a header store.
+func ChainType2BeaconChainID(chain ChainType) (uint64, bool) {
+	chainID, ok := chainType2BeaconChainID[chain]
+	return chainID, ok
+}
+
 func ChainType2LondonBlock(chain ChainType) (*big.Int, error) {
 	lb, ok := chainType2LondonBlock[chain]
 	if !ok {
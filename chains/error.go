@@ -1,8 +1,24 @@
 package chains
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 var (
 	ErrNotSupportChain = errors.New("not supported chain")
 	ErrRLPDecode       = errors.New("rlp decode error")
 )
+
+// LogNotFoundError is returned by receipt verifiers when the proven receipt has
+// no log matching the LogFilter.
+type LogNotFoundError struct {
+	Emitter common.Address
+	Topic0  common.Hash
+}
+
+func (e *LogNotFoundError) Error() string {
+	return fmt.Sprintf("not found event log, emitter: %v, topic0: %v", e.Emitter, e.Topic0)
+}
@@ -0,0 +1,359 @@
+package eth2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// ExecutionHistoryLimit is the number of execution blocks kept by the store,
+// older records are overwritten.
+const ExecutionHistoryLimit uint64 = 100000
+
+var (
+	ErrStoreNotInitialized = errors.New("please initialize eth2 light client store")
+	ErrStaleUpdate         = errors.New("finalized header is not newer than the stored one")
+	ErrUnknownExecution    = errors.New("unknown execution block")
+	ErrNetworkMismatch     = errors.New("eth2 light client store follows another network")
+	ErrNotFinalized        = errors.New("execution block is not finalized")
+)
+
+// ExecutionBlock is the part of a verified execution payload needed to prove
+// receipts and to link older execution headers to it.
+type ExecutionBlock struct {
+	Number       uint64
+	BlockHash    common.Hash
+	ParentHash   common.Hash
+	ReceiptsRoot common.Hash
+}
+
+// LightClientStore is the beacon light client state kept in the StateDB under
+// chains.Eth2LightClientAddress. Sync committees are stored by period, the
+// current committee is the one of the finalized header period, the next
+// committee the one of the following period.
+type LightClientStore struct {
+	ChainID            uint64
+	FinalizedHeader    *BeaconBlockHeader
+	FinalizedExecution uint64
+}
+
+func storeKey() common.Hash {
+	return common.BytesToHash(chains.Eth2LightClientAddress[:])
+}
+
+func syncCommitteeKey(period uint64) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("%s-%d", "committee", period)))
+}
+
+func executionKey(number uint64) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("%s-%d", "execution", number%ExecutionHistoryLimit)))
+}
+
+// LoadLightClientStore reads the light client store from the state.
+func LoadLightClientStore(db types.StateDB) (*LightClientStore, error) {
+	data := db.GetPOWState(chains.Eth2LightClientAddress, storeKey())
+	if len(data) == 0 {
+		return nil, ErrStoreNotInitialized
+	}
+	var s LightClientStore
+	if err := rlp.DecodeBytes(data, &s); err != nil {
+		log.Error("eth2 light client store rlp decode failed", "err", err)
+		return nil, fmt.Errorf("eth2 light client store rlp decode failed, error: %v", err)
+	}
+	return &s, nil
+}
+
+// LoadNetworkStore reads the light client store and checks that it follows the
+// beacon network with the given chain id.
+func LoadNetworkStore(db types.StateDB, chainID uint64) (*LightClientStore, error) {
+	s, err := LoadLightClientStore(db)
+	if err != nil {
+		return nil, err
+	}
+	if s.ChainID != chainID {
+		return nil, fmt.Errorf("%w, store: %d, chain: %d", ErrNetworkMismatch, s.ChainID, chainID)
+	}
+	return s, nil
+}
+
+// Store writes the light client store to the state.
+func (s *LightClientStore) Store(db types.StateDB) error {
+	data, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		log.Error("Failed to RLP encode eth2 light client store", "err", err)
+		return err
+	}
+	db.SetPOWState(chains.Eth2LightClientAddress, storeKey(), data)
+	return nil
+}
+
+// FinalizedPeriod returns the sync committee period of the finalized header.
+func (s *LightClientStore) FinalizedPeriod() uint64 {
+	return computeSyncCommitteePeriod(s.FinalizedHeader.Slot)
+}
+
+// SyncCommittee returns the sync committee stored for the given period.
+func (s *LightClientStore) SyncCommittee(db types.StateDB, period uint64) (*SyncCommittee, error) {
+	data := db.GetPOWState(chains.Eth2LightClientAddress, syncCommitteeKey(period))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("sync committee of period %d not found", period)
+	}
+	var committee SyncCommittee
+	if err := rlp.DecodeBytes(data, &committee); err != nil {
+		return nil, fmt.Errorf("sync committee rlp decode failed, error: %v", err)
+	}
+	return &committee, nil
+}
+
+func storeSyncCommittee(db types.StateDB, period uint64, committee *SyncCommittee) error {
+	data, err := rlp.EncodeToBytes(committee)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.Eth2LightClientAddress, syncCommitteeKey(period), data)
+	return nil
+}
+
+// state assembles the LightClientState the stateless verification works on.
+func (s *LightClientStore) state(db types.StateDB) (*LightClientState, error) {
+	period := s.FinalizedPeriod()
+	current, err := s.SyncCommittee(db, period)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.SyncCommittee(db, period+1)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientState{
+		finalizedHeader:      s.FinalizedHeader,
+		currentSyncCommittee: current,
+		nextSyncCommittee:    next,
+		chainID:              s.ChainID,
+	}, nil
+}
+
+// GetExecutionBlock returns the verified execution block with the given number,
+// nil if it is not known or was already overwritten.
+func GetExecutionBlock(db types.StateDB, number uint64) *ExecutionBlock {
+	data := db.GetPOWState(chains.Eth2LightClientAddress, executionKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	var block ExecutionBlock
+	if err := rlp.DecodeBytes(data, &block); err != nil {
+		log.Error("execution block rlp decode failed", "number", number, "err", err)
+		return nil
+	}
+	if block.Number != number {
+		return nil
+	}
+	return &block
+}
+
+// ExecutionSlotNumber returns the number of the execution block held by the
+// ring slot of the given number, false if the slot is empty.
+func ExecutionSlotNumber(db types.StateDB, number uint64) (uint64, bool) {
+	data := db.GetPOWState(chains.Eth2LightClientAddress, executionKey(number))
+	if len(data) == 0 {
+		return 0, false
+	}
+	var block ExecutionBlock
+	if err := rlp.DecodeBytes(data, &block); err != nil {
+		log.Error("execution block rlp decode failed", "number", number, "err", err)
+		return 0, false
+	}
+	return block.Number, true
+}
+
+// StoreExecutionBlock records a verified execution block. Callers are
+// responsible for linking it to a block verified by the light client.
+func StoreExecutionBlock(db types.StateDB, block *ExecutionBlock) error {
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.Eth2LightClientAddress, executionKey(block.Number), data)
+	return nil
+}
+
+// ResetLightClientStore initializes the store from a trusted state, the input
+// is the abi encoded finalized beacon header, current and next sync committee
+// and chain id.
+func ResetLightClientStore(db types.StateDB, input []byte) error {
+	state, err := decodeLightClientState(input)
+	if err != nil {
+		return err
+	}
+	return resetLightClientStore(db, state)
+}
+
+func resetLightClientStore(db types.StateDB, state *LightClientState) error {
+	if _, err := newNetworkConfig(state.chainID); err != nil {
+		return err
+	}
+	if len(state.currentSyncCommittee.Pubkeys) == 0 || len(state.nextSyncCommittee.Pubkeys) == 0 {
+		return errors.New("sync committee cannot be empty")
+	}
+
+	s := &LightClientStore{
+		ChainID:         state.chainID,
+		FinalizedHeader: state.finalizedHeader,
+	}
+	period := s.FinalizedPeriod()
+	if err := storeSyncCommittee(db, period, state.currentSyncCommittee); err != nil {
+		return err
+	}
+	if err := storeSyncCommittee(db, period+1, state.nextSyncCommittee); err != nil {
+		return err
+	}
+	return s.Store(db)
+}
+
+// ApplyLightClientUpdate verifies the abi encoded light client update against
+// the stored state of the beacon network chainID and moves the store to its
+// finalized header. The finalized execution payload is recorded and returned.
+func ApplyLightClientUpdate(db types.StateDB, chainID uint64, input []byte) (*ExecutionBlock, error) {
+	update, err := decodeLightClientUpdate(input)
+	if err != nil {
+		return nil, err
+	}
+	return applyLightClientUpdate(db, chainID, update)
+}
+
+func applyLightClientUpdate(db types.StateDB, chainID uint64, update *LightClientUpdateV2) (*ExecutionBlock, error) {
+	s, err := LoadNetworkStore(db, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.finalizedExecution == nil || update.finalizedExecution.BlockNumber == nil {
+		return nil, errors.New("finalized execution payload is missing")
+	}
+	if !(update.signatureSlot > update.attestedHeader.Slot && update.attestedHeader.Slot >= update.finalizedHeader.Slot) {
+		return nil, fmt.Errorf("invalid update slots, signature: %d, attested: %d, finalized: %d",
+			update.signatureSlot, update.attestedHeader.Slot, update.finalizedHeader.Slot)
+	}
+	if update.finalizedHeader.Slot <= s.FinalizedHeader.Slot {
+		return nil, ErrStaleUpdate
+	}
+	storePeriod := s.FinalizedPeriod()
+	updatePeriod := computeSyncCommitteePeriod(update.finalizedHeader.Slot)
+	if updatePeriod != storePeriod && updatePeriod != storePeriod+1 {
+		return nil, fmt.Errorf("update period should be %d or %d, but got %d", storePeriod, storePeriod+1, updatePeriod)
+	}
+
+	state, err := s.state(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyFinalityV2(update); err != nil {
+		return nil, err
+	}
+	if err := verifyNextSyncCommittee(state, update); err != nil {
+		return nil, err
+	}
+	if err := verifyBlsSignatures(state, update); err != nil {
+		return nil, err
+	}
+
+	if updatePeriod == storePeriod+1 {
+		// the committee of updatePeriod is already stored as the next one
+		if err := storeSyncCommittee(db, updatePeriod+1, update.nextSyncCommittee); err != nil {
+			return nil, err
+		}
+	}
+
+	execution := &ExecutionBlock{
+		Number:       update.finalizedExecution.BlockNumber.Uint64(),
+		BlockHash:    update.finalizedExecution.BlockHash,
+		ParentHash:   update.finalizedExecution.ParentHash,
+		ReceiptsRoot: update.finalizedExecution.ReceiptsRoot,
+	}
+	if err := StoreExecutionBlock(db, execution); err != nil {
+		return nil, err
+	}
+
+	s.FinalizedHeader = update.finalizedHeader
+	s.FinalizedExecution = execution.Number
+	if err := s.Store(db); err != nil {
+		return nil, err
+	}
+	return execution, nil
+}
+
+// GetExecutionReceiptsRoot returns the receipts root of a verified execution
+// block of the beacon network chainID. The block must be at least confirmations
+// blocks below the finalized execution block and within the kept history.
+func GetExecutionReceiptsRoot(db types.StateDB, chainID uint64, number, confirmations uint64) (common.Hash, error) {
+	s, err := LoadNetworkStore(db, chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	block := GetExecutionBlock(db, number)
+	if block == nil {
+		return common.Hash{}, fmt.Errorf("%w, number: %d", ErrUnknownExecution, number)
+	}
+	if number+confirmations > s.FinalizedExecution {
+		return common.Hash{}, fmt.Errorf("%w, number: %d, finalized: %d, confirmations: %d", ErrNotFinalized, number, s.FinalizedExecution, confirmations)
+	}
+	if s.FinalizedExecution-number >= ExecutionHistoryLimit {
+		return common.Hash{}, fmt.Errorf("obsolete execution block, finalized: %d, number: %d", s.FinalizedExecution, number)
+	}
+	return block.ReceiptsRoot, nil
+}
+
+func decodeLightClientState(input []byte) (*LightClientState, error) {
+	var beaconHeaderArg, syncCommitteeArg, chainIdArg abi.Argument
+	if err := beaconHeaderArg.UnmarshalJSON([]byte(BeaconHeaderABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal beacon header abi json failed: %v", err)
+	}
+	if err := syncCommitteeArg.UnmarshalJSON([]byte(SyncCommitteeABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal sync committee abi json failed: %v", err)
+	}
+	if err := chainIdArg.UnmarshalJSON([]byte(ChainIdABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal chain id abi json failed: %v", err)
+	}
+
+	args := abi.Arguments{beaconHeaderArg, syncCommitteeArg, syncCommitteeArg, chainIdArg}
+	ret, err := args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+
+	finalizedBeaconHeader := new(ILightNodeBeaconBlockHeader)
+	curSyncCommittee := new(ILightNodeSyncCommittee)
+	nextSyncCommittee := new(ILightNodeSyncCommittee)
+	chainId := new(uint64)
+	if err := args.Copy(&[]interface{}{finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, chainId}, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+
+	return ConvertToLightClientState(finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, *chainId), nil
+}
+
+func decodeLightClientUpdate(input []byte) (*LightClientUpdateV2, error) {
+	var updateArg abi.Argument
+	if err := updateArg.UnmarshalJSON([]byte(UpdateABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal update abi json failed: %v", err)
+	}
+
+	args := abi.Arguments{updateArg}
+	ret, err := args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+
+	update := new(ILightNodeLightClientUpdateV2)
+	if err := args.Copy(&update, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+	return update.toLightClientUpdateV2(), nil
+}
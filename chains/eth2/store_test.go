@@ -0,0 +1,120 @@
+package eth2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mapprotocol/atlas/core/rawdb"
+	atlasstate "github.com/mapprotocol/atlas/core/state"
+)
+
+func getStateDB() *atlasstate.StateDB {
+	finalDb := rawdb.NewMemoryDatabase()
+	finalState, _ := atlasstate.New(common.Hash{}, atlasstate.NewDatabase(finalDb), nil)
+	return finalState
+}
+
+func packLightClientState(t *testing.T, s *LightClientState) []byte {
+	var beaconHeaderArg, syncCommitteeArg, chainIdArg abi.Argument
+	assert.Nil(t, beaconHeaderArg.UnmarshalJSON([]byte(BeaconHeaderABIJSON)))
+	assert.Nil(t, syncCommitteeArg.UnmarshalJSON([]byte(SyncCommitteeABIJSON)))
+	assert.Nil(t, chainIdArg.UnmarshalJSON([]byte(ChainIdABIJSON)))
+
+	header := ILightNodeBeaconBlockHeader{
+		Slot:          s.finalizedHeader.Slot,
+		ProposerIndex: uint64(s.finalizedHeader.ProposerIndex),
+	}
+	copy(header.ParentRoot[:], s.finalizedHeader.ParentRoot)
+	copy(header.StateRoot[:], s.finalizedHeader.StateRoot)
+	copy(header.BodyRoot[:], s.finalizedHeader.BodyRoot)
+	committee := func(c *SyncCommittee) ILightNodeSyncCommittee {
+		return ILightNodeSyncCommittee{Pubkeys: bytes.Join(c.Pubkeys, nil), AggregatePubkey: c.AggregatePubkey}
+	}
+
+	args := abi.Arguments{beaconHeaderArg, syncCommitteeArg, syncCommitteeArg, chainIdArg}
+	input, err := args.Pack(header, committee(s.currentSyncCommittee), committee(s.nextSyncCommittee), s.chainID)
+	assert.Nil(t, err)
+	return input
+}
+
+func TestResetLightClientStore(t *testing.T) {
+	db := getStateDB()
+	_, err := LoadLightClientStore(db)
+	assert.Equal(t, ErrStoreNotInitialized, err)
+
+	assert.Nil(t, ResetLightClientStore(db, packLightClientState(t, &state)))
+
+	s, err := LoadLightClientStore(db)
+	assert.Nil(t, err)
+	assert.Equal(t, state.chainID, s.ChainID)
+	assert.Equal(t, state.finalizedHeader, s.FinalizedHeader)
+
+	restored, err := s.state(db)
+	assert.Nil(t, err)
+	assert.Equal(t, state, *restored)
+}
+
+func TestApplyLightClientUpdate(t *testing.T) {
+	db := getStateDB()
+	_, err := applyLightClientUpdate(db, state.chainID, &update)
+	assert.Equal(t, ErrStoreNotInitialized, err)
+
+	assert.Nil(t, resetLightClientStore(db, &state))
+	storePeriod := computeSyncCommitteePeriod(state.finalizedHeader.Slot)
+
+	_, err = applyLightClientUpdate(db, state.chainID+1, &update)
+	assert.ErrorIs(t, err, ErrNetworkMismatch)
+
+	execution, err := applyLightClientUpdate(db, state.chainID, &update)
+	assert.Nil(t, err)
+	assert.Equal(t, update.finalizedExecution.BlockNumber.Uint64(), execution.Number)
+	assert.Equal(t, update.finalizedExecution.BlockHash, execution.BlockHash)
+	assert.Equal(t, update.finalizedExecution.ReceiptsRoot, execution.ReceiptsRoot)
+
+	s, err := LoadLightClientStore(db)
+	assert.Nil(t, err)
+	assert.Equal(t, update.finalizedHeader, s.FinalizedHeader)
+	assert.Equal(t, execution.Number, s.FinalizedExecution)
+	// the update finalizes the next period, its next sync committee is kept
+	assert.Equal(t, storePeriod+1, s.FinalizedPeriod())
+	next, err := s.SyncCommittee(db, storePeriod+2)
+	assert.Nil(t, err)
+	assert.Equal(t, update.nextSyncCommittee, next)
+
+	root, err := GetExecutionReceiptsRoot(db, state.chainID, execution.Number, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, update.finalizedExecution.ReceiptsRoot, root)
+	_, err = GetExecutionReceiptsRoot(db, state.chainID, execution.Number+1, 0)
+	assert.ErrorIs(t, err, ErrUnknownExecution)
+	_, err = GetExecutionReceiptsRoot(db, state.chainID, execution.Number, 1)
+	assert.ErrorIs(t, err, ErrNotFinalized)
+	_, err = GetExecutionReceiptsRoot(db, state.chainID+1, execution.Number, 0)
+	assert.ErrorIs(t, err, ErrNetworkMismatch)
+
+	// replaying the update must not move the store
+	_, err = applyLightClientUpdate(db, state.chainID, &update)
+	assert.Equal(t, ErrStaleUpdate, err)
+}
+
+func TestApplyLightClientUpdateBadSignature(t *testing.T) {
+	db := getStateDB()
+	assert.Nil(t, resetLightClientStore(db, &state))
+
+	bad := update
+	bad.syncAggregate = &SyncAggregate{
+		SyncCommitteeBits:      update.syncAggregate.SyncCommitteeBits,
+		SyncCommitteeSignature: common.CopyBytes(update.syncAggregate.SyncCommitteeSignature),
+	}
+	bad.syncAggregate.SyncCommitteeSignature[len(bad.syncAggregate.SyncCommitteeSignature)-1] ^= 0x01
+	_, err := applyLightClientUpdate(db, state.chainID, &bad)
+	assert.NotNil(t, err)
+
+	s, err := LoadLightClientStore(db)
+	assert.Nil(t, err)
+	assert.Equal(t, state.finalizedHeader, s.FinalizedHeader)
+	assert.Nil(t, GetExecutionBlock(db, update.finalizedExecution.BlockNumber.Uint64()))
+}
@@ -8,4 +8,13 @@ var (
 	errFutureBlock     = errors.New("block in the future")
 	errInvalidNumber   = errors.New("invalid block number")
 	errNotSupportChain = errors.New("not supported chain")
+
+	errTooManyExecutionHeaders = errors.New("too many execution headers")
+	errOverwriteExecution      = errors.New("execution header is older than the block kept in its slot")
+
+	errFinalizedBlock      = errors.New("block is already finalized")
+	errReorgBelowFinalized = errors.New("reorg below the finalized block")
+	// ErrNotFinalized is returned when a receipt is proven against a block that
+	// is not buried under the confirmation depth yet.
+	ErrNotFinalized = errors.New("block is not finalized")
 )
@@ -0,0 +1,87 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// maxExecutionHeaders caps the headers of an InsertExecutionHeaders batch.
+const maxExecutionHeaders = 1024
+
+// InsertExecutionHeaders stores the receipt roots of post-merge execution
+// headers. Those headers have no seal to verify, so the batch together with an
+// execution block that is already known to the eth2 light client store following
+// the source chain must form one contiguous chain, ending in that block. A header
+// never replaces a newer block kept in its ring slot.
+func InsertExecutionHeaders(db types.StateDB, source chains.ChainType, headers []byte) ([]*params.NumberHash, error) {
+	chainID, ok := chains.ChainType2BeaconChainID(source)
+	if !ok {
+		return nil, fmt.Errorf("chain %d is not followed by the eth2 light client", source)
+	}
+	if _, err := eth2.LoadNetworkStore(db, chainID); err != nil {
+		return nil, err
+	}
+
+	var chain []*Header
+	if err := rlp.DecodeBytes(headers, &chain); err != nil {
+		log.Error("rlp decode execution headers failed.", "err", err)
+		return nil, chains.ErrRLPDecode
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("headers cannot be empty")
+	}
+	if len(chain) > maxExecutionHeaders {
+		return nil, fmt.Errorf("%w, have %d, max %d", errTooManyExecutionHeaders, len(chain), maxExecutionHeaders)
+	}
+	for i, header := range chain {
+		if header.Number == nil || !header.Number.IsUint64() {
+			return nil, errors.New("invalid header number")
+		}
+		if i == 0 {
+			continue
+		}
+		if header.Number.Uint64() != chain[i-1].Number.Uint64()+1 || header.ParentHash != chain[i-1].Hash() {
+			return nil, fmt.Errorf("non contiguous insert: item %d is #%d, item %d is #%d (parent [%x..])",
+				i-1, chain[i-1].Number, i, header.Number, header.ParentHash[:4])
+		}
+	}
+
+	last := chain[len(chain)-1]
+	child := eth2.GetExecutionBlock(db, last.Number.Uint64()+1)
+	if child == nil {
+		return nil, fmt.Errorf("%w, number: %d", eth2.ErrUnknownExecution, last.Number.Uint64()+1)
+	}
+	if child.ParentHash != last.Hash() {
+		return nil, fmt.Errorf("execution header #%d is not the parent of the verified block", last.Number)
+	}
+	for _, header := range chain {
+		number := header.Number.Uint64()
+		if kept, ok := eth2.ExecutionSlotNumber(db, number); ok && kept > number {
+			return nil, fmt.Errorf("%w, number: %d, kept: %d", errOverwriteExecution, number, kept)
+		}
+	}
+
+	imported := make([]*params.NumberHash, 0, len(chain))
+	for _, header := range chain {
+		hash := header.Hash()
+		if err := eth2.StoreExecutionBlock(db, &eth2.ExecutionBlock{
+			Number:       header.Number.Uint64(),
+			BlockHash:    hash,
+			ParentHash:   header.ParentHash,
+			ReceiptsRoot: header.ReceiptHash,
+		}); err != nil {
+			return nil, err
+		}
+		imported = append(imported, &params.NumberHash{Number: header.Number.Uint64(), Hash: hash})
+	}
+	log.Info("stored ethereum execution headers", "count", len(imported), "first", chain[0].Number, "last", last.Number)
+	return imported, nil
+}
@@ -0,0 +1,183 @@
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+func executionHeaders(first uint64, n int, receiptsRoot common.Hash) []*Header {
+	withdrawals := ethtypes.EmptyRootHash
+	headers := make([]*Header, 0, n)
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		h := &Header{
+			ParentHash:      parent,
+			UncleHash:       ethtypes.EmptyUncleHash,
+			ReceiptHash:     receiptsRoot,
+			Difficulty:      big.NewInt(0),
+			Number:          new(big.Int).SetUint64(first + uint64(i)),
+			GasLimit:        30_000_000,
+			Time:            1_700_000_000 + uint64(i)*12,
+			BaseFee:         big.NewInt(7),
+			WithdrawalsHash: &withdrawals,
+		}
+		headers = append(headers, h)
+		parent = h.Hash()
+	}
+	return headers
+}
+
+// storeLightClient writes an eth2 light client store following the beacon
+// network of chains.ChainTypeETH.
+func storeLightClient(t *testing.T, db types.StateDB, finalizedExecution uint64) {
+	s := &eth2.LightClientStore{ChainID: 1, FinalizedHeader: &eth2.BeaconBlockHeader{}, FinalizedExecution: finalizedExecution}
+	if err := s.Store(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInsertExecutionHeaders(t *testing.T) {
+	receiptsRoot := common.HexToHash("0x27022c6416c6a79e82c97f1d25f90b8543ea15fc5adfe11ec941d5ab0dec6d28")
+	headers := executionHeaders(271, 3, receiptsRoot)
+	last := headers[len(headers)-1]
+	input, err := rlp.EncodeToBytes(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := getStateDB()
+	if _, err := InsertExecutionHeaders(db, chains.ChainTypeETH, input); !errors.Is(err, eth2.ErrStoreNotInitialized) {
+		t.Fatalf("InsertExecutionHeaders() error = %v, want %v", err, eth2.ErrStoreNotInitialized)
+	}
+	storeLightClient(t, db, last.Number.Uint64()+1)
+	if _, err := InsertExecutionHeaders(db, chains.ChainTypeETHTest, input); err == nil {
+		t.Fatal("InsertExecutionHeaders() expected error for a chain without light client")
+	}
+	if _, err := InsertExecutionHeaders(db, chains.ChainTypeETH, input); !errors.Is(err, eth2.ErrUnknownExecution) {
+		t.Fatalf("InsertExecutionHeaders() error = %v, want %v", err, eth2.ErrUnknownExecution)
+	}
+
+	// a finalized block whose parent is not the last header
+	verified := &eth2.ExecutionBlock{Number: last.Number.Uint64() + 1, ParentHash: common.HexToHash("0x01")}
+	if err := eth2.StoreExecutionBlock(db, verified); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InsertExecutionHeaders(db, chains.ChainTypeETH, input); err == nil {
+		t.Fatal("InsertExecutionHeaders() expected error for unlinked headers")
+	}
+
+	verified.ParentHash = last.Hash()
+	if err := eth2.StoreExecutionBlock(db, verified); err != nil {
+		t.Fatal(err)
+	}
+	nums, err := InsertExecutionHeaders(db, chains.ChainTypeETH, input)
+	if err != nil {
+		t.Fatalf("InsertExecutionHeaders() error = %v", err)
+	}
+	if len(nums) != len(headers) {
+		t.Fatalf("imported %d headers, want %d", len(nums), len(headers))
+	}
+	for i, h := range headers {
+		if nums[i].Number != h.Number.Uint64() || nums[i].Hash != h.Hash() {
+			t.Errorf("imported #%d %x, want #%d %x", nums[i].Number, nums[i].Hash, h.Number, h.Hash())
+		}
+	}
+
+	// receipts of a backfilled block are proven without an ethash header store
+	txProve := getTxProve(273, 0, ReceiptsJSON)
+	filter := &chains.LogFilter{
+		Emitter: common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6"),
+		Topic0:  common.HexToHash("0x155e433be3576195943c515e1096620bc754e11b3a4b60fda7c4628caf373635"),
+	}
+	if _, err := NewChainVerify(chains.ChainTypeETH).Verify(db, filter, txProve); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if _, err := NewChainVerify(chains.ChainTypeETHTest).Verify(db, filter, txProve); err == nil {
+		t.Fatal("Verify() expected error for a chain without light client")
+	}
+	if _, err := NewVerify(common.HexToAddress("0x02")).Verify(db, filter, txProve); err == nil {
+		t.Fatal("Verify() expected error for another header store")
+	}
+}
+
+func TestInsertExecutionHeadersBounds(t *testing.T) {
+	insert := func(db types.StateDB, headers []*Header) error {
+		last := headers[len(headers)-1]
+		verified := &eth2.ExecutionBlock{Number: last.Number.Uint64() + 1, ParentHash: last.Hash()}
+		if err := eth2.StoreExecutionBlock(db, verified); err != nil {
+			t.Fatal(err)
+		}
+		input, err := rlp.EncodeToBytes(headers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = InsertExecutionHeaders(db, chains.ChainTypeETH, input)
+		return err
+	}
+
+	db := getStateDB()
+	storeLightClient(t, db, 0)
+	if err := insert(db, executionHeaders(1_000, maxExecutionHeaders+1, common.Hash{})); !errors.Is(err, errTooManyExecutionHeaders) {
+		t.Fatalf("InsertExecutionHeaders() error = %v, want %v", err, errTooManyExecutionHeaders)
+	}
+	if err := insert(db, executionHeaders(1_000, maxExecutionHeaders, common.Hash{})); err != nil {
+		t.Fatalf("InsertExecutionHeaders() error = %v", err)
+	}
+
+	// the slots of the batch hold newer blocks
+	db = getStateDB()
+	storeLightClient(t, db, 0)
+	headers := executionHeaders(1_000, 3, common.Hash{})
+	newer := &eth2.ExecutionBlock{Number: headers[1].Number.Uint64() + eth2.ExecutionHistoryLimit}
+	if err := eth2.StoreExecutionBlock(db, newer); err != nil {
+		t.Fatal(err)
+	}
+	if err := insert(db, headers); !errors.Is(err, errOverwriteExecution) {
+		t.Fatalf("InsertExecutionHeaders() error = %v, want %v", err, errOverwriteExecution)
+	}
+	if block := eth2.GetExecutionBlock(db, newer.Number); block == nil {
+		t.Fatal("newer execution block overwritten")
+	}
+
+	// older blocks of the slots are replaced
+	db = getStateDB()
+	storeLightClient(t, db, 0)
+	headers = executionHeaders(1_000+eth2.ExecutionHistoryLimit, 3, common.Hash{})
+	older := &eth2.ExecutionBlock{Number: headers[1].Number.Uint64() - eth2.ExecutionHistoryLimit}
+	if err := eth2.StoreExecutionBlock(db, older); err != nil {
+		t.Fatal(err)
+	}
+	if err := insert(db, headers); err != nil {
+		t.Fatalf("InsertExecutionHeaders() error = %v", err)
+	}
+}
+
+func TestHeaderHashPostMerge(t *testing.T) {
+	legacy := executionHeaders(1, 1, common.Hash{})[0]
+	legacy.WithdrawalsHash = nil
+	data, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Header
+	if err := rlp.DecodeBytes(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.WithdrawalsHash != nil || decoded.Hash() != legacy.Hash() {
+		t.Fatal("london header changed after rlp round trip")
+	}
+
+	shanghai := executionHeaders(1, 1, common.Hash{})[0]
+	if shanghai.Hash() == legacy.Hash() {
+		t.Fatal("withdrawals root is not part of the header hash")
+	}
+}
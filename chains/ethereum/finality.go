@@ -0,0 +1,163 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// SetConfirmations sets the confirmation depth of the header store, the
+// finalized pointer moves to the header buried that deep right away. Zero
+// disables finality tracking.
+func (hs *HeaderStore) SetConfirmations(db types.StateDB, confirmations uint64) error {
+	if confirmations >= MaxHeaderLimit {
+		return fmt.Errorf("confirmations must be less than %d", MaxHeaderLimit)
+	}
+	if err := hs.Load(db); err != nil {
+		return err
+	}
+	if confirmations == 0 {
+		hs.Confirmations, hs.FinalizedNumber, hs.FinalizedHash = 0, 0, common.Hash{}
+		return hs.Store(db)
+	}
+	hs.Confirmations = confirmations
+	if err := hs.finalize(db); err != nil {
+		return err
+	}
+	return hs.Store(db)
+}
+
+// FinalizeCount returns the number of headers SetConfirmations walks, and
+// prunes, when it moves the finalized pointer to the given depth.
+func (hs *HeaderStore) FinalizeCount(db types.StateDB, confirmations uint64) (uint64, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	// the first finalized pointer is set without walking the headers
+	if confirmations == 0 || hs.FinalizedHash == (common.Hash{}) || hs.CurNumber < confirmations {
+		return 0, nil
+	}
+	target := hs.CurNumber - confirmations
+	if target <= hs.FinalizedNumber {
+		return 0, nil
+	}
+	return target - hs.FinalizedNumber, nil
+}
+
+// GetFinalizedNumberAndHash returns the finalized pointer and the confirmation
+// depth, the pointer is zero when finality tracking is disabled.
+func (hs *HeaderStore) GetFinalizedNumberAndHash(db types.StateDB) (uint64, common.Hash, uint64, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, common.Hash{}, 0, err
+	}
+	return hs.FinalizedNumber, hs.FinalizedHash, hs.Confirmations, nil
+}
+
+// checkFinalized reports whether receipts of the given block may be proven.
+func (hs *HeaderStore) checkFinalized(number uint64) error {
+	if hs.Confirmations == 0 {
+		return nil
+	}
+	if number > hs.FinalizedNumber {
+		return fmt.Errorf("%w, number: %d, finalized: %d", ErrNotFinalized, number, hs.FinalizedNumber)
+	}
+	if hs.CurNumber >= MaxHeaderLimit && number <= hs.CurNumber-MaxHeaderLimit {
+		return fmt.Errorf("obsolete block, current number: %d, number: %d", hs.CurNumber, number)
+	}
+	return nil
+}
+
+// checkReorg rejects a reorg that would rewrite canonical headers at or below
+// the finalized pointer. first is the first header of the inserted batch and
+// last the number of the new head.
+func (hs *HeaderStore) checkReorg(db types.StateDB, first *Header, last uint64) error {
+	if hs.Confirmations == 0 || hs.FinalizedHash == (common.Hash{}) {
+		return nil
+	}
+	if last < hs.FinalizedNumber {
+		return fmt.Errorf("%w, head: %d, finalized: %d", errReorgBelowFinalized, last, hs.FinalizedNumber)
+	}
+	hash, number := first.ParentHash, first.Number.Uint64()-1
+	for number > hs.FinalizedNumber && hs.ReadCanonicalHash(number, db) != hash {
+		header := hs.GetHeader(hash, number, db)
+		if header == nil {
+			return fmt.Errorf("not found header, number: %d, hash: %s", number, hash)
+		}
+		hash, number = header.ParentHash, number-1
+	}
+	if number < hs.FinalizedNumber || (number == hs.FinalizedNumber && hash != hs.FinalizedHash) {
+		return fmt.Errorf("%w, fork number: %d, finalized: %d", errReorgBelowFinalized, number, hs.FinalizedNumber)
+	}
+	return nil
+}
+
+// finalize moves the finalized pointer to the canonical header Confirmations
+// below the head and drops the side chain headers of the newly final blocks.
+func (hs *HeaderStore) finalize(db types.StateDB) error {
+	if hs.Confirmations == 0 || hs.CurNumber < hs.Confirmations {
+		return nil
+	}
+	target := hs.CurNumber - hs.Confirmations
+	// the first finalized pointer is set without pruning, older ring slots are
+	// cleaned up when they are written again
+	started := hs.FinalizedHash != (common.Hash{})
+	if started && target <= hs.FinalizedNumber {
+		return nil
+	}
+
+	for number := hs.FinalizedNumber + 1; started && number <= target; number++ {
+		canonical := hs.ReadCanonicalHash(number, db)
+		if canonical == (common.Hash{}) {
+			continue
+		}
+		if err := hs.delOldHeaders(db, number, canonical); err != nil {
+			return err
+		}
+	}
+
+	hash := hs.ReadCanonicalHash(target, db)
+	if hash == (common.Hash{}) {
+		if !started {
+			// the head is not yet buried deep enough above the reset header
+			return nil
+		}
+		return errors.New("finalized header not found")
+	}
+	log.Debug("ethereum header finalized", "number", target, "hash", hash)
+	hs.FinalizedNumber, hs.FinalizedHash = target, hash
+	return nil
+}
+
+// delOldHeaders keeps only the canonical header in the ring slot of a final
+// block number.
+func (hs *HeaderStore) delOldHeaders(db types.StateDB, number uint64, canonical common.Hash) error {
+	lh, err := hs.LoadHeader(number, db)
+	if err != nil {
+		return err
+	}
+	if hs.pruneSlot(lh, number, &canonical) {
+		return hs.StoreHeader(db, number, lh)
+	}
+	return nil
+}
+
+// pruneSlot removes the headers of a ring slot that do not belong to number,
+// or that are not keep when it is given. It reports whether anything changed.
+func (hs *HeaderStore) pruneSlot(lh *LightHeader, number uint64, keep *common.Hash) bool {
+	pruned := false
+	for key, data := range lh.Headers {
+		hash := common.HexToHash(key)
+		header := decodeHeader(data, hash)
+		if header != nil && header.Number.Uint64() == number && (keep == nil || *keep == hash) {
+			continue
+		}
+		delete(lh.Headers, key)
+		delete(lh.TDs, key)
+		pruned = true
+	}
+	return pruned
+}
@@ -0,0 +1,238 @@
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/core/state"
+)
+
+// makeChain returns n headers on top of parent, seed tells forks apart.
+func makeChain(parent *Header, n int, difficulty int64, seed byte) []*Header {
+	headers := make([]*Header, 0, n)
+	for i := 0; i < n; i++ {
+		h := &Header{
+			ParentHash:  parent.Hash(),
+			ReceiptHash: common.BytesToHash([]byte{seed, byte(i)}),
+			Difficulty:  big.NewInt(difficulty),
+			Number:      new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Time:        parent.Time + 13,
+			Extra:       []byte{seed},
+		}
+		headers = append(headers, h)
+		parent = h
+	}
+	return headers
+}
+
+func insertChain(t *testing.T, db *state.StateDB, headers []*Header) error {
+	t.Helper()
+	data, err := rlp.EncodeToBytes(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewHeaderStore().InsertHeaders(db, data)
+	return err
+}
+
+func resetFinalityStore(t *testing.T, confirmations uint64) (*state.StateDB, []*Header) {
+	t.Helper()
+	db := getStateDB()
+	genesis := &Header{Number: big.NewInt(100), Difficulty: big.NewInt(1), Time: 1_600_000_000}
+	data, _ := rlp.EncodeToBytes(genesis)
+	if err := NewHeaderStore().ResetHeaderStore(db, data, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewHeaderStore().SetConfirmations(db, confirmations); err != nil {
+		t.Fatal(err)
+	}
+	chain := makeChain(genesis, 10, 10, 0)
+	if err := insertChain(t, db, chain); err != nil {
+		t.Fatal(err)
+	}
+	return db, append([]*Header{genesis}, chain...)
+}
+
+func TestHeaderStoreFinality(t *testing.T) {
+	db, chain := resetFinalityStore(t, 3)
+
+	hs := NewHeaderStore()
+	number, hash, confirmations, err := hs.GetFinalizedNumberAndHash(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != 107 || hash != chain[7].Hash() || confirmations != 3 {
+		t.Fatalf("finalized = #%d %x (%d), want #107 %x (3)", number, hash, confirmations, chain[7].Hash())
+	}
+
+	v := new(Verify)
+	if root, err := v.getReceiptsRoot(db, 105); err != nil || root != chain[5].ReceiptHash {
+		t.Errorf("getReceiptsRoot(105) = %x, %v, want %x", root, err, chain[5].ReceiptHash)
+	}
+	if _, err := v.getReceiptsRoot(db, 108); !errors.Is(err, ErrNotFinalized) {
+		t.Errorf("getReceiptsRoot(108) error = %v, want %v", err, ErrNotFinalized)
+	}
+
+	// blocks proven by the light client are also kept the confirmations below
+	// its finalized execution block
+	storeLightClient(t, db, 200)
+	for _, number := range []uint64{197, 198} {
+		if err := eth2.StoreExecutionBlock(db, &eth2.ExecutionBlock{Number: number, ReceiptsRoot: common.BigToHash(new(big.Int).SetUint64(number))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	eth := NewChainVerify(chains.ChainTypeETH)
+	if root, err := eth.getReceiptsRoot(db, 197); err != nil || root != common.BigToHash(big.NewInt(197)) {
+		t.Errorf("getReceiptsRoot(197) = %x, %v", root, err)
+	}
+	if _, err := eth.getReceiptsRoot(db, 198); !errors.Is(err, eth2.ErrNotFinalized) {
+		t.Errorf("getReceiptsRoot(198) error = %v, want %v", err, eth2.ErrNotFinalized)
+	}
+	if root, err := eth.getReceiptsRoot(db, 105); err != nil || root != chain[5].ReceiptHash {
+		t.Errorf("getReceiptsRoot(105) = %x, %v, want %x", root, err, chain[5].ReceiptHash)
+	}
+}
+
+func TestHeaderStoreFinalizeCount(t *testing.T) {
+	db, _ := resetFinalityStore(t, 3)
+
+	hs := NewHeaderStore()
+	for _, tt := range []struct {
+		confirmations, want uint64
+	}{
+		{confirmations: 1, want: 2},
+		{confirmations: 3, want: 0},
+		{confirmations: 5, want: 0},
+		{confirmations: 0, want: 0},
+	} {
+		if count, err := hs.FinalizeCount(db, tt.confirmations); err != nil || count != tt.want {
+			t.Errorf("FinalizeCount(%d) = %d, %v, want %d", tt.confirmations, count, err, tt.want)
+		}
+	}
+	if err := hs.SetConfirmations(db, 1); err != nil {
+		t.Fatal(err)
+	}
+	if number, _, _, _ := hs.GetFinalizedNumberAndHash(db); number != 109 {
+		t.Fatalf("finalized = #%d, want #109", number)
+	}
+}
+
+func TestHeaderStoreReorgBelowFinalized(t *testing.T) {
+	db, chain := resetFinalityStore(t, 3)
+
+	// a heavier fork starting below the finalized header is refused
+	fork := makeChain(chain[5], 8, 20, 1)
+	if err := insertChain(t, db, fork); !errors.Is(err, errFinalizedBlock) {
+		t.Fatalf("insert fork at 106 error = %v, want %v", err, errFinalizedBlock)
+	}
+
+	// a side chain stored while it was above the finalized header can not
+	// take over once the fork point is final
+	side := makeChain(chain[7], 3, 1, 2)
+	if err := insertChain(t, db, side); err != nil {
+		t.Fatal(err)
+	}
+	if err := insertChain(t, db, makeChain(chain[10], 1, 10, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := insertChain(t, db, makeChain(side[2], 3, 20, 2)); !errors.Is(err, errReorgBelowFinalized) {
+		t.Fatalf("insert fork at 108 error = %v, want %v", err, errReorgBelowFinalized)
+	}
+
+	// a reorg above the finalized header is accepted
+	fork = makeChain(chain[9], 3, 20, 3)
+	if err := insertChain(t, db, fork); err != nil {
+		t.Fatalf("insert fork at 110 error = %v", err)
+	}
+	hs := NewHeaderStore()
+	if err := hs.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if hs.CurHash != fork[2].Hash() || hs.ReadCanonicalHash(110, db) != fork[0].Hash() {
+		t.Fatalf("fork was not made canonical")
+	}
+	if hs.FinalizedNumber != 109 || hs.FinalizedHash != chain[9].Hash() {
+		t.Fatalf("finalized = #%d %x, want #109 %x", hs.FinalizedNumber, hs.FinalizedHash, chain[9].Hash())
+	}
+}
+
+func TestHeaderStorePruneFinalized(t *testing.T) {
+	db, chain := resetFinalityStore(t, 3)
+
+	side := makeChain(chain[8], 1, 1, 4)
+	if err := insertChain(t, db, side); err != nil {
+		t.Fatal(err)
+	}
+	hs := NewHeaderStore()
+	if !hs.HasHeader(side[0].Hash(), 109, db) {
+		t.Fatal("side header not stored")
+	}
+
+	if err := insertChain(t, db, makeChain(chain[10], 2, 10, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if hs.HasHeader(side[0].Hash(), 109, db) {
+		t.Error("side header of a finalized block was not pruned")
+	}
+	if !hs.HasHeader(chain[9].Hash(), 109, db) {
+		t.Error("canonical header of a finalized block was pruned")
+	}
+}
+
+func TestHeaderStoreFinalityDisabled(t *testing.T) {
+	db, chain := resetFinalityStore(t, 0)
+
+	if root, err := new(Verify).getReceiptsRoot(db, 110); err != nil || root != chain[10].ReceiptHash {
+		t.Errorf("getReceiptsRoot(110) = %x, %v, want %x", root, err, chain[10].ReceiptHash)
+	}
+	fork := makeChain(chain[5], 8, 20, 1)
+	if err := insertChain(t, db, fork); err != nil {
+		t.Errorf("insert fork error = %v", err)
+	}
+	if err := NewHeaderStore().SetConfirmations(db, MaxHeaderLimit); err == nil {
+		t.Error("SetConfirmations() expected error for a depth beyond the ring")
+	}
+}
+
+func TestHeaderStoreGetHeaderByHash(t *testing.T) {
+	db, chain := resetFinalityStore(t, 0)
+	side := makeChain(chain[8], 1, 1, 5)
+	if err := insertChain(t, db, side); err != nil {
+		t.Fatal(err)
+	}
+
+	hs := NewHeaderStore()
+	for _, h := range []*Header{chain[0], chain[5], chain[10]} {
+		if got := hs.GetHeaderByHash(h.Hash(), h.Number.Uint64(), db); got == nil || got.Hash() != h.Hash() {
+			t.Errorf("GetHeaderByHash(#%d) = %v, want %x", h.Number, got, h.Hash())
+		}
+	}
+	if got := hs.GetHeaderByHash(side[0].Hash(), side[0].Number.Uint64(), db); got != nil {
+		t.Errorf("GetHeaderByHash() of a side chain header = #%d, want nil", got.Number)
+	}
+	if got := hs.GetHeaderByHash(chain[5].Hash(), chain[6].Number.Uint64(), db); got != nil {
+		t.Errorf("GetHeaderByHash() with a wrong number = #%d, want nil", got.Number)
+	}
+	if td := hs.GetTd(chain[10].Hash(), 110, db); td == nil || td.Uint64() != 101 {
+		t.Errorf("GetTd(110) = %v, want 101", td)
+	}
+	// hashes leave the canonical ring when a reorg replaces them
+	fork := makeChain(chain[8], 3, 20, 6)
+	if err := insertChain(t, db, fork); err != nil {
+		t.Fatal(err)
+	}
+	if got := hs.GetHeaderByHash(fork[1].Hash(), fork[1].Number.Uint64(), db); got == nil || got.Hash() != fork[1].Hash() {
+		t.Errorf("GetHeaderByHash() of the new head = %v, want %x", got, fork[1].Hash())
+	}
+	for _, h := range chain[9:] {
+		if got := hs.GetHeaderByHash(h.Hash(), h.Number.Uint64(), db); got != nil {
+			t.Errorf("GetHeaderByHash() of the replaced #%d = %x, want nil", h.Number, got.Hash())
+		}
+	}
+}
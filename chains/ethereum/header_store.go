@@ -54,6 +54,15 @@ type HeaderStore struct {
 	CurNumber uint64
 	CurHash   common.Hash
 	//CanonicalNumberToHash []*common.Hash
+
+	// Confirmations is the depth below the head at which headers become final,
+	// zero disables finality tracking. Receipts are only proven against final
+	// headers and final headers can no longer be reorganized.
+	Confirmations   uint64      `rlp:"optional"`
+	FinalizedNumber uint64      `rlp:"optional"`
+	FinalizedHash   common.Hash `rlp:"optional"`
+
+	address common.Address // storage account, zero means chains.EthereumHeaderStoreAddress
 }
 
 type LightHeader struct {
@@ -65,6 +74,18 @@ func headerKey(number uint64, hash common.Hash) string {
 	return fmt.Sprintf("%d%s%s", number, SplicingSymbol, hash.Hex())
 }
 
+// HeaderDbKey returns the POW storage key the header store account keeps the
+// headers of the given number under.
+func HeaderDbKey(number uint64) common.Hash {
+	return new(HeaderStore).headerDbKey(number)
+}
+
+// CanonicalHeaderDbKey returns the POW storage key the header store account
+// keeps the canonical hash of the given number under.
+func CanonicalHeaderDbKey(number uint64) common.Hash {
+	return new(HeaderStore).canonicalHeaderDbKey(number)
+}
+
 func (hs *HeaderStore) headerDbKey(number uint64) common.Hash {
 	str := fmt.Sprintf("%s-%d", "eth2map", hs.loopIdx(number))
 	key := common.BytesToHash([]byte(str))
@@ -85,10 +106,6 @@ func (hs *HeaderStore) loopIdx(number uint64) uint64 {
 	return idx
 }
 
-func (hs *HeaderStore) delOldHeaders() {
-
-}
-
 func encodeHeader(header *Header) []byte {
 	data, err := rlp.EncodeToBytes(header)
 	if err != nil {
@@ -110,6 +127,20 @@ func NewHeaderStore() *HeaderStore {
 	return &HeaderStore{}
 }
 
+// NewHeaderStoreAt returns a header store that keeps its data under the given
+// account, so that chains sharing the ethereum header format can reuse it.
+func NewHeaderStoreAt(address common.Address) *HeaderStore {
+	return &HeaderStore{address: address}
+}
+
+// Address returns the account the header store writes to.
+func (hs *HeaderStore) Address() common.Address {
+	if hs.address == (common.Address{}) {
+		return chains.EthereumHeaderStoreAddress
+	}
+	return hs.address
+}
+
 func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, ethHeaders []byte, td *big.Int) error {
 	var header Header
 	if err := rlp.DecodeBytes(ethHeaders, &header); err != nil {
@@ -123,6 +154,12 @@ func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, ethHeaders []byte,
 	h := &HeaderStore{
 		CurHash:   hash,
 		CurNumber: number,
+		address:   hs.address,
+	}
+	// the confirmation depth survives a reset, the reset header is trusted
+	if prev := NewHeaderStoreAt(hs.address); prev.Load(state) == nil && prev.Confirmations > 0 {
+		h.Confirmations = prev.Confirmations
+		h.FinalizedNumber, h.FinalizedHash = number, hash
 	}
 	if err := h.Store(state); err != nil {
 		return err
@@ -158,7 +195,7 @@ func cloneLightHeader(src *LightHeader) (dst *LightHeader, err error) {
 
 func (hs *HeaderStore) Store(state types.StateDB) error {
 	var (
-		address = chains.EthereumHeaderStoreAddress
+		address = hs.Address()
 		key     = common.BytesToHash(address[:])
 	)
 
@@ -180,7 +217,7 @@ func (hs *HeaderStore) Store(state types.StateDB) error {
 }
 
 func (hs *HeaderStore) StoreHeader(state types.StateDB, number uint64, header *LightHeader) error {
-	address := chains.EthereumHeaderStoreAddress
+	address := hs.Address()
 	data, err := rlp.EncodeToBytes(header)
 	if err != nil {
 		log.Error("Failed to RLP encode HeaderStore", "err", err)
@@ -201,7 +238,7 @@ func (hs *HeaderStore) StoreHeader(state types.StateDB, number uint64, header *L
 }
 
 func (hs *HeaderStore) StoreCanonicalHash(state types.StateDB, number uint64, hash *common.Hash) error {
-	address := chains.EthereumHeaderStoreAddress
+	address := hs.Address()
 	data, err := rlp.EncodeToBytes(*hash)
 	if err != nil {
 		log.Error("Failed to RLP encode HeaderStore", "err", err)
@@ -217,7 +254,7 @@ func (hs *HeaderStore) StoreCanonicalHash(state types.StateDB, number uint64, ha
 func (hs *HeaderStore) Load(state types.StateDB) (err error) {
 	var (
 		h       HeaderStore
-		address = chains.EthereumHeaderStoreAddress
+		address = hs.Address()
 		key     = common.BytesToHash(address[:])
 	)
 
@@ -233,7 +270,7 @@ func (hs *HeaderStore) Load(state types.StateDB) (err error) {
 			return err
 		}
 		h = *cp
-		hs.CurHash, hs.CurNumber = h.CurHash, h.CurNumber
+		hs.load(&h)
 		//hs.CanonicalNumberToHash = h.CanonicalNumberToHash
 		return nil
 	}
@@ -248,14 +285,21 @@ func (hs *HeaderStore) Load(state types.StateDB) (err error) {
 		return err
 	}
 	storeCache.Cache.Add(hash, clone)
-	hs.CurHash, hs.CurNumber = h.CurHash, h.CurNumber
+	hs.load(&h)
 	//hs.CanonicalNumberToHash = h.CanonicalNumberToHash
 	return nil
 }
 
+// load copies the persisted fields of h.
+func (hs *HeaderStore) load(h *HeaderStore) {
+	hs.CurHash, hs.CurNumber = h.CurHash, h.CurNumber
+	hs.Confirmations = h.Confirmations
+	hs.FinalizedNumber, hs.FinalizedHash = h.FinalizedNumber, h.FinalizedHash
+}
+
 func (hs *HeaderStore) LoadHeader(number uint64, db types.StateDB) (lh *LightHeader, err error) {
 	key := hs.headerDbKey(number)
-	address := chains.EthereumHeaderStoreAddress
+	address := hs.Address()
 	data := db.GetPOWState(address, key)
 	if len(data) == 0 {
 		return &LightHeader{
@@ -288,7 +332,7 @@ func (hs *HeaderStore) LoadHeader(number uint64, db types.StateDB) (lh *LightHea
 
 func (hs *HeaderStore) LoadCanonicalHash(number uint64, db types.StateDB) (lh common.Hash, err error) {
 	key := hs.canonicalHeaderDbKey(number)
-	address := chains.EthereumHeaderStoreAddress
+	address := hs.Address()
 	data := db.GetPOWState(address, key)
 	if len(data) == 0 {
 		return common.Hash{}, nil
@@ -307,6 +351,10 @@ func (hs *HeaderStore) WriteHeaderAndTd(hash common.Hash, number uint64, td *big
 	if err != nil {
 		return err
 	}
+	if hs.Confirmations > 0 {
+		// drop the headers left in the ring slot by an older block number
+		hs.pruneSlot(loadHeader, number, nil)
+	}
 	loadHeader.Headers[hash.String()] = encodeHeader(header)
 	loadHeader.TDs[hash.String()] = td
 	// store
@@ -421,6 +469,9 @@ func (hs *HeaderStore) WriteHeaders(db types.StateDB, ethHeaders []byte) (*heade
 
 		alreadyKnown := parentKnown && hs.HasHeader(hash, number, db)
 		if !alreadyKnown {
+			if hs.Confirmations > 0 && number <= hs.FinalizedNumber {
+				return &headerWriteResult{}, fmt.Errorf("%w, number: %d, finalized: %d", errFinalizedBlock, number, hs.FinalizedNumber)
+			}
 			//hs.WriteTd(hash, number, newTD, header)
 			if err := hs.WriteHeaderAndTd(hash, number, newTD, header, db); err != nil {
 				return nil, err
@@ -457,8 +508,11 @@ func (hs *HeaderStore) WriteHeaders(db types.StateDB, ethHeaders []byte) (*heade
 	chainAlreadyCanon := headers[0].ParentHash == hs.CurHash
 	if reorg {
 		if !chainAlreadyCanon {
+			if err := hs.checkReorg(db, headers[0], lastNumber); err != nil {
+				return &headerWriteResult{}, err
+			}
 			for i := lastNumber + 1; ; i++ {
-				if i <= hs.CurNumber-MaxHeaderLimit+1 {
+				if hs.CurNumber >= MaxHeaderLimit && i <= hs.CurNumber-MaxHeaderLimit+1 {
 					log.Info("chainAlreadyCanon=false, obsolete block", "current", hs.CurNumber, "calNumber", i)
 					continue
 				}
@@ -502,9 +556,11 @@ func (hs *HeaderStore) WriteHeaders(db types.StateDB, ethHeaders []byte) (*heade
 			hs.WriteCanonicalHash(hn.Hash, hn.Number, db)
 		}
 
-		hs.delOldHeaders()
 		hs.CurHash = lastHash
 		hs.CurNumber = lastNumber
+		if err := hs.finalize(db); err != nil {
+			return &headerWriteResult{}, err
+		}
 
 		// Chain status is canonical since this insert was a reorg.
 		// Note that all inserts which have higher TD than existing are 'reorg'.
@@ -550,6 +606,20 @@ func (hs *HeaderStore) GetHeaderByNumber(number uint64, db types.StateDB) *Heade
 	return hs.GetHeader(hash, number, db)
 }
 
+// GetHeaderByHash returns the header with the given hash if it is the canonical
+// header at the given number, side chain headers can only be read with GetHeader.
+// A ring slot still holding an older number is not matched.
+func (hs *HeaderStore) GetHeaderByHash(hash common.Hash, number uint64, db types.StateDB) *Header {
+	if hs.ReadCanonicalHash(number, db) != hash {
+		return nil
+	}
+	header := hs.GetHeader(hash, number, db)
+	if header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
 func (hs *HeaderStore) GetCurrentNumberAndHash(db types.StateDB) (uint64, common.Hash, error) {
 	if err := hs.Load(db); err != nil {
 		return 0, common.Hash{}, err
@@ -6,18 +6,22 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
 	"github.com/mapprotocol/atlas/core/types"
 )
 
 var (
-// EventHash cross-chain transaction event hash
-// mapTransferOut(address indexed token, address indexed from, bytes32 indexed orderId, uint fromChain, uint toChain, bytes to, uint amount, bytes toChainToken);
-// mapTransferOut(address,address,bytes32,uint256,uint256,bytes,uint256,bytes)
-//EventHash = common.HexToHash("0x1d7c4ab437b83807c25950ac63192692227b29e3205a809db6a4c3841836eb02")
+	// EventHash cross-chain transaction event hash, it is matched when the log filter has no topic
+	// mapTransferOut(address indexed token, address indexed from, bytes32 indexed orderId, uint fromChain, uint toChain, bytes to, uint amount, bytes toChainToken);
+	// mapTransferOut(address,address,bytes32,uint256,uint256,bytes,uint256,bytes)
+	EventHash = common.HexToHash("0x1d7c4ab437b83807c25950ac63192692227b29e3205a809db6a4c3841836eb02")
 )
 
 type TxProve struct {
@@ -27,19 +31,55 @@ type TxProve struct {
 	TxIndex     uint
 }
 
+// ReceiptProve is one receipt of a BatchTxProve.
+type ReceiptProve struct {
+	Receipt *ethtypes.Receipt
+	TxIndex uint
+}
+
+// BatchTxProve proves several receipts of the same block. Prove is the
+// deduplicated union of the trie nodes of all receipt proofs.
+type BatchTxProve struct {
+	BlockNumber uint64
+	Receipts    []ReceiptProve
+	Prove       light.NodeList
+}
+
 type Verify struct {
+	address common.Address   // header store account, zero means chains.EthereumHeaderStoreAddress
+	chain   chains.ChainType // source chain, selects the eth2 light client network
 }
 
-func (v *Verify) Verify(db types.StateDB, routerContractAddr common.Address, txProveBytes []byte) (logs []byte, err error) {
+// NewVerify returns a receipt verifier that reads receipt roots from the header
+// store kept under address, for chains that share the ethereum receipt format.
+func NewVerify(address common.Address) *Verify {
+	return &Verify{address: address}
+}
+
+// NewChainVerify returns the receipt verifier of an ethereum chain, post-merge
+// blocks are proven through the eth2 light client when it follows that chain.
+func NewChainVerify(chain chains.ChainType) *Verify {
+	return &Verify{address: chains.EthereumHeaderStoreAddress, chain: chain}
+}
+
+// Verify proves the receipt of a TxProve and returns the rlp encoded
+// chains.MatchedLog of the first log matching filter, or all the logs of the
+// receipt when filter is nil.
+func (v *Verify) Verify(db types.StateDB, filter *chains.LogFilter, txProveBytes []byte) (logs []byte, err error) {
 	txProve, err := v.decode(txProveBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	//lgs, err := v.queryLog(routerContractAddr, txProve.Receipt.Logs)
-	//if err != nil {
-	//	return nil, err
-	//}
+	var lg *chains.MatchedLog
+	if filter != nil {
+		if txProve.Receipt == nil {
+			return nil, errors.New("receipt is empty")
+		}
+		if lg, err = v.queryLog(*filter, txProve.Receipt.Logs); err != nil {
+			return nil, err
+		}
+	}
 	receiptsRoot, err := v.getReceiptsRoot(db, txProve.BlockNumber)
 	if err != nil {
 		return nil, err
@@ -48,7 +88,95 @@ func (v *Verify) Verify(db types.StateDB, routerContractAddr common.Address, txP
 	if err := v.verifyProof(receiptsRoot, txProve); err != nil {
 		return nil, err
 	}
-	return rlp.EncodeToBytes(txProve.Receipt.Logs)
+	return encodeLogs(lg, txProve.Receipt)
+}
+
+// VerifyBatch proves every receipt of a BatchTxProve against the receipts root
+// of its block. Failures of single receipts are reported in errs, err is only
+// set when the batch as a whole cannot be verified.
+func (v *Verify) VerifyBatch(db types.StateDB, filter *chains.LogFilter, batchProveBytes []byte) (logs [][]byte, errs []error, err error) {
+	prove, err := DecodeBatchTxProve(batchProveBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(prove.Receipts) == 0 {
+		return nil, nil, errors.New("receipts cannot be empty")
+	}
+	if uniqueNodes(prove.Prove) != len(prove.Prove) {
+		return nil, nil, errors.New("duplicated proof nodes")
+	}
+
+	receiptsRoot, err := v.getReceiptsRoot(db, prove.BlockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodes := prove.Prove.NodeSet()
+	logs = make([][]byte, len(prove.Receipts))
+	errs = make([]error, len(prove.Receipts))
+	for i, r := range prove.Receipts {
+		if r.Receipt == nil {
+			errs[i] = errors.New("receipt is empty")
+			continue
+		}
+		var lg *chains.MatchedLog
+		if filter != nil {
+			if lg, errs[i] = v.queryLog(*filter, r.Receipt.Logs); errs[i] != nil {
+				continue
+			}
+		}
+		if errs[i] = v.verifyReceipt(receiptsRoot, r.Receipt, r.TxIndex, nodes); errs[i] != nil {
+			continue
+		}
+		logs[i], errs[i] = encodeLogs(lg, r.Receipt)
+	}
+	return logs, errs, nil
+}
+
+// DecodeBatchTxProve decodes a rlp encoded BatchTxProve.
+func DecodeBatchTxProve(batchProveBytes []byte) (*BatchTxProve, error) {
+	var prove BatchTxProve
+	if err := rlp.DecodeBytes(batchProveBytes, &prove); err != nil {
+		return nil, err
+	}
+	return &prove, nil
+}
+
+// BatchProofSize returns the number of receipts and trie nodes of a rlp encoded
+// BatchTxProve and their encoded size. It only walks the rlp structure, so the
+// batch is priced without decoding the receipts before it is verified.
+func BatchProofSize(batchProveBytes []byte) (receipts, nodes, size int, err error) {
+	content, _, err := rlp.SplitList(batchProveBytes)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// skip BlockNumber
+	if _, _, content, err = rlp.Split(content); err != nil {
+		return 0, 0, 0, err
+	}
+	receiptList, content, err := rlp.SplitList(content)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	nodeList, _, err := rlp.SplitList(content)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if receipts, err = rlp.CountValues(receiptList); err != nil {
+		return 0, 0, 0, err
+	}
+	if nodes, err = rlp.CountValues(nodeList); err != nil {
+		return 0, 0, 0, err
+	}
+	return receipts, nodes, len(receiptList) + len(nodeList), nil
+}
+
+func uniqueNodes(list light.NodeList) int {
+	seen := make(map[common.Hash]struct{}, len(list))
+	for _, node := range list {
+		seen[crypto.Keccak256Hash(node)] = struct{}{}
+	}
+	return len(seen)
 }
 
 func (v *Verify) decode(txProveBytes []byte) (*TxProve, error) {
@@ -59,24 +187,46 @@ func (v *Verify) decode(txProveBytes []byte) (*TxProve, error) {
 	return &txProve, nil
 }
 
-//func (v *Verify) queryLog(routerContractAddr common.Address, logs []*ethtypes.Log) (*ethtypes.Log, error) {
-//	for _, lg := range logs {
-//		if bytes.Equal(lg.Address.Bytes(), routerContractAddr.Bytes()) {
-//			if bytes.Equal(lg.Topics[0].Bytes(), EventHash.Bytes()) {
-//				return lg, nil
-//			}
-//		}
-//	}
-//	return nil, fmt.Errorf("not found event log, router contract addr: %v, event hash: %v", routerContractAddr, EventHash)
-//}
+func (v *Verify) queryLog(filter chains.LogFilter, logs []*ethtypes.Log) (*chains.MatchedLog, error) {
+	if filter.Topic0 == (common.Hash{}) {
+		filter.Topic0 = EventHash
+	}
+	return filter.Find(logs)
+}
+
+// encodeLogs returns the rlp encoded matched log, or all the logs of the receipt
+// when no log was matched because the proof was not filtered.
+func encodeLogs(lg *chains.MatchedLog, receipt *ethtypes.Receipt) ([]byte, error) {
+	if lg == nil {
+		return rlp.EncodeToBytes(receipt.Logs)
+	}
+	return rlp.EncodeToBytes(lg)
+}
 
 func (v *Verify) getReceiptsRoot(db types.StateDB, blockNumber uint64) (common.Hash, error) {
-	hs := NewHeaderStore()
-	if err := hs.Load(db); err != nil {
+	hs := NewHeaderStoreAt(v.address)
+	loadErr := hs.Load(db)
+
+	// post-merge blocks are only known to the eth2 light client store, the
+	// confirmations of the header store apply on top of beacon finality
+	if chainID, ok := chains.ChainType2BeaconChainID(v.chain); ok {
+		root, err := eth2.GetExecutionReceiptsRoot(db, chainID, blockNumber, hs.Confirmations)
+		if err == nil {
+			return root, nil
+		}
+		if !errors.Is(err, eth2.ErrUnknownExecution) && !errors.Is(err, eth2.ErrStoreNotInitialized) {
+			return common.Hash{}, err
+		}
+	}
+
+	if loadErr != nil {
+		return common.Hash{}, loadErr
+	}
+	if err := hs.checkFinalized(blockNumber); err != nil {
 		return common.Hash{}, err
 	}
 	header := hs.GetHeaderByNumber(blockNumber, db)
-	if header == nil {
+	if header == nil || header.Number.Uint64() != blockNumber {
 		return common.Hash{}, fmt.Errorf("get header by number failed, number: %d", blockNumber)
 	}
 
@@ -84,15 +234,19 @@ func (v *Verify) getReceiptsRoot(db types.StateDB, blockNumber uint64) (common.H
 }
 
 func (v *Verify) verifyProof(receiptsRoot common.Hash, txProve *TxProve) error {
+	return v.verifyReceipt(receiptsRoot, txProve.Receipt, txProve.TxIndex, txProve.Prove.NodeSet())
+}
+
+func (v *Verify) verifyReceipt(receiptsRoot common.Hash, receipt *ethtypes.Receipt, txIndex uint, nodes ethdb.KeyValueReader) error {
 	var buf bytes.Buffer
-	rs := ethtypes.Receipts{txProve.Receipt}
+	rs := ethtypes.Receipts{receipt}
 	rs.EncodeIndex(0, &buf)
 	giveReceipt := buf.Bytes()
 
 	var key []byte
-	key = rlp.AppendUint64(key[:0], uint64(txProve.TxIndex))
+	key = rlp.AppendUint64(key[:0], uint64(txIndex))
 
-	getReceipt, err := trie.VerifyProof(receiptsRoot, key, txProve.Prove.NodeSet())
+	getReceipt, err := trie.VerifyProof(receiptsRoot, key, nodes)
 	if err != nil {
 		return err
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/mapprotocol/atlas/core/rawdb"
 	"log"
 	"math/big"
@@ -18,6 +19,8 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
 	"github.com/mapprotocol/atlas/core/state"
 )
 
@@ -163,6 +166,7 @@ func getTxProve(blockNumber uint64, txIndex uint, receiptsJSON string) []byte {
 func TestVerify_Verify(t *testing.T) {
 	type args struct {
 		router       common.Address
+		topic0       common.Hash
 		srcChain     *big.Int
 		dstChain     *big.Int
 		blockNumber  uint64
@@ -181,6 +185,7 @@ func TestVerify_Verify(t *testing.T) {
 			name: "",
 			args: args{
 				router:       common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6"),
+				topic0:       common.HexToHash("0x155e433be3576195943c515e1096620bc754e11b3a4b60fda7c4628caf373635"),
 				srcChain:     big.NewInt(10),
 				dstChain:     big.NewInt(211),
 				blockNumber:  273,
@@ -201,8 +206,12 @@ func TestVerify_Verify(t *testing.T) {
 
 			//set := flag.NewFlagSet("test", 0)
 			//chainsdb.NewStoreDb(cli.NewContext(nil, set, nil), 10, 2)
+			storeLightClient(t, tt.args.statedb, tt.args.blockNumber)
+			if err := eth2.StoreExecutionBlock(tt.args.statedb, &eth2.ExecutionBlock{Number: tt.args.blockNumber, ReceiptsRoot: tt.wantReceiptHash}); err != nil {
+				t.Fatal(err)
+			}
 			txProve := getTxProve(tt.args.blockNumber, tt.args.txIndex, tt.args.receiptsJSON)
-			if _, err := new(Verify).Verify(tt.args.statedb, tt.args.router, txProve); (err != nil) != tt.wantErr {
+			if _, err := NewChainVerify(chains.ChainTypeETH).Verify(tt.args.statedb, &chains.LogFilter{Emitter: tt.args.router, Topic0: tt.args.topic0}, txProve); (err != nil) != tt.wantErr {
 				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -223,3 +232,191 @@ func TestKey(t *testing.T) {
 		}
 	}
 }
+
+var testEmitter = common.HexToAddress("0x0000000000000000000000000000000000000a11")
+
+// makeReceipts returns receipts whose second log is a mapTransferOut of testEmitter.
+func makeReceipts(n int) []*types.Receipt {
+	receipts := make([]*types.Receipt, 0, n)
+	for i := 0; i < n; i++ {
+		r := types.NewReceipt(nil, false, uint64(21000*(i+1)))
+		r.Logs = []*types.Log{
+			{Address: common.BigToAddress(big.NewInt(int64(i + 1))), Topics: []common.Hash{EventHash}},
+			{Address: testEmitter, Topics: []common.Hash{EventHash, common.BigToHash(big.NewInt(int64(i)))}, Data: []byte{byte(i)}},
+		}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+		receipts = append(receipts, r)
+	}
+	return receipts
+}
+
+func getBatchTxProve(t *testing.T, blockNumber uint64, receipts []*types.Receipt, txIndexes ...uint) (common.Hash, *BatchTxProve) {
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range receipts {
+		var buf bytes.Buffer
+		types.Receipts(receipts).EncodeIndex(i, &buf)
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, common.CopyBytes(buf.Bytes()))
+	}
+
+	proof := light.NewNodeSet()
+	prove := &BatchTxProve{BlockNumber: blockNumber}
+	for _, idx := range txIndexes {
+		key, _ := rlp.EncodeToBytes(idx)
+		if err := tr.Prove(key, 0, proof); err != nil {
+			t.Fatal(err)
+		}
+		prove.Receipts = append(prove.Receipts, ReceiptProve{Receipt: receipts[idx], TxIndex: idx})
+	}
+	prove.Prove = proof.NodeList()
+	return tr.Hash(), prove
+}
+
+func TestVerify_VerifyBatch(t *testing.T) {
+	const blockNumber = 16_000_000
+	receipts := makeReceipts(40)
+	root, prove := getBatchTxProve(t, blockNumber, receipts, 1, 7, 20, 33)
+
+	db := getStateDB()
+	input, err := rlp.EncodeToBytes(prove)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewChainVerify(chains.ChainTypeETH).VerifyBatch(db, &chains.LogFilter{Emitter: testEmitter}, input); err == nil {
+		t.Fatal("VerifyBatch() expected error for unknown block")
+	}
+	storeLightClient(t, db, blockNumber)
+	if err := eth2.StoreExecutionBlock(db, &eth2.ExecutionBlock{Number: blockNumber, ReceiptsRoot: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, errs, err := NewChainVerify(chains.ChainTypeETH).VerifyBatch(db, &chains.LogFilter{Emitter: testEmitter}, input)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	for i, r := range prove.Receipts {
+		if errs[i] != nil {
+			t.Fatalf("receipt %d: %v", r.TxIndex, errs[i])
+		}
+		want, _ := rlp.EncodeToBytes(&chains.MatchedLog{Index: 1, Log: receipts[r.TxIndex].Logs[1]})
+		if !bytes.Equal(logs[i], want) {
+			t.Errorf("receipt %d: logs mismatch", r.TxIndex)
+		}
+	}
+
+	// a wrong receipt only fails its own entry
+	bad := *prove
+	bad.Receipts = append([]ReceiptProve{}, prove.Receipts...)
+	bad.Receipts[1] = ReceiptProve{Receipt: receipts[8], TxIndex: 7}
+	input, _ = rlp.EncodeToBytes(&bad)
+	_, errs, err = NewChainVerify(chains.ChainTypeETH).VerifyBatch(db, &chains.LogFilter{Emitter: testEmitter}, input)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	for i := range errs {
+		if (errs[i] != nil) != (i == 1) {
+			t.Errorf("receipt %d: unexpected result %v", i, errs[i])
+		}
+	}
+
+	// the shared node set must not repeat nodes
+	bad = *prove
+	bad.Prove = append(append(light.NodeList{}, prove.Prove...), prove.Prove[0])
+	input, _ = rlp.EncodeToBytes(&bad)
+	if _, _, err := NewChainVerify(chains.ChainTypeETH).VerifyBatch(db, &chains.LogFilter{Emitter: testEmitter}, input); err == nil {
+		t.Fatal("VerifyBatch() expected error for duplicated nodes")
+	}
+
+	// the size counts every node the verifier gets to see
+	input, _ = rlp.EncodeToBytes(prove)
+	receiptCount, nodes, size, err := BatchProofSize(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSize := 0
+	for _, r := range prove.Receipts {
+		enc, _ := rlp.EncodeToBytes(r)
+		wantSize += len(enc)
+	}
+	for _, node := range prove.Prove {
+		enc, _ := rlp.EncodeToBytes(node)
+		wantSize += len(enc)
+	}
+	if receiptCount != len(prove.Receipts) || nodes != len(prove.Prove) || size != wantSize {
+		t.Errorf("BatchProofSize() = %d, %d, %d, want %d, %d, %d", receiptCount, nodes, size, len(prove.Receipts), len(prove.Prove), wantSize)
+	}
+	if _, _, _, err := BatchProofSize(input[:len(input)-1]); err == nil {
+		t.Error("BatchProofSize() expected error for a truncated proof")
+	}
+}
+
+func TestVerify_VerifyLogFilter(t *testing.T) {
+	const blockNumber = 16_000_001
+	receipts := makeReceipts(3)
+	root, batch := getBatchTxProve(t, blockNumber, receipts, 2)
+	db := getStateDB()
+	storeLightClient(t, db, blockNumber)
+	if err := eth2.StoreExecutionBlock(db, &eth2.ExecutionBlock{Number: blockNumber, ReceiptsRoot: root}); err != nil {
+		t.Fatal(err)
+	}
+	txProve, err := rlp.EncodeToBytes(&TxProve{
+		Receipt:     receipts[2],
+		Prove:       batch.Prove,
+		BlockNumber: blockNumber,
+		TxIndex:     2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := common.BigToAddress(big.NewInt(3))
+	tests := []struct {
+		name      string
+		filter    *chains.LogFilter
+		wantIndex uint
+		wantErr   bool
+	}{
+		{name: "default-topic", filter: &chains.LogFilter{Emitter: testEmitter}, wantIndex: 1},
+		{name: "explicit-topic", filter: &chains.LogFilter{Emitter: testEmitter, Topic0: EventHash}, wantIndex: 1},
+		{name: "first-log", filter: &chains.LogFilter{Emitter: other}, wantIndex: 0},
+		{name: "wrong-topic", filter: &chains.LogFilter{Emitter: testEmitter, Topic0: common.HexToHash("0x01")}, wantErr: true},
+		{name: "wrong-emitter", filter: &chains.LogFilter{Emitter: common.HexToAddress("0x02")}, wantErr: true},
+		{name: "unfiltered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewChainVerify(chains.ChainTypeETH).Verify(db, tt.filter, txProve)
+			if tt.wantErr {
+				var notFound *chains.LogNotFoundError
+				if !errors.As(err, &notFound) {
+					t.Fatalf("Verify() error = %v, want *chains.LogNotFoundError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if tt.filter == nil {
+				var logs []*types.Log
+				if err := rlp.DecodeBytes(data, &logs); err != nil {
+					t.Fatal(err)
+				}
+				if len(logs) != len(receipts[2].Logs) {
+					t.Fatalf("Verify() returned %d logs, want all %d of the receipt", len(logs), len(receipts[2].Logs))
+				}
+				return
+			}
+			var got chains.MatchedLog
+			if err := rlp.DecodeBytes(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			want := receipts[2].Logs[tt.wantIndex]
+			if got.Index != tt.wantIndex || got.Log.Address != want.Address || !bytes.Equal(got.Log.Data, want.Data) {
+				t.Errorf("Verify() = #%d %v, want #%d %v", got.Index, got.Log.Address, tt.wantIndex, want.Address)
+			}
+		})
+	}
+}
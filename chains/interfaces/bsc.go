@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/bsc"
+)
+
+func init() {
+	// BSC has no default activation block, networks enable it through
+	// params.ChainConfig.ChainGroupBlocks.
+	RegisterChainGroup(&ChainGroupRegistration{
+		Group:          chains.ChainGroupBSC,
+		Chains:         []chains.ChainType{chains.ChainTypeBSC, chains.ChainTypeBSCTest},
+		NewValidate:    func() IValidate { return new(bsc.Validate) },
+		NewHeaderStore: func() IHeaderStore { return bsc.NewHeaderStore() },
+		NewVerify:      func(chains.ChainType) IVerify { return new(bsc.Verify) },
+	})
+}
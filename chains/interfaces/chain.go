@@ -1,16 +1,13 @@
 package interfaces
 
 import (
-	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/mapprotocol/atlas/chains"
-	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/params"
-
 )
 
 type IChain interface {
@@ -44,13 +41,12 @@ func (c *Chain) GetHashByNumber(db types.StateDB, number uint64) (common.Hash, e
 }
 
 func ChainFactory(group chains.ChainGroup) (IChain, error) {
-	switch group {
-	case chains.ChainGroupETH:
-		return &Chain{
-			Validate:    new(ethereum.Validate),
-			HeaderStore: new(ethereum.HeaderStore),
-		}, nil
+	reg, err := lookupChainGroup(group)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, errors.New("not support chain")
+	return &Chain{
+		Validate:    reg.NewValidate(),
+		HeaderStore: reg.NewHeaderStore(),
+	}, nil
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"math/big"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+)
+
+func init() {
+	RegisterChainGroup(&ChainGroupRegistration{
+		Group:           chains.ChainGroupETH,
+		Chains:          []chains.ChainType{chains.ChainTypeETH, chains.ChainTypeETHTest},
+		ActivationBlock: big.NewInt(0),
+		NewValidate:     func() IValidate { return new(ethereum.Validate) },
+		NewHeaderStore:  func() IHeaderStore { return new(ethereum.HeaderStore) },
+		NewVerify:       func(chain chains.ChainType) IVerify { return ethereum.NewChainVerify(chain) },
+	})
+}
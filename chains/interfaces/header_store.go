@@ -23,10 +23,30 @@ type IHeaderStore interface {
 	GetHashByNumber(db types.StateDB, number uint64) (common.Hash, error)
 }
 
+// IFinality is implemented by header stores that only accept receipt proofs of
+// headers buried under a confirmation depth.
+type IFinality interface {
+	SetConfirmations(db types.StateDB, confirmations uint64) error
+	GetFinalizedNumberAndHash(db types.StateDB) (number uint64, hash common.Hash, confirmations uint64, err error)
+	// FinalizeCount returns the number of headers SetConfirmations walks to
+	// move the finalized pointer to the given depth.
+	FinalizeCount(db types.StateDB, confirmations uint64) (uint64, error)
+}
+
+// IHeaderReader is implemented by header stores that keep the full headers of
+// the source chain, it backs the header RPC API.
+type IHeaderReader interface {
+	GetHeader(hash common.Hash, number uint64, db types.StateDB) *ethereum.Header
+	GetHeaderByNumber(number uint64, db types.StateDB) *ethereum.Header
+	GetHeaderByHash(hash common.Hash, number uint64, db types.StateDB) *ethereum.Header
+	GetTd(hash common.Hash, number uint64, db types.StateDB) *big.Int
+	ReadCanonicalHash(number uint64, db types.StateDB) common.Hash
+}
+
 func HeaderStoreFactory(group chains.ChainGroup) (IHeaderStore, error) {
-	switch group {
-	case chains.ChainGroupETH:
-		return new(ethereum.HeaderStore), nil
+	reg, err := lookupChainGroup(group)
+	if err != nil {
+		return nil, err
 	}
-	return nil, chains.ErrNotSupportChain
+	return reg.NewHeaderStore(), nil
 }
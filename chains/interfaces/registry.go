@@ -0,0 +1,72 @@
+package interfaces
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// ChainGroupRegistration binds a source chain group to the implementations
+// used by the header store and tx verify contracts.
+type ChainGroupRegistration struct {
+	Group  chains.ChainGroup
+	Chains []chains.ChainType // source chains whose headers are kept by this group
+
+	// ActivationBlock is the atlas block from which the group is accepted when
+	// params.ChainConfig.ChainGroupBlocks has no entry for it (nil = never).
+	ActivationBlock *big.Int
+
+	NewValidate    func() IValidate
+	NewHeaderStore func() IHeaderStore
+	NewVerify      func(chain chains.ChainType) IVerify
+}
+
+var chainGroups = make(map[chains.ChainGroup]*ChainGroupRegistration)
+
+// RegisterChainGroup makes a chain group available to the factories in this
+// package. It is meant to be called from init and panics on duplicates.
+func RegisterChainGroup(reg *ChainGroupRegistration) {
+	if _, ok := chainGroups[reg.Group]; ok {
+		panic(fmt.Sprintf("chain group %d registered twice", reg.Group))
+	}
+	if reg.NewValidate == nil || reg.NewHeaderStore == nil || reg.NewVerify == nil {
+		panic(fmt.Sprintf("chain group %d registered without implementation", reg.Group))
+	}
+	for _, c := range reg.Chains {
+		chains.RegisterChainType(c, reg.Group)
+	}
+	chainGroups[reg.Group] = reg
+}
+
+func lookupChainGroup(group chains.ChainGroup) (*ChainGroupRegistration, error) {
+	reg, ok := chainGroups[group]
+	if !ok {
+		return nil, chains.ErrNotSupportChain
+	}
+	return reg, nil
+}
+
+// IsChainGroupActive returns whether the group is registered and enabled by
+// the chain config at the given atlas block.
+func IsChainGroupActive(config *params.ChainConfig, group chains.ChainGroup, num *big.Int) bool {
+	reg, ok := chainGroups[group]
+	if !ok {
+		return false
+	}
+	return config.IsChainGroupActive(uint64(group), reg.ActivationBlock, num)
+}
+
+// ActiveChainGroup resolves the group of a source chain and checks that it is
+// enabled at the given atlas block.
+func ActiveChainGroup(config *params.ChainConfig, chain chains.ChainType, num *big.Int) (chains.ChainGroup, error) {
+	group, err := chains.ChainType2ChainGroup(chain)
+	if err != nil {
+		return 0, err
+	}
+	if !IsChainGroupActive(config, group, num) {
+		return 0, chains.ErrNotSupportChain
+	}
+	return group, nil
+}
@@ -0,0 +1,71 @@
+package interfaces
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func TestEthereumRegistered(t *testing.T) {
+	for _, c := range []chains.ChainType{chains.ChainTypeETH, chains.ChainTypeETHTest} {
+		if !chains.IsSupportedChain(c) {
+			t.Fatalf("chain %d is not supported", c)
+		}
+		group, err := chains.ChainType2ChainGroup(c)
+		if err != nil || group != chains.ChainGroupETH {
+			t.Fatalf("chain %d: group mismatch, have %d, err %v", c, group, err)
+		}
+	}
+
+	hs, err := HeaderStoreFactory(chains.ChainGroupETH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hs.(*ethereum.HeaderStore); !ok {
+		t.Fatalf("unexpected header store type %T", hs)
+	}
+	v, err := VerifyFactory(chains.ChainGroupETH, chains.ChainTypeETH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(*ethereum.Verify); !ok {
+		t.Fatalf("unexpected verify type %T", v)
+	}
+	if _, err := ChainFactory(chains.ChainGroupMAP); err != chains.ErrNotSupportChain {
+		t.Fatalf("expected %v, have %v", chains.ErrNotSupportChain, err)
+	}
+}
+
+func TestChainGroupActivation(t *testing.T) {
+	var (
+		group  = chains.ChainGroup(chains.ChainGroupETH)
+		config = &params.ChainConfig{}
+	)
+	if !IsChainGroupActive(config, group, big.NewInt(0)) {
+		t.Fatal("ethereum group should be active by default")
+	}
+
+	config.ChainGroupBlocks = map[uint64]*big.Int{uint64(group): big.NewInt(100)}
+	if IsChainGroupActive(config, group, big.NewInt(99)) {
+		t.Fatal("group active before its fork block")
+	}
+	if !IsChainGroupActive(config, group, big.NewInt(100)) {
+		t.Fatal("group inactive at its fork block")
+	}
+	if _, err := ActiveChainGroup(config, chains.ChainTypeETH, big.NewInt(99)); err != chains.ErrNotSupportChain {
+		t.Fatalf("expected %v, have %v", chains.ErrNotSupportChain, err)
+	}
+	if IsChainGroupActive(config, chains.ChainGroupMAP, big.NewInt(100)) {
+		t.Fatal("unregistered group reported active")
+	}
+	if IsChainGroupActive(config, chains.ChainGroupBSC, big.NewInt(100)) {
+		t.Fatal("bsc group must be enabled explicitly")
+	}
+	config.ChainGroupBlocks[chains.ChainGroupBSC] = big.NewInt(0)
+	if group, err := ActiveChainGroup(config, chains.ChainTypeBSC, big.NewInt(100)); err != nil || group != chains.ChainGroupBSC {
+		t.Fatalf("bsc group not resolved, have %d, err %v", group, err)
+	}
+}
@@ -1,21 +1,29 @@
 package interfaces
 
 import (
-	"github.com/ethereum/go-ethereum/common"
-
 	"github.com/mapprotocol/atlas/chains"
-	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 )
 
+// IVerify proves a receipt of the source chain and returns the rlp encoded
+// chains.MatchedLog selected by filter, a *chains.LogNotFoundError is returned
+// when the receipt has no such log. Before the log filter fork filter is nil and
+// all the logs of the receipt are returned.
 type IVerify interface {
-	Verify(db types.StateDB, router common.Address, txProveBytes []byte) (logs []byte, err error)
+	Verify(db types.StateDB, filter *chains.LogFilter, txProveBytes []byte) (logs []byte, err error)
+}
+
+// IBatchVerify is implemented by verifiers that prove several receipts of one
+// block with a shared proof. Failures of single receipts are reported in errs.
+type IBatchVerify interface {
+	VerifyBatch(db types.StateDB, filter *chains.LogFilter, batchProveBytes []byte) (logs [][]byte, errs []error, err error)
 }
 
-func VerifyFactory(group chains.ChainGroup) (IVerify, error) {
-	switch group {
-	case chains.ChainGroupETH:
-		return new(ethereum.Verify), nil
+// VerifyFactory returns the receipt verifier of a source chain of the group.
+func VerifyFactory(group chains.ChainGroup, chain chains.ChainType) (IVerify, error) {
+	reg, err := lookupChainGroup(group)
+	if err != nil {
+		return nil, err
 	}
-	return nil, chains.ErrNotSupportChain
+	return reg.NewVerify(chain), nil
 }
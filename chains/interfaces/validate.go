@@ -2,7 +2,6 @@ package interfaces
 
 import (
 	"github.com/mapprotocol/atlas/chains"
-	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 )
 
@@ -11,11 +10,11 @@ type IValidate interface {
 }
 
 func ValidateFactory(group chains.ChainGroup) (IValidate, error) {
-	switch group {
-	case chains.ChainGroupETH:
-		return new(ethereum.Validate), nil
+	reg, err := lookupChainGroup(group)
+	if err != nil {
+		return nil, err
 	}
-	return nil, chains.ErrNotSupportChain
+	return reg.NewValidate(), nil
 }
 
 
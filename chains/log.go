@@ -0,0 +1,34 @@
+package chains
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogFilter selects the log of a proven receipt that is handed to the caller.
+type LogFilter struct {
+	Emitter common.Address
+	Topic0  common.Hash
+}
+
+// Match reports whether lg was emitted by f.Emitter with f.Topic0 as first topic.
+func (f *LogFilter) Match(lg *ethtypes.Log) bool {
+	return lg.Address == f.Emitter && len(lg.Topics) > 0 && lg.Topics[0] == f.Topic0
+}
+
+// Find returns the first log matching the filter and its index in logs.
+func (f *LogFilter) Find(logs []*ethtypes.Log) (*MatchedLog, error) {
+	for i, lg := range logs {
+		if f.Match(lg) {
+			return &MatchedLog{Index: uint(i), Log: lg}, nil
+		}
+	}
+	return nil, &LogNotFoundError{Emitter: f.Emitter, Topic0: f.Topic0}
+}
+
+// MatchedLog is the log a receipt proof resolves to, Index is its position in
+// the receipt.
+type MatchedLog struct {
+	Index uint
+	Log   *ethtypes.Log
+}
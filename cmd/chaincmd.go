@@ -119,6 +119,7 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 			utils.DataDirFlag,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
+			utils.EpochRewardsFileFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -126,7 +127,10 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
-be gzipped.`,
+be gzipped.
+
+With --epochrewards, the rewards recorded for the epochs ending
+in the exported blocks are written to that file as well.`,
 	}
 	importPreimagesCommand = cli.Command{
 		Action:    utils.MigrateFlags(importPreimages),
@@ -314,32 +318,41 @@ func exportChain(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
 
-	chain, _ := utils.MakeChain(ctx, stack)
+	chain, db := utils.MakeChain(ctx, stack)
 	start := time.Now()
 
-	var err error
+	var (
+		err         error
+		first, last = uint64(0), chain.CurrentBlock().NumberU64()
+	)
 	fp := ctx.Args().First()
 	if len(ctx.Args()) < 3 {
 		err = utils.ExportChain(chain, fp)
 	} else {
 		// This can be improved to allow for numbers larger than 9223372036854775807
-		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
-		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		ffirst, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		flast, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
 		if ferr != nil || lerr != nil {
 			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
 		}
-		if first < 0 || last < 0 {
+		if ffirst < 0 || flast < 0 {
 			utils.Fatalf("Export error: block number must be greater than 0\n")
 		}
-		if head := chain.CurrentFastBlock(); uint64(last) > head.NumberU64() {
-			utils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head.NumberU64())
+		if head := chain.CurrentFastBlock(); uint64(flast) > head.NumberU64() {
+			utils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(flast), head.NumberU64())
 		}
-		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+		first, last = uint64(ffirst), uint64(flast)
+		err = utils.ExportAppendChain(chain, fp, first, last)
 	}
 
 	if err != nil {
 		utils.Fatalf("Export error: %v\n", err)
 	}
+	if fn := ctx.String(utils.EpochRewardsFileFlag.Name); fn != "" {
+		if err := utils.ExportEpochRewards(db, fn, first, last); err != nil {
+			utils.Fatalf("Export error: %v\n", err)
+		}
+	}
 	fmt.Printf("Export done in %v\n", time.Since(start))
 	return nil
 }
@@ -144,6 +144,7 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerThreadsFlag,
 		utils.MinerGasPriceFlag,
+		utils.IstanbulReplicaFailoverBlocksFlag,
 	}
 
 	rpcFlags = []cli.Flag{
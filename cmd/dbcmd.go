@@ -17,11 +17,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -31,7 +35,12 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/mapprotocol/atlas/cmd/utils"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -62,6 +71,7 @@ Remove blockchain and state databases`,
 			dbPutCmd,
 			dbGetSlotsCmd,
 			dbDumpFreezerIndex,
+			dbUptimeCmd,
 		},
 	}
 	dbInspectCmd = cli.Command{
@@ -171,6 +181,48 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		},
 		Description: "This command displays information about the freezer index.",
 	}
+	dbUptimeCmd = cli.Command{
+		Action: utils.MigrateFlags(dbUptime),
+		Name:   "uptime",
+		Usage:  "Show the validators signing activity and uptime of an epoch or block range",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.SyncModeFlag,
+			utils.MainnetFlag,
+			utils.TestnetFlag,
+			uptimeEpochFlag,
+			uptimeFromFlag,
+			uptimeToFlag,
+			uptimeLookbackFlag,
+			uptimeJSONFlag,
+		},
+		Description: `This command reads the headers of a stopped node and decodes the parent
+aggregated seal bitmap of every block to count, per validator, the blocks signed and
+missed and the longest run of missed blocks. The uptime score is computed like the
+node does at the end of an epoch. The lookback window defaults to the one of the
+chain config; pass --lookback if it was changed in the blockchain parameters.`,
+	}
+
+	uptimeEpochFlag = cli.Uint64Flag{
+		Name:  "epoch",
+		Usage: "Epoch to inspect",
+	}
+	uptimeFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to inspect",
+	}
+	uptimeToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to inspect (defaults to the head block)",
+	}
+	uptimeLookbackFlag = cli.Uint64Flag{
+		Name:  "lookback",
+		Usage: "Lookback window used for the uptime score (defaults to the chain config)",
+	}
+	uptimeJSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as JSON",
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -483,3 +535,287 @@ func freezerInspect(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+func dbUptime(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil || config.Istanbul == nil {
+		return errors.New("istanbul chain config not found in the database")
+	}
+	epochSize, lookbackWindow := config.Istanbul.Epoch, config.Istanbul.LookbackWindow
+	if ctx.IsSet(uptimeLookbackFlag.Name) {
+		lookbackWindow = ctx.Uint64(uptimeLookbackFlag.Name)
+	}
+
+	var from, to uint64
+	switch {
+	case ctx.IsSet(uptimeEpochFlag.Name):
+		epoch := ctx.Uint64(uptimeEpochFlag.Name)
+		if epoch == 0 {
+			return errors.New("epoch 0 only holds the genesis block")
+		}
+		from, _ = istanbul.GetEpochFirstBlockNumber(epoch, epochSize)
+		to = istanbul.GetEpochLastBlockNumber(epoch, epochSize)
+	case ctx.IsSet(uptimeFromFlag.Name):
+		from = ctx.Uint64(uptimeFromFlag.Name)
+		if ctx.IsSet(uptimeToFlag.Name) {
+			to = ctx.Uint64(uptimeToFlag.Name)
+		} else {
+			number := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db))
+			if number == nil {
+				return errors.New("head header not found in the database")
+			}
+			to = *number
+		}
+	default:
+		return fmt.Errorf("required flags: --%s or --%s", uptimeEpochFlag.Name, uptimeFromFlag.Name)
+	}
+
+	epochs, err := readValidatorsUptime(db, epochSize, lookbackWindow, from, to)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(uptimeJSONFlag.Name) {
+		out, err := json.MarshalIndent(epochs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	for _, e := range epochs {
+		state := "complete"
+		if e.To != istanbul.GetEpochLastBlockNumber(e.Epoch, epochSize) {
+			state = "partial"
+		}
+		fmt.Printf("Epoch %d, blocks %d-%d (%s)\n", e.Epoch, e.From, e.To, state)
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tADDRESS\tSIGNED\tMISSED\tMISSED STREAK\tMAX MISSED STREAK\tUP BLOCKS\tMISSED WINDOWS\tUPTIME")
+		for _, v := range e.Validators {
+			score := "-"
+			if v.Uptime != nil {
+				score = fmt.Sprintf("%.4f", *v.Uptime)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", v.Index, v.Address.Hex(), v.Signed, v.Missed,
+				v.MissedStreak, v.MaxMissedStreak, v.UpBlocks, v.MissedWindows, score)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+	return nil
+}
+
+// validatorUptime is the signing activity of a validator over the blocks of
+// an epoch that were inspected.
+type validatorUptime struct {
+	Index           int            `json:"index"`
+	Address         common.Address `json:"address"`
+	Signed          uint64         `json:"signed"`
+	Missed          uint64         `json:"missed"`
+	MissedStreak    uint64         `json:"missedStreak"`
+	MaxMissedStreak uint64         `json:"maxMissedStreak"`
+	UpBlocks        uint64         `json:"upBlocks"`
+	MissedWindows   uint64         `json:"missedWindows"`
+	// Uptime is the score the epoch rewards are computed with, as a fraction
+	// of the monitoring window. It is nil if it could not be computed.
+	Uptime *float64 `json:"uptime"`
+}
+
+type epochUptime struct {
+	Epoch      uint64             `json:"epoch"`
+	From       uint64             `json:"from"`
+	To         uint64             `json:"to"`
+	Validators []*validatorUptime `json:"validators"`
+}
+
+// memoryUptimeStore keeps the accumulated uptimes in memory so inspecting a
+// database never writes to it.
+type memoryUptimeStore map[uint64]*uptime.Uptime
+
+func (s memoryUptimeStore) ReadAccumulatedEpochUptime(epoch uint64) *uptime.Uptime {
+	return s[epoch]
+}
+
+func (s memoryUptimeStore) WriteAccumulatedEpochUptime(epoch uint64, u *uptime.Uptime) {
+	s[epoch] = u
+}
+
+// readValidatorsUptime tallies the signers of the blocks from..to, which are
+// found in the parent aggregated seal of the block after each of them, and
+// the uptime of the validators for every epoch of the range. The range ends
+// early at the last block whose child is in the database.
+func readValidatorsUptime(db ethdb.Reader, epochSize, lookbackWindow, from, to uint64) ([]*epochUptime, error) {
+	if from == 0 || from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	var (
+		valSets = &epochValidatorSets{db: db, epochSize: epochSize}
+		store   = make(memoryUptimeStore)
+		monitor = uptime.NewMonitor(store, epochSize, lookbackWindow)
+		epochs  []*epochUptime
+	)
+	for epoch := istanbul.GetEpochNumber(from, epochSize); epoch <= istanbul.GetEpochNumber(to, epochSize); epoch++ {
+		if _, err := uptime.MonitoringWindow(epoch, epochSize, lookbackWindow); err != nil {
+			return nil, err
+		}
+		validators, err := valSets.validators(epoch)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			epochFirst, _ = istanbul.GetEpochFirstBlockNumber(epoch, epochSize)
+			epochLast     = istanbul.GetEpochLastBlockNumber(epoch, epochSize)
+			first         = epochFirst
+			last          = epochLast
+			processed     = uint64(0)
+			bitmaps       []*big.Int
+		)
+		if first < from {
+			first = from
+		}
+		if last > to {
+			last = to
+		}
+		vals := make([]*validatorUptime, len(validators))
+		for i, val := range validators {
+			vals[i] = &validatorUptime{Index: i, Address: val.Address()}
+		}
+		// The uptime counts from the start of the epoch even if the range
+		// starts later, the signing tally only covers the range.
+		for number := epochFirst + 1; number <= last+1; number++ {
+			header := readCanonicalHeader(db, number)
+			if header == nil {
+				break
+			}
+			if number <= epochLast {
+				if err := monitor.ProcessBlock(types.NewBlockWithHeader(header)); err != nil {
+					return nil, err
+				}
+			}
+			extra, err := types.ExtractIstanbulExtra(header)
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %v", number, err)
+			}
+			bitmap := extra.ParentAggregatedSeal.Bitmap
+			if bitmap == nil {
+				bitmap = new(big.Int)
+			}
+			if number <= epochLast {
+				bitmaps = append(bitmaps, bitmap)
+			}
+			if number-1 < first {
+				continue
+			}
+			for i, v := range vals {
+				if bitmap.Bit(i) == 1 {
+					v.Signed++
+					v.MissedStreak = 0
+					continue
+				}
+				v.Missed++
+				v.MissedStreak++
+				if v.MissedStreak > v.MaxMissedStreak {
+					v.MaxMissedStreak = v.MissedStreak
+				}
+			}
+			processed = number - 1
+		}
+		if processed == 0 {
+			break
+		}
+
+		if accumulated := store[epoch]; accumulated != nil {
+			for i, v := range vals {
+				if i < len(accumulated.Entries) {
+					v.UpBlocks = accumulated.Entries[i].UpBlocks
+				}
+			}
+		}
+		if downtime, err := uptime.EpochDowntime(epoch, epochSize, lookbackWindow, len(vals), bitmaps); err == nil {
+			for i, v := range vals {
+				v.MissedWindows = downtime[i].MissedWindows
+			}
+		}
+		if scores, err := monitor.ComputeValidatorsUptime(epoch, len(vals)); err == nil {
+			for i, v := range vals {
+				score, _ := new(big.Float).Quo(new(big.Float).SetInt(scores[i]), new(big.Float).SetInt(params.Fixidity1)).Float64()
+				v.Uptime = &score
+			}
+		}
+		epochs = append(epochs, &epochUptime{Epoch: epoch, From: first, To: processed, Validators: vals})
+		if processed < last {
+			break
+		}
+	}
+	if len(epochs) == 0 {
+		return nil, fmt.Errorf("no signed blocks found in range %d-%d", from, to)
+	}
+	return epochs, nil
+}
+
+func readCanonicalHeader(db ethdb.Reader, number uint64) *types.Header {
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return rawdb.ReadHeader(db, hash, number)
+}
+
+// epochValidatorSets replays the validator set diffs of the epoch headers the
+// way the istanbul snapshots do, so the bitmap indexes can be mapped to
+// validators without the snapshots of a running node.
+type epochValidatorSets struct {
+	db        ethdb.Reader
+	epochSize uint64
+
+	epoch  uint64
+	valSet istanbul.ValidatorSet
+}
+
+// validators returns the validator set of epoch. Epochs must be requested in
+// ascending order.
+func (s *epochValidatorSets) validators(epoch uint64) ([]istanbul.Validator, error) {
+	if s.valSet == nil {
+		extra, err := s.extra(0)
+		if err != nil {
+			return nil, err
+		}
+		validators, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+		if err != nil {
+			return nil, err
+		}
+		s.epoch, s.valSet = 1, validator.NewSet(validators)
+	}
+	for ; s.epoch < epoch; s.epoch++ {
+		number := istanbul.GetEpochLastBlockNumber(s.epoch, s.epochSize)
+		extra, err := s.extra(number)
+		if err != nil {
+			return nil, err
+		}
+		validators, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+		if err != nil {
+			return nil, err
+		}
+		if !s.valSet.RemoveValidators(extra.RemovedValidators) || !s.valSet.AddValidators(validators) {
+			return nil, fmt.Errorf("invalid validator set diff in block %d", number)
+		}
+	}
+	return s.valSet.List(), nil
+}
+
+func (s *epochValidatorSets) extra(number uint64) (*types.IstanbulExtra, error) {
+	header := readCanonicalHeader(s.db, number)
+	if header == nil {
+		return nil, fmt.Errorf("header %d not found", number)
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %v", number, err)
+	}
+	return extra, nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+)
+
+func TestReadValidatorsUptime(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		epochSize = uint64(10)
+		a         = common.HexToAddress("0x0a")
+		b         = common.HexToAddress("0x0b")
+		c         = common.HexToAddress("0x0c")
+		d         = common.HexToAddress("0x0d")
+	)
+	writeHeader := func(number uint64, extra *types.IstanbulExtra) {
+		if extra.RemovedValidators == nil {
+			extra.RemovedValidators = new(big.Int)
+		}
+		payload, err := rlp.EncodeToBytes(extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Extra: append(make([]byte, types.IstanbulExtraVanity), payload...)}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), number)
+	}
+	added := func(addrs ...common.Address) *types.IstanbulExtra {
+		return &types.IstanbulExtra{
+			AddedValidators:             addrs,
+			AddedValidatorsPublicKeys:   make([]blscrypto.SerializedPublicKey, len(addrs)),
+			AddedValidatorsG1PublicKeys: make([]blscrypto.SerializedG1PublicKey, len(addrs)),
+		}
+	}
+
+	// c never signs and is replaced by d at the end of epoch 1, b misses blocks 3 to 5
+	writeHeader(0, added(a, b, c))
+	for number := uint64(1); number <= 21; number++ {
+		extra := &types.IstanbulExtra{}
+		if number == 10 {
+			extra = added(d)
+			extra.RemovedValidators = big.NewInt(4)
+		}
+		if parent := number - 1; parent > 0 {
+			bitmap := big.NewInt(1)
+			if parent < 3 || parent > 5 {
+				bitmap.SetBit(bitmap, 1, 1)
+			}
+			if parent > 10 {
+				bitmap.SetBit(bitmap, 2, 1)
+			}
+			extra.ParentAggregatedSeal.Bitmap = bitmap
+		}
+		writeHeader(number, extra)
+	}
+
+	epochs, err := readValidatorsUptime(db, epochSize, 3, 2, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(epochs) != 2 {
+		t.Fatalf("epochs = %d, want 2", len(epochs))
+	}
+	if e := epochs[0]; e.From != 2 || e.To != 10 {
+		t.Errorf("epoch 1 range = %d-%d, want 2-10", e.From, e.To)
+	}
+	if e := epochs[1]; e.From != 11 || e.To != 20 {
+		t.Errorf("epoch 2 range = %d-%d, want 11-20", e.From, e.To)
+	}
+
+	want := []struct {
+		address                           common.Address
+		signed, missed, streak, maxStreak uint64
+		upBlocks                          uint64
+	}{
+		{a, 9, 0, 0, 0, 6},
+		{b, 6, 3, 0, 3, 5},
+		{c, 0, 9, 9, 9, 0},
+		{a, 10, 0, 0, 0, 6},
+		{b, 10, 0, 0, 0, 6},
+		{d, 10, 0, 0, 0, 6},
+	}
+	got := append(epochs[0].Validators, epochs[1].Validators...)
+	if len(got) != len(want) {
+		t.Fatalf("validators = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		v := got[i]
+		if v.Address != w.address || v.Signed != w.signed || v.Missed != w.missed || v.MissedStreak != w.streak || v.MaxMissedStreak != w.maxStreak || v.UpBlocks != w.upBlocks {
+			t.Errorf("validator %d = %+v, want %+v", i, *v, w)
+		}
+	}
+	if v := epochs[0].Validators[0]; v.Uptime == nil || *v.Uptime != 1 {
+		t.Errorf("uptime of %x = %v, want 1", v.Address, v.Uptime)
+	}
+	if v := epochs[0].Validators[2]; v.Uptime == nil || *v.Uptime != 0 {
+		t.Errorf("uptime of %x = %v, want 0", v.Address, v.Uptime)
+	}
+	// epoch 2 stops at block 20 as the seal of block 21 is not known yet
+	if v := epochs[1].Validators[0]; v.Uptime == nil || *v.Uptime != 1 {
+		t.Errorf("uptime of %x = %v, want 1", v.Address, v.Uptime)
+	}
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -8,6 +11,7 @@ import (
 	"github.com/mapprotocol/atlas/cmd/new_marker/connections"
 	"github.com/mapprotocol/atlas/cmd/new_marker/define"
 	"github.com/mapprotocol/atlas/cmd/new_marker/writer"
+	"github.com/mapprotocol/atlas/core/types"
 	"math/big"
 )
 
@@ -59,3 +63,29 @@ func (b *base) handleMessage(rpcAddr string, msg writer.Message) {
 	go w.ResolveMessage(msg)
 	b.waitUntilMsgHandled(1)
 }
+
+// errReadOnlyRunner is returned by the write calls of a callRunner
+var errReadOnlyRunner = errors.New("read only runner")
+
+// callRunner runs the queries of the contract wrappers as eth_call on the latest
+// block of the node it is connected to.
+type callRunner struct {
+	conn *ethclient.Client
+}
+
+func (r callRunner) Execute(recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return nil, errReadOnlyRunner
+}
+
+func (r callRunner) ExecuteFrom(sender, recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return nil, errReadOnlyRunner
+}
+
+func (r callRunner) Query(recipient common.Address, input []byte, gas uint64) ([]byte, error) {
+	return r.conn.CallContract(context.Background(), ethereum.CallMsg{To: &recipient, Data: input, Gas: gas}, nil)
+}
+
+func (r callRunner) StopGasMetering()  {}
+func (r callRunner) StartGasMetering() {}
+
+func (r callRunner) GetStateDB() types.StateDB { return nil }
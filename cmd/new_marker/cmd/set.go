@@ -117,6 +117,18 @@ func init() {
 			Action: MigrateFlags(validator.DeregisterValidator),
 			Flags:  define.MustFlagCombination,
 		},
+		{
+			Name:   "unjail",
+			Usage:  "Unjail the signer of the validator once its jail term is over",
+			Action: MigrateFlags(validator.Unjail),
+			Flags:  define.MustFlagCombination,
+		},
+		{
+			Name:   "getJailStatus",
+			Usage:  "Returns whether `target` validator is jailed and the epoch it can be unjailed from",
+			Action: MigrateFlags(validator.GetJailStatus),
+			Flags:  define.BaseFlagCombination,
+		},
 		{
 			Name:   "quicklyRegister",
 			Usage:  "Register validator",
@@ -328,7 +340,7 @@ func init() {
 			Name:   "updateBlsPublicKey",
 			Usage:  "UpdateBlsPublicKey",
 			Action: MigrateFlags(voter.updateBlsPublicKey),
-			Flags:  define.MustFlagCombination,
+			Flags:  append(define.MustFlagCombination, define.BLS12381Flag),
 		},
 		{
 			Name:   "setNextCommissionUpdate",
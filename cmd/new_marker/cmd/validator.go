@@ -12,6 +12,7 @@ import (
 	"github.com/mapprotocol/atlas/cmd/marker/account"
 	"github.com/mapprotocol/atlas/cmd/new_marker/define"
 	"github.com/mapprotocol/atlas/cmd/new_marker/mapprotocol"
+	"github.com/mapprotocol/atlas/contracts/jail"
 	"github.com/mapprotocol/atlas/helper/bls"
 	"github.com/mapprotocol/atlas/params"
 	"gopkg.in/urfave/cli.v1"
@@ -25,6 +26,8 @@ type Validator struct {
 	account                         *Account
 	to, lockGoldTo, electionTo      common.Address
 	abi, lockedGoldAbi, electionAbi *abi.ABI
+	jailTo                          common.Address
+	jailAbi                         *abi.ABI
 }
 
 func NewValidator() *Validator {
@@ -37,6 +40,8 @@ func NewValidator() *Validator {
 		electionAbi:   mapprotocol.AbiFor("Election"),
 		lockGoldTo:    mapprotocol.MustProxyAddressFor("LockedGold"),
 		lockedGoldAbi: mapprotocol.AbiFor("LockedGold"),
+		jailTo:        params.JailAddress,
+		jailAbi:       mapprotocol.AbiFor("Jail"),
 	}
 }
 
@@ -118,6 +123,28 @@ func (v *Validator) DeregisterValidator(_ *cli.Context, cfg *define.Config) erro
 	return nil
 }
 
+func (v *Validator) Unjail(_ *cli.Context, cfg *define.Config) error {
+	log.Info("=== unjail ===", "account", cfg.From)
+	v.handleType1Msg(cfg, v.jailTo, nil, v.jailAbi, "unjail")
+	return nil
+}
+
+// GetJailStatus queries the jail status of the target validator, given by its
+// signer or its account, it defaults to the keystore account.
+func (v *Validator) GetJailStatus(_ *cli.Context, cfg *define.Config) error {
+	target := cfg.TargetAddress
+	if target == params.ZeroAddress {
+		target = cfg.From
+	}
+	log.Info("=== getJailStatus ===", "target", target)
+	jailed, releaseEpoch, err := jail.GetJailStatus(callRunner{v.newConn(cfg.RPCAddr)}, target)
+	if err != nil {
+		return err
+	}
+	log.Info("getJailStatus", "jailed", jailed, "releaseEpoch", releaseEpoch)
+	return nil
+}
+
 func (v *Validator) GenerateSignerProof(_ *cli.Context, cfg *define.Config) error {
 	log.Info("generateBLSProof", "validator", cfg.AccountAddress, "signerPrivate", cfg.SignerPriv)
 	private, err := crypto.ToECDSA(common.FromHex(cfg.SignerPriv))
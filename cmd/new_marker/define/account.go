@@ -69,6 +69,29 @@ func (a *Account) BLSG1PublicKey() (bls.SerializedG1PublicKey, error) {
 	return bls.CryptoType().PrivateToG1Public(privateKey)
 }
 
+// BLS12381Keys returns the BLS12-381 public keys and the proof of possession
+// validators register from the BLS12-381 fork on
+func (a *Account) BLS12381Keys() (bls.SerializedPublicKey, bls.SerializedG1PublicKey, []byte, error) {
+	curve := bls.BLS12381{}
+	privateKey, err := curve.ECDSAToBLS(a.PrivateKey)
+	if err != nil {
+		return bls.SerializedPublicKey{}, bls.SerializedG1PublicKey{}, nil, err
+	}
+	pub, err := curve.PrivateToPublic(privateKey)
+	if err != nil {
+		return bls.SerializedPublicKey{}, bls.SerializedG1PublicKey{}, nil, err
+	}
+	g1Pub, err := curve.PrivateToG1Public(privateKey)
+	if err != nil {
+		return bls.SerializedPublicKey{}, bls.SerializedG1PublicKey{}, nil, err
+	}
+	pop, err := curve.ProofOfPossession(privateKey, a.Address.Bytes())
+	if err != nil {
+		return bls.SerializedPublicKey{}, bls.SerializedG1PublicKey{}, nil, err
+	}
+	return pub, g1Pub, pop[:], nil
+}
+
 // PublicKey hex representation of the public key
 func (a *Account) PublicKey() []byte {
 	return crypto.FromECDSAPub(&a.PrivateKey.PublicKey)
@@ -189,6 +189,12 @@ func AssemblyConfig(ctx *cli.Context) (*Config, error) {
 		config.BlsPub = blsPub
 		config.BlsG1Pub = blsG1Pub
 		config.BLSProof = _account.MustBLSProofOfPossession()
+		if ctx.Bool(BLS12381Flag.Name) {
+			config.BlsPub, config.BlsG1Pub, config.BLSProof, err = _account.BLS12381Keys()
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	ValidatorAddress := mapprotocol.MustProxyAddressFor("Validators")
@@ -136,6 +136,10 @@ var (
 		Name:  "markercfg",
 		Usage: "Marker config path",
 	}
+	BLS12381Flag = cli.BoolFlag{
+		Name:  "bls12381",
+		Usage: "Use the BLS12-381 keys and proof of possession required from the BLS12-381 fork on",
+	}
 )
 
 var TemplateFlags = []cli.Flag{
@@ -2002,6 +2002,37 @@ func init() {
       "name": "OwnershipTransferred",
       "type": "event"
     },
+    {
+      "anonymous": false,
+      "inputs": [
+        {
+          "indexed": false,
+          "internalType": "uint256",
+          "name": "requestTimeout",
+          "type": "uint256"
+        },
+        {
+          "indexed": false,
+          "internalType": "uint256",
+          "name": "timeoutBackoffFactor",
+          "type": "uint256"
+        },
+        {
+          "indexed": false,
+          "internalType": "uint256",
+          "name": "minResendRoundChangeTimeout",
+          "type": "uint256"
+        },
+        {
+          "indexed": false,
+          "internalType": "uint256",
+          "name": "maxResendRoundChangeTimeout",
+          "type": "uint256"
+        }
+      ],
+      "name": "RoundChangeTimeoutsSet",
+      "type": "event"
+    },
     {
       "anonymous": false,
       "inputs": [
@@ -2636,6 +2667,66 @@ func init() {
       "stateMutability": "view",
       "type": "function"
     },
+    {
+      "constant": false,
+      "inputs": [
+        {
+          "internalType": "uint256",
+          "name": "requestTimeout",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "timeoutBackoffFactor",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "minResendRoundChangeTimeout",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "maxResendRoundChangeTimeout",
+          "type": "uint256"
+        }
+      ],
+      "name": "setRoundChangeTimeouts",
+      "outputs": [],
+      "payable": false,
+      "stateMutability": "nonpayable",
+      "type": "function"
+    },
+    {
+      "constant": true,
+      "inputs": [],
+      "name": "getRoundChangeTimeouts",
+      "outputs": [
+        {
+          "internalType": "uint256",
+          "name": "requestTimeout",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "timeoutBackoffFactor",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "minResendRoundChangeTimeout",
+          "type": "uint256"
+        },
+        {
+          "internalType": "uint256",
+          "name": "maxResendRoundChangeTimeout",
+          "type": "uint256"
+        }
+      ],
+      "payable": false,
+      "stateMutability": "view",
+      "type": "function"
+    },
     {
       "constant": true,
       "inputs": [],
@@ -9779,6 +9870,8 @@ func init() {
       "type": "receive"
     }
   ]`) // Validators ABI
+	// Jail system contract
+	abis["Jail"] = mustParseABI(params.JailABIJSON)
 }
 
 var genesisAddresses = map[string]common.Address{
@@ -178,6 +178,12 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			//utils.MinerNoVerifyFlag,
 		},
 	},
+	{
+		Name: "ISTANBUL",
+		Flags: []cli.Flag{
+			utils.IstanbulReplicaFailoverBlocksFlag,
+		},
+	},
 	{
 		Name: "GAS PRICE ORACLE",
 		Flags: []cli.Flag{
@@ -269,6 +269,39 @@ func ExportAppendChain(blockchain *chain.BlockChain, fn string, first uint64, la
 	return nil
 }
 
+// ExportEpochRewards exports the rewards recorded for the epochs ending between
+// the first and last blocks into the specified file, truncating any data already
+// present in the file.
+func ExportEpochRewards(db ethdb.Database, fn string, first uint64, last uint64) error {
+	log.Info("Exporting epoch rewards", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the recorded epochs and export the ones in range
+	count := 0
+	for _, rewards := range rawdb.ReadAllEpochRewards(db) {
+		if rewards.Number < first || rewards.Number > last {
+			continue
+		}
+		if err := rlp.Encode(writer, rewards); err != nil {
+			return err
+		}
+		count++
+	}
+	log.Info("Exported epoch rewards", "file", fn, "epochs", count)
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 func ImportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
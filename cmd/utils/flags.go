@@ -52,6 +52,8 @@ import (
 	"github.com/mapprotocol/atlas/atlas/downloader"
 	"github.com/mapprotocol/atlas/atlas/ethconfig"
 	"github.com/mapprotocol/atlas/atlas/gasprice"
+	"github.com/mapprotocol/atlas/atlas/staking"
+	"github.com/mapprotocol/atlas/atlas/supply"
 	"github.com/mapprotocol/atlas/atlas/tracers"
 	"github.com/mapprotocol/atlas/cmd/node"
 	"github.com/mapprotocol/atlas/consensus"
@@ -189,6 +191,10 @@ var (
 		Usage: "Max number of elements (0 = no limit)",
 		Value: 0,
 	}
+	EpochRewardsFileFlag = cli.StringFlag{
+		Name:  "epochrewards",
+		Usage: "File to export the epoch reward ledger of the exported blocks to, as an RLP stream",
+	}
 	defaultSyncMode = ethconfig.Defaults.SyncMode
 	SyncModeFlag    = TextMarshalerFlag{
 		Name:  "syncmode",
@@ -389,6 +395,12 @@ var (
 		Name:  "miner.extradata",
 		Usage: "Block extra data set by the miner (default = client version)",
 	}
+	// Istanbul settings
+	IstanbulReplicaFailoverBlocksFlag = cli.Uint64Flag{
+		Name:  "istanbul.replicafailoverblocks",
+		Usage: "Number of seals the validator may miss in a row before a replica takes over and the primary steps down (0 = disabled)",
+		Value: ethconfig.Defaults.Istanbul.ReplicaFailoverBlocks,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -1273,6 +1285,12 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	//}
 }
 
+func setIstanbul(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.GlobalIsSet(IstanbulReplicaFailoverBlocksFlag.Name) {
+		cfg.Istanbul.ReplicaFailoverBlocks = ctx.GlobalUint64(IstanbulReplicaFailoverBlocksFlag.Name)
+	}
+}
+
 func setWhitelist(ctx *cli.Context, cfg *ethconfig.Config) {
 	whitelist := ctx.GlobalString(WhitelistFlag.Name)
 	if whitelist == "" {
@@ -1360,6 +1378,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setTxFeeRecipient(ctx, ks, cfg)
 	//setEthash(ctx, cfg)
 	setMiner(ctx, &cfg.Miner)
+	setIstanbul(ctx, cfg)
 	setWhitelist(ctx, cfg)
 	setLes(ctx, cfg)
 
@@ -1583,6 +1602,8 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (atlasapi.Backe
 	//	}
 	//}
 	stack.RegisterAPIs(tracers.APIs(backend.APIBackend))
+	stack.RegisterAPIs(supply.APIs(backend.APIBackend))
+	stack.RegisterAPIs(staking.APIs(backend.APIBackend))
 	return backend.APIBackend, backend
 }
 
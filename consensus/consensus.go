@@ -25,6 +25,7 @@ import (
 
 	"github.com/mapprotocol/atlas/chains"
 	"github.com/mapprotocol/atlas/consensus/istanbul"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/core/vm"
@@ -197,6 +198,14 @@ type Istanbul interface {
 
 	// GenerateRandomness will generate the random beacon randomness
 	GenerateRandomness(parentHash common.Hash) (common.Hash, common.Hash, error)
+
+	// EpochRewards returns the rewards distributed by the last block of an epoch
+	// with the given hash, if the engine finalized it recently
+	EpochRewards(hash common.Hash) *types.EpochRewards
+
+	// TraceFinalize runs the state modifications of Finalize without keeping
+	// anything derived from the block in the engine
+	TraceFinalize(chain ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction)
 }
 
 // ChainContext defines a small collection of methods needed to access the local
@@ -211,6 +220,19 @@ type ChainContext interface {
 	NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner
 }
 
+// MMRReader is implemented by chains that maintain a Merkle Mountain Range over
+// their block hashes, starting at the configured MMR block.
+type MMRReader interface {
+	// MMRRoot returns the MMR root over the blocks from the MMR block up to the
+	// parent of header. The caller may pass in the ancestors of header that are
+	// not stored yet (ascending order).
+	MMRRoot(header *types.Header, parents []*types.Header) (common.Hash, error)
+
+	// MMRProof returns the proof of the canonical block number against the MMR
+	// root committed in the canonical block end.
+	MMRProof(number, end uint64) (*mmr.ProofInfo, error)
+}
+
 // Handler should be implemented if the consensus needs to handle and send peer messages
 type Handler interface {
 	// NewWork handles a new work event from the miner
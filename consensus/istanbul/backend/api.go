@@ -24,6 +24,7 @@ import (
 	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/mapprotocol/atlas/consensus"
@@ -35,8 +36,12 @@ import (
 	"github.com/mapprotocol/atlas/consensus/istanbul/uptime"
 	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
+	"github.com/mapprotocol/atlas/consensus/istanbul/verifier"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+	"github.com/mapprotocol/atlas/core/rawdb"
 	"github.com/mapprotocol/atlas/core/types"
 	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
 )
 
 // API is a user facing RPC API to dump Istanbul state
@@ -63,6 +68,29 @@ type EpochInfo struct {
 	Validators []Validator `json:"validators"`
 }
 
+// MMRProof proves a block against the header mmr root committed in the extra
+// data of a later epoch block.
+type MMRProof struct {
+	Number     uint64         `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	RootNumber uint64         `json:"rootNumber"`
+	Root       common.Hash    `json:"root"`
+	Leaf       uint64         `json:"leaf"`
+	Proof      *mmr.ProofInfo `json:"proof"`
+	ProofRLP   hexutil.Bytes  `json:"proofRlp"`
+}
+
+// ValidatorDowntime describes the consecutive lookback windows a validator
+// missed during the current epoch.
+type ValidatorDowntime struct {
+	Address          common.Address `json:"address"`
+	Account          common.Address `json:"account"`
+	MissedWindows    uint64         `json:"missedWindows"`
+	MaxMissedWindows uint64         `json:"maxMissedWindows"`
+	// Slashable is set once the validator will be slashed at the end of the epoch
+	Slashable bool `json:"slashable"`
+}
+
 // getHeaderByNumber retrieves the header requested block or current if unspecified.
 func (api *API) getHeaderByNumber(number *rpc.BlockNumber) (*types.Header, error) {
 	var header *types.Header
@@ -151,8 +179,8 @@ func (api *API) GetProposer(sequence *rpc.BlockNumber, round *uint64) (common.Ad
 		return common.Address{}, err
 	}
 
-	valSet := api.istanbul.getOrderedValidators(header.Number.Uint64(), header.Hash())
-	if valSet == nil {
+	valSet, err := api.istanbul.getOrderedValidators(header.Number.Uint64(), header.Hash())
+	if err != nil {
 		return common.Address{}, err
 	}
 	previousProposer, err := api.istanbul.Author(header)
@@ -375,6 +403,51 @@ func (api *API) Activity() (map[string]interface{}, error) {
 	return ret, nil
 }
 
+// GetValidatorsAtRisk retrieves the validators that are currently missing lookback
+// windows or already missed enough of them to be slashed at the end of the epoch.
+func (api *API) GetValidatorsAtRisk(number *rpc.BlockNumber) ([]*ValidatorDowntime, error) {
+	header, err := api.getHeaderByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	state, err := api.istanbul.stateAt(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	signers := api.istanbul.GetValidators(big.NewInt(header.Number.Int64()-1), header.ParentHash)
+	if len(signers) == 0 {
+		return nil, errors.New("unable to fetch validators")
+	}
+	vmRunner := api.istanbul.chain.NewEVMRunner(header, state)
+	accounts, err := api.istanbul.GetAccountsFromSigners(vmRunner, signers)
+	if err != nil {
+		return nil, err
+	}
+
+	epochNum := istanbul.GetEpochNumber(header.Number.Uint64(), api.istanbul.EpochSize())
+	downtime, err := api.istanbul.epochDowntime(header, epochNum, api.istanbul.LookbackWindow(header, state), len(signers))
+	if err != nil {
+		return nil, err
+	}
+
+	atRisk := make([]*ValidatorDowntime, 0)
+	for i, signer := range signers {
+		slashable := downtime[i].MaxMissedWindows >= params.SlashableDowntimeWindows
+		if downtime[i].MissedWindows == 0 && !slashable {
+			continue
+		}
+		atRisk = append(atRisk, &ValidatorDowntime{
+			Address:          signer.Address(),
+			Account:          accounts[i],
+			MissedWindows:    downtime[i].MissedWindows,
+			MaxMissedWindows: downtime[i].MaxMissedWindows,
+			Slashable:        slashable,
+		})
+	}
+	return atRisk, nil
+}
+
 // GetEpochInfo retrieves the epoch info
 func (api *API) GetEpochInfo(epochNumber uint64) *EpochInfo {
 	number, _ := istanbul.GetEpochFirstBlockNumber(epochNumber, api.istanbul.config.Epoch)
@@ -410,3 +483,124 @@ func (api *API) GetEpochInfo(epochNumber uint64) *EpochInfo {
 	}
 	return epochInfo
 }
+
+// GetMMRProof retrieves the mmr proof of the block number against the root in
+// block end, which defaults to the first epoch block after number that commits one.
+func (api *API) GetMMRProof(number rpc.BlockNumber, end *rpc.BlockNumber) (*MMRProof, error) {
+	reader, ok := api.chain.(consensus.MMRReader)
+	if !ok {
+		return nil, errors.New("header mmr not available")
+	}
+	header, err := api.getHeaderByNumber(&number)
+	if err != nil {
+		return nil, err
+	}
+	if end == nil {
+		epochSize := api.istanbul.config.Epoch
+		last := rpc.BlockNumber(istanbul.GetEpochLastBlockNumber(istanbul.GetEpochNumber(header.Number.Uint64()+1, epochSize), epochSize))
+		end = &last
+	}
+	rootHeader, err := api.getHeaderByNumber(end)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := types.ExtractIstanbulExtra(rootHeader)
+	if err != nil {
+		return nil, err
+	}
+	if extra.MmrRoot == (common.Hash{}) {
+		return nil, fmt.Errorf("block %d has no mmr root", rootHeader.Number)
+	}
+
+	proof, err := reader.MMRProof(header.Number.Uint64(), rootHeader.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	if proof.RootHash != extra.MmrRoot {
+		return nil, fmt.Errorf("mmr root mismatch in block %d", rootHeader.Number)
+	}
+	enc, err := mmr.ProofInfoToBytes(proof)
+	if err != nil {
+		return nil, err
+	}
+	return &MMRProof{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		RootNumber: rootHeader.Number.Uint64(),
+		Root:       extra.MmrRoot,
+		Leaf:       proof.Checked[0],
+		Proof:      proof,
+		ProofRLP:   enc,
+	}, nil
+}
+
+// GetEpochProof retrieves the proof of the validator set elected in the last block
+// of epoch, which can be checked with the verifier package.
+func (api *API) GetEpochProof(epoch uint64) (*verifier.EpochProof, error) {
+	if epoch == 0 {
+		return nil, errors.New("no epoch proof for the genesis epoch")
+	}
+	reader, ok := api.chain.(consensus.ChainReader)
+	if !ok {
+		return nil, errors.New("blocks not available")
+	}
+	epochSize := api.istanbul.config.Epoch
+	header := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(epoch, epochSize))
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	parent := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(epoch-1, epochSize))
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	block := reader.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil || block.EpochSnarkData() == nil || block.EpochSnarkData().IsEmpty() {
+		return nil, fmt.Errorf("no epoch validator set seal in block %d", header.Number)
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := api.istanbul.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	validators := snap.validators()
+	addresses := make([]common.Address, 0, len(validators))
+	publicKeys := make([]blscrypto.SerializedPublicKey, 0, len(validators))
+	for _, v := range validators {
+		addresses = append(addresses, v.Address)
+		publicKeys = append(publicKeys, v.BLSPublicKey)
+	}
+	return &verifier.EpochProof{
+		Epoch:                       epoch,
+		Header:                      header,
+		ParentEpochHash:             parent.Hash(),
+		AddedValidators:             extra.AddedValidators,
+		AddedValidatorsPublicKeys:   extra.AddedValidatorsPublicKeys,
+		AddedValidatorsG1PublicKeys: extra.AddedValidatorsG1PublicKeys,
+		AddedValidatorsBLSCurve:     extra.AddedValidatorsBLSCurve,
+		RemovedValidators:           extra.RemovedValidators,
+		Validators:                  addresses,
+		ValidatorsBLSPublicKeys:     publicKeys,
+		AggregatedSeal:              extra.AggregatedSeal,
+		EpochValidatorSetSeal: types.IstanbulEpochValidatorSetSeal{
+			Bitmap:    block.EpochSnarkData().Bitmap,
+			Signature: block.EpochSnarkData().Signature,
+		},
+	}, nil
+}
+
+// GetEpochRewards retrieves the rewards recorded when the canonical last block of
+// the epoch was written to the chain by this node.
+func (api *API) GetEpochRewards(epoch uint64) (*types.EpochRewards, error) {
+	header := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(epoch, api.istanbul.EpochSize()))
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	rewards := rawdb.ReadEpochRewards(api.istanbul.db, header.Hash(), header.Number.Uint64())
+	if rewards == nil {
+		return nil, fmt.Errorf("no rewards recorded for epoch %d", epoch)
+	}
+	return rewards, nil
+}
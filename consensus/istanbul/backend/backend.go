@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
@@ -43,6 +44,7 @@ import (
 	"github.com/mapprotocol/atlas/consensus/istanbul/proxy"
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
 	"github.com/mapprotocol/atlas/contracts/election"
 	"github.com/mapprotocol/atlas/contracts/random"
 	"github.com/mapprotocol/atlas/contracts/validators"
@@ -57,6 +59,10 @@ import (
 var (
 	// errInvalidSigningFn is returned when the consensus signing function is invalid.
 	errInvalidSigningFn = errors.New("invalid signing function for istanbul messages")
+
+	// errNoBLSCurveValidators is returned when none of the elected validators has a
+	// BLS public key on the curve of the set.
+	errNoBLSCurveValidators = errors.New("no elected validator with a valid BLS public key")
 )
 
 type EcdsaInfo struct {
@@ -92,12 +98,12 @@ type BlsInfo struct {
 	sign    istanbul.BLSSignerFn // Signer function to authorize BLS messages
 }
 
-// Sign signs with the bls account
-func (bi *BlsInfo) Sign(data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
+// Sign signs with the bls account on the given curve
+func (bi *BlsInfo) Sign(curve blscrypto.BLSCryptoSelector, data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
 	if bi.sign == nil {
 		return blscrypto.SerializedSignature{}, errInvalidSigningFn
 	}
-	return bi.sign(accounts.Account{Address: bi.Address}, data, extra, useComposite, cip22, fork, cur)
+	return bi.sign(accounts.Account{Address: bi.Address}, curve, data, extra, useComposite, cip22, fork, cur)
 }
 
 type Wallets struct {
@@ -113,6 +119,18 @@ func New(config *istanbul.Config, db ethdb.Database) consensus.Istanbul {
 	if err != nil {
 		logger.Crit("Failed to create recent snapshots cache", "err", err)
 	}
+	recentValidatorVotes, err := lru.NewARC(inmemoryValidatorVotes)
+	if err != nil {
+		logger.Crit("Failed to create recent validator votes cache", "err", err)
+	}
+	recentRoundTimeouts, err := lru.NewARC(inmemoryRoundTimeouts)
+	if err != nil {
+		logger.Crit("Failed to create recent round timeouts cache", "err", err)
+	}
+	recentEpochRewards, err := lru.NewARC(inmemoryEpochRewards)
+	if err != nil {
+		logger.Crit("Failed to create recent epoch rewards cache", "err", err)
+	}
 
 	coreStarted := atomic.Value{}
 	coreStarted.Store(false)
@@ -122,6 +140,9 @@ func New(config *istanbul.Config, db ethdb.Database) consensus.Istanbul {
 		logger:                             logger,
 		db:                                 db,
 		recentSnapshots:                    recentSnapshots,
+		recentValidatorVotes:               recentValidatorVotes,
+		recentRoundTimeouts:                recentRoundTimeouts,
+		recentEpochRewards:                 recentEpochRewards,
 		coreStarted:                        coreStarted,
 		announceRunning:                    false,
 		gossipCache:                        NewLRUGossipCache(inmemoryPeers, inmemoryMessages),
@@ -166,7 +187,8 @@ func New(config *istanbul.Config, db ethdb.Database) consensus.Istanbul {
 	//)
 
 	if config.Validator {
-		rs, err := replica.NewState(config.Replica, config.ReplicaStateDBPath, backend.StartValidating, backend.StopValidating)
+		failoverTimeout := time.Duration(config.ReplicaFailoverBlocks*config.BlockPeriod) * time.Second
+		rs, err := replica.NewState(config.Replica, config.ReplicaFailoverBlocks, failoverTimeout, config.ReplicaStateDBPath, backend.StartValidating, backend.StopValidating)
 		if err != nil {
 			logger.Crit("Can't open ReplicaStateDB", "err", err, "dbpath", config.ReplicaStateDBPath)
 		}
@@ -250,6 +272,12 @@ type Backend struct {
 
 	// Snapshots for recent blocks to speed up reorgs
 	recentSnapshots *lru.ARCCache
+	// Validator votes of recent epochs, keyed by the hash of the epoch block recording them
+	recentValidatorVotes *lru.ARCCache
+	// Round change timeouts of recent epochs, keyed by the hash of the epoch block they are read at
+	recentRoundTimeouts *lru.ARCCache
+	// Rewards distributed by recently finalized epoch blocks, keyed by the block hash
+	recentEpochRewards *lru.ARCCache
 
 	// event subscription for ChainHeadEvent event
 	broadcaster consensus.Broadcaster
@@ -489,12 +517,12 @@ func (sb *Backend) Close() error {
 }
 
 // Validators implements istanbul.Backend.Validators
-func (sb *Backend) Validators(proposal istanbul.Proposal) istanbul.ValidatorSet {
+func (sb *Backend) Validators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error) {
 	return sb.getOrderedValidators(proposal.Number().Uint64(), proposal.Hash())
 }
 
 // ParentBlockValidators implements istanbul.Backend.ParentBlockValidators
-func (sb *Backend) ParentBlockValidators(proposal istanbul.Proposal) istanbul.ValidatorSet {
+func (sb *Backend) ParentBlockValidators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error) {
 	return sb.getOrderedValidators(proposal.Number().Uint64()-1, proposal.ParentHash())
 }
 
@@ -506,7 +534,7 @@ func (sb *Backend) NextBlockValidators(proposal istanbul.Proposal) (istanbul.Val
 
 	// There was no change
 	if len(istExtra.AddedValidators) == 0 && istExtra.RemovedValidators.BitLen() == 0 {
-		return sb.ParentBlockValidators(proposal), nil
+		return sb.ParentBlockValidators(proposal)
 	}
 
 	snap, err := sb.snapshot(sb.chain, proposal.Number().Uint64()-1, common.Hash{}, nil)
@@ -515,7 +543,7 @@ func (sb *Backend) NextBlockValidators(proposal istanbul.Proposal) (istanbul.Val
 	}
 	snap = snap.copy()
 
-	addedValidators, err := istanbul.CombineIstanbulExtraToValidatorData(istExtra.AddedValidators, istExtra.AddedValidatorsPublicKeys, istExtra.AddedValidatorsG1PublicKeys)
+	addedValidators, err := istanbul.CombineIstanbulExtraToValidatorData(istExtra.AddedValidators, istExtra.AddedValidatorsPublicKeys, istExtra.AddedValidatorsG1PublicKeys, istExtra.AddedValidatorsBLSCurve)
 	if err != nil {
 		return nil, err
 	}
@@ -658,8 +686,54 @@ func (sb *Backend) getNewValidatorSet(header *types.Header, state *state.StateDB
 	if err != nil {
 		return nil, err
 	}
+	newValSetAddresses = filterExcludedSigners(header, state, sb.EpochSize(), newValSetAddresses)
 	newValSet, err := validators.GetValidatorData(vmRunner, newValSetAddresses)
-	return newValSet, err
+	if err != nil {
+		return nil, err
+	}
+	if sb.ChainConfig().IsBLS12381(header.Number) {
+		newValSet = selectBLSCurve(newValSet)
+		if len(newValSet) == 0 {
+			return nil, errNoBLSCurveValidators
+		}
+	}
+	return newValSet, nil
+}
+
+// selectBLSCurve tags the validators elected from the BLS12-381 fork on with the
+// curve of the key they registered, BN256 for the keys registered before the fork.
+// A set aggregates its seals on a single curve, so it takes the curve most of its
+// validators are on and leaves out the others, as well as the validators whose
+// key is on neither curve.
+func selectBLSCurve(valSet []istanbul.ValidatorData) []istanbul.ValidatorData {
+	tagged := make([]istanbul.ValidatorData, 0, len(valSet))
+	bls12381Keys := 0
+	for _, val := range valSet {
+		curve, err := blscrypto.CurveOfPublicKey(val.BLSPublicKey)
+		if err != nil {
+			log.Warn("Not electing validator with an invalid BLS public key", "address", val.Address, "err", err)
+			continue
+		}
+		if curve == blscrypto.BLS12381Curve {
+			bls12381Keys++
+		}
+		val.BLSCurve = curve
+		tagged = append(tagged, val)
+	}
+
+	var setCurve uint8
+	if bls12381Keys > len(tagged)-bls12381Keys {
+		setCurve = blscrypto.BLS12381Curve
+	}
+	selected := make([]istanbul.ValidatorData, 0, len(tagged))
+	for _, val := range tagged {
+		if val.BLSCurve != setCurve {
+			log.Warn("Not electing validator with a BLS public key on another curve than the set", "address", val.Address, "curve", val.BLSCurve, "setCurve", setCurve)
+			continue
+		}
+		selected = append(selected, val)
+	}
+	return selected
 }
 
 func (sb *Backend) verifyValSetDiff(proposal istanbul.Proposal, block *types.Block, state *state.StateDB) error {
@@ -678,7 +752,10 @@ func (sb *Backend) verifyValSetDiff(proposal istanbul.Proposal, block *types.Blo
 			return errInvalidValidatorSetDiff
 		}
 	} else {
-		parentValidators := sb.ParentBlockValidators(proposal)
+		parentValidators, err := sb.ParentBlockValidators(proposal)
+		if err != nil {
+			return err
+		}
 		oldValSet := make([]istanbul.ValidatorData, 0, parentValidators.Size())
 
 		for _, val := range parentValidators.List() {
@@ -686,10 +763,15 @@ func (sb *Backend) verifyValSetDiff(proposal istanbul.Proposal, block *types.Blo
 				Address:        val.Address(),
 				BLSPublicKey:   val.BLSPublicKey(),
 				BLSG1PublicKey: val.BLSG1PublicKey(),
+				BLSCurve:       val.BLSCurve(),
 			})
 		}
 
 		addedValidators, removedValidators := istanbul.ValidatorSetDiff(oldValSet, newValSet)
+		var addedValidatorsBLSCurve uint8
+		if len(addedValidators) > 0 {
+			addedValidatorsBLSCurve = addedValidators[0].BLSCurve
+		}
 
 		addedValidatorsAddresses := make([]common.Address, 0, len(addedValidators))
 		addedValidatorsPublicKeys := make([]blscrypto.SerializedPublicKey, 0, len(addedValidators))
@@ -700,18 +782,20 @@ func (sb *Backend) verifyValSetDiff(proposal istanbul.Proposal, block *types.Blo
 			addedValidatorsG1PublicKeys = append(addedValidatorsG1PublicKeys, val.BLSG1PublicKey)
 		}
 
-		if !istanbul.CompareValidatorSlices(addedValidatorsAddresses, istExtra.AddedValidators) || removedValidators.Cmp(istExtra.RemovedValidators) != 0 || !istanbul.CompareValidatorPublicKeySlices(addedValidatorsPublicKeys, istExtra.AddedValidatorsPublicKeys) || !istanbul.CompareValidatorG1PublicKeySlices(addedValidatorsG1PublicKeys, istExtra.AddedValidatorsG1PublicKeys) {
+		if !istanbul.CompareValidatorSlices(addedValidatorsAddresses, istExtra.AddedValidators) || removedValidators.Cmp(istExtra.RemovedValidators) != 0 || !istanbul.CompareValidatorPublicKeySlices(addedValidatorsPublicKeys, istExtra.AddedValidatorsPublicKeys) || !istanbul.CompareValidatorG1PublicKeySlices(addedValidatorsG1PublicKeys, istExtra.AddedValidatorsG1PublicKeys) || addedValidatorsBLSCurve != istExtra.AddedValidatorsBLSCurve {
 			sb.logger.Error("verifyValSetDiff - Invalid val set diff. Comparison failed. ",
 
 				"got addedValidators", types.ConvertToStringSlice(istExtra.AddedValidators),
 				"got removedValidators", istExtra.RemovedValidators.Text(16),
 				"got addedValidatorsPublicKeys", istanbul.ConvertPublicKeysToStringSlice(istExtra.AddedValidatorsPublicKeys),
 				"got addedValidatorsG1PublicKeys", istanbul.ConvertG1PublicKeysToStringSlice(istExtra.AddedValidatorsG1PublicKeys),
+				"got addedValidatorsBLSCurve", istExtra.AddedValidatorsBLSCurve,
 
 				"expected addedValidators", types.ConvertToStringSlice(addedValidatorsAddresses),
 				"expected removedValidators", removedValidators.Text(16),
 				"expected addedValidatorsPublicKeys", istanbul.ConvertPublicKeysToStringSlice(addedValidatorsPublicKeys),
-				"expected addedValidatorsG1PublicKeys", istanbul.ConvertG1PublicKeysToStringSlice(addedValidatorsG1PublicKeys))
+				"expected addedValidatorsG1PublicKeys", istanbul.ConvertG1PublicKeysToStringSlice(addedValidatorsG1PublicKeys),
+				"expected addedValidatorsBLSCurve", addedValidatorsBLSCurve)
 			return errInvalidValidatorSetDiff
 		}
 	}
@@ -725,9 +809,9 @@ func (sb *Backend) Sign(data []byte) ([]byte, error) {
 }
 
 // Sign implements istanbul.Backend.SignBLS
-func (sb *Backend) SignBLS(data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
+func (sb *Backend) SignBLS(curve blscrypto.BLSCryptoSelector, data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (blscrypto.SerializedSignature, error) {
 	w := sb.wallets()
-	return w.Bls.Sign(data, extra, useComposite, cip22, fork, cur)
+	return w.Bls.Sign(curve, data, extra, useComposite, cip22, fork, cur)
 }
 
 // CheckSignature implements istanbul.Backend.CheckSignature
@@ -788,13 +872,77 @@ func (sb *Backend) validatorRandomnessAtBlockNumber(number uint64, hash common.H
 	return random.BlockRandomness(vmRunner, lastBlockInPreviousEpoch)
 }
 
-func (sb *Backend) getOrderedValidators(number uint64, hash common.Hash) istanbul.ValidatorSet {
+// validatorVotesAtBlockNumber returns the votes of the validators in valSet, in list order, as recorded
+// in the last block of the previous epoch. Validators without recorded votes get none.
+func (sb *Backend) validatorVotesAtBlockNumber(number uint64, hash common.Hash, valSet istanbul.ValidatorSet) ([]*big.Int, error) {
+	next := number + 1
+	epochBlock := sb.chain.GetHeaderByNumber(next - istanbul.GetNumberWithinEpoch(next, sb.config.Epoch))
+	if epochBlock == nil {
+		return nil, errUnknownBlock
+	}
+	var recorded []validatorVotes
+	if cached, ok := sb.recentValidatorVotes.Get(epochBlock.Hash()); ok {
+		recorded = cached.([]validatorVotes)
+	} else {
+		state, err := sb.stateAt(hash)
+		if err != nil {
+			return nil, err
+		}
+		recorded, err = recordedValidatorVotes(state, istanbul.GetEpochNumber(next, sb.config.Epoch))
+		if err != nil {
+			return nil, err
+		}
+		sb.recentValidatorVotes.Add(epochBlock.Hash(), recorded)
+	}
+	if recorded == nil {
+		return nil, nil
+	}
+
+	votes := make([]*big.Int, valSet.Size())
+	for i := range votes {
+		votes[i] = new(big.Int)
+	}
+	for _, v := range recorded {
+		if i := valSet.GetIndex(v.Signer); i >= 0 {
+			votes[i].Set(v.Votes)
+		}
+	}
+	return votes, nil
+}
+
+// RoundTimeouts implements istanbulCore.CoreBackend.RoundTimeouts. The timeouts of the block after head
+// are read from the BlockchainParameters contract at the last block of the previous epoch, so they only
+// change at epoch boundaries. Timeouts the contract does not set fall back to the config.
+func (sb *Backend) RoundTimeouts(head istanbul.Proposal) *istanbul.RoundTimeouts {
+	next := head.Number().Uint64() + 1
+	epochBlock := sb.chain.GetHeaderByNumber(next - istanbul.GetNumberWithinEpoch(next, sb.config.Epoch))
+	if epochBlock == nil {
+		return sb.config.RoundTimeouts()
+	}
+	if cached, ok := sb.recentRoundTimeouts.Get(epochBlock.Hash()); ok {
+		return cached.(*istanbul.RoundTimeouts)
+	}
+	state, err := sb.stateAt(epochBlock.Hash())
+	if err != nil {
+		sb.logger.Warn("Error getting state for round timeouts", "number", epochBlock.Number, "err", err)
+		return sb.config.RoundTimeouts()
+	}
+	vmRunner := sb.chain.NewEVMRunner(epochBlock, state)
+	timeouts := blockchain_parameters.GetRoundChangeTimeoutsOrDefault(vmRunner, sb.config.RoundTimeouts())
+	sb.recentRoundTimeouts.Add(epochBlock.Hash(), timeouts)
+	return timeouts
+}
+
+// getOrderedValidators returns the validator set of the block with its proposer ordering. Under the
+// stake weighted policy it fails if the recorded votes cannot be read, the proposer would otherwise
+// depend on the state this node happens to have.
+func (sb *Backend) getOrderedValidators(number uint64, hash common.Hash) (istanbul.ValidatorSet, error) {
 	valSet := sb.getValidators(number, hash)
 	if valSet.Size() == 0 {
-		return valSet
+		return valSet, nil
 	}
 
-	if sb.config.ProposerPolicy == istanbul.ShuffledRoundRobin {
+	if sb.config.ProposerPolicy == istanbul.ShuffledRoundRobin || sb.config.ProposerPolicy == istanbul.StakeWeighted {
 		seed, err := sb.validatorRandomnessAtBlockNumber(number, hash)
 		if err != nil {
 			if err == contracts.ErrRegistryContractNotDeployed {
@@ -803,10 +951,22 @@ func (sb *Backend) getOrderedValidators(number uint64, hash common.Hash) istanbu
 				sb.logger.Warn("Failed to set randomness for proposer selection", "block_number", number, "hash", hash, "error", err)
 			}
 		}
+		if sb.config.ProposerPolicy == istanbul.StakeWeighted {
+			// The proposer is drawn for every block, so the epoch randomness is mixed with the
+			// block number on a copy of the set shared by the epoch.
+			valSet = valSet.Copy()
+			seed = crypto.Keccak256Hash(seed[:], new(big.Int).SetUint64(number).Bytes())
+			votes, err := sb.validatorVotesAtBlockNumber(number, hash, valSet)
+			if err != nil {
+				sb.logger.Warn("Failed to set votes for proposer selection", "block_number", number, "hash", hash, "error", err)
+				return nil, err
+			}
+			valSet.SetVotes(votes)
+		}
 		valSet.SetRandomness(seed)
 	}
 
-	return valSet
+	return valSet, nil
 }
 
 // GetCurrentHeadBlock retrieves the last block
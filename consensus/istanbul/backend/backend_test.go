@@ -27,6 +27,7 @@ import (
 	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
 )
 
 func TestSign(t *testing.T) {
@@ -172,3 +173,87 @@ func TestGetProposer(t *testing.T) {
 	}
 
 }
+
+func TestSelectBLSCurve(t *testing.T) {
+	type signer struct {
+		data       istanbul.ValidatorData
+		privateKey []byte
+	}
+	newSigner := func(curve blscrypto.BLSCryptoSelector) signer {
+		key, _ := crypto.GenerateKey()
+		privateKey, _ := curve.ECDSAToBLS(key)
+		publicKey, _ := curve.PrivateToPublic(privateKey)
+		return signer{istanbul.ValidatorData{Address: crypto.PubkeyToAddress(key.PublicKey), BLSPublicKey: publicKey}, privateKey}
+	}
+	elect := func(signers []signer) []istanbul.ValidatorData {
+		valSet := make([]istanbul.ValidatorData, 0, len(signers))
+		for _, s := range signers {
+			valSet = append(valSet, s.data)
+		}
+		return selectBLSCurve(valSet)
+	}
+	checkSeals := func(selected []istanbul.ValidatorData, signers []signer) {
+		seal := []byte("committed seal")
+		for _, val := range selected {
+			for _, s := range signers {
+				if s.data.Address != val.Address {
+					continue
+				}
+				curve := blscrypto.CryptoTypeOf(val.BLSCurve)
+				sig, err := curve.Sign(s.privateKey, seal, []byte{}, false, false, common.Big0, common.Big0)
+				if err != nil {
+					t.Fatalf("sign error: %v", err)
+				}
+				if err := curve.VerifySignature(val.BLSPublicKey, seal, []byte{}, sig[:], false, false, common.Big0, common.Big0); err != nil {
+					t.Errorf("seal of %v does not verify on curve %d: %v", val.Address, val.BLSCurve, err)
+				}
+			}
+		}
+	}
+
+	// all the validators registered their keys before the fork
+	signers := make([]signer, 4)
+	for i := range signers {
+		signers[i] = newSigner(blscrypto.BN256{})
+	}
+	selected := elect(signers)
+	if len(selected) != len(signers) {
+		t.Fatalf("elected validators mismatch: have %d, want %d", len(selected), len(signers))
+	}
+	for _, val := range selected {
+		if val.BLSCurve != 0 {
+			t.Errorf("curve mismatch for %v: have %d, want BN256", val.Address, val.BLSCurve)
+		}
+	}
+	checkSeals(selected, signers)
+
+	// the set stays on BN256 until most validators registered BLS12-381 keys
+	signers[0] = newSigner(blscrypto.BLS12381{})
+	selected = elect(signers)
+	if len(selected) != 3 || selected[0].Address != signers[1].data.Address {
+		t.Errorf("elected validators mismatch: have %v", selected)
+	}
+	checkSeals(selected, signers)
+
+	signers[1] = newSigner(blscrypto.BLS12381{})
+	signers[2] = newSigner(blscrypto.BLS12381{})
+	selected = elect(signers)
+	if len(selected) != 3 {
+		t.Fatalf("elected validators mismatch: have %d, want 3", len(selected))
+	}
+	for i, val := range selected {
+		if val.Address != signers[i].data.Address || val.BLSCurve != blscrypto.BLS12381Curve {
+			t.Errorf("elected validator mismatch: have %v, want %v on BLS12-381", val, signers[i].data.Address)
+		}
+	}
+	checkSeals(selected, signers)
+
+	// keys on neither curve are not elected
+	invalid := newSigner(blscrypto.BLS12381{})
+	for i := range invalid.data.BLSPublicKey {
+		invalid.data.BLSPublicKey[i] = 0xff
+	}
+	if selected := elect([]signer{invalid}); len(selected) != 0 {
+		t.Errorf("elected validator with an invalid key: %v", selected)
+	}
+}
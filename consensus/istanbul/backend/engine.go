@@ -35,6 +35,7 @@ import (
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/consensus/misc"
 	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/slasher"
 	ethCore "github.com/mapprotocol/atlas/core"
 	ethChain "github.com/mapprotocol/atlas/core/chain"
 	"github.com/mapprotocol/atlas/core/state"
@@ -48,6 +49,9 @@ const (
 	inmemorySnapshots             = 128 // Number of recent vote snapshots to keep in memory
 	inmemoryPeers                 = 40
 	inmemoryMessages              = 1024
+	inmemoryValidatorVotes        = 8 // Number of recent epochs to keep the validator votes of
+	inmemoryRoundTimeouts         = 8 // Number of recent epochs to keep the round change timeouts of
+	inmemoryEpochRewards          = 8 // Number of recent epoch blocks to keep the distributed rewards of
 	mobileAllowedClockSkew uint64 = 5
 )
 
@@ -82,6 +86,8 @@ var (
 	errMismatchTxhashes = errors.New("mismatch transactions hashes")
 	// errInvalidValidatorSetDiff is returned if the header contains invalid validator set diff
 	errInvalidValidatorSetDiff = errors.New("invalid validator set diff")
+	// errInvalidMMRRoot is returned if the header mmr root in the extra data is not the expected one
+	errInvalidMMRRoot = errors.New("invalid mmr root")
 	// errUnauthorizedAnnounceMessage is returned when the received announce message is from
 	// an unregistered validator
 	errUnauthorizedAnnounceMessage = errors.New("unauthorized announce message")
@@ -156,9 +162,52 @@ func (sb *Backend) verifyHeader(chain consensus.ChainHeaderReader, header *types
 			}
 		}
 	}
+	if err := sb.verifyMMRRoot(chain, header, parents); err != nil {
+		return err
+	}
+	if err := sb.verifyEvidence(chain.Config(), header); err != nil {
+		return err
+	}
 	return sb.verifyCascadingFields(chain, header, parents)
 }
 
+// commitsMMRRoot returns whether the header carries the header mmr root, which
+// is the case for the last block of every epoch after the first MMR block.
+func (sb *Backend) commitsMMRRoot(chain consensus.ChainHeaderReader, header *types.Header) bool {
+	number := header.Number.Uint64()
+	if number == 0 || !istanbul.IsLastBlockOfEpoch(number, sb.config.Epoch) {
+		return false
+	}
+	return chain.Config().IsMMR(new(big.Int).SetUint64(number - 1))
+}
+
+// verifyMMRRoot checks the header mmr root in the extra data. Chains that don't
+// maintain the mmr can only check that it is absent outside epoch boundaries.
+func (sb *Backend) verifyMMRRoot(chain consensus.ChainHeaderReader, header *types.Header, parents []*types.Header) error {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return errInvalidExtraDataFormat
+	}
+	if !sb.commitsMMRRoot(chain, header) {
+		if extra.MmrRoot != (common.Hash{}) {
+			return errInvalidMMRRoot
+		}
+		return nil
+	}
+	reader, ok := chain.(consensus.MMRReader)
+	if !ok {
+		return nil
+	}
+	root, err := reader.MMRRoot(header, parents)
+	if err != nil {
+		return err
+	}
+	if extra.MmrRoot != root {
+		return errInvalidMMRRoot
+	}
+	return nil
+}
+
 // A sanity check for lightest mode. Checks that the correct epoch block exists for this header
 func (sb *Backend) checkEpochBlockExists(chain consensus.ChainHeaderReader, header *types.Header, parents []*types.Header) error {
 	number := header.Number.Uint64()
@@ -360,7 +409,7 @@ func (sb *Backend) verifyAggregatedSeal(headerHash common.Hash, validators istan
 		logger.Error("Aggregated seal does not aggregate enough seals", "numSeals", len(publicKeys), "minimum quorum size", validators.MinQuorumSize())
 		return errInsufficientSeals
 	}
-	err := blscrypto.CryptoType().VerifyAggregatedSignature(publicKeys, proposalSeal, []byte{}, aggregatedSeal.Signature,
+	err := validators.BLSCrypto().VerifyAggregatedSignature(publicKeys, proposalSeal, []byte{}, aggregatedSeal.Signature,
 		false, false, fork, cur)
 	if err != nil {
 		logger.Error("Unable to verify aggregated signature", "err", err)
@@ -423,6 +472,15 @@ func (sb *Backend) Prepare(chain consensus.ChainHeaderReader, header *types.Head
 	if err := writeEmptyIstanbulExtra(header); err != nil {
 		return err
 	}
+	if reader, ok := chain.(consensus.MMRReader); ok && sb.commitsMMRRoot(chain, header) {
+		root, err := reader.MMRRoot(header, nil)
+		if err != nil {
+			return err
+		}
+		if err := writeMMRRoot(header, root); err != nil {
+			return err
+		}
+	}
 
 	// addParentSeal blocks for up to 500ms waiting for the core to reach the target sequence.
 	// Prepare is called from non-validators, so don't bother with the parent seal unless this
@@ -465,6 +523,15 @@ func (sb *Backend) EpochSize() uint64 {
 	return sb.config.Epoch
 }
 
+// EpochRewards returns the rewards distributed by the recently finalized last
+// block of an epoch with the given hash, the chain writes them with the block.
+func (sb *Backend) EpochRewards(hash common.Hash) *types.EpochRewards {
+	if rewards, ok := sb.recentEpochRewards.Get(hash); ok {
+		return rewards.(*types.EpochRewards)
+	}
+	return nil
+}
+
 // LookbackWindow returns the size of the lookback window for calculating uptime (in blocks)
 // Value is constant during an epoch
 func (sb *Backend) LookbackWindow(header *types.Header, state *state.StateDB) uint64 {
@@ -486,6 +553,18 @@ func (sb *Backend) LookbackWindow(header *types.Header, state *state.StateDB) ui
 // Note: The block header and state database might be updated to reflect any
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	sb.finalize(chain, header, state, txs, true)
+}
+
+// TraceFinalize runs the same state modifications as Finalize for tracing, the
+// engine keeps nothing it derives from the block, such as its epoch rewards.
+func (sb *Backend) TraceFinalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	sb.finalize(chain, header, state, txs, false)
+}
+
+// finalize runs the post-transaction state modifications of header, keep tells
+// whether the engine keeps the data derived from the block for the chain.
+func (sb *Backend) finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, keep bool) {
 	start := time.Now()
 	defer sb.finalizationTimer.UpdateSince(start)
 
@@ -501,26 +580,63 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 
 	snapshot := state.Snapshot()
 	vmRunner := sb.chain.NewEVMRunner(header, state)
+	var rewards *types.EpochRewards
 	err := sb.setInitialGoldTokenTotalSupplyIfUnset(vmRunner)
 	if err != nil {
 		state.RevertToSnapshot(snapshot)
 	}
 
-	// Trigger an update to the gas price minimum in the GasPriceMinimum contract based on block congestion
-	snapshot = state.Snapshot()
+	if chain.Config().IsDoubleSigning(header.Number) {
+		if header.Number.Cmp(chain.Config().DoubleSigningBlock) == 0 {
+			snapshot = state.Snapshot()
+			err := slasher.RegisterSlasher(vmRunner, "DoubleSigningSlasher", params.DoubleSigningSlasherRegistryId, params.DoubleSigningSlasherAddress)
+			if err != nil {
+				logger.Error("Failed to register the double signing slasher", "err", err)
+				state.RevertToSnapshot(snapshot)
+			}
+		}
+
+		// Slash the offenders of the double signing evidence included in the block
+		snapshot = state.Snapshot()
+		if err := sb.slashDoubleSigners(header, state); err != nil {
+			logger.Error("Failed to slash double signing validators", "err", err)
+			state.RevertToSnapshot(snapshot)
+		}
+	}
 
 	lastBlockOfEpoch := istanbul.IsLastBlockOfEpoch(header.Number.Uint64(), sb.config.Epoch)
 	if lastBlockOfEpoch {
+		if chain.Config().IsDowntimeSlashing(header.Number) {
+			snapshot = state.Snapshot()
+			if err := sb.slashDowntimeValidators(header, state); err != nil {
+				logger.Error("Failed to slash validators for downtime", "err", err)
+				state.RevertToSnapshot(snapshot)
+			}
+		}
+
 		snapshot = state.Snapshot()
-		err = sb.distributeEpochRewards(header, state, chain.Config().EnableRewardBlock, chain.Config().BN256ForkBlock,
+		rewards, err = sb.distributeEpochRewards(header, state, chain.Config().EnableRewardBlock, chain.Config().BN256ForkBlock,
 			chain.Config().DeregisterBlock)
 		if err != nil {
 			sb.logger.Error("Failed to distribute epoch rewards", "blockNumber", header.Number, "err", err)
 			state.RevertToSnapshot(snapshot)
+			rewards = nil
+		}
+
+		if chain.Config().IsStakeWeighted(header.Number) {
+			snapshot = state.Snapshot()
+			if err := sb.recordValidatorVotes(header, state); err != nil {
+				logger.Error("Failed to record validator votes", "err", err)
+				state.RevertToSnapshot(snapshot)
+			}
 		}
 	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	if rewards != nil && keep {
+		// The record is persisted once the block is written to the chain
+		sb.recentEpochRewards.Add(header.Hash(), rewards)
+	}
 	logger.Info("Finalized", "duration", now().Sub(start), "lastInEpoch", lastBlockOfEpoch)
 }
 
@@ -530,6 +646,14 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 // Note: The block header and state database might be updated to reflect any
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt, randomness *types.Randomness) (*types.Block, error) {
+	// Include the double signing evidence that has not been slashed yet
+	if chain.Config().IsDoubleSigning(header.Number) {
+		if evidence := sb.pendingEvidence(header, state); len(evidence) > 0 {
+			if err := writeEvidence(header, evidence); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	sb.Finalize(chain, header, state, txs)
 
@@ -650,6 +774,13 @@ func (sb *Backend) updateReplicaStateLoop(bc *ethChain.BlockChain) {
 	for {
 		select {
 		case chainEvent := <-chainEventCh:
+			// Only count seals at the chain head, not the ones of blocks being synced
+			if sb.replicaState != nil && chainEvent.Block.Hash() == sb.currentBlock().Hash() {
+				sb.replicaState.ResetFailoverTimeout()
+				if signed, elected := sb.signedParentOfBlock(chainEvent.Block); elected {
+					sb.replicaState.NewBlockSeal(new(big.Int).Sub(chainEvent.Block.Number(), common.Big1), signed)
+				}
+			}
 			sb.coreMu.RLock()
 			if !sb.isCoreStarted() && sb.replicaState != nil {
 				consensusBlock := new(big.Int).Add(chainEvent.Block.Number(), common.Big1)
@@ -921,7 +1052,7 @@ func (sb *Backend) snapshot(chain consensus.ChainHeaderReader, number uint64, ha
 			return nil, errInvalidValidatorSetDiff
 		}
 
-		validators, err := istanbul.CombineIstanbulExtraToValidatorData(istanbulExtra.AddedValidators, istanbulExtra.AddedValidatorsPublicKeys, istanbulExtra.AddedValidatorsG1PublicKeys)
+		validators, err := istanbul.CombineIstanbulExtraToValidatorData(istanbulExtra.AddedValidators, istanbulExtra.AddedValidatorsPublicKeys, istanbulExtra.AddedValidatorsG1PublicKeys, istanbulExtra.AddedValidatorsBLSCurve)
 		if err != nil {
 			log.Error("Cannot construct validators data from istanbul extra")
 			return nil, errInvalidValidatorSetDiff
@@ -1023,17 +1154,18 @@ func (sb *Backend) addParentSeal(chain consensus.ChainHeaderReader, header *type
 		logger = logger.New("numParentCommits", parentCommits.Size())
 		logger.Trace("Found commit messages from previous sequence to combine with ParentAggregatedSeal")
 
+		// need to pass the previous block from the parent to get the parent's validators
+		// (otherwise we'd be getting the validators for the current block)
+		parentValidators := sb.getValidators(parent.Number.Uint64()-1, parent.ParentHash)
+
 		// if we had any seals gossiped to us, proceed to add them to the
 		// already aggregated signature
-		unionAggregatedSeal, err := istanbulCore.UnionOfSeals(parentExtra.AggregatedSeal, parentCommits)
+		unionAggregatedSeal, err := istanbulCore.UnionOfSeals(parentValidators.BLSCrypto(), parentExtra.AggregatedSeal, parentCommits)
 		if err != nil {
 			logger.Error("Failed to combine commit messages with ParentAggregatedSeal", "err", err)
 			return parentExtra.AggregatedSeal
 		}
 
-		// need to pass the previous block from the parent to get the parent's validators
-		// (otherwise we'd be getting the validators for the current block)
-		parentValidators := sb.getValidators(parent.Number.Uint64()-1, parent.ParentHash)
 		// only update to use the union if we indeed provided a valid aggregate signature for this block
 		fork, cur := new(big.Int).Set(sb.chain.Config().BN256ForkBlock), new(big.Int).Set(parent.Number)
 		if err := sb.verifyAggregatedSeal(parent.Hash(), parentValidators, unionAggregatedSeal, fork, cur); err != nil {
@@ -1157,6 +1289,10 @@ func writeValidatorSetDiff(header *types.Header, oldValSet []istanbul.ValidatorD
 	extra.AddedValidators = addedValidatorsAddresses
 	extra.AddedValidatorsPublicKeys = addedValidatorsPublicKeys
 	extra.AddedValidatorsG1PublicKeys = addedValidatorsG1PublicKeys
+	extra.AddedValidatorsBLSCurve = 0
+	if len(addedValidators) > 0 {
+		extra.AddedValidatorsBLSCurve = addedValidators[0].BLSCurve
+	}
 	extra.RemovedValidators = removedValidators
 
 	// update the header's extra with the new diff
@@ -1169,6 +1305,23 @@ func writeValidatorSetDiff(header *types.Header, oldValSet []istanbul.ValidatorD
 	return nil
 }
 
+// writeMMRRoot writes the header mmr root into the extra-data field of the given header.
+func writeMMRRoot(h *types.Header, root common.Hash) error {
+	istanbulExtra, err := types.ExtractIstanbulExtra(h)
+	if err != nil {
+		return err
+	}
+
+	istanbulExtra.MmrRoot = root
+	payload, err := rlp.EncodeToBytes(&istanbulExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.IstanbulExtraVanity], payload...)
+	return nil
+}
+
 // writeSeal writes the extra-data field of the given header with the given seal.
 func writeSeal(h *types.Header, seal []byte) error {
 	if len(seal) != types.IstanbulExtraSeal {
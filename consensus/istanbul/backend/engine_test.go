@@ -474,3 +474,59 @@ func TestWriteAggregatedSeal(t *testing.T) {
 	err = writeAggregatedSeal(h, invalidAggregatedSeal, true)
 	g.Expect(err).To(BeIdenticalTo(errInvalidAggregatedSeal))
 }
+
+func TestWriteMMRRoot(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	h := &types.Header{}
+	g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+	withoutRoot := common.CopyBytes(h.Extra)
+
+	root := common.HexToHash("0x3c2b1ad0d2a7f0e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071829")
+	g.Expect(writeMMRRoot(h, root)).To(Succeed())
+	g.Expect(h.Extra).NotTo(Equal(withoutRoot))
+
+	// the root survives the other extra-data writers
+	g.Expect(writeValidatorSetDiff(h, []istanbul.ValidatorData{}, []istanbul.ValidatorData{})).To(Succeed())
+	g.Expect(writeSeal(h, make([]byte, types.IstanbulExtraSeal))).To(Succeed())
+	extra, err := types.ExtractIstanbulExtra(h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(extra.MmrRoot).To(Equal(root))
+
+	// an empty root keeps the previous encoding
+	h = &types.Header{}
+	g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+	g.Expect(writeMMRRoot(h, common.Hash{})).To(Succeed())
+	g.Expect(h.Extra).To(Equal(withoutRoot))
+}
+
+func TestWriteValidatorSetDiffBLSCurve(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	oldValidators := []istanbul.ValidatorData{
+		{Address: common.HexToAddress("0x44add0ec310f115a0e603b2d7db9f067778eaf8a")},
+		{Address: common.HexToAddress("0x294fc7e8f22b3bcdcf955dd7ff3ba2ed833f8212")},
+	}
+	// the same validators elected with BLS12-381 keys replace the whole set
+	newValidators := []istanbul.ValidatorData{
+		{Address: oldValidators[0].Address, BLSCurve: bls.BLS12381Curve},
+		{Address: oldValidators[1].Address, BLSCurve: bls.BLS12381Curve},
+	}
+
+	h := &types.Header{}
+	g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+	withoutCurve := common.CopyBytes(h.Extra)
+	g.Expect(writeValidatorSetDiff(h, oldValidators, newValidators)).To(Succeed())
+
+	extra, err := types.ExtractIstanbulExtra(h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(extra.AddedValidatorsBLSCurve).To(Equal(uint8(bls.BLS12381Curve)))
+	g.Expect(extra.RemovedValidators.Int64()).To(Equal(int64(3)))
+	added, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(added).To(Equal(newValidators))
+
+	// an empty diff keeps the previous encoding
+	g.Expect(writeValidatorSetDiff(h, []istanbul.ValidatorData{}, []istanbul.ValidatorData{})).To(Succeed())
+	g.Expect(h.Extra).To(Equal(withoutCurve))
+}
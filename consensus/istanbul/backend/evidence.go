@@ -0,0 +1,219 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// maxEvidencePerBlock bounds the double signing evidence a proposer includes
+// in a single block.
+const maxEvidencePerBlock = 4
+
+var (
+	// errTooManyEvidence is returned if a header includes more than maxEvidencePerBlock evidence
+	errTooManyEvidence = errors.New("too many double signing evidence")
+	// errDuplicateEvidence is returned if a header includes evidence for the same offence twice
+	errDuplicateEvidence = errors.New("duplicate double signing evidence")
+	// errStaleEvidence is returned if evidence is not for one of the last epoch size blocks
+	errStaleEvidence = errors.New("stale double signing evidence")
+	// errUnknownOffender is returned if the evidence was not signed by a validator of its sequence
+	errUnknownOffender = errors.New("double signing evidence from a non validator")
+	// errUnexpectedEvidence is returned if a header before the double signing fork includes evidence
+	errUnexpectedEvidence = errors.New("double signing evidence before the double signing fork")
+)
+
+// offence identifies a double signing offence, a validator is slashed at most
+// once per sequence.
+type offence struct {
+	offender common.Address
+	sequence uint64
+}
+
+func evidenceOffence(e *istanbul.Evidence) offence {
+	return offence{e.Offender(), e.View().Sequence.Uint64()}
+}
+
+// evidenceInWindow returns whether evidence for sequence may be included in
+// the block number.
+func (sb *Backend) evidenceInWindow(sequence, number uint64) bool {
+	return sequence > 0 && sequence < number && number-sequence <= sb.config.Epoch
+}
+
+// ReportEquivocation implements istanbulCore.CoreBackend.ReportEquivocation. The
+// evidence is stored until a proposer includes it and gossiped to the network.
+func (sb *Backend) ReportEquivocation(evidence *istanbul.Evidence) {
+	enc, err := evidence.Encode()
+	if err != nil {
+		sb.logger.Error("Failed to encode double signing evidence", "evidence", evidence, "err", err)
+		return
+	}
+	if rawdb.HasIstanbulEvidence(sb.db, enc.Hash()) {
+		return
+	}
+	rawdb.WriteIstanbulEvidence(sb.db, &enc)
+
+	payload, err := rlp.EncodeToBytes(&enc)
+	if err != nil {
+		sb.logger.Error("Failed to encode double signing evidence", "evidence", evidence, "err", err)
+		return
+	}
+	if err := sb.Gossip(payload, istanbul.EvidenceMsg); err != nil {
+		sb.logger.Warn("Failed to gossip double signing evidence", "evidence", evidence, "err", err)
+	}
+}
+
+// handleEvidenceMsg stores and regossips evidence received from a peer once it
+// is verified against the validators of the current chain.
+func (sb *Backend) handleEvidenceMsg(addr common.Address, payload []byte) error {
+	logger := sb.logger.New("func", "handleEvidenceMsg")
+
+	sb.gossipCache.MarkMessageProcessedByPeer(addr, payload)
+	if sb.gossipCache.CheckIfMessageProcessedBySelf(payload) {
+		return nil
+	}
+	defer sb.gossipCache.MarkMessageProcessedBySelf(payload)
+
+	var enc types.IstanbulEvidence
+	if err := rlp.DecodeBytes(payload, &enc); err != nil {
+		logger.Debug("Failed to decode double signing evidence", "err", err)
+		return err
+	}
+	evidence, err := istanbul.DecodeEvidence(enc)
+	if err != nil {
+		logger.Debug("Received invalid double signing evidence", "err", err)
+		return err
+	}
+	head := sb.currentBlock().Header()
+	if !sb.evidenceInWindow(evidence.View().Sequence.Uint64(), head.Number.Uint64()+1) {
+		return errStaleEvidence
+	}
+	if !sb.isValidatorAt(head, evidence) {
+		return errUnknownOffender
+	}
+	if rawdb.HasIstanbulEvidence(sb.db, enc.Hash()) {
+		return nil
+	}
+	logger.Warn("Received double signing evidence", "evidence", evidence)
+	rawdb.WriteIstanbulEvidence(sb.db, &enc)
+	return sb.Gossip(payload, istanbul.EvidenceMsg)
+}
+
+// isValidatorAt returns whether the offender was a validator of the evidence
+// sequence on the chain of header, which is at or after that sequence - 1.
+func (sb *Backend) isValidatorAt(header *types.Header, evidence *istanbul.Evidence) bool {
+	target := evidence.View().Sequence.Uint64() - 1
+	for header != nil && header.Number.Uint64() > target {
+		header = sb.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	if header == nil {
+		return false
+	}
+	_, val := sb.getValidators(target, header.Hash()).GetByAddress(evidence.Offender())
+	return val != nil
+}
+
+// pendingEvidence returns the stored evidence the block of header should include.
+// Evidence that is stale or was already processed on the chain of state is
+// removed from the database.
+func (sb *Backend) pendingEvidence(header *types.Header, state *state.StateDB) []types.IstanbulEvidence {
+	var (
+		number   = header.Number.Uint64()
+		pending  []types.IstanbulEvidence
+		included = make(map[offence]bool)
+	)
+	for _, enc := range rawdb.ReadAllIstanbulEvidence(sb.db) {
+		evidence, err := istanbul.DecodeEvidence(enc)
+		if err != nil {
+			rawdb.DeleteIstanbulEvidence(sb.db, enc.Hash())
+			continue
+		}
+		o := evidenceOffence(evidence)
+		if o.sequence >= number {
+			// Not includable yet.
+			continue
+		}
+		if !sb.evidenceInWindow(o.sequence, number) || isOffenceProcessed(state, o) {
+			rawdb.DeleteIstanbulEvidence(sb.db, enc.Hash())
+			continue
+		}
+		if included[o] || len(pending) >= maxEvidencePerBlock {
+			continue
+		}
+		included[o] = true
+		pending = append(pending, enc)
+	}
+	return pending
+}
+
+// verifyEvidence checks the double signing evidence included in the header, which
+// is only allowed from the double signing fork block on. That the offenders were
+// validators is checked when the block is finalized.
+func (sb *Backend) verifyEvidence(config *params.ChainConfig, header *types.Header) error {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return errInvalidExtraDataFormat
+	}
+	if !config.IsDoubleSigning(header.Number) && len(extra.Evidence) > 0 {
+		return errUnexpectedEvidence
+	}
+	if len(extra.Evidence) > maxEvidencePerBlock {
+		return errTooManyEvidence
+	}
+	seen := make(map[offence]bool)
+	for _, enc := range extra.Evidence {
+		evidence, err := istanbul.DecodeEvidence(enc)
+		if err != nil {
+			return err
+		}
+		o := evidenceOffence(evidence)
+		if !sb.evidenceInWindow(o.sequence, header.Number.Uint64()) {
+			return errStaleEvidence
+		}
+		if seen[o] {
+			return errDuplicateEvidence
+		}
+		seen[o] = true
+	}
+	return nil
+}
+
+// writeEvidence writes the extra-data field of the given header with the given evidence.
+func writeEvidence(h *types.Header, evidence []types.IstanbulEvidence) error {
+	istanbulExtra, err := types.ExtractIstanbulExtra(h)
+	if err != nil {
+		return err
+	}
+
+	istanbulExtra.Evidence = evidence
+	payload, err := rlp.EncodeToBytes(&istanbulExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.IstanbulExtraVanity], payload...)
+	return nil
+}
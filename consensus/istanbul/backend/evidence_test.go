@@ -0,0 +1,165 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/gomega"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func newTestEvidence(t *testing.T, key *ecdsa.PrivateKey, sequence int64) types.IstanbulEvidence {
+	view := &istanbul.View{Round: big.NewInt(0), Sequence: big.NewInt(sequence)}
+	prepare := func(digest common.Hash) *istanbul.Message {
+		msg := istanbul.NewPrepareMessage(&istanbul.Subject{View: view, Digest: digest}, crypto.PubkeyToAddress(key.PublicKey))
+		err := msg.Sign(func(data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), key)
+		})
+		if err != nil {
+			t.Fatalf("failed to sign message: %v", err)
+		}
+		return msg
+	}
+	enc, err := istanbul.NewEvidence(prepare(common.HexToHash("0x01")), prepare(common.HexToHash("0x02"))).Encode()
+	if err != nil {
+		t.Fatalf("failed to encode evidence: %v", err)
+	}
+	return enc
+}
+
+func TestWriteEvidence(t *testing.T) {
+	g := NewGomegaWithT(t)
+	sb := &Backend{config: &istanbul.Config{Epoch: 10}}
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	h := &types.Header{Number: big.NewInt(20)}
+	g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+	withoutEvidence := common.CopyBytes(h.Extra)
+	g.Expect(writeEvidence(h, nil)).To(Succeed())
+	g.Expect(h.Extra).To(Equal(withoutEvidence))
+
+	evidence := []types.IstanbulEvidence{newTestEvidence(t, key, 15), newTestEvidence(t, other, 15)}
+	g.Expect(writeEvidence(h, evidence)).To(Succeed())
+	// the evidence survives the other extra-data writers
+	g.Expect(writeSeal(h, make([]byte, types.IstanbulExtraSeal))).To(Succeed())
+	extra, err := types.ExtractIstanbulExtra(h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(extra.Evidence).To(Equal(evidence))
+	config := *params.TestChainConfig
+	config.DoubleSigningBlock = big.NewInt(20)
+	g.Expect(sb.verifyEvidence(&config, h)).To(Succeed())
+	// evidence is rejected before the double signing fork
+	config.DoubleSigningBlock = big.NewInt(21)
+	g.Expect(sb.verifyEvidence(&config, h)).To(MatchError(errUnexpectedEvidence))
+	g.Expect(sb.verifyEvidence(&config, &types.Header{Number: big.NewInt(20), Extra: withoutEvidence})).To(Succeed())
+	config.DoubleSigningBlock = big.NewInt(0)
+
+	tests := []struct {
+		name     string
+		evidence []types.IstanbulEvidence
+		err      error
+	}{
+		{"duplicate", []types.IstanbulEvidence{evidence[0], newTestEvidence(t, key, 15)}, errDuplicateEvidence},
+		{"stale", []types.IstanbulEvidence{newTestEvidence(t, key, 9)}, errStaleEvidence},
+		{"future", []types.IstanbulEvidence{newTestEvidence(t, key, 20)}, errStaleEvidence},
+		{"too many", make([]types.IstanbulEvidence, maxEvidencePerBlock+1), errTooManyEvidence},
+		{"invalid", []types.IstanbulEvidence{{First: evidence[0].First, Second: evidence[1].Second}}, istanbul.ErrInvalidEvidence},
+	}
+	for _, tt := range tests {
+		h := &types.Header{Number: big.NewInt(20)}
+		g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+		g.Expect(writeEvidence(h, tt.evidence)).To(Succeed())
+		if err := sb.verifyEvidence(&config, h); !errors.Is(err, tt.err) {
+			t.Errorf("%s: have %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestSlashDoubleSigners(t *testing.T) {
+	g := NewGomegaWithT(t)
+	genesis, keys := getGenesisAndKeys(2, true)
+	bc, sb, _ := newBlockChainWithKeys(false, common.Address{}, false, genesis, keys[0])
+	defer stopEngine(sb)
+
+	statedb, err := bc.State()
+	g.Expect(err).NotTo(HaveOccurred())
+	var (
+		offender    = crypto.PubkeyToAddress(keys[0].PublicKey)
+		honest      = crypto.PubkeyToAddress(keys[1].PublicKey)
+		outsider, _ = crypto.GenerateKey()
+		elected     = []common.Address{offender, honest}
+	)
+	// the test genesis has no core contracts, the slash of the honest signer fails
+	defer func(slash func(vm.EVMRunner, common.Hash, common.Address, common.Address, *big.Int, *big.Int) error) {
+		slashValidator = slash
+	}(slashValidator)
+	slashValidator = func(_ vm.EVMRunner, _ common.Hash, signer, _ common.Address, _, _ *big.Int) error {
+		if signer == honest {
+			return errors.New("slash failed")
+		}
+		return nil
+	}
+	newHeader := func(number int64, evidence ...types.IstanbulEvidence) *types.Header {
+		h := &types.Header{Number: big.NewInt(number), ParentHash: bc.Genesis().Hash()}
+		g.Expect(writeEmptyIstanbulExtra(h)).To(Succeed())
+		g.Expect(writeEvidence(h, evidence)).To(Succeed())
+		return h
+	}
+
+	// no evidence leaves the elected signers alone
+	header := newHeader(1)
+	g.Expect(sb.slashDoubleSigners(header, statedb)).To(Succeed())
+	g.Expect(filterExcludedSigners(header, statedb, sb.EpochSize(), elected)).To(Equal(elected))
+
+	// evidence against a non validator is ignored, valid evidence excludes the offender
+	valid := newTestEvidence(t, keys[0], 1)
+	header = newHeader(1, valid, newTestEvidence(t, outsider, 1))
+	g.Expect(sb.slashDoubleSigners(header, statedb)).To(Succeed())
+	epoch := istanbul.GetEpochNumber(1, sb.EpochSize())
+	g.Expect(excludedSigners(statedb, epoch)).To(Equal([]common.Address{offender}))
+	g.Expect(isOffenceProcessed(statedb, offence{offender, 1})).To(BeTrue())
+	g.Expect(isOffenceProcessed(statedb, offence{crypto.PubkeyToAddress(outsider.PublicKey), 1})).To(BeFalse())
+
+	// a failed slash neither excludes the signer nor marks its offence processed
+	header = newHeader(1, newTestEvidence(t, keys[1], 1))
+	g.Expect(sb.slashDoubleSigners(header, statedb)).To(Succeed())
+	g.Expect(excludedSigners(statedb, epoch)).To(Equal([]common.Address{offender}))
+	g.Expect(isOffenceProcessed(statedb, offence{honest, 1})).To(BeFalse())
+
+	// evidence for an offence that was already slashed does not count twice
+	header = newHeader(1, newTestEvidence(t, keys[0], 1))
+	g.Expect(sb.slashDoubleSigners(header, statedb)).To(Succeed())
+	g.Expect(excludedSigners(statedb, epoch)).To(Equal([]common.Address{offender}))
+
+	// the offender is filtered from the elected signers, unless none would be left
+	g.Expect(filterExcludedSigners(header, statedb, sb.EpochSize(), elected)).To(Equal([]common.Address{honest}))
+	g.Expect(filterExcludedSigners(header, statedb, sb.EpochSize(), []common.Address{offender})).To(Equal([]common.Address{offender}))
+	// exclusions only apply to the epoch they were made in
+	next := &types.Header{Number: new(big.Int).SetUint64(istanbul.GetEpochLastBlockNumber(epoch+1, sb.EpochSize()))}
+	g.Expect(filterExcludedSigners(next, statedb, sb.EpochSize(), elected)).To(Equal(elected))
+}
@@ -85,6 +85,9 @@ func (sb *Backend) HandleMsg(addr common.Address, msg p2p.Msg, peer consensus.Pe
 		case istanbul.VersionCertificatesMsg:
 			go sb.handleVersionCertificatesMsg(addr, peer, data)
 			return true, nil
+		case istanbul.EvidenceMsg:
+			go sb.handleEvidenceMsg(addr, data)
+			return true, nil
 		case istanbul.ValidatorHandshakeMsg:
 			logger.Warn("Received unexpected Istanbul validator handshake message")
 			return true, nil
@@ -120,6 +123,9 @@ func (sb *Backend) HandleMsg(addr common.Address, msg p2p.Msg, peer consensus.Pe
 		case istanbul.VersionCertificatesMsg:
 			go sb.handleVersionCertificatesMsg(addr, peer, data)
 			return true, nil
+		case istanbul.EvidenceMsg:
+			go sb.handleEvidenceMsg(addr, data)
+			return true, nil
 		case istanbul.ValidatorHandshakeMsg:
 			logger.Warn("Received unexpected Istanbul validator handshake message")
 			return true, nil
@@ -153,6 +159,9 @@ func (sb *Backend) HandleMsg(addr common.Address, msg p2p.Msg, peer consensus.Pe
 		case istanbul.VersionCertificatesMsg:
 			go sb.handleVersionCertificatesMsg(addr, peer, data)
 			return true, nil
+		case istanbul.EvidenceMsg:
+			go sb.handleEvidenceMsg(addr, data)
+			return true, nil
 		case istanbul.ValidatorHandshakeMsg:
 			logger.Warn("Received unexpected Istanbul validator handshake message")
 			return true, nil
@@ -214,6 +223,30 @@ func (sb *Backend) NewWork() error {
 	return nil
 }
 
+// signedParentOfBlock returns whether this validator signer is in the parent seal of
+// the supplied (child) block, the canonical seal of its parent, and whether it was
+// elected to sign the parent at all.
+func (sb *Backend) signedParentOfBlock(child *types.Block) (signed bool, elected bool) {
+	number := child.Number().Uint64()
+	if number <= 1 {
+		return false, false
+	}
+	parentHeader := sb.chain.GetHeader(child.ParentHash(), number-1)
+	if parentHeader == nil {
+		return false, false
+	}
+	gpValSet := sb.getValidators(number-2, parentHeader.ParentHash)
+	gpValSetIndex, _ := gpValSet.GetByAddress(sb.Address())
+	if gpValSetIndex < 0 {
+		return false, false
+	}
+	childExtra, err := types.ExtractIstanbulExtra(child.Header())
+	if err != nil {
+		return false, false
+	}
+	return childExtra.ParentAggregatedSeal.Bitmap.Bit(gpValSetIndex) != 0, true
+}
+
 // UpdateMetricsForParentOfBlock maintains metrics around the *parent* of the supplied block.
 // To figure out if this validator signed the parent block:
 // * First check the grandparent's validator set. If not elected, it didn't.
@@ -22,6 +22,7 @@ import (
 	"io"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -67,6 +68,12 @@ type State interface {
 	// Internal functions
 	// Updates replica state given the current block undergoing consensus.
 	NewChainHead(blockNumber *big.Int)
+	// Records whether this validator signed the canonical seal of the given block.
+	// With failover enabled, schedules a takeover or a step down after missed seals.
+	NewBlockSeal(blockNumber *big.Int, signed bool)
+	// Restarts the failover timeout after which a primary steps down, called
+	// whenever the chain head advances.
+	ResetFailoverTimeout()
 	// Closes the replica state database.
 	Close() error
 
@@ -82,6 +89,21 @@ type replicaStateImpl struct {
 	startValidatingBlock *big.Int
 	stopValidatingBlock  *big.Int
 
+	// Number of seals missed in a row after which a replica takes over and
+	// a primary steps down. Zero disables failover.
+	failoverBlocks uint64
+	missedBlocks   uint64
+	lastSealBlock  *big.Int
+
+	// A primary that sees the chain head stand still for failoverTimeout steps
+	// down on its own, as it may be cut off from the network and a replica may
+	// take over. It leaves the takeover to the replica, and resumes if no seal
+	// of the validator shows up: after another failoverTimeout without a new
+	// chain head, or once the replica missed its own fence as well.
+	failoverTimeout time.Duration
+	failoverTimer   *time.Timer
+	steppedDown     bool
+
 	rsdb *ReplicaStateDB
 	mu   *sync.RWMutex
 
@@ -90,7 +112,9 @@ type replicaStateImpl struct {
 }
 
 // NewState creates a replicaState in the given replica state and opens or creates the replica state DB at `path`.
-func NewState(isReplica bool, path string, startFn, stopFn func() error) (State, error) {
+// A non zero failoverBlocks enables automatic failover after that many missed seals, and a
+// non zero failoverTimeout makes a primary step down when the chain head does not advance for that long.
+func NewState(isReplica bool, failoverBlocks uint64, failoverTimeout time.Duration, path string, startFn, stopFn func() error) (State, error) {
 	db, err := OpenReplicaStateDB(path)
 	if err != nil {
 		log.Crit("Can't open ReplicaStateDB", "err", err, "dbpath", path)
@@ -114,12 +138,19 @@ func NewState(isReplica bool, path string, startFn, stopFn func() error) (State,
 		return nil, err
 	}
 	rs.rsdb = db
+	rs.failoverBlocks = failoverBlocks
+	rs.failoverTimeout = failoverTimeout
 	rs.startFn = startFn
 	rs.stopFn = stopFn
 	if err := db.StoreReplicaState(rs); err != nil {
 		log.Warn("Can't store replica state to ReplicaStateDB", "err", err)
 		return rs, err
 	}
+	if failoverBlocks != 0 && failoverTimeout != 0 {
+		rs.mu.Lock()
+		rs.failoverTimer = time.AfterFunc(failoverTimeout, rs.failoverTimeoutExpired)
+		rs.mu.Unlock()
+	}
 	return rs, nil
 }
 
@@ -127,9 +158,116 @@ func NewState(isReplica bool, path string, startFn, stopFn func() error) (State,
 func (rs *replicaStateImpl) Close() error {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
+	if rs.failoverTimer != nil {
+		rs.failoverTimer.Stop()
+		rs.failoverTimer = nil
+	}
 	return rs.rsdb.Close()
 }
 
+// ResetFailoverTimeout restarts the failover timeout
+func (rs *replicaStateImpl) ResetFailoverTimeout() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.resetFailoverTimer()
+}
+
+// resetFailoverTimer restarts the failover timer if it is running. The caller must hold rs.mu.
+func (rs *replicaStateImpl) resetFailoverTimer() {
+	if rs.failoverTimer == nil {
+		return
+	}
+	rs.failoverTimer.Reset(rs.failoverTimeout)
+}
+
+// failoverTimeoutExpired steps a primary down to a permanent replica once the chain
+// head did not advance for failoverTimeout. The replica counting this validator's
+// missed seals only starts validating failoverBlocks+2 blocks after the last seal,
+// so the primary has stopped by then. A node that stepped down and saw neither a
+// new chain head nor a seal of the validator for another failoverTimeout resumes
+// as primary, the chain stalls for the whole network and the replica did not take
+// over. Core is stopped and started without holding rs.mu, as its handlers may be
+// waiting on the replica state.
+func (rs *replicaStateImpl) failoverTimeoutExpired() {
+	logger := log.New("func", "failoverTimeoutExpired", "timeout", rs.failoverTimeout)
+
+	rs.mu.RLock()
+	isPrimary := rs.state == primaryPermanent || rs.state == primaryInRange
+	resume := rs.steppedDown && rs.state == replicaPermanent
+	rs.mu.RUnlock()
+
+	if isPrimary {
+		logger.Warn("Chain head did not advance, stepping down to replica")
+		if err := rs.stopFn(); err != nil {
+			logger.Warn("Error stopping core", "err", err)
+		} else {
+			rs.stepDown(logger)
+		}
+	} else if resume {
+		logger.Warn("No seal of the validator since stepping down, resuming as primary")
+		if err := rs.startFn(); err != nil {
+			logger.Warn("Error starting core", "err", err)
+		} else {
+			rs.resume(logger)
+		}
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.resetFailoverTimer()
+}
+
+// stepDown switches a primary whose core was stopped to a permanent replica.
+func (rs *replicaStateImpl) stepDown(logger log.Logger) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	oldState := rs.state
+	oldStart := rs.startValidatingBlock
+	oldStop := rs.stopValidatingBlock
+
+	rs.state = replicaPermanent
+	rs.startValidatingBlock = nil
+	rs.stopValidatingBlock = nil
+	rs.steppedDown = true
+	rs.missedBlocks = 0
+
+	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.steppedDown = false
+		if startErr := rs.startFn(); startErr != nil {
+			// Stopped, but could not restart
+			logger.Crit("Error when saving rsdb in failoverTimeoutExpired in transition to replica. Tried to restart core, but that also failed", "rsdb_err", err, "start_err", startErr)
+			return
+		}
+		rs.state = oldState
+		rs.startValidatingBlock = oldStart
+		rs.stopValidatingBlock = oldStop
+		logger.Crit("Error when saving rsdb in failoverTimeoutExpired in transition to replica. Rolled back transition.", "err", err)
+	}
+}
+
+// resume switches a node that stepped down and whose core was started back to a
+// permanent primary.
+func (rs *replicaStateImpl) resume(logger log.Logger) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.state = primaryPermanent
+	rs.steppedDown = false
+	rs.missedBlocks = 0
+
+	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.steppedDown = true
+		if stopErr := rs.stopFn(); stopErr != nil {
+			// Started, but could not stop
+			logger.Crit("Error when saving rsdb in failoverTimeoutExpired in transition to primary. Tried to stop core, but that also failed", "rsdb_err", err, "stop_err", stopErr)
+			return
+		}
+		rs.state = replicaPermanent
+		logger.Crit("Error when saving rsdb in failoverTimeoutExpired in transition to primary. Rolled back transition.", "err", err)
+	}
+}
+
 // NewChainHead updates replica state and starts/stops the core if needed
 func (rs *replicaStateImpl) NewChainHead(blockNumber *big.Int) {
 	logger := log.New("func", "NewChainHead", "seq", blockNumber)
@@ -200,6 +338,89 @@ func (rs *replicaStateImpl) NewChainHead(blockNumber *big.Int) {
 	}
 }
 
+// NewBlockSeal counts the seals this validator missed in a row. Once failoverBlocks
+// are missed, a replica schedules to start validating and a primary to stop
+// validating at the same fence block. The switch goes through the persisted
+// start/stop blocks, so both nodes derive the fence from the same chain and the
+// primary has stopped by the time the replica starts, even across restarts.
+func (rs *replicaStateImpl) NewBlockSeal(blockNumber *big.Int, signed bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.failoverBlocks == 0 {
+		return
+	}
+	// Only seals of consecutive blocks count as missed in a row
+	if rs.lastSealBlock != nil && new(big.Int).Sub(blockNumber, rs.lastSealBlock).Cmp(common.Big1) != 0 {
+		rs.missedBlocks = 0
+	}
+	rs.lastSealBlock = new(big.Int).Set(blockNumber)
+	if signed {
+		rs.missedBlocks = 0
+		if rs.steppedDown {
+			rs.setSteppedDown(false)
+		}
+		return
+	}
+	rs.missedBlocks++
+	threshold := rs.failoverBlocks
+	if rs.steppedDown && rs.state == replicaPermanent {
+		// After stepping down on a timeout the other node is left to take over.
+		// It seals from the third block after its failoverBlocks-th missed seal,
+		// so this node waits for failoverBlocks+2 more missed seals, past the
+		// seal the other node would have made at its fence.
+		threshold = 2*rs.failoverBlocks + 2
+	}
+	if rs.missedBlocks < threshold {
+		return
+	}
+	rs.missedBlocks = 0
+
+	// The canonical seal of blockNumber is in its child, so consensus is already
+	// on the block after it. Switch one block later, leaving the primary a full
+	// block to schedule its stop before its core would start the fence block.
+	fenceBlock := new(big.Int).Add(blockNumber, common.Big3)
+	logger := log.New("func", "NewBlockSeal", "number", blockNumber, "missed", rs.failoverBlocks, "fence", fenceBlock)
+
+	oldState := rs.state
+	oldStart := rs.startValidatingBlock
+	oldStop := rs.stopValidatingBlock
+	oldSteppedDown := rs.steppedDown
+
+	switch rs.state {
+	case replicaPermanent:
+		logger.Warn("Primary stopped sealing, taking over")
+		rs.state = replicaWaiting
+		rs.startValidatingBlock = fenceBlock
+		rs.steppedDown = false
+	case primaryPermanent:
+		logger.Warn("Stopped sealing, stepping down to replica")
+		rs.state = primaryInRange
+		rs.stopValidatingBlock = fenceBlock
+	default:
+		// pass. A start or stop is already scheduled.
+		return
+	}
+
+	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.state = oldState
+		rs.startValidatingBlock = oldStart
+		rs.stopValidatingBlock = oldStop
+		rs.steppedDown = oldSteppedDown
+		logger.Error("Error when saving rsdb in NewBlockSeal. Rolled back failover.", "err", err)
+	}
+}
+
+// setSteppedDown persists whether this node stepped down on a failover timeout. The caller must hold rs.mu.
+func (rs *replicaStateImpl) setSteppedDown(steppedDown bool) {
+	old := rs.steppedDown
+	rs.steppedDown = steppedDown
+	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.steppedDown = old
+		log.Error("Error when saving rsdb in setSteppedDown", "steppedDown", steppedDown, "err", err)
+	}
+}
+
 // SetStartValidatingBlock sets the start block in the range [start, stop)
 func (rs *replicaStateImpl) SetStartValidatingBlock(blockNumber *big.Int) error {
 	rs.mu.Lock()
@@ -286,11 +507,14 @@ func (rs *replicaStateImpl) MakeReplica() error {
 			return err
 		}
 	}
+	oldSteppedDown := rs.steppedDown
 	rs.startValidatingBlock = nil
 	rs.stopValidatingBlock = nil
 	rs.state = replicaPermanent
+	rs.steppedDown = false
 
 	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.steppedDown = oldSteppedDown
 		if startErr := rs.startFn(); startErr != nil {
 			// Stopped, but could not restart
 			return fmt.Errorf("Error when saving rsdb in MakeReplica: %v. Tried to restart core, but failed with: %v.", err, startErr)
@@ -317,11 +541,14 @@ func (rs *replicaStateImpl) MakePrimary() error {
 			return err
 		}
 	}
+	oldSteppedDown := rs.steppedDown
 	rs.startValidatingBlock = nil
 	rs.stopValidatingBlock = nil
 	rs.state = primaryPermanent
+	rs.steppedDown = false
 
 	if err := rs.rsdb.StoreReplicaState(rs); err != nil {
+		rs.steppedDown = oldSteppedDown
 		if stopErr := rs.stopFn(); stopErr != nil {
 			// Started, but could not stop
 			return fmt.Errorf("Error when saving rsdb in MakePrimary: %v. Tried to stop core, but failed with: %v.", err, stopErr)
@@ -331,6 +558,7 @@ func (rs *replicaStateImpl) MakePrimary() error {
 		rs.stopValidatingBlock = oldStop
 		return fmt.Errorf("Error when saving rsdb in MakePrimary. err: %v", err)
 	}
+	rs.resetFailoverTimer()
 	return nil
 }
 
@@ -367,6 +595,9 @@ type ReplicaStateSummary struct {
 	IsPrimary            bool     `json:"isPrimary"`
 	StartValidatingBlock *big.Int `json:"startValidatingBlock"`
 	StopValidatingBlock  *big.Int `json:"stopValidatingBlock"`
+	FailoverBlocks       uint64   `json:"failoverBlocks"`
+	MissedBlocks         uint64   `json:"missedBlocks"`
+	SteppedDown          bool     `json:"steppedDown"`
 }
 
 func (rs *replicaStateImpl) Summary() *ReplicaStateSummary {
@@ -378,6 +609,9 @@ func (rs *replicaStateImpl) Summary() *ReplicaStateSummary {
 		IsPrimary:            rs.state == primaryPermanent || rs.state == primaryInRange,
 		StartValidatingBlock: rs.startValidatingBlock,
 		StopValidatingBlock:  rs.stopValidatingBlock,
+		FailoverBlocks:       rs.failoverBlocks,
+		MissedBlocks:         rs.missedBlocks,
+		SteppedDown:          rs.steppedDown,
 	}
 
 	return summary
@@ -387,6 +621,7 @@ type replicaStateRLP struct {
 	State                state
 	StartValidatingBlock *big.Int
 	StopValidatingBlock  *big.Int
+	SteppedDown          bool `rlp:"optional"`
 }
 
 // EncodeRLP should write the RLP encoding of its receiver to w.
@@ -402,6 +637,7 @@ func (rs *replicaStateImpl) EncodeRLP(w io.Writer) error {
 		State:                rs.state,
 		StartValidatingBlock: rs.startValidatingBlock,
 		StopValidatingBlock:  rs.stopValidatingBlock,
+		SteppedDown:          rs.steppedDown,
 	}
 	return rlp.Encode(w, entry)
 }
@@ -419,6 +655,7 @@ func (rs *replicaStateImpl) DecodeRLP(stream *rlp.Stream) error {
 
 	rs.mu = new(sync.RWMutex)
 	rs.state = data.State
+	rs.steppedDown = data.SteppedDown
 	if data.StartValidatingBlock.Cmp(common.Big0) == 0 {
 		rs.startValidatingBlock = nil
 	} else {
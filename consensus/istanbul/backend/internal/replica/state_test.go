@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 )
 
 func noop() error {
@@ -39,6 +40,9 @@ func (rs *replicaStateImpl) CheckRSDB() error {
 	if loaded.state != rs.state {
 		return fmt.Errorf("Expected loaded state to equal rs. loaded: %v; rs: %v.", loaded.state, rs.state)
 	}
+	if loaded.steppedDown != rs.steppedDown {
+		return fmt.Errorf("Expected loaded stepped down to equal rs. loaded: %v; rs: %v.", loaded.steppedDown, rs.steppedDown)
+	}
 	hasLoadedStart := loaded.startValidatingBlock != nil
 	hasRsStart := rs.startValidatingBlock != nil
 	hasLoadedStop := loaded.stopValidatingBlock != nil
@@ -65,7 +69,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 	t.Run("permanent primary", func(t *testing.T) {
 
 		seqs := []int64{0, 1, 2, 4, 8, 16, 32, 64, 128}
-		rsState, _ := NewState(false, "", noop, noop)
+		rsState, _ := NewState(false, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		for _, seq := range seqs {
 			n := big.NewInt(seq)
@@ -83,7 +87,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 
 	t.Run("permanent replica", func(t *testing.T) {
 		seqs := []int64{0, 1, 2, 4, 8, 16, 32, 64, 128}
-		rsState, _ := NewState(true, "", noop, noop)
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		for _, seq := range seqs {
 			n := big.NewInt(seq)
@@ -105,7 +109,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 
 	t.Run("replica waiting", func(t *testing.T) {
 		seqs := []int64{1, 2, 4, 8, 16, 32, 64, 128}
-		rsState, _ := NewState(true, "", noop, noop)
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		rs.SetStartValidatingBlock(big.NewInt(200))
 		for _, seq := range seqs {
@@ -146,7 +150,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 
 	t.Run("replica waiting to primary in range to permanent replica", func(t *testing.T) {
 		seqs := []int64{1, 2, 4, 8, 16, 32, 64, 128}
-		rsState, _ := NewState(true, "", noop, noop)
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		rs.SetStartValidatingBlock(big.NewInt(200))
 		rs.SetStopValidatingBlock(big.NewInt(210))
@@ -209,7 +213,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 
 	t.Run("primary in range to permanent replica", func(t *testing.T) {
 		seqs := []int64{1, 2, 4, 8, 16, 32, 64, 128, 209}
-		rsState, _ := NewState(false, "", noop, noop)
+		rsState, _ := NewState(false, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		rs.SetStopValidatingBlock(big.NewInt(210))
 
@@ -254,7 +258,7 @@ func TestIsPrimaryForSeq(t *testing.T) {
 func TestSetStartValidatingBlock(t *testing.T) {
 
 	t.Run("Respects start/stop block ordering", func(t *testing.T) {
-		rsState, _ := NewState(true, "", noop, noop)
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		rs.state = replicaWaiting
 		rs.SetStopValidatingBlock(big.NewInt(10))
@@ -276,7 +280,7 @@ func TestSetStopValidatingBlock(t *testing.T) {
 
 	//start <= seq < stop
 	t.Run("Respects start/stop block ordering", func(t *testing.T) {
-		rsState, _ := NewState(true, "", noop, noop)
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
 		rs := rsState.(*replicaStateImpl)
 		rs.SetStartValidatingBlock(big.NewInt(10))
 
@@ -296,3 +300,193 @@ func TestSetStopValidatingBlock(t *testing.T) {
 	})
 
 }
+
+func TestNewBlockSeal(t *testing.T) {
+
+	t.Run("replica takes over after missed seals", func(t *testing.T) {
+		started := 0
+		rsState, _ := NewState(true, 3, 0, "", func() error { started++; return nil }, noop)
+		rs := rsState.(*replicaStateImpl)
+
+		rs.NewBlockSeal(big.NewInt(10), true)
+		rs.NewBlockSeal(big.NewInt(11), false)
+		rs.NewBlockSeal(big.NewInt(12), false)
+		if rs.state != replicaPermanent {
+			t.Fatalf("expected to stay replica before %v missed seals, got %v", rs.failoverBlocks, rs.state)
+		}
+		rs.NewBlockSeal(big.NewInt(13), false)
+		if rs.state != replicaWaiting || rs.startValidatingBlock.Cmp(big.NewInt(16)) != 0 {
+			t.Fatalf("expected to wait to start at 16, got %v from %v", rs.state, rs.startValidatingBlock)
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+		if rs.IsPrimaryForSeq(big.NewInt(15)) || !rs.IsPrimaryForSeq(big.NewInt(16)) {
+			t.Errorf("expected to be primary from the fence block 16 on")
+		}
+
+		rs.NewChainHead(big.NewInt(16))
+		if !rs.IsPrimary() || started != 1 {
+			t.Errorf("expected to have started as primary, state %v, started %v times", rs.state, started)
+		}
+	})
+
+	t.Run("primary steps down after missed seals", func(t *testing.T) {
+		stopped := 0
+		rsState, _ := NewState(false, 2, 0, "", noop, func() error { stopped++; return nil })
+		rs := rsState.(*replicaStateImpl)
+
+		rs.NewBlockSeal(big.NewInt(5), false)
+		rs.NewBlockSeal(big.NewInt(6), false)
+		if rs.state != primaryInRange || rs.stopValidatingBlock.Cmp(big.NewInt(9)) != 0 {
+			t.Fatalf("expected to stop at 9, got %v until %v", rs.state, rs.stopValidatingBlock)
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+		if !rs.IsPrimaryForSeq(big.NewInt(8)) || rs.IsPrimaryForSeq(big.NewInt(9)) {
+			t.Errorf("expected to be primary until the fence block 9")
+		}
+
+		rs.NewChainHead(big.NewInt(9))
+		if rs.IsPrimary() || stopped != 1 {
+			t.Errorf("expected to have stopped as replica, state %v, stopped %v times", rs.state, stopped)
+		}
+	})
+
+	t.Run("only consecutive missed seals count", func(t *testing.T) {
+		rsState, _ := NewState(true, 2, 0, "", noop, noop)
+		rs := rsState.(*replicaStateImpl)
+
+		rs.NewBlockSeal(big.NewInt(20), false)
+		rs.NewBlockSeal(big.NewInt(22), false)
+		rs.NewBlockSeal(big.NewInt(23), true)
+		rs.NewBlockSeal(big.NewInt(24), false)
+		if rs.state != replicaPermanent {
+			t.Errorf("expected to stay replica, got %v", rs.state)
+		}
+	})
+
+	t.Run("failover disabled", func(t *testing.T) {
+		rsState, _ := NewState(true, 0, 0, "", noop, noop)
+		rs := rsState.(*replicaStateImpl)
+
+		for i := int64(1); i <= 10; i++ {
+			rs.NewBlockSeal(big.NewInt(i), false)
+		}
+		if rs.state != replicaPermanent {
+			t.Errorf("expected to stay replica, got %v", rs.state)
+		}
+	})
+}
+
+func TestFailoverTimeout(t *testing.T) {
+
+	t.Run("primary steps down when the head stands still", func(t *testing.T) {
+		rsState, _ := NewState(false, 2, 20*time.Millisecond, "", noop, noop)
+		rs := rsState.(*replicaStateImpl)
+		defer rs.Close()
+
+		for deadline := time.Now().Add(time.Second); rs.IsPrimary(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the primary to step down")
+			}
+		}
+		if summary := rs.Summary(); !summary.SteppedDown {
+			t.Fatalf("expected to have stepped down to replica, state %v", summary.State)
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+	})
+
+	t.Run("stepped down node leaves the takeover to the other node", func(t *testing.T) {
+		rsState, _ := NewState(true, 2, 0, "", noop, noop)
+		rs := rsState.(*replicaStateImpl)
+		rs.steppedDown = true
+
+		// Missed seals do not make it take over before the other node's fence
+		for i := int64(1); i <= 5; i++ {
+			rs.NewBlockSeal(big.NewInt(i), false)
+		}
+		if rs.state != replicaPermanent {
+			t.Fatalf("expected to stay replica after stepping down, got %v", rs.state)
+		}
+		// Until another node seals for the validator
+		rs.NewBlockSeal(big.NewInt(6), true)
+		if rs.steppedDown {
+			t.Fatalf("expected to resume failover after a seal")
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+		rs.NewBlockSeal(big.NewInt(7), false)
+		rs.NewBlockSeal(big.NewInt(8), false)
+		if rs.state != replicaWaiting {
+			t.Errorf("expected to take over after missed seals, got %v", rs.state)
+		}
+	})
+
+	t.Run("stepped down node takes over when the other node misses its fence", func(t *testing.T) {
+		rsState, _ := NewState(true, 2, 0, "", noop, noop)
+		rs := rsState.(*replicaStateImpl)
+
+		rs.NewBlockSeal(big.NewInt(10), true)
+		rs.steppedDown = true
+		for i := int64(11); i <= 15; i++ {
+			rs.NewBlockSeal(big.NewInt(i), false)
+		}
+		if rs.state != replicaPermanent {
+			t.Fatalf("expected to stay replica until the fence of the other node, got %v", rs.state)
+		}
+		rs.NewBlockSeal(big.NewInt(16), false)
+		if rs.state != replicaWaiting || rs.startValidatingBlock.Cmp(big.NewInt(19)) != 0 || rs.steppedDown {
+			t.Fatalf("expected to wait to start at 19, got %v from %v, stepped down %v", rs.state, rs.startValidatingBlock, rs.steppedDown)
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+	})
+
+	t.Run("stepped down primary resumes when the head keeps standing still", func(t *testing.T) {
+		started, stopped := 0, 0
+		rsState, _ := NewState(false, 2, 20*time.Millisecond, "", func() error { started++; return nil }, func() error { stopped++; return nil })
+		rs := rsState.(*replicaStateImpl)
+		defer rs.Close()
+
+		for deadline := time.Now().Add(time.Second); rs.IsPrimary(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the primary to step down")
+			}
+		}
+		for deadline := time.Now().Add(time.Second); !rs.IsPrimary(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the primary to resume")
+			}
+		}
+		rs.mu.Lock()
+		rs.failoverTimer.Stop()
+		rs.mu.Unlock()
+		if summary := rs.Summary(); summary.SteppedDown || stopped < 1 || started < 1 {
+			t.Errorf("expected to have resumed as primary, state %v, stopped %v, started %v times", summary.State, stopped, started)
+		}
+		if err := rs.CheckRSDB(); err != nil {
+			t.Errorf("expected RSDB to be the same, err: %v", err)
+		}
+	})
+
+	t.Run("primary stays while the head advances", func(t *testing.T) {
+		stopped := 0
+		rsState, _ := NewState(false, 2, 50*time.Millisecond, "", noop, func() error { stopped++; return nil })
+		rs := rsState.(*replicaStateImpl)
+		defer rs.Close()
+
+		for i := 0; i < 10; i++ {
+			time.Sleep(10 * time.Millisecond)
+			rs.ResetFailoverTimeout()
+		}
+		if !rs.IsPrimary() || stopped != 0 {
+			t.Errorf("expected to stay primary, state %v, stopped %v times", rs.state, stopped)
+		}
+	})
+}
@@ -19,7 +19,9 @@ package backend
 import (
 	"errors"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/consensus/istanbul/uptime"
 	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
@@ -28,6 +30,7 @@ import (
 	"github.com/mapprotocol/atlas/contracts/election"
 	"github.com/mapprotocol/atlas/contracts/epoch_rewards"
 	"github.com/mapprotocol/atlas/contracts/gold_token"
+	"github.com/mapprotocol/atlas/contracts/slasher"
 	"github.com/mapprotocol/atlas/contracts/validators"
 	"github.com/mapprotocol/atlas/core/chain"
 	"github.com/mapprotocol/atlas/core/state"
@@ -39,7 +42,7 @@ import (
 )
 
 func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.StateDB,
-	EnableRewardBlock, bn256Block, deregisterBlock *big.Int) error {
+	EnableRewardBlock, bn256Block, deregisterBlock *big.Int) (*types.EpochRewards, error) {
 	start := time.Now()
 	defer sb.rewardDistributionTimer.UpdateSince(start)
 	logger := sb.logger.New("func", "Backend.distributeEpochPaymentsAndRewards", "blocknum", header.Number.Uint64())
@@ -48,12 +51,12 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 
 	communityPartnerAddress, err := epoch_rewards.GetCommunityPartnerAddress(vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	validatorVoterReward, communityReward, maintainerReward, err := epoch_rewards.CalculateTargetEpochRewards(vmRunner)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if communityPartnerAddress == params.ZeroAddress {
@@ -68,64 +71,84 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 	if len(signerSet) == 0 {
 		err := errors.New("Unable to fetch validator set to update scores and distribute rewards")
 		logger.Error(err.Error())
-		return err
+		return nil, err
 	}
 	validators_, err := sb.GetAccountsFromSigners(vmRunner, signerSet)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	uptimeRets, ignores, err := sb.updateValidatorScores(header, state, signerSet)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	record := &types.EpochRewards{
+		Epoch:            istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize()),
+		Number:           header.Number.Uint64(),
+		CommunityReward:  new(big.Int),
+		MaintainerReward: new(big.Int),
+		Validators:       make([]types.ValidatorEpochReward, len(signerSet)),
+	}
+	for i, val := range signerSet {
+		record.Validators[i] = types.ValidatorEpochReward{
+			Account:         validators_[i],
+			Signer:          val.Address(),
+			Uptime:          uptimeRets[i],
+			ValidatorReward: new(big.Int),
+			VoterReward:     new(big.Int),
+			Ignored:         ignores[i],
+		}
 	}
 
 	if header.Number.Cmp(EnableRewardBlock) > 0 {
 		scores, err := sb.calculatePaymentScoreDenominator(vmRunner, uptimeRets, ignores)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// Reward Validators And voters
-		totalValidatorRewards, voterRewardData, err := sb.distributeValidatorRewards(vmRunner, signerSet, validators_, validatorVoterReward, scores)
+		totalValidatorRewards, voterRewardData, err := sb.distributeValidatorRewards(vmRunner, signerSet, validators_, validatorVoterReward, scores, record)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Info("totalValidatorRewards", "maxReward", totalValidatorRewards.String())
 		totalVoterRewards, err := sb.distributeVoterRewards(vmRunner, validators_, voterRewardData)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Info("distributeVoterRewards", "totalVoterRewards", totalVoterRewards.String())
 		if communityReward.Cmp(new(big.Int)) != 0 {
 			if err = gold_token.Mint(vmRunner, communityPartnerAddress, communityReward); err != nil {
-				return err
+				return nil, err
 			}
+			record.CommunityReward = communityReward
 		}
 		// mint to mgrMaintainer
 		if maintainerReward.Cmp(new(big.Int)) != 0 {
 			mmAddress, err := epoch_rewards.GetMgrMaintainerAddress(vmRunner)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if mmAddress != params.ZeroAddress {
 				if err = gold_token.Mint(vmRunner, mmAddress, maintainerReward); err != nil {
 					log.Error("reward to maintainer fail", "addr", mmAddress, "maintainerReward", maintainerReward.String())
-					return err
+					return nil, err
 				}
 				log.Info("reward to maintainer success", "addr", mmAddress, "maintainerReward", maintainerReward.String())
+				record.MaintainerReward = maintainerReward
 			}
 		}
 	}
+
 	//----------------------------- deRegister -------------------
 	if header.Number.Cmp(deregisterBlock) > 0 {
 		deRegisters, err := sb.deRegisterAllValidatorsInPending(vmRunner, true)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Info("deRegister AllValidators InPending", "deRegisters", deRegisters)
 	} else {
 		deRegisters, err := sb.deRegisterAllValidatorsInPending(vmRunner, false)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Info("deRegister AllValidators InPending", "deRegisters", deRegisters)
 	}
@@ -136,23 +159,23 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 		// active the next epoch validators
 		vals, err := sb.GetValidatorAccounts(vmRunner)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		b, err = sb.activeAllPending(vmRunner, vals)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		b, err = sb.activeAllPending(vmRunner, validators_)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	log.Info("Automatic active pending voter", "success", b)
 	//----------------------------------------------------------------------
 
-	return nil
+	return record, nil
 }
 
 func (sb *Backend) updateValidatorScores(header *types.Header, state *state.StateDB, valSet []istanbul.Validator) ([]*big.Int, []bool, error) {
@@ -194,8 +217,9 @@ func (sb *Backend) updateValidatorScores(header *types.Header, state *state.Stat
 
 /*
 @param maxReward is epochReward for all validators
+@param record collects the payment of each validator of signerSet
 */
-func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, signerSet []istanbul.Validator, valSets []common.Address, maxReward *big.Int, scoreDenominator *big.Int) (*big.Int, map[common.Address]*big.Int, error) {
+func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, signerSet []istanbul.Validator, valSets []common.Address, maxReward *big.Int, scoreDenominator *big.Int, record *types.EpochRewards) (*big.Int, map[common.Address]*big.Int, error) {
 	totalValidatorRewards := big.NewInt(0)
 	voterRewards := make(map[common.Address]*big.Int, len(signerSet))
 	for i, val := range signerSet {
@@ -207,6 +231,7 @@ func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, signerSet [
 		}
 		voterRewards[valSets[i]] = voterReward
 		totalValidatorRewards.Add(totalValidatorRewards, validatorReward)
+		record.Validators[i].ValidatorReward, record.Validators[i].VoterReward = validatorReward, voterReward
 	}
 	return totalValidatorRewards, voterRewards, nil
 }
@@ -319,3 +344,299 @@ func (sb *Backend) GetValidatorAccounts(vmRunner vm.EVMRunner) ([]common.Address
 	}
 	return sb.GetAccountsFromSignersAddress(vmRunner, signers)
 }
+
+// offenceKey is the POW state key under which a slashed offence is recorded.
+func offenceKey(o offence) common.Hash {
+	return crypto.Keccak256Hash([]byte("double-sign"), o.offender.Bytes(), new(big.Int).SetUint64(o.sequence).Bytes())
+}
+
+// excludedSignersKey is the POW state key of the signers excluded from the
+// election at the end of epoch.
+func excludedSignersKey(epoch uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("excluded"), new(big.Int).SetUint64(epoch).Bytes())
+}
+
+func isOffenceProcessed(state *state.StateDB, o offence) bool {
+	return len(state.GetPOWState(params.SlashingAddress, offenceKey(o))) > 0
+}
+
+// excludedSigners returns the signers slashed during epoch, they are not elected
+// at the end of it.
+func excludedSigners(state *state.StateDB, epoch uint64) []common.Address {
+	data := state.GetPOWState(params.SlashingAddress, excludedSignersKey(epoch))
+	if len(data) == 0 {
+		return nil
+	}
+	var signers []common.Address
+	if err := rlp.DecodeBytes(data, &signers); err != nil {
+		log.Error("Failed to decode excluded signers", "epoch", epoch, "err", err)
+		return nil
+	}
+	return signers
+}
+
+// slashValidator slashes a validator on behalf of a slasher, tests replace it to
+// run without the core contracts.
+var slashValidator = slasher.SlashValidator
+
+// slashDoubleSigners slashes the offenders of the double signing evidence
+// included in header and excludes them from the next election. Evidence against
+// a non validator or for an offence that was already slashed is ignored.
+func (sb *Backend) slashDoubleSigners(header *types.Header, state *state.StateDB) error {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.Evidence) == 0 {
+		return nil
+	}
+	logger := sb.logger.New("func", "Backend.slashDoubleSigners", "blocknum", header.Number.Uint64())
+	parent := sb.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return errUnknownBlock
+	}
+
+	var (
+		vmRunner = sb.chain.NewEVMRunner(header, state)
+		epoch    = istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
+		excluded = excludedSigners(state, epoch)
+		slashed  int
+	)
+	for _, enc := range extra.Evidence {
+		evidence, err := istanbul.DecodeEvidence(enc)
+		if err != nil {
+			return err
+		}
+		o := evidenceOffence(evidence)
+		if isOffenceProcessed(state, o) {
+			continue
+		}
+		if !sb.isValidatorAt(parent, evidence) {
+			logger.Warn("Ignoring double signing evidence from a non validator", "evidence", evidence)
+			continue
+		}
+
+		// The offence is only marked processed once the slash went through, so the
+		// evidence stays includable until then.
+		snapshot := state.Snapshot()
+		err = slashValidator(vmRunner, params.DoubleSigningSlasherRegistryId, o.offender, header.Coinbase,
+			params.DoubleSigningPenalty, params.DoubleSigningReward)
+		if err != nil {
+			logger.Error("Failed to slash double signing validator", "evidence", evidence, "err", err)
+			state.RevertToSnapshot(snapshot)
+			continue
+		}
+		state.SetPOWState(params.SlashingAddress, offenceKey(o), []byte{1})
+		if !containsAddress(excluded, o.offender) {
+			excluded = append(excluded, o.offender)
+			slashed++
+		}
+		if err := sb.jailSigner(header, vmRunner, state, o.offender, epoch); err != nil {
+			logger.Error("Failed to jail double signing validator", "evidence", evidence, "err", err)
+		}
+		logger.Info("Slashed double signing validator", "evidence", evidence)
+	}
+	if slashed == 0 {
+		return nil
+	}
+
+	data, err := rlp.EncodeToBytes(excluded)
+	if err != nil {
+		return err
+	}
+	state.SetPOWState(params.SlashingAddress, excludedSignersKey(epoch), data)
+	return nil
+}
+
+// slashDowntimeValidators slashes the validators of the epoch ending with header
+// that missed at least params.SlashableDowntimeWindows consecutive lookback
+// windows and excludes them from the election of the next epoch.
+func (sb *Backend) slashDowntimeValidators(header *types.Header, state *state.StateDB) error {
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
+	logger := sb.logger.New("func", "Backend.slashDowntimeValidators", "blocknum", header.Number.Uint64(), "epoch", epoch)
+
+	valSet := sb.GetValidators(big.NewInt(header.Number.Int64()-1), header.ParentHash)
+	if len(valSet) == 0 {
+		return errors.New("unable to fetch validator set to check downtime")
+	}
+
+	downtime, err := sb.epochDowntime(header, epoch, sb.LookbackWindow(header, state), len(valSet))
+	if err != nil {
+		return err
+	}
+
+	var (
+		vmRunner = sb.chain.NewEVMRunner(header, state)
+		excluded = excludedSigners(state, epoch)
+		slashed  int
+	)
+	for i, val := range valSet {
+		if downtime[i].MaxMissedWindows < params.SlashableDowntimeWindows {
+			continue
+		}
+
+		// The validator is only jailed and excluded once the slash went through,
+		// a failure reverts all of them together.
+		snapshot := state.Snapshot()
+		err := slashValidator(vmRunner, params.DowntimeSlasherRegistryId, val.Address(), header.Coinbase,
+			params.DowntimePenalty, params.DowntimeReward)
+		if err != nil {
+			logger.Error("Failed to slash validator for downtime", "address", val.Address(), "downtime", &downtime[i], "err", err)
+			state.RevertToSnapshot(snapshot)
+			continue
+		}
+		if err := sb.jailSigner(header, vmRunner, state, val.Address(), epoch); err != nil {
+			logger.Error("Failed to jail validator for downtime", "address", val.Address(), "err", err)
+			state.RevertToSnapshot(snapshot)
+			continue
+		}
+		if !containsAddress(excluded, val.Address()) {
+			excluded = append(excluded, val.Address())
+			slashed++
+		}
+		logger.Info("Slashed validator for downtime", "address", val.Address(), "downtime", &downtime[i])
+	}
+	if slashed == 0 {
+		return nil
+	}
+
+	data, err := rlp.EncodeToBytes(excluded)
+	if err != nil {
+		return err
+	}
+	state.SetPOWState(params.SlashingAddress, excludedSignersKey(epoch), data)
+	return nil
+}
+
+// epochDowntime returns the missed windows of the valSetSize validators of epoch up to
+// header, replayed from the parent seal bitmaps of the chain of header.
+func (sb *Backend) epochDowntime(header *types.Header, epoch, lookbackWindow uint64, valSetSize int) ([]uptime.DowntimeEntry, error) {
+	first, err := istanbul.GetEpochFirstBlockNumber(epoch, sb.EpochSize())
+	if err != nil {
+		return nil, err
+	}
+	var bitmaps []*big.Int
+	if number := header.Number.Uint64(); number > first {
+		bitmaps = make([]*big.Int, number-first)
+	}
+	h := header
+	for number := header.Number.Uint64(); number > first; number-- {
+		if h == nil {
+			return nil, errUnknownBlock
+		}
+		extra, err := types.ExtractIstanbulExtra(h)
+		if err != nil {
+			return nil, err
+		}
+		bitmap := extra.ParentAggregatedSeal.Bitmap
+		if bitmap == nil {
+			bitmap = new(big.Int)
+		}
+		bitmaps[number-first-1] = bitmap
+		h = sb.chain.GetHeader(h.ParentHash, number-1)
+	}
+	return uptime.EpochDowntime(epoch, sb.EpochSize(), lookbackWindow, valSetSize, bitmaps)
+}
+
+// jailSigner jails the slashed signer until params.JailEpochs epochs after epoch,
+// the elections skip it until its account unjails it. Validators are only jailed
+// from the jail fork on, where the jail contract serves unjail requests.
+func (sb *Backend) jailSigner(header *types.Header, vmRunner vm.EVMRunner, state *state.StateDB, signer common.Address, epoch uint64) error {
+	if !sb.chain.Config().IsJail(header.Number) {
+		return nil
+	}
+	account, err := accounts.GetSignerToAccountMethod(vmRunner, signer)
+	if err != nil {
+		return err
+	}
+	return vm.JailValidator(state, signer, account, epoch+params.JailEpochs)
+}
+
+// filterExcludedSigners removes the signers excluded during the epoch of header
+// from the elected ones, unless no signer would be left.
+func filterExcludedSigners(header *types.Header, state *state.StateDB, epochSize uint64, elected []common.Address) []common.Address {
+	excluded := excludedSigners(state, istanbul.GetEpochNumber(header.Number.Uint64(), epochSize))
+	if len(excluded) == 0 {
+		return elected
+	}
+	filtered := make([]common.Address, 0, len(elected))
+	for _, signer := range elected {
+		if !containsAddress(excluded, signer) {
+			filtered = append(filtered, signer)
+		}
+	}
+	if len(filtered) == 0 {
+		return elected
+	}
+	return filtered
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// validatorVotes is the active votes received by a validator signer.
+type validatorVotes struct {
+	Signer common.Address
+	Votes  *big.Int
+}
+
+func validatorVotesKey(epoch uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("votes"), new(big.Int).SetUint64(epoch).Bytes())
+}
+
+// recordValidatorVotes stores the active votes of the validators elected in the last
+// block of an epoch, they weight the proposer selection during the next epoch.
+func (sb *Backend) recordValidatorVotes(header *types.Header, state *state.StateDB) error {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	added, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+	if err != nil {
+		return err
+	}
+	valSet := sb.getValidators(header.Number.Uint64()-1, header.ParentHash).Copy()
+	if !valSet.RemoveValidators(extra.RemovedValidators) || !valSet.AddValidators(added) {
+		return errInvalidValidatorSetDiff
+	}
+
+	vmRunner := sb.chain.NewEVMRunner(header, state)
+	accounts, err := sb.GetAccountsFromSigners(vmRunner, valSet.List())
+	if err != nil {
+		return err
+	}
+	votes := make([]validatorVotes, len(accounts))
+	for i, account := range accounts {
+		v, err := election.GetActiveVotesForValidator(vmRunner, account)
+		if err != nil {
+			return err
+		}
+		votes[i] = validatorVotes{Signer: valSet.GetByIndex(uint64(i)).Address(), Votes: v}
+	}
+	data, err := rlp.EncodeToBytes(votes)
+	if err != nil {
+		return err
+	}
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.config.Epoch) + 1
+	state.SetPOWState(params.ProposerVotesAddress, validatorVotesKey(epoch), data)
+	return nil
+}
+
+// recordedValidatorVotes returns the votes recorded for the validators of epoch.
+func recordedValidatorVotes(state *state.StateDB, epoch uint64) ([]validatorVotes, error) {
+	data := state.GetPOWState(params.ProposerVotesAddress, validatorVotesKey(epoch))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var votes []validatorVotes
+	if err := rlp.DecodeBytes(data, &votes); err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
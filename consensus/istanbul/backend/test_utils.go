@@ -319,7 +319,14 @@ func SignBLSFn(key *ecdsa.PrivateKey) istanbul.BLSSignerFn {
 		key, _ = generatePrivateKey()
 	}
 
-	return func(_ accounts.Account, data []byte, extraData []byte, useComposite, cip22 bool, fork *big.Int, cur *big.Int) (blscrypto.SerializedSignature, error) {
+	return func(_ accounts.Account, curve blscrypto.BLSCryptoSelector, data []byte, extraData []byte, useComposite, cip22 bool, fork *big.Int, cur *big.Int) (blscrypto.SerializedSignature, error) {
+		if _, ok := curve.(blscrypto.BN256); !ok {
+			keybytes, err := curve.ECDSAToBLS(key)
+			if err != nil {
+				return blscrypto.SerializedSignature{}, err
+			}
+			return curve.Sign(keybytes, data, extraData, useComposite, cip22, fork, cur)
+		}
 
 		keybytes, err := blscrypto.CryptoType().ECDSAToBLS(key)
 		if err != nil {
@@ -37,6 +37,7 @@ const (
 	RoundRobin ProposerPolicy = iota
 	Sticky
 	ShuffledRoundRobin
+	StakeWeighted
 )
 
 // Config represents the istanbul consensus engine
@@ -55,6 +56,7 @@ type Config struct {
 	RoundStateDBPath            string         `toml:",omitempty"` // The location for the round states DB
 	Validator                   bool           `toml:",omitempty"` // Specified if this node is configured to validate  (specifically if --mine command line is set)
 	Replica                     bool           `toml:",omitempty"` // Specified if this node is configured to be a replica
+	ReplicaFailoverBlocks       uint64         `toml:",omitempty"` // If non-zero, a replica takes over and a primary steps down after the validator missed this many seals in a row
 
 	// Proxy Configs
 	Proxy                   bool           `toml:",omitempty"` // Specifies if this node is a proxy
@@ -128,3 +130,22 @@ func ApplyParamsChainConfigToConfig(chainConfig *params2.ChainConfig, config *Co
 
 	return nil
 }
+
+// RoundTimeouts are the round change timeouts, in milliseconds, in force for
+// an epoch. See Config for the meaning of each of them.
+type RoundTimeouts struct {
+	RequestTimeout              uint64
+	TimeoutBackoffFactor        uint64
+	MinResendRoundChangeTimeout uint64
+	MaxResendRoundChangeTimeout uint64
+}
+
+// RoundTimeouts returns the round change timeouts set in the config
+func (c *Config) RoundTimeouts() *RoundTimeouts {
+	return &RoundTimeouts{
+		RequestTimeout:              c.RequestTimeout,
+		TimeoutBackoffFactor:        c.TimeoutBackoffFactor,
+		MinResendRoundChangeTimeout: c.MinResendRoundChangeTimeout,
+		MaxResendRoundChangeTimeout: c.MaxResendRoundChangeTimeout,
+	}
+}
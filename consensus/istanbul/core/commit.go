@@ -39,10 +39,11 @@ func (c *core) sendCommit() {
 	c.broadcastCommit(sub)
 }
 
-func (c *core) generateCommittedSeal(sub *istanbul.Subject) (blscrypto.SerializedSignature, error) {
+// generateCommittedSeal signs the committed seal of sub on the curve of the validator set
+func (c *core) generateCommittedSeal(curve blscrypto.BLSCryptoSelector, sub *istanbul.Subject) (blscrypto.SerializedSignature, error) {
 	fork, cur := new(big.Int).Set(c.backend.ChainConfig().BN256ForkBlock), new(big.Int).Set(sub.View.Sequence)
 	seal := PrepareCommittedSeal(sub.Digest, sub.View.Round)
-	committedSeal, err := c.backend.SignBLS(seal, []byte{}, false, false, fork, cur)
+	committedSeal, err := c.backend.SignBLS(curve, seal, []byte{}, false, false, fork, cur)
 	if err != nil {
 		return blscrypto.SerializedSignature{}, err
 	}
@@ -56,39 +57,45 @@ func (c *core) generateEpochValidatorSetData(blockNumber uint64, round uint8, bl
 		return nil, nil, false, errNotLastBlockInEpoch
 	}
 
-	// Serialize the public keys for the validators in the validator set.
-	blsPubKeys := []blscrypto.SerializedPublicKey{}
-	for _, v := range newValSet.List() {
-		blsPubKeys = append(blsPubKeys, v.BLSPublicKey())
-	}
-
-	maxNonSigners := uint32(newValSet.Size() - newValSet.MinQuorumSize())
-
-	// Before the Donut fork, use the snark data encoding with epoch entropy.
-
 	// Retrieve the block hash for the last block of the previous epoch.
 	parentEpochBlockHash := c.backend.HashForBlock(blockNumber - c.config.Epoch)
 	if blockNumber > 0 && parentEpochBlockHash == (common.Hash{}) {
 		return nil, nil, false, errors.New("unknown block")
 	}
 
-	maxNonSigners = maxValidators - uint32(newValSet.MinQuorumSize())
-	message, extraData, err := blscrypto.CryptoType().EncodeEpochSnarkDataCIP22(
+	message, extraData, err := EncodeEpochValidatorSetData(newValSet,
+		istanbul.GetEpochNumber(blockNumber, c.config.Epoch), round, blockHash, parentEpochBlockHash)
+	// This is after the Donut hardfork, so signify this uses CIP22.
+	return message, extraData, true, err
+}
+
+// EncodeEpochValidatorSetData serializes the validator set elected in the last block
+// of an epoch into the data signed by the epoch validator set seal, in the encoding
+// of the curve of its keys. parentEpochBlockHash is the hash of the last block of
+// the previous epoch.
+func EncodeEpochValidatorSetData(newValSet istanbul.ValidatorSet, epoch uint64,
+	round uint8, blockHash, parentEpochBlockHash common.Hash) ([]byte, []byte, error) {
+	// Serialize the public keys for the validators in the validator set.
+	blsPubKeys := []blscrypto.SerializedPublicKey{}
+	for _, v := range newValSet.List() {
+		blsPubKeys = append(blsPubKeys, v.BLSPublicKey())
+	}
+
+	maxNonSigners := maxValidators - uint32(newValSet.MinQuorumSize())
+	return newValSet.BLSCrypto().EncodeEpochSnarkData(
 		blsPubKeys, maxNonSigners, maxValidators,
-		uint16(istanbul.GetEpochNumber(blockNumber, c.config.Epoch)),
+		uint16(epoch),
 		round,
 		blscrypto.EpochEntropyFromHash(blockHash),
 		blscrypto.EpochEntropyFromHash(parentEpochBlockHash),
 	)
-	// This is after the Donut hardfork, so signify this uses CIP22.
-	return message, extraData, true, err
 }
 
 func (c *core) broadcastCommit(sub *istanbul.Subject) {
 	logger := c.newLogger("func", "broadcastCommit")
 
 	fork, cur := new(big.Int).Set(c.backend.ChainConfig().BN256ForkBlock), new(big.Int).Set(sub.View.Sequence)
-	committedSeal, err := c.generateCommittedSeal(sub)
+	committedSeal, err := c.generateCommittedSeal(c.current.ValidatorSet().BLSCrypto(), sub)
 	if err != nil {
 		logger.Error("Failed to commit seal", "err", err)
 		return
@@ -107,7 +114,7 @@ func (c *core) broadcastCommit(sub *istanbul.Subject) {
 	}
 	var epochValidatorSetSeal blscrypto.SerializedSignature
 	if err == nil {
-		epochValidatorSetSeal, err = c.backend.SignBLS(epochValidatorSetData, epochValidatorSetExtraData, true, cip22, fork, cur)
+		epochValidatorSetSeal, err = c.backend.SignBLS(c.current.ValidatorSet().BLSCrypto(), epochValidatorSetData, epochValidatorSetExtraData, true, cip22, fork, cur)
 		if err != nil {
 			logger.Error("Failed to sign epoch validator set seal", "err", err)
 			return
@@ -151,7 +158,11 @@ func (c *core) handleCheckedCommitForPreviousSequence(msg *istanbul.Message, com
 	headBlock := c.backend.GetCurrentHeadBlock()
 	// Retrieve the validator set for the previous proposal (which should
 	// match the one broadcast)
-	parentValset := c.backend.ParentBlockValidators(headBlock)
+	parentValset, err := c.backend.ParentBlockValidators(headBlock)
+	if err != nil {
+		logger.Warn("Failed to get the parent validator set", "err", err)
+		return err
+	}
 	_, validator := parentValset.GetByAddress(msg.Address)
 	if validator == nil {
 		return errInvalidValidatorAddress
@@ -191,6 +202,7 @@ func (c *core) handleCheckedCommitForCurrentSequence(msg *istanbul.Message, comm
 	if err := c.verifyCommittedSeal(commit, validator, fork, cur); err != nil {
 		return errInvalidCommittedSeal
 	}
+	c.detectEquivocation(msg)
 
 	newValSet, err := c.backend.NextBlockValidators(c.current.Proposal())
 	if err != nil {
@@ -260,7 +272,7 @@ func (c *core) verifyCommit(commit *istanbul.CommittedSubject) error {
 // verifyCommittedSeal verifies the commit seal in the received COMMIT message
 func (c *core) verifyCommittedSeal(comSub *istanbul.CommittedSubject, src istanbul.Validator, fork, cur *big.Int) error {
 	seal := PrepareCommittedSeal(comSub.Subject.Digest, comSub.Subject.View.Round)
-	return blscrypto.CryptoType().VerifySignature(src.BLSPublicKey(), seal, []byte{}, comSub.CommittedSeal,
+	return blscrypto.CryptoTypeOf(src.BLSCurve()).VerifySignature(src.BLSPublicKey(), seal, []byte{}, comSub.CommittedSeal,
 		false, false, fork, cur)
 }
 
@@ -278,6 +290,6 @@ func (c *core) verifyEpochValidatorSetSeal(comSub *istanbul.CommittedSubject, bl
 		return err
 	}
 	fork, cur := new(big.Int).Set(c.backend.ChainConfig().BN256ForkBlock), big.NewInt(int64(blockNumber))
-	return blscrypto.CryptoType().VerifySignature(src.BLSPublicKey(), epochData, epochExtraData,
+	return blscrypto.CryptoTypeOf(src.BLSCurve()).VerifySignature(src.BLSPublicKey(), epochData, epochExtraData,
 		comSub.EpochValidatorSetSeal, true, cip22, fork, cur)
 }
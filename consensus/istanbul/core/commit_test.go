@@ -204,17 +204,14 @@ OUTER:
 
 		for i, v := range test.system.backends {
 			validator := r0.current.ValidatorSet().GetByIndex(uint64(i))
-			privateKey, _ := bls.DeserializePrivateKey(test.system.validatorsKeys[i])
-			//defer privateKey.Destroy()
+			proposal := v.engine.(*core).current.Proposal()
 
-			hash := PrepareCommittedSeal(v.engine.(*core).current.Proposal().Hash(), v.engine.(*core).current.Round())
-			//signature, _ := privateKey.SignMessage(hash, []byte{}, false, false)
-			pubkey := privateKey.ToPublic()
-			signature, _ := bls.Sign(privateKey, pubkey, hash)
-			signatureBytes := signature.Marshal()
+			hash := PrepareCommittedSeal(proposal.Hash(), v.engine.(*core).current.Round())
+			fork, cur := new(big.Int).Set(v.ChainConfig().BN256ForkBlock), new(big.Int).Set(proposal.Number())
+			signature, _ := bls.CryptoTypeOf(validator.BLSCurve()).Sign(test.system.validatorsKeys[i], hash, []byte{}, false, false, fork, cur)
 
 			msg := istanbul.NewCommitMessage(
-				&istanbul.CommittedSubject{Subject: v.engine.(*core).current.Subject(), CommittedSeal: signatureBytes},
+				&istanbul.CommittedSubject{Subject: v.engine.(*core).current.Subject(), CommittedSeal: signature[:]},
 				validator.Address(),
 			)
 
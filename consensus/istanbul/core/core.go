@@ -48,7 +48,7 @@ type CoreBackend interface {
 	ChainConfig() *params.ChainConfig
 
 	// Validators returns the validator set
-	Validators(proposal istanbul.Proposal) istanbul.ValidatorSet
+	Validators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error)
 	NextBlockValidators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error)
 
 	// EventMux returns the event mux in backend
@@ -74,7 +74,7 @@ type CoreBackend interface {
 	Sign([]byte) ([]byte, error)
 
 	// Sign with the data with the BLS key, using either a direct or composite hasher and optional cip22 encoding
-	SignBLS([]byte, []byte, bool, bool, *big.Int, *big.Int) (blscrypto.SerializedSignature, error)
+	SignBLS(blscrypto.BLSCryptoSelector, []byte, []byte, bool, bool, *big.Int, *big.Int) (blscrypto.SerializedSignature, error)
 
 	// CheckSignature verifies the signature by checking if it's signed by
 	// the given validator
@@ -99,12 +99,28 @@ type CoreBackend interface {
 	HashForBlock(number uint64) common.Hash
 
 	// ParentBlockValidators returns the validator set of the given proposal's parent block
-	ParentBlockValidators(proposal istanbul.Proposal) istanbul.ValidatorSet
+	ParentBlockValidators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error)
 
 	IsPrimaryForSeq(seq *big.Int) bool
 	UpdateReplicaState(seq *big.Int)
+
+	// ReportEquivocation hands over evidence of a validator signing conflicting messages
+	ReportEquivocation(evidence *istanbul.Evidence)
+
+	// RoundTimeouts returns the round change timeouts in force for the block after head,
+	// or nil to use the configured ones
+	RoundTimeouts(head istanbul.Proposal) *istanbul.RoundTimeouts
 }
 
+// Reasons for moving to another round, as recorded in the round state
+const (
+	roundChangeReasonTimeout      = "timeout"
+	roundChangeReasonCertificate  = "round change certificate"
+	roundChangeReasonQuorum       = "quorum of round changes"
+	roundChangeReasonFPlusOne     = "f+1 round changes"
+	roundChangeReasonCommitFailed = "commit failed"
+)
+
 type core struct {
 	config         *istanbul.Config
 	address        common.Address
@@ -122,6 +138,9 @@ type core struct {
 	roundChangeTimer   *time.Timer
 	roundChangeTimerMu sync.RWMutex
 
+	// Round change timeouts of the current sequence, nil for the configured ones
+	timeouts *istanbul.RoundTimeouts
+
 	validateFn func([]byte, []byte) (common.Address, error)
 
 	backlog MsgBacklog
@@ -131,6 +150,7 @@ type core struct {
 	handlerWg *sync.WaitGroup
 
 	roundChangeSet *roundChangeSet
+	equivocations  *equivocationDetector
 
 	pendingRequests   *prque.Prque
 	pendingRequestsMu *sync.Mutex
@@ -166,6 +186,7 @@ func New(backend CoreBackend, config *istanbul.Config) Engine {
 		pendingRequestsMu:         new(sync.Mutex),
 		consensusTimestamp:        time.Time{},
 		rsdb:                      rsdb,
+		equivocations:             newEquivocationDetector(),
 		consensusPrepareTimeGauge: metrics.NewRegisteredGauge("consensus/istanbul/core/consensus_prepare", nil),
 		consensusCommitTimeGauge:  metrics.NewRegisteredGauge("consensus/istanbul/core/consensus_commit", nil),
 		verifyGauge:               metrics.NewRegisteredGauge("consensus/istanbul/core/verify", nil),
@@ -232,7 +253,7 @@ func PrepareCommittedSeal(hash common.Hash, round *big.Int) []byte {
 
 // GetAggregatedSeal aggregates all the given seals for a given message set to a bls aggregated
 // signature and bitmap
-func GetAggregatedSeal(seals MessageSet, round *big.Int) (types.IstanbulAggregatedSeal, error) {
+func GetAggregatedSeal(curve blscrypto.BLSCryptoSelector, seals MessageSet, round *big.Int) (types.IstanbulAggregatedSeal, error) {
 	bitmap := big.NewInt(0)
 	committedSeals := make([][]byte, seals.Size())
 	for i, v := range seals.Values() {
@@ -247,7 +268,7 @@ func GetAggregatedSeal(seals MessageSet, round *big.Int) (types.IstanbulAggregat
 		bitmap.SetBit(bitmap, int(j), 1)
 	}
 
-	asig, err := blscrypto.CryptoType().AggregateSignatures(committedSeals)
+	asig, err := curve.AggregateSignatures(committedSeals)
 	if err != nil {
 		return types.IstanbulAggregatedSeal{}, err
 	}
@@ -259,7 +280,7 @@ func GetAggregatedSeal(seals MessageSet, round *big.Int) (types.IstanbulAggregat
 // validator was not found in the previous bitmap.
 // This function assumes that the provided seals' validator set is the same one
 // which produced the provided bitmap
-func UnionOfSeals(aggregatedSignature types.IstanbulAggregatedSeal, seals MessageSet) (types.IstanbulAggregatedSeal, error) {
+func UnionOfSeals(curve blscrypto.BLSCryptoSelector, aggregatedSignature types.IstanbulAggregatedSeal, seals MessageSet) (types.IstanbulAggregatedSeal, error) {
 	// TODO(asa): Check for round equality...
 	// Check who already has signed the message
 	newBitmap := new(big.Int).Set(aggregatedSignature.Bitmap)
@@ -279,7 +300,7 @@ func UnionOfSeals(aggregatedSignature types.IstanbulAggregatedSeal, seals Messag
 		}
 	}
 
-	asig, err := blscrypto.CryptoType().AggregateSignatures(committedSeals)
+	asig, err := curve.AggregateSignatures(committedSeals)
 	if err != nil {
 		return types.IstanbulAggregatedSeal{}, err
 	}
@@ -373,18 +394,19 @@ func (c *core) commit() error {
 
 	proposal := c.current.Proposal()
 	if proposal != nil {
-		aggregatedSeal, err := GetAggregatedSeal(c.current.Commits(), c.current.Round())
+		curve := c.current.ValidatorSet().BLSCrypto()
+		aggregatedSeal, err := GetAggregatedSeal(curve, c.current.Commits(), c.current.Round())
 		if err != nil {
 			nextRound := new(big.Int).Add(c.current.Round(), common.Big1)
 			logger.Warn("Error on commit, waiting for desired round", "reason", "getAggregatedSeal", "err", err, "desired_round", nextRound)
-			c.waitForDesiredRound(nextRound)
+			c.waitForDesiredRound(nextRound, roundChangeReasonCommitFailed)
 			return nil
 		}
-		aggregatedEpochValidatorSetSeal, err := GetAggregatedEpochValidatorSetSeal(proposal.Number().Uint64(), c.config.Epoch, c.current.Commits())
+		aggregatedEpochValidatorSetSeal, err := GetAggregatedEpochValidatorSetSeal(curve, proposal.Number().Uint64(), c.config.Epoch, c.current.Commits())
 		if err != nil {
 			nextRound := new(big.Int).Add(c.current.Round(), common.Big1)
 			c.logger.Warn("Error on commit, waiting for desired round", "reason", "GetAggregatedEpochValidatorSetSeal", "err", err, "desired_round", nextRound)
-			c.waitForDesiredRound(nextRound)
+			c.waitForDesiredRound(nextRound, roundChangeReasonCommitFailed)
 			return nil
 		}
 
@@ -393,7 +415,7 @@ func (c *core) commit() error {
 		if err := c.backend.Commit(proposal, aggregatedSeal, aggregatedEpochValidatorSetSeal, result); err != nil {
 			nextRound := new(big.Int).Add(c.current.Round(), common.Big1)
 			logger.Warn("Error on commit, waiting for desired round", "reason", "backend.Commit", "err", err, "desired_round", nextRound)
-			c.waitForDesiredRound(nextRound)
+			c.waitForDesiredRound(nextRound, roundChangeReasonCommitFailed)
 			return nil
 		}
 	}
@@ -404,7 +426,7 @@ func (c *core) commit() error {
 
 // GetAggregatedEpochValidatorSetSeal aggregates all the given seals for the SNARK-friendly epoch encoding
 // to a bls aggregated signature. Returns an empty signature on a non-epoch block.
-func GetAggregatedEpochValidatorSetSeal(blockNumber, epoch uint64, seals MessageSet) (types.IstanbulEpochValidatorSetSeal, error) {
+func GetAggregatedEpochValidatorSetSeal(curve blscrypto.BLSCryptoSelector, blockNumber, epoch uint64, seals MessageSet) (types.IstanbulEpochValidatorSetSeal, error) {
 	if !istanbul.IsLastBlockOfEpoch(blockNumber, epoch) {
 		return types.IstanbulEpochValidatorSetSeal{}, nil
 	}
@@ -422,7 +444,7 @@ func GetAggregatedEpochValidatorSetSeal(blockNumber, epoch uint64, seals Message
 		bitmap.SetBit(bitmap, int(j), 1)
 	}
 
-	asig, err := blscrypto.CryptoType().AggregateSignatures(epochSeals)
+	asig, err := curve.AggregateSignatures(epochSeals)
 	if err != nil {
 		return types.IstanbulEpochValidatorSetSeal{}, err
 	}
@@ -466,7 +488,7 @@ func (c *core) getPreprepareWithRoundChangeCertificate(round *big.Int) (*istanbu
 }
 
 // startNewRound starts a new round with the desired round
-func (c *core) startNewRound(round *big.Int) error {
+func (c *core) startNewRound(round *big.Int, reason string) error {
 	logger := c.newLogger("func", "startNewRound", "tag", "stateTransition")
 
 	if round.Cmp(c.current.Round()) == 0 {
@@ -498,6 +520,7 @@ func (c *core) startNewRound(round *big.Int) error {
 	nextProposer := c.selectProposer(valSet, blockAuthor, newView.Round.Uint64())
 
 	// Update the roundstate db
+	c.current.AddRoundChange(c.current.Round(), round, reason)
 	c.current.StartNewRound(round, valSet, nextProposer)
 
 	// Process backlog
@@ -538,7 +561,11 @@ func (c *core) startNewSequence() error {
 		Sequence: new(big.Int).Add(headBlock.Number(), common.Big1),
 		Round:    new(big.Int).Set(common.Big0),
 	}
-	valSet := c.backend.Validators(headBlock)
+	valSet, err := c.backend.Validators(headBlock)
+	if err != nil {
+		logger.Error("Failed to get the validator set", "err", err)
+		return err
+	}
 	c.roundChangeSet = newRoundChangeSet(valSet)
 
 	// Inform the backend that a new sequence has started & bail if the backed stopped the core
@@ -557,7 +584,7 @@ func (c *core) startNewSequence() error {
 	nextProposer := c.selectProposer(valSet, headAuthor, newView.Round.Uint64())
 
 	// Update the roundstate
-	err := c.resetRoundState(newView, valSet, nextProposer)
+	err = c.resetRoundState(newView, valSet, nextProposer)
 	if err != nil {
 		return err
 	}
@@ -566,6 +593,7 @@ func (c *core) startNewSequence() error {
 	c.processPendingRequests()
 	c.backlog.updateState(c.current.View(), c.current.State())
 
+	c.timeouts = c.backend.RoundTimeouts(headBlock)
 	c.resetRoundChangeTimer()
 
 	// Some round info will have changed.
@@ -575,8 +603,8 @@ func (c *core) startNewSequence() error {
 }
 
 // All actions that occur when transitioning to waiting for round change state.
-func (c *core) waitForDesiredRound(r *big.Int) error {
-	logger := c.newLogger("func", "waitForDesiredRound", "new_desired_round", r)
+func (c *core) waitForDesiredRound(r *big.Int, reason string) error {
+	logger := c.newLogger("func", "waitForDesiredRound", "new_desired_round", r, "reason", reason)
 
 	// Don't wait for an older round
 	if c.current.DesiredRound().Cmp(r) >= 0 {
@@ -589,10 +617,12 @@ func (c *core) waitForDesiredRound(r *big.Int) error {
 	// Perform all of the updates
 	_, headAuthor := c.backend.GetCurrentHeadBlockAndAuthor()
 	nextProposer := c.selectProposer(c.current.ValidatorSet(), headAuthor, r.Uint64())
+	prevDesiredRound := c.current.DesiredRound()
 	err := c.current.TransitionToWaitingForNewRound(r, nextProposer)
 	if err != nil {
 		return err
 	}
+	c.current.AddRoundChange(prevDesiredRound, r, reason)
 
 	c.resetRoundChangeTimer()
 
@@ -628,7 +658,11 @@ func (c *core) createRoundState() (RoundState, error) {
 		} else {
 			logger.Info("Creating new RoundState", "reason", "old view", "stored_view", lastStoredView, "requested_seq", nextSequence)
 		}
-		valSet := c.backend.Validators(headBlock)
+		valSet, err := c.backend.Validators(headBlock)
+		if err != nil {
+			logger.Error("Failed to get the validator set", "err", err)
+			return nil, err
+		}
 		proposer := c.selectProposer(valSet, headAuthor, 0)
 		roundState = newRoundState(&istanbul.View{Sequence: nextSequence, Round: common.Big0}, valSet, proposer)
 	} else {
@@ -661,7 +695,11 @@ func (c *core) resetRoundState(view *istanbul.View, validatorSet istanbul.Valida
 	} else {
 		// Otherwise, we will initialize an empty ParentCommits field with the validator set of the last proposal.
 		headBlock := c.backend.GetCurrentHeadBlock()
-		newParentCommits = newMessageSet(c.backend.ParentBlockValidators(headBlock))
+		parentValset, err := c.backend.ParentBlockValidators(headBlock)
+		if err != nil {
+			return err
+		}
+		newParentCommits = newMessageSet(parentValset)
 	}
 	return c.current.StartNewSequence(view.Sequence, validatorSet, nextProposer, newParentCommits)
 
@@ -703,15 +741,24 @@ func (c *core) stopAllTimers() {
 	c.stopResendRoundChangeTimer()
 }
 
+// roundTimeouts returns the round change timeouts in force for the current sequence
+func (c *core) roundTimeouts() *istanbul.RoundTimeouts {
+	if c.timeouts == nil {
+		return c.config.RoundTimeouts()
+	}
+	return c.timeouts
+}
+
 func (c *core) getRoundChangeTimeout() time.Duration {
-	baseTimeout := time.Duration(c.config.RequestTimeout) * time.Millisecond
+	timeouts := c.roundTimeouts()
+	baseTimeout := time.Duration(timeouts.RequestTimeout) * time.Millisecond
 	round := c.current.DesiredRound().Uint64()
 	if round == 0 {
 		// timeout for first round takes into account expected block period
 		return baseTimeout + time.Duration(c.config.BlockPeriod)*time.Second
 	} else {
 		// timeout for subsequent rounds adds an exponential backoff.
-		return baseTimeout + time.Duration(math.Pow(2, float64(round)))*time.Duration(c.config.TimeoutBackoffFactor)*time.Millisecond
+		return baseTimeout + time.Duration(math.Pow(2, float64(round)))*time.Duration(timeouts.TimeoutBackoffFactor)*time.Millisecond
 	}
 }
 
@@ -744,12 +791,13 @@ func (c *core) resetRoundChangeTimer() {
 func (c *core) resetResendRoundChangeTimer() {
 	c.stopResendRoundChangeTimer()
 	if c.current.State() == StateWaitingForNewRound {
-		minResendTimeout := time.Duration(c.config.MinResendRoundChangeTimeout) * time.Millisecond
+		timeouts := c.roundTimeouts()
+		minResendTimeout := time.Duration(timeouts.MinResendRoundChangeTimeout) * time.Millisecond
 		resendTimeout := c.getRoundChangeTimeout() / 2
 		if resendTimeout < minResendTimeout {
 			return
 		}
-		maxResendTimeout := time.Duration(c.config.MaxResendRoundChangeTimeout) * time.Millisecond
+		maxResendTimeout := time.Duration(timeouts.MaxResendRoundChangeTimeout) * time.Millisecond
 		if resendTimeout > maxResendTimeout {
 			resendTimeout = maxResendTimeout
 		}
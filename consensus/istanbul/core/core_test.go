@@ -87,6 +87,40 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestNewRequestBLS12381(t *testing.T) {
+	N := uint64(4)
+	F := uint64(1)
+
+	sys := newTestSystemWithBackendOnCurve(N, F, bls.BLS12381Curve)
+
+	close := sys.Run(true)
+	defer close()
+
+	request := makeBlock(1)
+	sys.backends[0].NewRequest(request)
+
+	<-time.After(1 * time.Second)
+
+	for _, backend := range sys.backends {
+		if len(backend.committedMsgs) != 1 {
+			t.Errorf("the number of executed requests mismatch: have %v, want 1", len(backend.committedMsgs))
+			continue
+		}
+		committed := backend.committedMsgs[0]
+		publicKeys := make([]bls.SerializedPublicKey, 0)
+		for i, val := range backend.peers.List() {
+			if committed.aggregatedSeal.Bitmap.Bit(i) == 1 {
+				publicKeys = append(publicKeys, val.BLSPublicKey())
+			}
+		}
+		seal := PrepareCommittedSeal(committed.commitProposal.Hash(), committed.aggregatedSeal.Round)
+		fork, cur := new(big.Int).Set(backend.ChainConfig().BN256ForkBlock), committed.commitProposal.Number()
+		if err := (bls.BLS12381{}).VerifyAggregatedSignature(publicKeys, seal, []byte{}, committed.aggregatedSeal.Signature, false, false, fork, cur); err != nil {
+			t.Errorf("aggregated seal does not verify on BLS12-381: %v", err)
+		}
+	}
+}
+
 func TestVerifyProposal(t *testing.T) {
 	// Check that it should not be in the cache
 	sys := NewTestSystemWithBackend(1, 0)
@@ -202,26 +236,53 @@ func TestEpochSnarkData(t *testing.T) {
 
 	publicKey, _ := bls.CryptoType().PrivateToPublic(serializedPrivateKey)
 
-	message, extraData, cip22, _ := backendCore.generateEpochValidatorSetData(0, 0, common.Hash{}, sys.backends[0].Validators(backendCore.current.Proposal()))
+	valSet, _ := sys.backends[0].Validators(backendCore.current.Proposal())
+	message, extraData, cip22, _ := backendCore.generateEpochValidatorSetData(0, 0, common.Hash{}, valSet)
 	//if cip22 || len(extraData) > 0 {
 	//	t.Errorf("Unexpected cip22 (%t != false) or extraData length (%v > 0)", cip22, len(extraData))
 	//}
 	fork, cur := new(big.Int).Set(backendCore.backend.ChainConfig().BN256ForkBlock), big.NewInt(0)
-	epochValidatorSetSeal, _ := backendCore.backend.SignBLS(message, extraData, true, cip22, fork, cur)
+	epochValidatorSetSeal, _ := backendCore.backend.SignBLS(bls.CryptoType(), message, extraData, true, cip22, fork, cur)
 
-	if err := bls.CryptoType().VerifySignature(publicKey, message, extraData, epochValidatorSetSeal[:], true, cip22); err != nil {
+	if err := bls.CryptoType().VerifySignature(publicKey, message, extraData, epochValidatorSetSeal[:], true, cip22, big.NewInt(0), big.NewInt(0)); err != nil {
 		t.Errorf("Failed verifying BLS signature")
 	}
 
-	message, extraData, cip22, _ = backendCore.generateEpochValidatorSetData(2, 0, common.Hash{}, sys.backends[0].Validators(backendCore.current.Proposal()))
+	message, extraData, cip22, _ = backendCore.generateEpochValidatorSetData(2, 0, common.Hash{}, valSet)
 	if !cip22 || len(extraData) == 0 {
 		t.Errorf("Unexpected cip22 (%t != true) or extraData length (%v == 0)", cip22, len(extraData))
 	}
 	cur = big.NewInt(2)
-	epochValidatorSetSeal, _ = backendCore.backend.SignBLS(message, extraData, true, cip22, fork, cur)
+	epochValidatorSetSeal, _ = backendCore.backend.SignBLS(bls.CryptoType(), message, extraData, true, cip22, fork, cur)
 
-	if err := bls.CryptoType().VerifySignature(publicKey, message, extraData, epochValidatorSetSeal[:], true, cip22); err != nil {
+	if err := bls.CryptoType().VerifySignature(publicKey, message, extraData, epochValidatorSetSeal[:], true, cip22, big.NewInt(0), big.NewInt(0)); err != nil {
 		t.Errorf("Failed verifying BLS signature after Donut")
 	}
 
 }
+
+func TestEpochSnarkDataBLS12381(t *testing.T) {
+	sys := newTestSystemWithBackendOnCurve(4, 1, bls.BLS12381Curve)
+	backend := sys.backends[0]
+	backendCore := backend.engine.(*core)
+	backendCore.config.Epoch = 1
+
+	message, extraData, cip22, err := backendCore.generateEpochValidatorSetData(2, 0, common.Hash{}, backend.peers)
+	if err != nil {
+		t.Fatalf("generateEpochValidatorSetData error: %v", err)
+	}
+	fork, cur := new(big.Int).Set(backend.ChainConfig().BN256ForkBlock), big.NewInt(2)
+	epochValidatorSetSeal, err := backend.SignBLS(backend.peers.BLSCrypto(), message, extraData, true, cip22, fork, cur)
+	if err != nil {
+		t.Fatalf("SignBLS error: %v", err)
+	}
+
+	src := backend.peers.GetByIndex(0)
+	comSub := &istanbul.CommittedSubject{
+		Subject:               &istanbul.Subject{View: &istanbul.View{Round: big.NewInt(0), Sequence: cur}, Digest: common.Hash{}},
+		EpochValidatorSetSeal: epochValidatorSetSeal[:],
+	}
+	if err := backendCore.verifyEpochValidatorSetSeal(comSub, 2, backend.peers, src); err != nil {
+		t.Errorf("Failed verifying BLS12-381 epoch validator set seal: %v", err)
+	}
+}
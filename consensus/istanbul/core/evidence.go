@@ -0,0 +1,102 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+)
+
+type equivocationKey struct {
+	code     uint64
+	sequence uint64
+	round    uint64
+	address  common.Address
+}
+
+// equivocationDetector remembers the first PREPARE and COMMIT message of every
+// validator and view, and turns a later message for a different digest into
+// evidence. Messages of sequences before the previous one are forgotten.
+type equivocationDetector struct {
+	seen     map[equivocationKey]*istanbul.Message
+	sequence uint64 // highest sequence seen
+	reported map[equivocationKey]bool
+}
+
+func newEquivocationDetector() *equivocationDetector {
+	return &equivocationDetector{
+		seen:     make(map[equivocationKey]*istanbul.Message),
+		reported: make(map[equivocationKey]bool),
+	}
+}
+
+// check records msg and returns the evidence if its sender already signed a
+// different digest for the same view. Every equivocation is reported once.
+func (d *equivocationDetector) check(msg *istanbul.Message) *istanbul.Evidence {
+	subject := istanbul.MessageSubject(msg)
+	if subject == nil || subject.View == nil || len(msg.Signature) == 0 {
+		return nil
+	}
+	key := equivocationKey{
+		code:     msg.Code,
+		sequence: subject.View.Sequence.Uint64(),
+		round:    subject.View.Round.Uint64(),
+		address:  msg.Address,
+	}
+	d.prune(key.sequence)
+	if key.sequence+1 < d.sequence {
+		return nil
+	}
+
+	first, ok := d.seen[key]
+	if !ok {
+		d.seen[key] = msg
+		return nil
+	}
+	if istanbul.MessageSubject(first).Digest == subject.Digest || d.reported[key] {
+		return nil
+	}
+	d.reported[key] = true
+	return istanbul.NewEvidence(first, msg)
+}
+
+// prune forgets the messages of sequences before the previous one once a new
+// sequence is seen.
+func (d *equivocationDetector) prune(sequence uint64) {
+	if sequence <= d.sequence {
+		return
+	}
+	d.sequence = sequence
+	for key := range d.seen {
+		if key.sequence+1 < sequence {
+			delete(d.seen, key)
+			delete(d.reported, key)
+		}
+	}
+}
+
+// detectEquivocation reports msg to the backend if it conflicts with an earlier
+// message of the same sender and view.
+func (c *core) detectEquivocation(msg *istanbul.Message) {
+	evidence := c.equivocations.check(msg)
+	if evidence == nil {
+		return
+	}
+	c.newLogger("func", "detectEquivocation").Warn("Detected equivocating validator", "evidence", evidence)
+	c.backend.ReportEquivocation(evidence)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+)
+
+func TestHandlePrepareEquivocation(t *testing.T) {
+	sys := NewTestSystemWithBackend(4, 1)
+	view := istanbul.View{Round: big.NewInt(0), Sequence: big.NewInt(1)}
+	proposal := newTestProposal()
+	for _, backend := range sys.backends {
+		c := backend.engine.(*core)
+		c.current = newTestRoundState(&view, backend.peers)
+	}
+	receiver, offender := sys.backends[0], sys.backends[1]
+	c := receiver.engine.(*core)
+	c.current.(*roundStateImpl).state = StatePreprepared
+
+	honest, err := offender.getPrepareMessage(view, proposal.Hash())
+	if err != nil {
+		t.Fatalf("failed to create prepare: %v", err)
+	}
+	conflicting, err := offender.getPrepareMessage(view, common.HexToHash("0x1234"))
+	if err != nil {
+		t.Fatalf("failed to create prepare: %v", err)
+	}
+
+	if err := c.handlePrepare(&honest); err != nil {
+		t.Fatalf("honest prepare: %v", err)
+	}
+	c.handlePrepare(&honest)
+	if len(receiver.evidence) != 0 {
+		t.Fatalf("unexpected evidence for a duplicate message")
+	}
+	if err := c.handlePrepare(&conflicting); err != errInconsistentSubject {
+		t.Fatalf("conflicting prepare: have %v, want %v", err, errInconsistentSubject)
+	}
+	// Reported once per view.
+	c.handlePrepare(&conflicting)
+
+	if len(receiver.evidence) != 1 {
+		t.Fatalf("have %d evidence, want 1", len(receiver.evidence))
+	}
+	evidence := receiver.evidence[0]
+	if err := evidence.Verify(); err != nil {
+		t.Fatalf("invalid evidence: %v", err)
+	}
+	if evidence.Offender() != offender.address || evidence.View().Cmp(&view) != 0 {
+		t.Fatalf("unexpected evidence %v", evidence)
+	}
+}
+
+func TestEquivocationDetectorPrune(t *testing.T) {
+	sys := NewTestSystemWithBackend(1, 0)
+	backend := sys.backends[0]
+	backend.engine.(*core).current = newTestRoundState(&istanbul.View{Round: big.NewInt(0), Sequence: big.NewInt(1)}, backend.peers)
+
+	prepare := func(seq int64, digest common.Hash) *istanbul.Message {
+		msg, err := backend.getPrepareMessage(istanbul.View{Round: big.NewInt(0), Sequence: big.NewInt(seq)}, digest)
+		if err != nil {
+			t.Fatalf("failed to create prepare: %v", err)
+		}
+		return &msg
+	}
+	d := newEquivocationDetector()
+	d.check(prepare(1, common.HexToHash("0x01")))
+	d.check(prepare(2, common.HexToHash("0x01")))
+	if e := d.check(prepare(1, common.HexToHash("0x02"))); e == nil {
+		t.Fatal("expected evidence for the previous sequence")
+	}
+	d.check(prepare(3, common.HexToHash("0x01")))
+	if len(d.seen) != 2 {
+		t.Fatalf("have %d messages, want 2 after pruning", len(d.seen))
+	}
+	if e := d.check(prepare(1, common.HexToHash("0x03"))); e != nil {
+		t.Fatal("unexpected evidence for a pruned sequence")
+	}
+}
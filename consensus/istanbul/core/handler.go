@@ -33,6 +33,7 @@ func (c *core) Start() error {
 
 	c.current = roundState
 	c.roundChangeSet = newRoundChangeSet(c.current.ValidatorSet())
+	c.timeouts = c.backend.RoundTimeouts(c.backend.GetCurrentHeadBlock())
 
 	// Reset the Round Change timer for the current round to timeout.
 	// (If we've restored RoundState such that we are in StateWaitingForRoundChange,
@@ -221,7 +222,7 @@ func (c *core) handleTimeoutAndMoveToNextRound(timedOutView *istanbul.View) erro
 
 	logger.Debug("Timed out, trying to wait for next round")
 	nextRound := new(big.Int).Add(timedOutView.Round, common.Big1)
-	return c.waitForDesiredRound(nextRound)
+	return c.waitForDesiredRound(nextRound, roundChangeReasonTimeout)
 }
 
 func (c *core) handleResendRoundChangeEvent(desiredView *istanbul.View) error {
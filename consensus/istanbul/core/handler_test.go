@@ -233,7 +233,7 @@ func bemchmarkE2EHandleCommit(b *testing.B, sys *testSystem) {
 	c := v0.engine.(*core)
 	subject := v0.engine.(*core).current.Subject()
 
-	committedSeal, err := v0.engine.(*core).generateCommittedSeal(subject)
+	committedSeal, err := c.generateCommittedSeal(c.current.ValidatorSet().BLSCrypto(), subject)
 	if err != nil {
 		b.Errorf("Got error: %v", err)
 	}
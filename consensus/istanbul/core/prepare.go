@@ -149,6 +149,7 @@ func (c *core) handlePrepare(msg *istanbul.Message) error {
 	if err := c.checkMessage(istanbul.MsgPrepare, prepare.View); err != nil {
 		return err
 	}
+	c.detectEquivocation(msg)
 
 	if err := c.verifyPrepare(prepare); err != nil {
 		return err
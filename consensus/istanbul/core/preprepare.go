@@ -58,7 +58,10 @@ func (c *core) handlePreprepare(msg *istanbul.Message) error {
 	if err := c.checkMessage(istanbul.MsgPreprepare, preprepare.View); err != nil {
 		if err == errOldMessage {
 			// Get validator set for the given proposal
-			valSet := c.backend.ParentBlockValidators(preprepare.Proposal)
+			valSet, valSetErr := c.backend.ParentBlockValidators(preprepare.Proposal)
+			if valSetErr != nil {
+				return valSetErr
+			}
 			prevBlockAuthor := c.backend.AuthorForBlock(preprepare.Proposal.Number().Uint64() - 1)
 			proposer := c.selectProposer(valSet, prevBlockAuthor, preprepare.View.Round.Uint64())
 
@@ -444,7 +444,7 @@ func TestHandlePreprepare(t *testing.T) {
 				if expectedCode == istanbul.MsgCommit {
 					srcValidator := c.current.GetValidatorByAddress(v.address)
 
-					if err := c.verifyCommittedSeal(decodedMsg.Commit(), srcValidator); err != nil {
+					if err := c.verifyCommittedSeal(decodedMsg.Commit(), srcValidator, big.NewInt(0), big.NewInt(0)); err != nil {
 						t.Errorf("invalid seal.  verify commmited seal error: %v, subject: %v, committedSeal: %v", err, expectedSubject, decodedMsg.Commit().CommittedSeal)
 					}
 				}
@@ -572,7 +572,7 @@ func TestPeriodicRoundChanges(t *testing.T) {
 	go sys.distributeIstMsgs(t, sys, istMsgDistribution)
 
 	for _, b := range sys.backends {
-		b.engine.(*core).waitForDesiredRound(big.NewInt(5))
+		b.engine.(*core).waitForDesiredRound(big.NewInt(5), roundChangeReasonTimeout)
 	}
 
 	// Expect at least one repeat RC before move to next round.
@@ -633,3 +633,79 @@ loop2:
 	}
 	close(sys.quit)
 }
+
+// waitForBackends polls until cond holds for every core, or fails the test after timeout.
+func waitForBackends(t *testing.T, sys *testSystem, timeout time.Duration, cond func(c *core) bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		done := true
+		for _, b := range sys.backends {
+			done = done && cond(b.engine.(*core))
+		}
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Backends did not reach the expected round state within %v", timeout)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// This tests that validators split into two groups without a quorum keep timing out
+// without starting a new round, then converge on a new round once the partition heals,
+// and that the round state summary records why each round change happened.
+func TestRoundChangesUnderPartition(t *testing.T) {
+	sys := NewMutedTestSystemWithBackend(4, 1)
+	for _, b := range sys.backends {
+		b.roundTimeouts = &istanbul.RoundTimeouts{
+			RequestTimeout:              100,
+			TimeoutBackoffFactor:        20,
+			MinResendRoundChangeTimeout: 1000,
+			MaxResendRoundChangeTimeout: 10000,
+		}
+		b.engine.(*core).config.BlockPeriod = 0
+	}
+	sys.Partition([]int{0, 1}, []int{2, 3})
+
+	closer := sys.Run(true)
+	defer closer()
+	for _, b := range sys.backends {
+		b.NewRequest(makeBlock(1))
+	}
+
+	waitForBackends(t, sys, 5*time.Second, func(c *core) bool {
+		return c.current.DesiredRound().Cmp(big.NewInt(3)) >= 0
+	})
+	for i, b := range sys.backends {
+		summary := b.engine.(*core).current.Summary()
+		if summary.Round.Sign() != 0 {
+			t.Errorf("Backend %v started round %v without a quorum", i, summary.Round)
+		}
+		if len(summary.RoundChanges) == 0 {
+			t.Fatalf("Backend %v recorded no round changes", i)
+		}
+		for _, rc := range summary.RoundChanges {
+			if rc.Reason != roundChangeReasonTimeout && rc.Reason != roundChangeReasonFPlusOne {
+				t.Errorf("Backend %v changed round from %v to %v because of %q within its group", i, rc.From, rc.To, rc.Reason)
+			}
+			if rc.To.Cmp(rc.From) <= 0 {
+				t.Errorf("Backend %v recorded a round change from %v to %v", i, rc.From, rc.To)
+			}
+		}
+	}
+
+	sys.Heal()
+	waitForBackends(t, sys, 10*time.Second, func(c *core) bool {
+		return c.current.Round().Sign() > 0
+	})
+	for i, b := range sys.backends {
+		started := false
+		for _, rc := range b.engine.(*core).current.Summary().RoundChanges {
+			started = started || rc.Reason == roundChangeReasonQuorum || rc.Reason == roundChangeReasonCertificate
+		}
+		if !started {
+			t.Errorf("Backend %v started a new round without recording a quorum or a round change certificate", i)
+		}
+	}
+}
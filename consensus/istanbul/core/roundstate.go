@@ -22,6 +22,7 @@ import (
 	"io"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -53,6 +54,7 @@ type RoundState interface {
 	SetPendingRequest(pendingRequest *istanbul.Request) error
 	SetProposalVerificationStatus(proposalHash common.Hash, verificationStatus error)
 	SetStateProcessResult(proposalHash common.Hash, blockProcessResult *StateProcessResult)
+	AddRoundChange(from, to *big.Int, reason string)
 
 	// view functions
 	DesiredRound() *big.Int
@@ -106,6 +108,9 @@ type roundStateImpl struct {
 	// Cache for StateProcessResult in this sequence.
 	stateProcessResults map[common.Hash]*StateProcessResult
 
+	// Round changes in this sequence, for diagnostics only. Not persisted.
+	roundChanges []RoundChangeRecord
+
 	mu     *sync.RWMutex
 	logger log.Logger
 
@@ -131,6 +136,19 @@ type RoundStateSummary struct {
 
 	Preprepare          *istanbul.PreprepareSummary          `json:"preprepare"`
 	PreparedCertificate *istanbul.PreparedCertificateSummary `json:"preparedCertificate"`
+
+	RoundChanges []RoundChangeRecord `json:"roundChanges"`
+}
+
+// maxRoundChangeRecords is the number of round changes kept per sequence
+const maxRoundChangeRecords = 64
+
+// RoundChangeRecord is a move to a higher round, or desired round, and why it happened
+type RoundChangeRecord struct {
+	From   *big.Int  `json:"from"`
+	To     *big.Int  `json:"to"`
+	Reason string    `json:"reason"`
+	Time   time.Time `json:"time"`
 }
 
 func newRoundState(view *istanbul.View, validatorSet istanbul.ValidatorSet, proposer istanbul.Validator) RoundState {
@@ -316,6 +334,7 @@ func (rs *roundStateImpl) StartNewSequence(nextSequence *big.Int, validatorSet i
 	rs.parentCommits = parentCommits
 	rs.proposalVerificationStatus = nil
 	rs.stateProcessResults = nil
+	rs.roundChanges = nil
 
 	// Update sequence gauge
 	rs.sequenceGauge.Update(nextSequence.Int64())
@@ -502,6 +521,23 @@ func (rs *roundStateImpl) GetStateProcessResult(proposalHash common.Hash) (state
 	return
 }
 
+// AddRoundChange records a move from one round to another, dropping the oldest
+// record once maxRoundChangeRecords are kept
+func (rs *roundStateImpl) AddRoundChange(from, to *big.Int, reason string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if len(rs.roundChanges) == maxRoundChangeRecords {
+		rs.roundChanges = rs.roundChanges[1:]
+	}
+	rs.roundChanges = append(rs.roundChanges, RoundChangeRecord{
+		From:   new(big.Int).Set(from),
+		To:     new(big.Int).Set(to),
+		Reason: reason,
+		Time:   time.Now(),
+	})
+}
+
 func (rs *roundStateImpl) Summary() *RoundStateSummary {
 	rs.mu.RLock()
 	defer rs.mu.RUnlock()
@@ -518,6 +554,8 @@ func (rs *roundStateImpl) Summary() *RoundStateSummary {
 		Prepares:      rs.prepares.Addresses(),
 		Commits:       rs.commits.Addresses(),
 		ParentCommits: rs.parentCommits.Addresses(),
+
+		RoundChanges: append([]RoundChangeRecord(nil), rs.roundChanges...),
 	}
 
 	if rs.pendingRequest != nil {
@@ -83,6 +83,10 @@ func (rsp *rsSaveDecorator) SetProposalVerificationStatus(proposalHash common.Ha
 func (rsp *rsSaveDecorator) SetStateProcessResult(proposalHash common.Hash, result *StateProcessResult) {
 	rsp.rs.SetStateProcessResult(proposalHash, result)
 }
+func (rsp *rsSaveDecorator) AddRoundChange(from, to *big.Int, reason string) {
+	// Don't persist on round change records, since they are diagnostics only
+	rsp.rs.AddRoundChange(from, to, reason)
+}
 
 // DesiredRound implements RoundState.DesiredRound
 func (rsp *rsSaveDecorator) DesiredRound() *big.Int { return rsp.rs.DesiredRound() }
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -59,6 +60,7 @@ type testSystemBackend struct {
 
 	committedMsgs []testCommittedMsgs
 	sentMsgs      [][]byte // store the message when Send is called by core
+	evidence      []*istanbul.Evidence
 
 	key     ecdsa.PrivateKey
 	blsKey  []byte
@@ -70,6 +72,9 @@ type testSystemBackend struct {
 	verifyImpl func(proposal istanbul.Proposal) (*StateProcessResult, time.Duration, error)
 
 	donutBlock *big.Int
+
+	// Round change timeouts returned to the core, nil for the configured ones
+	roundTimeouts *istanbul.RoundTimeouts
 }
 
 type testCommittedMsgs struct {
@@ -93,8 +98,8 @@ func (self *testSystemBackend) Address() common.Address {
 }
 
 // Peers returns all connected peers
-func (self *testSystemBackend) Validators(proposal istanbul.Proposal) istanbul.ValidatorSet {
-	return self.peers
+func (self *testSystemBackend) Validators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error) {
+	return self.peers, nil
 }
 
 func (self *testSystemBackend) IsValidating() bool {
@@ -103,7 +108,8 @@ func (self *testSystemBackend) IsValidating() bool {
 
 func (self *testSystemBackend) ChainConfig() *params.ChainConfig {
 	return &params.ChainConfig{
-		DonutBlock: self.donutBlock,
+		DonutBlock:     self.donutBlock,
+		BN256ForkBlock: big.NewInt(0),
 	}
 }
 
@@ -157,28 +163,8 @@ func (self *testSystemBackend) Gossip(payload []byte, ethMsgCode uint64) error {
 	return nil
 }
 
-func (self *testSystemBackend) SignBLS(data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error) {
-	privateKey, err := bls.DeserializePrivateKey(self.blsKey)
-	if err != nil {
-		return bls.SerializedSignature{}, err
-	}
-	pubkey := privateKey.ToPublic()
-	if err != nil {
-		return bls.SerializedSignature{}, err
-	}
-	var signature *bls.Signature
-	if params.IsBN256Fork(fork, cur) {
-		signature, err = bls.Sign2(privateKey, pubkey, data)
-	} else {
-		signature, err = bls.Sign(privateKey, pubkey, data)
-	}
-
-	if err != nil {
-		return bls.SerializedSignature{}, err
-	}
-	signatureBytes := signature.Marshal()
-
-	return bls.SerializedSignatureFromBytes(signatureBytes)
+func (self *testSystemBackend) SignBLS(curve bls.BLSCryptoSelector, data []byte, extra []byte, useComposite, cip22 bool, fork, cur *big.Int) (bls.SerializedSignature, error) {
+	return curve.Sign(self.blsKey, data, extra, useComposite, cip22, fork, cur)
 }
 
 func (self *testSystemBackend) Commit(proposal istanbul.Proposal, aggregatedSeal types.IstanbulAggregatedSeal, aggregatedEpochValidatorSetSeal types.IstanbulEpochValidatorSetSeal, stateProcessResult *StateProcessResult) error {
@@ -271,12 +257,20 @@ func (self *testSystemBackend) AuthorForBlock(number uint64) common.Address {
 	return common.Address{}
 }
 
-func (self *testSystemBackend) ParentBlockValidators(proposal istanbul.Proposal) istanbul.ValidatorSet {
-	return self.peers
+func (self *testSystemBackend) ParentBlockValidators(proposal istanbul.Proposal) (istanbul.ValidatorSet, error) {
+	return self.peers, nil
 }
 
 func (self *testSystemBackend) UpdateReplicaState(seq *big.Int) { /* pass */ }
 
+func (self *testSystemBackend) ReportEquivocation(evidence *istanbul.Evidence) {
+	self.evidence = append(self.evidence, evidence)
+}
+
+func (self *testSystemBackend) RoundTimeouts(head istanbul.Proposal) *istanbul.RoundTimeouts {
+	return self.roundTimeouts
+}
+
 func (self *testSystemBackend) finalizeAndReturnMessage(msg *istanbul.Message) (istanbul.Message, error) {
 	message := new(istanbul.Message)
 	data, err := self.engine.(*core).finalizeMessage(msg)
@@ -311,7 +305,7 @@ func (self *testSystemBackend) getCommitMessage(view istanbul.View, proposal ist
 		Digest: proposal.Hash(),
 	}
 
-	committedSeal, err := self.engine.(*core).generateCommittedSeal(subject)
+	committedSeal, err := self.engine.(*core).generateCommittedSeal(self.peers.BLSCrypto(), subject)
 	if err != nil {
 		return istanbul.Message{}, err
 	}
@@ -367,6 +361,11 @@ type testSystem struct {
 
 	queuedMessage chan istanbul.MessageEvent
 	quit          chan struct{}
+
+	// Group of each partitioned validator. Messages are only delivered within
+	// a group, validators in no group send to and receive from everyone.
+	partition   map[common.Address]int
+	partitionMu sync.RWMutex
 }
 
 func newTestSystem(n uint64, f uint64, keys [][]byte) *testSystem {
@@ -382,16 +381,22 @@ func newTestSystem(n uint64, f uint64, keys [][]byte) *testSystem {
 }
 
 func generateValidators(n int) ([]istanbul.ValidatorData, [][]byte, []*ecdsa.PrivateKey) {
+	return generateValidatorsOnCurve(n, 0)
+}
+
+// generateValidatorsOnCurve generates n validators with BLS keys on the given curve
+func generateValidatorsOnCurve(n int, curve uint8) ([]istanbul.ValidatorData, [][]byte, []*ecdsa.PrivateKey) {
 	vals := make([]istanbul.ValidatorData, 0)
 	blsKeys := make([][]byte, 0)
 	keys := make([]*ecdsa.PrivateKey, 0)
 	for i := 0; i < n; i++ {
 		privateKey, _ := crypto.GenerateKey()
-		blsPrivateKey, _ := bls.CryptoType().ECDSAToBLS(privateKey)
-		blsPublicKey, _ := bls.CryptoType().PrivateToPublic(blsPrivateKey)
+		blsPrivateKey, _ := bls.CryptoTypeOf(curve).ECDSAToBLS(privateKey)
+		blsPublicKey, _ := bls.CryptoTypeOf(curve).PrivateToPublic(blsPrivateKey)
 		vals = append(vals, istanbul.ValidatorData{
 			Address:      crypto.PubkeyToAddress(privateKey.PublicKey),
 			BLSPublicKey: blsPublicKey,
+			BLSCurve:     curve,
 		})
 		keys = append(keys, privateKey)
 		blsKeys = append(blsKeys, blsPrivateKey)
@@ -405,8 +410,12 @@ func newTestValidatorSet(n int) istanbul.ValidatorSet {
 }
 
 func newTestSystemWithBackend(n, f uint64) *testSystem {
+	return newTestSystemWithBackendOnCurve(n, f, 0)
+}
 
-	validators, blsKeys, keys := generateValidators(int(n))
+func newTestSystemWithBackendOnCurve(n, f uint64, curve uint8) *testSystem {
+
+	validators, blsKeys, keys := generateValidatorsOnCurve(int(n), curve)
 	sys := newTestSystem(n, f, blsKeys)
 	config := *istanbul.DefaultConfig
 	config.ProposerPolicy = istanbul.RoundRobin
@@ -488,7 +497,15 @@ func (t *testSystem) listen() {
 			return
 		case queuedMessage := <-t.queuedMessage:
 			testLogger.Info("consuming a queue message...")
+			msg := new(istanbul.Message)
+			if err := msg.FromPayload(queuedMessage.Payload, nil); err != nil {
+				testLogger.Error("Could not decode payload", "err", err)
+			}
 			for _, backend := range t.backends {
+				if !t.connected(msg.Address, backend.address) {
+					testLogger.Info("partitioned, not sending msg", "from", msg.Address, "to", backend.address, "code", msg.Code)
+					continue
+				}
 				go backend.EventMux().Post(queuedMessage)
 			}
 		}
@@ -543,6 +560,38 @@ func (t *testSystem) NewBackend(id uint64, donutBlock *big.Int) *testSystemBacke
 	return backend
 }
 
+// Partition splits the validators, given by their backend indexes, into groups
+// that only receive the messages sent within the group, until Heal is called.
+func (t *testSystem) Partition(groups ...[]int) {
+	t.partitionMu.Lock()
+	defer t.partitionMu.Unlock()
+
+	t.partition = make(map[common.Address]int)
+	for group, indexes := range groups {
+		for _, i := range indexes {
+			t.partition[t.backends[i].address] = group
+		}
+	}
+}
+
+// Heal removes the partition, delivering every message to every validator again.
+func (t *testSystem) Heal() {
+	t.partitionMu.Lock()
+	defer t.partitionMu.Unlock()
+
+	t.partition = nil
+}
+
+// connected returns whether the messages sent by from are delivered to to.
+func (t *testSystem) connected(from, to common.Address) bool {
+	t.partitionMu.RLock()
+	defer t.partitionMu.RUnlock()
+
+	fromGroup, fromOk := t.partition[from]
+	toGroup, toOk := t.partition[to]
+	return !fromOk || !toOk || fromGroup == toGroup
+}
+
 func (t *testSystem) F() uint64 {
 	return t.f
 }
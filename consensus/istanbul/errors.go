@@ -44,4 +44,6 @@ var (
 	ErrValidatorNotProxied = errors.New("validator not proxied")
 	// ErrInvalidEnodeCertMsgMapOldVersion is returned if a validator sends old enode certificate message
 	ErrInvalidEnodeCertMsgMapOldVersion = errors.New("invalid enode certificate message map because of old version")
+	// ErrInvalidEvidence is returned if double signing evidence does not hold two conflicting messages
+	ErrInvalidEvidence = errors.New("invalid double signing evidence")
 )
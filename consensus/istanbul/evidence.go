@@ -0,0 +1,134 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// Evidence proves that a validator equivocated: it holds two PREPARE or two
+// COMMIT messages signed by the same validator for the same view but for
+// different proposals.
+type Evidence struct {
+	First  *Message
+	Second *Message
+}
+
+// NewEvidence returns the evidence for the conflicting messages a and b. The
+// messages are ordered by digest, so the evidence does not depend on the order
+// in which they were received.
+func NewEvidence(a, b *Message) *Evidence {
+	sa, sb := MessageSubject(a), MessageSubject(b)
+	if sa != nil && sb != nil && bytes.Compare(sa.Digest[:], sb.Digest[:]) > 0 {
+		a, b = b, a
+	}
+	return &Evidence{First: a, Second: b}
+}
+
+// MessageSubject returns the subject of a PREPARE or COMMIT message, or nil for
+// any other message.
+func MessageSubject(m *Message) *Subject {
+	switch m.Code {
+	case MsgPrepare:
+		return m.Prepare()
+	case MsgCommit:
+		if commit := m.Commit(); commit != nil {
+			return commit.Subject
+		}
+	}
+	return nil
+}
+
+// Offender returns the validator that signed both messages.
+func (e *Evidence) Offender() common.Address {
+	return e.First.Address
+}
+
+// View returns the view both messages were signed for.
+func (e *Evidence) View() *View {
+	return MessageSubject(e.First).View
+}
+
+// Verify checks that the evidence holds two validly signed PREPARE or COMMIT
+// messages of the same validator and view for different digests.
+func (e *Evidence) Verify() error {
+	if e.First == nil || e.Second == nil {
+		return ErrInvalidEvidence
+	}
+	if e.First.Code != e.Second.Code || e.First.Address != e.Second.Address {
+		return ErrInvalidEvidence
+	}
+	first, second := MessageSubject(e.First), MessageSubject(e.Second)
+	if first == nil || second == nil || first.View == nil || second.View == nil ||
+		first.View.Round == nil || first.View.Sequence == nil || second.View.Round == nil || second.View.Sequence == nil {
+		return ErrInvalidEvidence
+	}
+	if first.View.Cmp(second.View) != 0 || first.Digest == second.Digest {
+		return ErrInvalidEvidence
+	}
+	for _, msg := range []*Message{e.First, e.Second} {
+		payload, err := msg.PayloadNoSig()
+		if err != nil {
+			return err
+		}
+		signer, err := GetSignatureAddress(payload, msg.Signature)
+		if err != nil {
+			return err
+		}
+		if signer != msg.Address {
+			return ErrInvalidSigner
+		}
+	}
+	return nil
+}
+
+// Encode returns the evidence in the form it is included in the header extra.
+func (e *Evidence) Encode() (types.IstanbulEvidence, error) {
+	first, err := e.First.Payload()
+	if err != nil {
+		return types.IstanbulEvidence{}, err
+	}
+	second, err := e.Second.Payload()
+	if err != nil {
+		return types.IstanbulEvidence{}, err
+	}
+	return types.IstanbulEvidence{First: first, Second: second}, nil
+}
+
+// DecodeEvidence decodes and verifies evidence taken from a header extra.
+func DecodeEvidence(enc types.IstanbulEvidence) (*Evidence, error) {
+	e := &Evidence{First: new(Message), Second: new(Message)}
+	if err := e.First.FromPayload(enc.First, nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvidence, err)
+	}
+	if err := e.Second.FromPayload(enc.Second, nil); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvidence, err)
+	}
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Evidence) String() string {
+	return fmt.Sprintf("{Code: %d, Offender: %v, View: %v}", e.First.Code, e.Offender().String(), e.View())
+}
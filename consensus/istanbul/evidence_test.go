@@ -0,0 +1,88 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func signedPrepare(t *testing.T, key *ecdsa.PrivateKey, view *View, digest common.Hash) *Message {
+	msg := NewPrepareMessage(&Subject{View: view, Digest: digest}, crypto.PubkeyToAddress(key.PublicKey))
+	err := msg.Sign(func(data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	})
+	if err != nil {
+		t.Fatalf("failed to sign message: %v", err)
+	}
+	return msg
+}
+
+func TestEvidenceVerify(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	view := &View{Round: big.NewInt(1), Sequence: big.NewInt(10)}
+	a := signedPrepare(t, key, view, common.HexToHash("0x01"))
+	b := signedPrepare(t, key, view, common.HexToHash("0x02"))
+
+	e := NewEvidence(b, a)
+	if err := e.Verify(); err != nil {
+		t.Fatalf("valid evidence: %v", err)
+	}
+	if e.First != a || e.Offender() != a.Address || e.View().Cmp(view) != 0 {
+		t.Fatalf("unexpected evidence %v", e)
+	}
+
+	enc, err := e.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode evidence: %v", err)
+	}
+	dec, err := DecodeEvidence(enc)
+	if err != nil {
+		t.Fatalf("failed to decode evidence: %v", err)
+	}
+	if dec.Offender() != e.Offender() || MessageSubject(dec.Second).Digest != MessageSubject(b).Digest {
+		t.Fatalf("decoded evidence mismatch: %v", dec)
+	}
+
+	otherView := &View{Round: big.NewInt(2), Sequence: big.NewInt(10)}
+	forged := signedPrepare(t, key, view, common.HexToHash("0x03"))
+	forged.Signature = signedPrepare(t, other, view, common.HexToHash("0x03")).Signature
+	commit := NewCommitMessage(&CommittedSubject{Subject: &Subject{View: view, Digest: common.HexToHash("0x02")}}, a.Address)
+
+	tests := []struct {
+		name          string
+		first, second *Message
+		err           error
+	}{
+		{"same digest", a, signedPrepare(t, key, view, common.HexToHash("0x01")), ErrInvalidEvidence},
+		{"different view", a, signedPrepare(t, key, otherView, common.HexToHash("0x02")), ErrInvalidEvidence},
+		{"different signer", a, signedPrepare(t, other, view, common.HexToHash("0x02")), ErrInvalidEvidence},
+		{"different code", a, commit, ErrInvalidEvidence},
+		{"forged signature", a, forged, ErrInvalidSigner},
+	}
+	for _, tt := range tests {
+		if err := NewEvidence(tt.first, tt.second).Verify(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: have %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
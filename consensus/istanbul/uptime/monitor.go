@@ -39,6 +39,20 @@ func (u *UptimeEntry) String() string {
 	return fmt.Sprintf("UptimeEntry { upBlocks: %v, lastBlock: %v}", u.UpBlocks, u.LastSignedBlock)
 }
 
+// DowntimeEntry counts the consecutive lookback windows of the monitoring window a
+// validator did not sign any block in. The monitoring window is split in
+// consecutive windows of lookbackWindow blocks.
+type DowntimeEntry struct {
+	// Numbers of consecutive windows missed up to the latest window
+	MissedWindows uint64
+	// Longest run of consecutive missed windows within the epoch
+	MaxMissedWindows uint64
+}
+
+func (d *DowntimeEntry) String() string {
+	return fmt.Sprintf("DowntimeEntry { missedWindows: %v, maxMissedWindows: %v}", d.MissedWindows, d.MaxMissedWindows)
+}
+
 // Monitor is responsible for monitoring uptime by processing blocks
 type Monitor struct {
 	epochSize      uint64
@@ -137,6 +151,32 @@ func (um *Monitor) GetValidatorsActivity(epoch, numberWithinEpoch uint64, valSet
 	return accumulated.Entries, uptimes, nil
 }
 
+// EpochDowntime replays the parent seal bitmaps of the blocks of epoch and returns the
+// missed windows of each of the valSetSize validators. bitmaps[i] is the bitmap of the
+// block following the first block of the epoch by i + 1, which records who signed its
+// parent, so the result only depends on the chain.
+func EpochDowntime(epoch, epochSize, lookbackWindow uint64, valSetSize int, bitmaps []*big.Int) ([]DowntimeEntry, error) {
+	monitoringWindow, err := MonitoringWindow(epoch, epochSize, lookbackWindow)
+	if err != nil {
+		return nil, err
+	}
+	first, err := istanbul.GetEpochFirstBlockNumber(epoch, epochSize)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		uptime   = &Uptime{Entries: make([]UptimeEntry, valSetSize)}
+		downtime = make([]DowntimeEntry, valSetSize)
+	)
+	for i, bitmap := range bitmaps {
+		// the first block of the epoch is signed by the validators of the previous epoch
+		parent := first + uint64(i)
+		uptime = updateUptime(uptime, parent, bitmap, lookbackWindow, monitoringWindow)
+		downtime = updateDowntime(downtime, uptime.Entries, parent, lookbackWindow, monitoringWindow)
+	}
+	return downtime, nil
+}
+
 // ProcessBlock uses the block's signature bitmap (which encodes who signed the parent block) to update the epoch's Uptime data
 func (um *Monitor) ProcessBlock(block *types.Block) error {
 	// The epoch's first block's aggregated parent signatures is for the previous epoch's valset.
@@ -198,6 +238,33 @@ func updateUptime(uptime *Uptime, blockNumber uint64, bitmap *big.Int, lookbackW
 	return uptime
 }
 
+// updateDowntime updates the missed windows once blockNumber ends one of the windows
+// the monitoring window is split in. It relies on the LastSignedBlock of the
+// entries having been updated with blockNumber.
+func updateDowntime(downtime []DowntimeEntry, entries []UptimeEntry, blockNumber uint64, lookbackWindowSize uint64, monitoringWindow Window) []DowntimeEntry {
+	if !monitoringWindow.Contains(blockNumber) || (blockNumber-monitoringWindow.Start)%lookbackWindowSize != 0 {
+		return downtime
+	}
+	if len(downtime) < len(entries) {
+		grown := make([]DowntimeEntry, len(entries))
+		copy(grown, downtime)
+		downtime = grown
+	}
+
+	currentWindow := newWindowEndingAt(blockNumber, lookbackWindowSize)
+	for i := 0; i < len(entries); i++ {
+		if currentWindow.Contains(entries[i].LastSignedBlock) {
+			downtime[i].MissedWindows = 0
+			continue
+		}
+		downtime[i].MissedWindows++
+		if downtime[i].MissedWindows > downtime[i].MaxMissedWindows {
+			downtime[i].MaxMissedWindows = downtime[i].MissedWindows
+		}
+	}
+	return downtime
+}
+
 // https://stackoverflow.com/questions/19105791/is-there-a-big-bitcount/32702348#32702348
 func bitCount(n *big.Int) int {
 	count := 0
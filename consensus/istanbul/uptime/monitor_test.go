@@ -102,3 +102,66 @@ func TestUptimeSingle(t *testing.T) {
 		t.Fatalf("uptimes were not updated correctly, got %v, expected %v", uptimes, expected)
 	}
 }
+
+// downtimeBitmaps returns the signers of blocks 1 to 18: validator 0 always signs,
+// validator 1 goes offline after block 5 and validator 2 is offline from block 4 to 10.
+func downtimeBitmaps() []*big.Int {
+	bitmaps := make([]*big.Int, 0, 18)
+	for block := uint64(1); block <= 18; block++ {
+		bitmap := big.NewInt(1)
+		if block <= 5 {
+			bitmap.SetBit(bitmap, 1, 1)
+		}
+		if block <= 3 || block >= 11 {
+			bitmap.SetBit(bitmap, 2, 1)
+		}
+		bitmaps = append(bitmaps, bitmap)
+	}
+	return bitmaps
+}
+
+func TestDowntime(t *testing.T) {
+	var (
+		uptimes  *Uptime
+		downtime []DowntimeEntry
+	)
+	// use a window of 2 blocks, the monitoring window [2,18] is split in 9 windows
+	monitoringWindow := MustMonitoringWindow(1, 20, 2)
+
+	for i, bitmap := range downtimeBitmaps() {
+		block := uint64(i + 1)
+		uptimes = updateUptime(uptimes, block, bitmap, 2, monitoringWindow)
+		downtime = updateDowntime(downtime, uptimes.Entries, block, 2, monitoringWindow)
+	}
+
+	expected := []DowntimeEntry{
+		{MissedWindows: 0, MaxMissedWindows: 0},
+		{MissedWindows: 6, MaxMissedWindows: 6},
+		{MissedWindows: 0, MaxMissedWindows: 3},
+		// the dummies never sign
+		{MissedWindows: 9, MaxMissedWindows: 9},
+		{MissedWindows: 9, MaxMissedWindows: 9},
+		{MissedWindows: 9, MaxMissedWindows: 9},
+	}
+	if !reflect.DeepEqual(downtime, expected) {
+		t.Fatalf("downtime was not updated correctly, got %v, expected %v", downtime, expected)
+	}
+}
+
+func TestEpochDowntime(t *testing.T) {
+	downtime, err := EpochDowntime(1, 20, 2, 3, downtimeBitmaps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DowntimeEntry{
+		{MissedWindows: 0, MaxMissedWindows: 0},
+		{MissedWindows: 6, MaxMissedWindows: 6},
+		{MissedWindows: 0, MaxMissedWindows: 3},
+	}
+	if !reflect.DeepEqual(downtime, expected) {
+		t.Fatalf("EpochDowntime() = %v, want %v", downtime, expected)
+	}
+	if _, err := EpochDowntime(0, 20, 2, 3, nil); err == nil {
+		t.Error("EpochDowntime() expected error for epoch 0")
+	}
+}
@@ -150,7 +150,7 @@ func ValidatorSetDiff(oldValSet []ValidatorData, newValSet []ValidatorData) ([]V
 	var addedValidators []ValidatorData
 	for _, newVal := range newValSet {
 		index, ok := oldValSetIndices[newVal.Address]
-		if ok && (oldValSet[index].BLSPublicKey == newVal.BLSPublicKey) {
+		if ok && oldValSet[index].BLSPublicKey == newVal.BLSPublicKey && oldValSet[index].BLSCurve == newVal.BLSCurve {
 			// We found a common validator.  Pop from the map
 			delete(valSetMap, newVal.Address)
 		} else {
@@ -159,6 +159,7 @@ func ValidatorSetDiff(oldValSet []ValidatorData, newValSet []ValidatorData) ([]V
 				Address:        newVal.Address,
 				BLSPublicKey:   newVal.BLSPublicKey,
 				BLSG1PublicKey: newVal.BLSG1PublicKey,
+				BLSCurve:       newVal.BLSCurve,
 			})
 		}
 	}
@@ -35,6 +35,8 @@ type ValidatorData struct {
 	Address        common.Address
 	BLSPublicKey   blscrypto.SerializedPublicKey
 	BLSG1PublicKey blscrypto.SerializedG1PublicKey
+	// BLSCurve is the curve of the BLS public keys, zero for the BN256 keys
+	BLSCurve uint8 `rlp:"optional"`
 }
 
 type ValidatorDataWithBLSKeyCache struct {
@@ -42,6 +44,7 @@ type ValidatorDataWithBLSKeyCache struct {
 	BLSPublicKey             blscrypto.SerializedPublicKey
 	BLSG1PublicKey           blscrypto.SerializedG1PublicKey
 	UncompressedBLSPublicKey []byte
+	BLSCurve                 uint8 `rlp:"optional"`
 }
 
 type Validator interface {
@@ -55,6 +58,9 @@ type Validator interface {
 
 	BLSG1PublicKey() blscrypto.SerializedG1PublicKey
 
+	// BLSCurve returns the curve of the BLS public keys, zero for BN256
+	BLSCurve() uint8
+
 	// BLSPublicKeyUncompressed returns the BLS public key (uncompressed format)
 	BLSPublicKeyUncompressed() []byte
 
@@ -117,6 +123,11 @@ type ValidatorSet interface {
 	SetRandomness(seed common.Hash)
 	// Sets the randomness for use in the proposer policy
 	GetRandomness() common.Hash
+	// Sets the active votes of the validators, in list order, for use in the proposer policy.
+	// This is injected into the ValidatorSet when we call `getOrderedValidators`
+	SetVotes(votes []*big.Int)
+	// Gets the active votes of the validators for use in the proposer policy
+	GetVotes() []*big.Int
 
 	// Return the validator size
 	Size() int
@@ -143,6 +154,9 @@ type ValidatorSet interface {
 	// Copy validator set
 	Copy() ValidatorSet
 
+	// BLSCrypto returns the BLS implementation the validators sign with
+	BLSCrypto() blscrypto.BLSCryptoSelector
+
 	// CacheUncompressedBLSKey stores the uncompressed BLS public key to cache for each validator in the valset
 	CacheUncompressedBLSKey()
 
@@ -171,7 +185,7 @@ type ProposerSelector func(validatorSet ValidatorSet, lastBlockProposer common.A
 
 // ----------------------------------------------------------------------------
 
-func CombineIstanbulExtraToValidatorData(addrs []common.Address, blsPublicKeys []blscrypto.SerializedPublicKey, blsG1PublicKeys []blscrypto.SerializedG1PublicKey) ([]ValidatorData, error) {
+func CombineIstanbulExtraToValidatorData(addrs []common.Address, blsPublicKeys []blscrypto.SerializedPublicKey, blsG1PublicKeys []blscrypto.SerializedG1PublicKey, blsCurve uint8) ([]ValidatorData, error) {
 	if len(addrs) != len(blsPublicKeys) {
 		return nil, errInvalidValidatorSetDiffSize
 	}
@@ -181,6 +195,7 @@ func CombineIstanbulExtraToValidatorData(addrs []common.Address, blsPublicKeys [
 			Address:        addrs[i],
 			BLSPublicKey:   blsPublicKeys[i],
 			BLSG1PublicKey: blsG1PublicKeys[i],
+			BLSCurve:       blsCurve,
 		})
 	}
 
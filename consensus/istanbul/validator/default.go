@@ -37,6 +37,7 @@ type defaultValidator struct {
 	blsPublicKey             blscrypto.SerializedPublicKey
 	blsG1PublicKey           blscrypto.SerializedG1PublicKey
 	uncompressedBlsPublicKey []byte
+	blsCurve                 uint8
 }
 
 func newValidatorFromDataWithBLSKeyCache(data *istanbul.ValidatorDataWithBLSKeyCache) *defaultValidator {
@@ -45,6 +46,7 @@ func newValidatorFromDataWithBLSKeyCache(data *istanbul.ValidatorDataWithBLSKeyC
 		blsPublicKey:             data.BLSPublicKey,
 		blsG1PublicKey:           data.BLSG1PublicKey,
 		uncompressedBlsPublicKey: data.UncompressedBLSPublicKey,
+		blsCurve:                 data.BLSCurve,
 	}
 }
 
@@ -53,6 +55,7 @@ func newValidatorFromData(data *istanbul.ValidatorData) *defaultValidator {
 		address:        data.Address,
 		blsPublicKey:   data.BLSPublicKey,
 		blsG1PublicKey: data.BLSG1PublicKey,
+		blsCurve:       data.BLSCurve,
 	}
 }
 
@@ -61,6 +64,7 @@ func (val *defaultValidator) AsData() *istanbul.ValidatorData {
 		Address:        val.address,
 		BLSPublicKey:   val.blsPublicKey,
 		BLSG1PublicKey: val.blsG1PublicKey,
+		BLSCurve:       val.blsCurve,
 	}
 }
 
@@ -70,6 +74,7 @@ func (val *defaultValidator) AsDataWithBLSKeyCache() *istanbul.ValidatorDataWith
 		BLSPublicKey:             val.blsPublicKey,
 		BLSG1PublicKey:           val.blsG1PublicKey,
 		UncompressedBLSPublicKey: val.uncompressedBlsPublicKey,
+		BLSCurve:                 val.blsCurve,
 	}
 }
 
@@ -78,7 +83,8 @@ func (val *defaultValidator) BLSPublicKey() blscrypto.SerializedPublicKey { retu
 func (val *defaultValidator) BLSG1PublicKey() blscrypto.SerializedG1PublicKey {
 	return val.blsG1PublicKey
 }
-func (val *defaultValidator) String() string { return val.Address().String() }
+func (val *defaultValidator) BLSCurve() uint8 { return val.blsCurve }
+func (val *defaultValidator) String() string  { return val.Address().String() }
 
 func (val *defaultValidator) BLSPublicKeyUncompressed() []byte {
 	if len(val.uncompressedBlsPublicKey) == 0 {
@@ -94,12 +100,13 @@ func (val *defaultValidator) Copy() istanbul.Validator {
 		blsPublicKey:             val.blsPublicKey,
 		blsG1PublicKey:           val.blsG1PublicKey,
 		uncompressedBlsPublicKey: val.uncompressedBlsPublicKey,
+		blsCurve:                 val.blsCurve,
 	}
 }
 
 func (val *defaultValidator) CacheUncompressedBLSKey() {
 	if len(val.uncompressedBlsPublicKey) == 0 {
-		uncompressed, err := blscrypto.CryptoType().UncompressKey(val.blsPublicKey)
+		uncompressed, err := blscrypto.CryptoTypeOf(val.blsCurve).UncompressKey(val.blsPublicKey)
 		if err != nil {
 			log.Error("Bad BLS public key", "adddress", val.address, "bls", val.blsPublicKey)
 		}
@@ -142,6 +149,8 @@ type defaultSet struct {
 	// This is set when we call `getOrderedValidators`
 	// TODO Rename to `EpochState` that has validators & randomness
 	randomness common.Hash
+	// The active votes of the validators at the start of the epoch, in list order
+	votes []*big.Int
 }
 
 func newDefaultSet(validators []istanbul.ValidatorData) *defaultSet {
@@ -182,6 +191,8 @@ func (valSet *defaultSet) MinQuorumSize() int { return int(math.Ceil(float64(2*v
 
 func (valSet *defaultSet) SetRandomness(seed common.Hash) { valSet.randomness = seed }
 func (valSet *defaultSet) GetRandomness() common.Hash     { return valSet.randomness }
+func (valSet *defaultSet) SetVotes(votes []*big.Int)      { valSet.votes = votes }
+func (valSet *defaultSet) GetVotes() []*big.Int           { return valSet.votes }
 
 func (valSet *defaultSet) String() string {
 	var buf strings.Builder
@@ -204,6 +215,17 @@ func (valSet *defaultSet) String() string {
 	return fmt.Sprintf("{randomness: %s, validators: %s}", valSet.randomness.String(), buf.String())
 }
 
+// BLSCrypto returns the BLS implementation of the curve of the validators. A set
+// is replaced as a whole when the curve changes, so the first validator decides.
+func (valSet *defaultSet) BLSCrypto() blscrypto.BLSCryptoSelector {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+	if len(valSet.validators) == 0 {
+		return blscrypto.CryptoTypeOf(0)
+	}
+	return blscrypto.CryptoTypeOf(valSet.validators[0].BLSCurve())
+}
+
 func (valSet *defaultSet) CacheUncompressedBLSKey() {
 	valSet.validatorMu.RLock()
 	defer valSet.validatorMu.RUnlock()
@@ -272,6 +294,8 @@ func (valSet *defaultSet) AddValidators(validators []istanbul.ValidatorData) boo
 	}
 
 	valSet.validators = append(valSet.validators, newValidators...)
+	// The votes no longer match the list
+	valSet.votes = nil
 
 	return true
 }
@@ -298,6 +322,7 @@ func (valSet *defaultSet) RemoveValidators(removedValidators *big.Int) bool {
 	}
 
 	valSet.validators = tempList
+	valSet.votes = nil
 	return true
 }
 
@@ -310,6 +335,12 @@ func (valSet *defaultSet) Copy() istanbul.ValidatorSet {
 		newValSet.validators[i] = v.Copy()
 	}
 	newValSet.SetRandomness(valSet.randomness)
+	if valSet.votes != nil {
+		newValSet.votes = make([]*big.Int, len(valSet.votes))
+		for i, v := range valSet.votes {
+			newValSet.votes[i] = new(big.Int).Set(v)
+		}
+	}
 	return newValSet
 }
 
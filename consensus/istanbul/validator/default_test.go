@@ -74,7 +74,7 @@ func testNormalValSet(t *testing.T) {
 	val2 := New(addr2, bls.SerializedPublicKey{})
 
 	validators, _ := istanbul.CombineIstanbulExtraToValidatorData([]common.Address{addr1, addr2}, []bls.SerializedPublicKey{{}, {}},
-		[]bls.SerializedG1PublicKey{{}, {}})
+		[]bls.SerializedG1PublicKey{{}, {}}, 0)
 	valSet := newDefaultSet(validators)
 	if valSet == nil {
 		t.Errorf("the format of validator set is invalid")
@@ -254,3 +254,30 @@ func TestValidatorSetRLPEncoding(t *testing.T) {
 		t.Errorf("validatorSet mismatch: have %v, want %v", valSet, result)
 	}
 }
+
+func TestValidatorSetBLSCrypto(t *testing.T) {
+	if _, ok := NewSet(nil).BLSCrypto().(bls.BN256); !ok {
+		t.Errorf("empty validator set doesn't sign with BN256")
+	}
+
+	valSet := NewSet([]istanbul.ValidatorData{
+		{Address: common.BytesToAddress([]byte(string(rune(2)))), BLSCurve: bls.BLS12381Curve},
+		{Address: common.BytesToAddress([]byte(string(rune(4)))), BLSCurve: bls.BLS12381Curve},
+	})
+	if _, ok := valSet.BLSCrypto().(bls.BLS12381); !ok {
+		t.Errorf("BLS12-381 validator set doesn't sign with BLS12-381")
+	}
+
+	// the curve survives the snapshot encoding
+	rawVal, err := rlp.EncodeToBytes(valSet)
+	if err != nil {
+		t.Fatalf("Error %v", err)
+	}
+	var result *defaultSet
+	if err = rlp.DecodeBytes(rawVal, &result); err != nil {
+		t.Fatalf("Error %v", err)
+	}
+	if curve := result.GetByIndex(1).BLSCurve(); curve != bls.BLS12381Curve {
+		t.Errorf("validator curve mismatch: have %d, want %d", curve, bls.BLS12381Curve)
+	}
+}
@@ -18,8 +18,10 @@ package validator
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator/random"
 )
@@ -74,6 +76,39 @@ func StickyProposer(valSet istanbul.ValidatorSet, proposer common.Address, round
 	return valSet.List()[idx%uint64(valSet.Size())]
 }
 
+// StakeWeightedProposer selects the proposer of the first round at random, with a probability
+// proportional to the active votes of each validator, and advances in storage order on round
+// change. Under this policy the validator set randomness is unique to each block, so the draw
+// only depends on it. It falls back to ShuffledRoundRobinProposer when the votes are unknown.
+func StakeWeightedProposer(valSet istanbul.ValidatorSet, proposer common.Address, round uint64) istanbul.Validator {
+	if valSet.Size() == 0 {
+		return nil
+	}
+	votes := valSet.GetVotes()
+	total := new(big.Int)
+	if len(votes) == valSet.Size() {
+		for _, v := range votes {
+			total.Add(total, v)
+		}
+	}
+	if total.Sign() <= 0 {
+		return ShuffledRoundRobinProposer(valSet, proposer, round)
+	}
+
+	seed := valSet.GetRandomness()
+	draw := new(big.Int).SetBytes(crypto.Keccak256(seed[:]))
+	draw.Mod(draw, total)
+	idx := uint64(0)
+	for i, v := range votes {
+		if draw.Cmp(v) < 0 {
+			idx = uint64(i)
+			break
+		}
+		draw.Sub(draw, v)
+	}
+	return valSet.List()[(idx+round)%uint64(valSet.Size())]
+}
+
 // GetProposerSelector returns the ProposerSelector for the given Policy
 func GetProposerSelector(pp istanbul.ProposerPolicy) istanbul.ProposerSelector {
 	switch pp {
@@ -83,6 +118,8 @@ func GetProposerSelector(pp istanbul.ProposerPolicy) istanbul.ProposerSelector {
 		return RoundRobinProposer
 	case istanbul.ShuffledRoundRobin:
 		return ShuffledRoundRobinProposer
+	case istanbul.StakeWeighted:
+		return StakeWeightedProposer
 	default:
 		// Programming error.
 		panic(fmt.Sprintf("unknown proposer selection policy: %v", pp))
@@ -18,10 +18,12 @@ package validator
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/helper/bls"
@@ -45,7 +47,7 @@ func TestStickyProposer(t *testing.T) {
 	}
 
 	v, err := istanbul.CombineIstanbulExtraToValidatorData(addrs, make([]bls.SerializedPublicKey, len(addrs)),
-		make([]bls.SerializedG1PublicKey,len(addrs)))
+		make([]bls.SerializedG1PublicKey,len(addrs)), 0)
 	if err != nil {
 		t.Fatalf("CombineIstanbulExtraToValidatorData(...): %v", err)
 	}
@@ -103,7 +105,7 @@ func TestRoundRobinProposer(t *testing.T) {
 	}
 
 	v, err := istanbul.CombineIstanbulExtraToValidatorData(addrs, make([]bls.SerializedPublicKey, len(addrs)),
-		make([]bls.SerializedG1PublicKey,len(addrs)))
+		make([]bls.SerializedG1PublicKey,len(addrs)), 0)
 	if err != nil {
 		t.Fatalf("CombineIstanbulExtraToValidatorData(...): %v", err)
 	}
@@ -161,7 +163,7 @@ func TestShuffledRoundRobinProposer(t *testing.T) {
 	}
 
 	v, err := istanbul.CombineIstanbulExtraToValidatorData(addrs, make([]bls.SerializedPublicKey, len(addrs)),
-		make([]bls.SerializedG1PublicKey,len(addrs)))
+		make([]bls.SerializedG1PublicKey,len(addrs)), 0)
 	if err != nil {
 		t.Fatalf("CombineIstanbulExtraToValidatorData(...): %v", err)
 	}
@@ -272,3 +274,113 @@ func TestShuffledRoundRobinProposer(t *testing.T) {
 		}
 	})
 }
+
+func TestStakeWeightedProposer(t *testing.T) {
+	var addrs []common.Address
+	var validators []istanbul.Validator
+	for _, strAddr := range testAddresses {
+		addr := common.HexToAddress(strAddr)
+		addrs = append(addrs, addr)
+		validators = append(validators, New(addr, bls.SerializedPublicKey{}))
+	}
+
+	v, err := istanbul.CombineIstanbulExtraToValidatorData(addrs, make([]bls.SerializedPublicKey, len(addrs)),
+		make([]bls.SerializedG1PublicKey, len(addrs)), 0)
+	if err != nil {
+		t.Fatalf("CombineIstanbulExtraToValidatorData(...): %v", err)
+	}
+	valSet := newDefaultSet(v)
+	selector := GetProposerSelector(istanbul.StakeWeighted)
+
+	// Without votes the selection is the shuffled round robin one.
+	testSeed := common.HexToHash("f36aa9716b892ec8")
+	valSet.SetRandomness(testSeed)
+	for round := uint64(0); round < 5; round++ {
+		want := ShuffledRoundRobinProposer(valSet, addrs[0], round)
+		if have := selector(valSet, addrs[0], round); !reflect.DeepEqual(have, want) {
+			t.Errorf("proposer mismatch without votes on round %d: have %v, want %v", round, have, want)
+		}
+	}
+
+	votes := []int64{100, 0, 300, 500, 100}
+	var total int64
+	var bigVotes []*big.Int
+	for _, v := range votes {
+		bigVotes = append(bigVotes, big.NewInt(v))
+		total += v
+	}
+	valSet.SetVotes(bigVotes)
+
+	// Verify a number of explicit cases with expected output.
+	cases := []struct {
+		lastProposer common.Address
+		round        uint64
+		seed         common.Hash
+		want         istanbul.Validator
+	}{{
+		lastProposer: common.Address{},
+		round:        0,
+		seed:         testSeed,
+		want:         validators[4],
+	}, {
+		lastProposer: addrs[0],
+		round:        1,
+		seed:         testSeed,
+		want:         validators[0],
+	}, {
+		lastProposer: addrs[2],
+		round:        0,
+		seed:         common.HexToHash("02"),
+		want:         validators[0],
+	}, {
+		lastProposer: addrs[2],
+		round:        1,
+		seed:         common.HexToHash("02"),
+		want:         validators[1],
+	}, {
+		lastProposer: addrs[3],
+		round:        0,
+		seed:         common.HexToHash("04"),
+		want:         validators[3],
+	}}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case:%d", i), func(t *testing.T) {
+			t.Logf("SetRandomness(%s)", c.seed.String())
+			valSet.SetRandomness(c.seed)
+			t.Logf("selectProposer(%s, %d)", c.lastProposer.String(), c.round)
+			proposer := selector(valSet, c.lastProposer, c.round)
+			if val := proposer; !reflect.DeepEqual(val, c.want) {
+				t.Errorf("proposer mismatch: have %v, want %v", val, c.want)
+			}
+		})
+	}
+
+	// Verify that every validator proposes in turn during round changes.
+	valSet.SetRandomness(testSeed)
+	t.Run("round changes", func(t *testing.T) {
+		first := valSet.GetIndex(selector(valSet, common.Address{}, 0).Address())
+		for round := uint64(0); round < 100; round++ {
+			proposer := selector(valSet, common.Address{}, round)
+			if want := validators[(uint64(first)+round)%uint64(len(validators))]; !reflect.DeepEqual(proposer, want) {
+				t.Errorf("proposer mismatch on round %d: have %v, want %v", round, proposer, want)
+			}
+		}
+	})
+
+	// Verify that the proposals follow the votes when the randomness changes every sequence.
+	t.Run("sequence advancement", func(t *testing.T) {
+		const sequences = 20000
+		counts := make([]int64, len(validators))
+		for seq := uint64(0); seq < sequences; seq++ {
+			valSet.SetRandomness(crypto.Keccak256Hash(testSeed[:], new(big.Int).SetUint64(seq).Bytes()))
+			counts[valSet.GetIndex(selector(valSet, common.Address{}, 0).Address())]++
+		}
+		for i, count := range counts {
+			want := sequences * votes[i] / total
+			if count < want*9/10 || count > want*11/10 {
+				t.Errorf("proposals mismatch for validator %d: have %d, want about %d", i, count, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package verifier implements the light client verification of the epoch proofs
+// served by istanbul_getEpochProof. Starting from the genesis validator set, each
+// proof hands the validator set over to the one elected at the end of its epoch.
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	istanbulCore "github.com/mapprotocol/atlas/consensus/istanbul/core"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	// errInvalidEpoch is returned if a proof doesn't follow the last verified epoch.
+	errInvalidEpoch = errors.New("invalid epoch")
+	// errInvalidParentEpochHash is returned if a proof doesn't build on the last verified epoch block.
+	errInvalidParentEpochHash = errors.New("invalid parent epoch hash")
+	// errInvalidValidatorSetDiff is returned if the diff in a proof doesn't match its header
+	// or can't be applied to the validator set.
+	errInvalidValidatorSetDiff = errors.New("invalid validator set diff")
+	// errInvalidValidatorSet is returned if the validators in a proof don't match the diff.
+	errInvalidValidatorSet = errors.New("invalid validator set")
+	// errInvalidAggregatedSeal is returned if the aggregated seal in a proof doesn't match its header.
+	errInvalidAggregatedSeal = errors.New("invalid aggregated seal")
+	// errInsufficientSeals is returned if a seal isn't signed by a quorum of the validators.
+	errInsufficientSeals = errors.New("not enough seals to reach quorum")
+)
+
+// EpochProof bundles the last block of an epoch with the data needed to verify
+// the validator set it elects.
+type EpochProof struct {
+	Epoch  uint64        `json:"epoch"`
+	Header *types.Header `json:"header"`
+	// ParentEpochHash is the hash of the last block of the previous epoch.
+	ParentEpochHash common.Hash `json:"parentEpochHash"`
+
+	// The validator set diff decoded from the header extra.
+	AddedValidators             []common.Address                  `json:"addedValidators"`
+	AddedValidatorsPublicKeys   []blscrypto.SerializedPublicKey   `json:"addedValidatorsPublicKeys"`
+	AddedValidatorsG1PublicKeys []blscrypto.SerializedG1PublicKey `json:"addedValidatorsG1PublicKeys"`
+	AddedValidatorsBLSCurve     uint8                             `json:"addedValidatorsBLSCurve"`
+	RemovedValidators           *big.Int                          `json:"removedValidators"`
+
+	// Validators and ValidatorsBLSPublicKeys are the validator set of the next epoch.
+	Validators              []common.Address                `json:"validators"`
+	ValidatorsBLSPublicKeys []blscrypto.SerializedPublicKey `json:"validatorsBLSPublicKeys"`
+
+	// AggregatedSeal is the seal over the header, signed by the validators of the epoch.
+	AggregatedSeal types.IstanbulAggregatedSeal `json:"aggregatedSeal"`
+	// EpochValidatorSetSeal is the seal over the next validator set, signed by the
+	// validators of the epoch.
+	EpochValidatorSetSeal types.IstanbulEpochValidatorSetSeal `json:"epochValidatorSetSeal"`
+}
+
+// Verifier verifies a chain of epoch proofs and tracks the validator set.
+type Verifier struct {
+	config    *params.ChainConfig
+	epochSize uint64
+
+	epoch  uint64      // Last verified epoch
+	hash   common.Hash // Hash of the last block of the last verified epoch
+	valSet istanbul.ValidatorSet
+}
+
+// New creates a verifier starting from the validator set of the genesis block.
+func New(config *params.ChainConfig, epochSize uint64, genesis *types.Header) (*Verifier, error) {
+	if genesis.Number.Sign() != 0 {
+		return nil, fmt.Errorf("block %d is not the genesis block", genesis.Number)
+	}
+	extra, err := types.ExtractIstanbulExtra(genesis)
+	if err != nil {
+		return nil, err
+	}
+	if extra.RemovedValidators.BitLen() != 0 {
+		return nil, errInvalidValidatorSetDiff
+	}
+	validators, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+	if err != nil {
+		return nil, errInvalidValidatorSetDiff
+	}
+	return &Verifier{
+		config:    config,
+		epochSize: epochSize,
+		hash:      genesis.Hash(),
+		valSet:    validator.NewSet(validators),
+	}, nil
+}
+
+// Epoch returns the last verified epoch.
+func (v *Verifier) Epoch() uint64 { return v.epoch }
+
+// ValidatorSet returns a copy of the validator set elected by the last verified epoch.
+func (v *Verifier) ValidatorSet() istanbul.ValidatorSet { return v.valSet.Copy() }
+
+// Verify checks the proof of the epoch following the last verified one and moves
+// the verifier to the validator set it elects.
+func (v *Verifier) Verify(proof *EpochProof) error {
+	header := proof.Header
+	if proof.Epoch != v.epoch+1 || header == nil || header.Number == nil ||
+		!header.Number.IsUint64() || header.Number.Uint64() != istanbul.GetEpochLastBlockNumber(proof.Epoch, v.epochSize) {
+		return errInvalidEpoch
+	}
+	if proof.ParentEpochHash != v.hash {
+		return errInvalidParentEpochHash
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	if !istanbul.CompareValidatorSlices(proof.AddedValidators, extra.AddedValidators) ||
+		!istanbul.CompareValidatorPublicKeySlices(proof.AddedValidatorsPublicKeys, extra.AddedValidatorsPublicKeys) ||
+		!istanbul.CompareValidatorG1PublicKeySlices(proof.AddedValidatorsG1PublicKeys, extra.AddedValidatorsG1PublicKeys) ||
+		proof.AddedValidatorsBLSCurve != extra.AddedValidatorsBLSCurve ||
+		proof.RemovedValidators == nil || proof.RemovedValidators.Cmp(extra.RemovedValidators) != 0 {
+		return errInvalidValidatorSetDiff
+	}
+	if !sameAggregatedSeal(proof.AggregatedSeal, extra.AggregatedSeal) {
+		return errInvalidAggregatedSeal
+	}
+
+	fork, cur := v.config.BN256ForkBlock, header.Number
+	// Both seals are signed by the validators of the epoch, on the curve of their keys
+	curve := v.valSet.BLSCrypto()
+	hash := header.Hash()
+
+	// The header is sealed by the validators of the epoch
+	publicKeys, err := v.signers(extra.AggregatedSeal.Bitmap)
+	if err != nil {
+		return err
+	}
+	if extra.AggregatedSeal.Round == nil {
+		return errInvalidAggregatedSeal
+	}
+	seal := istanbulCore.PrepareCommittedSeal(hash, extra.AggregatedSeal.Round)
+	if err := curve.VerifyAggregatedSignature(publicKeys, seal, []byte{}, extra.AggregatedSeal.Signature, false, false, fork, cur); err != nil {
+		return err
+	}
+
+	// Apply the diff and check the next validator set against the proof
+	valSet := v.valSet.Copy()
+	added, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys, extra.AddedValidatorsBLSCurve)
+	if err != nil {
+		return errInvalidValidatorSetDiff
+	}
+	if !valSet.RemoveValidators(extra.RemovedValidators) || !valSet.AddValidators(added) {
+		return errInvalidValidatorSetDiff
+	}
+	if !istanbul.CompareValidatorSlices(proof.Validators, istanbul.MapValidatorsToAddresses(valSet.List())) ||
+		!istanbul.CompareValidatorPublicKeySlices(proof.ValidatorsBLSPublicKeys, istanbul.MapValidatorsToPublicKeys(valSet.List())) {
+		return errInvalidValidatorSet
+	}
+
+	// The next validator set is sealed by the validators of the epoch in the same round
+	publicKeys, err = v.signers(proof.EpochValidatorSetSeal.Bitmap)
+	if err != nil {
+		return err
+	}
+	message, extraData, err := istanbulCore.EncodeEpochValidatorSetData(valSet, proof.Epoch,
+		uint8(extra.AggregatedSeal.Round.Uint64()), hash, proof.ParentEpochHash)
+	if err != nil {
+		return err
+	}
+	if err := curve.VerifyAggregatedSignature(publicKeys, message, extraData, proof.EpochValidatorSetSeal.Signature, true, true, fork, cur); err != nil {
+		return err
+	}
+
+	v.epoch, v.hash, v.valSet = proof.Epoch, hash, valSet
+	return nil
+}
+
+// signers returns the public keys of the validators in bitmap, it fails if they
+// don't reach the quorum of the current validator set.
+func (v *Verifier) signers(bitmap *big.Int) ([]blscrypto.SerializedPublicKey, error) {
+	if bitmap == nil || bitmap.BitLen() > v.valSet.Size() {
+		return nil, errInsufficientSeals
+	}
+	publicKeys := []blscrypto.SerializedPublicKey{}
+	for i := 0; i < v.valSet.Size(); i++ {
+		if bitmap.Bit(i) == 1 {
+			publicKeys = append(publicKeys, v.valSet.GetByIndex(uint64(i)).BLSPublicKey())
+		}
+	}
+	if len(publicKeys) < v.valSet.MinQuorumSize() {
+		return nil, errInsufficientSeals
+	}
+	return publicKeys, nil
+}
+
+func sameAggregatedSeal(a, b types.IstanbulAggregatedSeal) bool {
+	if a.Bitmap == nil || b.Bitmap == nil || a.Round == nil || b.Round == nil {
+		return false
+	}
+	return a.Bitmap.Cmp(b.Bitmap) == 0 && a.Round.Cmp(b.Round) == 0 && string(a.Signature) == string(b.Signature)
+}
+
+// VerifyChain verifies proofs for consecutive epochs starting from epoch 1 and
+// returns the validator set elected by the last one.
+func VerifyChain(config *params.ChainConfig, epochSize uint64, genesis *types.Header, proofs []*EpochProof) (istanbul.ValidatorSet, error) {
+	v, err := New(config, epochSize, genesis)
+	if err != nil {
+		return nil, err
+	}
+	for _, proof := range proofs {
+		if err := v.Verify(proof); err != nil {
+			return nil, fmt.Errorf("epoch %d: %v", proof.Epoch, err)
+		}
+	}
+	return v.ValidatorSet(), nil
+}
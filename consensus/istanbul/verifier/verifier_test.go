@@ -0,0 +1,259 @@
+package verifier
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	istanbulCore "github.com/mapprotocol/atlas/consensus/istanbul/core"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const testEpochSize = 10
+
+var testConfig = &params.ChainConfig{BN256ForkBlock: big.NewInt(0)}
+
+type testValidator struct {
+	data istanbul.ValidatorData
+	key  []byte
+}
+
+func newTestValidator(t *testing.T) testValidator {
+	ecdsaKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve := blscrypto.BN256{}
+	key, err := curve.ECDSAToBLS(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := curve.PrivateToPublic(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1, err := curve.PrivateToG1Public(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testValidator{
+		data: istanbul.ValidatorData{Address: crypto.PubkeyToAddress(ecdsaKey.PublicKey), BLSPublicKey: pub, BLSG1PublicKey: g1},
+		key:  key,
+	}
+}
+
+func makeHeader(t *testing.T, number uint64, extra *types.IstanbulExtra) *types.Header {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  append(bytes.Repeat([]byte{0x00}, types.IstanbulExtraVanity), payload...),
+	}
+}
+
+func makeGenesis(t *testing.T, vals []testValidator) *types.Header {
+	var data []istanbul.ValidatorData
+	for _, v := range vals {
+		data = append(data, v.data)
+	}
+	addrs, keys, g1Keys := istanbul.SeparateValidatorDataIntoIstanbulExtra(data)
+	return makeHeader(t, 0, &types.IstanbulExtra{
+		AddedValidators:             addrs,
+		AddedValidatorsPublicKeys:   keys,
+		AddedValidatorsG1PublicKeys: g1Keys,
+		RemovedValidators:           big.NewInt(0),
+		Seal:                        []byte{},
+	})
+}
+
+// makeProof builds the proof of epoch, sealed by signers of the current validators,
+// which replaces the removed validators with added.
+func makeProof(t *testing.T, epoch uint64, parentHash common.Hash, current []testValidator, signers []int,
+	removed *big.Int, added []testValidator) (*EpochProof, []testValidator) {
+	curve := blscrypto.BN256{}
+	number := epoch * testEpochSize
+	fork, cur := testConfig.BN256ForkBlock, new(big.Int).SetUint64(number)
+	round := big.NewInt(1)
+
+	var addedData []istanbul.ValidatorData
+	for _, v := range added {
+		addedData = append(addedData, v.data)
+	}
+	addrs, keys, g1Keys := istanbul.SeparateValidatorDataIntoIstanbulExtra(addedData)
+	extra := &types.IstanbulExtra{
+		AddedValidators:             addrs,
+		AddedValidatorsPublicKeys:   keys,
+		AddedValidatorsG1PublicKeys: g1Keys,
+		RemovedValidators:           removed,
+		Seal:                        []byte{},
+	}
+	hash := makeHeader(t, number, extra).Hash()
+
+	var next []testValidator
+	for i, v := range current {
+		if removed.Bit(i) == 0 {
+			next = append(next, v)
+		}
+	}
+	next = append(next, added...)
+	var nextData []istanbul.ValidatorData
+	for _, v := range next {
+		nextData = append(nextData, v.data)
+	}
+	nextSet := validator.NewSet(nextData)
+	message, extraData, err := istanbulCore.EncodeEpochValidatorSetData(nextSet, epoch, uint8(round.Uint64()), hash, parentHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitmap := big.NewInt(0)
+	var seals, epochSeals [][]byte
+	for _, i := range signers {
+		bitmap.SetBit(bitmap, i, 1)
+		seal, err := curve.Sign(current[i].key, istanbulCore.PrepareCommittedSeal(hash, round), []byte{}, false, false, fork, cur)
+		if err != nil {
+			t.Fatal(err)
+		}
+		epochSeal, err := curve.Sign(current[i].key, message, extraData, true, true, fork, cur)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seals, epochSeals = append(seals, seal[:]), append(epochSeals, epochSeal[:])
+	}
+	aggregatedSeal, err := curve.AggregateSignatures(seals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregatedEpochSeal, err := curve.AggregateSignatures(epochSeals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra.AggregatedSeal = types.IstanbulAggregatedSeal{Bitmap: bitmap, Signature: aggregatedSeal, Round: round}
+
+	return &EpochProof{
+		Epoch:                       epoch,
+		Header:                      makeHeader(t, number, extra),
+		ParentEpochHash:             parentHash,
+		AddedValidators:             addrs,
+		AddedValidatorsPublicKeys:   keys,
+		AddedValidatorsG1PublicKeys: g1Keys,
+		RemovedValidators:           removed,
+		Validators:                  istanbul.MapValidatorsToAddresses(nextSet.List()),
+		ValidatorsBLSPublicKeys:     istanbul.MapValidatorsToPublicKeys(nextSet.List()),
+		AggregatedSeal:              extra.AggregatedSeal,
+		EpochValidatorSetSeal:       types.IstanbulEpochValidatorSetSeal{Bitmap: new(big.Int).Set(bitmap), Signature: aggregatedEpochSeal},
+	}, next
+}
+
+func TestVerifyChain(t *testing.T) {
+	vals := []testValidator{newTestValidator(t), newTestValidator(t), newTestValidator(t), newTestValidator(t)}
+	genesis := makeGenesis(t, vals)
+
+	proof1, vals1 := makeProof(t, 1, genesis.Hash(), vals, []int{0, 1, 2}, big.NewInt(0), nil)
+	proof2, vals2 := makeProof(t, 2, proof1.Header.Hash(), vals1, []int{1, 2, 3}, big.NewInt(1), []testValidator{newTestValidator(t)})
+	proof3, vals3 := makeProof(t, 3, proof2.Header.Hash(), vals2, []int{0, 1, 3}, big.NewInt(0), nil)
+
+	valSet, err := VerifyChain(testConfig, testEpochSize, genesis, []*EpochProof{proof1, proof2, proof3})
+	if err != nil {
+		t.Fatalf("failed to verify epoch proofs: %v", err)
+	}
+	if valSet.Size() != len(vals3) {
+		t.Fatalf("validator set size mismatch: have %d, want %d", valSet.Size(), len(vals3))
+	}
+	for i, v := range vals3 {
+		if valSet.GetByIndex(uint64(i)).Address() != v.data.Address {
+			t.Errorf("validator %d mismatch: have %v, want %v", i, valSet.GetByIndex(uint64(i)).Address(), v.data.Address)
+		}
+	}
+}
+
+func TestVerifyInvalidProofs(t *testing.T) {
+	vals := []testValidator{newTestValidator(t), newTestValidator(t), newTestValidator(t), newTestValidator(t)}
+	genesis := makeGenesis(t, vals)
+
+	tests := []struct {
+		name   string
+		mutate func(*EpochProof) *EpochProof
+		err    error
+	}{
+		{
+			"skipped epoch",
+			func(p *EpochProof) *EpochProof {
+				proof, _ := makeProof(t, 2, genesis.Hash(), vals, []int{0, 1, 2}, big.NewInt(0), nil)
+				return proof
+			},
+			errInvalidEpoch,
+		},
+		{
+			"wrong parent epoch",
+			func(p *EpochProof) *EpochProof {
+				proof, _ := makeProof(t, 1, common.Hash{1}, vals, []int{0, 1, 2}, big.NewInt(0), nil)
+				return proof
+			},
+			errInvalidParentEpochHash,
+		},
+		{
+			"no quorum",
+			func(p *EpochProof) *EpochProof {
+				proof, _ := makeProof(t, 1, genesis.Hash(), vals, []int{0, 1}, big.NewInt(0), nil)
+				return proof
+			},
+			errInsufficientSeals,
+		},
+		{
+			"diff mismatch",
+			func(p *EpochProof) *EpochProof {
+				p.RemovedValidators = big.NewInt(1)
+				return p
+			},
+			errInvalidValidatorSetDiff,
+		},
+		{
+			"validator set mismatch",
+			func(p *EpochProof) *EpochProof {
+				p.Validators = p.Validators[1:]
+				return p
+			},
+			errInvalidValidatorSet,
+		},
+		{
+			"seal mismatch",
+			func(p *EpochProof) *EpochProof {
+				p.AggregatedSeal.Round = big.NewInt(2)
+				return p
+			},
+			errInvalidAggregatedSeal,
+		},
+	}
+	for _, tt := range tests {
+		proof, _ := makeProof(t, 1, genesis.Hash(), vals, []int{0, 1, 2}, big.NewInt(0), nil)
+		v, err := New(testConfig, testEpochSize, genesis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := v.Verify(tt.mutate(proof)); err != tt.err {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+		}
+		if v.Epoch() != 0 {
+			t.Errorf("%s: verifier advanced to epoch %d", tt.name, v.Epoch())
+		}
+	}
+
+	// An epoch validator set seal not made by the validators in its bitmap is rejected
+	proof, _ := makeProof(t, 1, genesis.Hash(), vals, []int{0, 1, 2}, big.NewInt(0), nil)
+	other, _ := makeProof(t, 1, genesis.Hash(), vals, []int{1, 2, 3}, big.NewInt(0), nil)
+	proof.EpochValidatorSetSeal.Signature = other.EpochValidatorSetSeal.Signature
+	v, _ := New(testConfig, testEpochSize, genesis)
+	if err := v.Verify(proof); err == nil {
+		t.Error("invalid epoch validator set seal accepted")
+	}
+}
@@ -14,6 +14,8 @@ var (
 	BlockchainParameters *abi.ABI = mustParseAbi("BlockchainParameters", BlockchainParametersStr)
 	ERC20                *abi.ABI = mustParseAbi("ERC20", ERC20Str)
 	FeeCurrency          *abi.ABI = mustParseAbi("FeeCurrency", FeeCurrencyStr)
+	FeeCurrencyToken     *abi.ABI = mustParseAbi("FeeCurrencyToken", FeeCurrencyTokenStr)
+	SortedOracles        *abi.ABI = mustParseAbi("SortedOracles", SortedOraclesStr)
 	Elections            *abi.ABI = mustParseAbi("Elections", ElectionsStr)
 	EpochRewards         *abi.ABI = mustParseAbi("EpochRewards", EpochRewardsStr)
 	GasPriceMinimum      *abi.ABI = mustParseAbi("GasPriceMinimum", GasPriceMinimumStr)
@@ -21,6 +23,8 @@ var (
 	Random               *abi.ABI = mustParseAbi("Random", RandomStr)
 	Validators           *abi.ABI = mustParseAbi("Validators", ValidatorsStr)
 	Accounts             *abi.ABI = mustParseAbi("Accounts", AccountsStr)
+	LockedGold           *abi.ABI = mustParseAbi("LockedGold", LockedGoldStr)
+	Jail                 *abi.ABI = mustParseAbi("Jail", params.JailABIJSON)
 )
 
 func mustParseAbi(name, abiStr string) *abi.ABI {
@@ -38,7 +42,9 @@ var byRegistryId = map[common.Hash]*abi.ABI{
 	params.EpochRewardsRegistryId:         EpochRewards,
 	params.GasPriceMinimumRegistryId:      GasPriceMinimum,
 	params.GoldTokenRegistryId:            GoldToken,
+	params.LockedGoldRegistryId:           LockedGold,
 	params.RandomRegistryId:               Random,
+	params.SortedOraclesRegistryId:        SortedOracles,
 	params.ValidatorsRegistryId:           Validators,
 }
 
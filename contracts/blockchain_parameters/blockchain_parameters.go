@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/contracts"
 	"github.com/mapprotocol/atlas/contracts/abis"
 	"github.com/mapprotocol/atlas/core/vm"
@@ -33,6 +34,7 @@ var (
 	intrinsicGasForAlternativeFeeCurrencyMethod = contracts.NewRegisteredContractMethod(params.BlockchainParametersRegistryId, abis.BlockchainParameters, "intrinsicGasForAlternativeFeeCurrency", params.MaxGasForReadBlockchainParameter)
 	blockGasLimitMethod                         = contracts.NewRegisteredContractMethod(params.BlockchainParametersRegistryId, abis.BlockchainParameters, "blockGasLimit", params.MaxGasForReadBlockchainParameter)
 	getUptimeLookbackWindowMethod               = contracts.NewRegisteredContractMethod(params.BlockchainParametersRegistryId, abis.BlockchainParameters, "getUptimeLookbackWindow", params.MaxGasForReadBlockchainParameter)
+	getRoundChangeTimeoutsMethod                = contracts.NewRegisteredContractMethod(params.BlockchainParametersRegistryId, abis.BlockchainParameters, "getRoundChangeTimeouts", params.MaxGasForReadBlockchainParameter)
 )
 
 // getMinimumVersion retrieves the client required minimum version
@@ -66,8 +68,6 @@ func GetIntrinsicGasForAlternativeFeeCurrencyOrDefault(vmRunner vm.EVMRunner) ui
 // getIntrinsicGasForAlternativeFeeCurrency retrieves the intrisic gas for transactions that pay gas in
 // with an alternative currency
 func getIntrinsicGasForAlternativeFeeCurrency(vmRunner vm.EVMRunner) (uint64, error) {
-	return 0, nil
-	//TODO Replace with the following in the future
 	var gas *big.Int
 	err := intrinsicGasForAlternativeFeeCurrencyMethod.Query(vmRunner, &gas)
 
@@ -116,6 +116,44 @@ func GetLookbackWindow(vmRunner vm.EVMRunner) (uint64, error) {
 	return lookbackWindow.Uint64(), nil
 }
 
+// GetRoundChangeTimeoutsOrDefault retrieves the istanbul round change timeouts
+// In case of error, or for the timeouts not set, it returns the default values
+func GetRoundChangeTimeoutsOrDefault(vmRunner vm.EVMRunner, defaults *istanbul.RoundTimeouts) *istanbul.RoundTimeouts {
+	timeouts, err := getRoundChangeTimeouts(vmRunner)
+	if err != nil {
+		logError("getRoundChangeTimeouts", err)
+		return defaults
+	}
+	if timeouts.RequestTimeout == 0 {
+		timeouts.RequestTimeout = defaults.RequestTimeout
+	}
+	if timeouts.TimeoutBackoffFactor == 0 {
+		timeouts.TimeoutBackoffFactor = defaults.TimeoutBackoffFactor
+	}
+	if timeouts.MinResendRoundChangeTimeout == 0 {
+		timeouts.MinResendRoundChangeTimeout = defaults.MinResendRoundChangeTimeout
+	}
+	if timeouts.MaxResendRoundChangeTimeout == 0 {
+		timeouts.MaxResendRoundChangeTimeout = defaults.MaxResendRoundChangeTimeout
+	}
+	return timeouts
+}
+
+// getRoundChangeTimeouts retrieves the istanbul round change timeouts
+func getRoundChangeTimeouts(vmRunner vm.EVMRunner) (*istanbul.RoundTimeouts, error) {
+	timeouts := [4]*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)}
+	err := getRoundChangeTimeoutsMethod.Query(vmRunner, &timeouts)
+	if err != nil {
+		return nil, err
+	}
+	return &istanbul.RoundTimeouts{
+		RequestTimeout:              timeouts[0].Uint64(),
+		TimeoutBackoffFactor:        timeouts[1].Uint64(),
+		MinResendRoundChangeTimeout: timeouts[2].Uint64(),
+		MaxResendRoundChangeTimeout: timeouts[3].Uint64(),
+	}, nil
+}
+
 // checkMinimumVersion performs a check on the client's minimum version
 // In case of not passing hte check it will exit the node
 func checkMinimumVersion(vmRunner vm.EVMRunner) {
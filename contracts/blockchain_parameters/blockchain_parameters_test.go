@@ -0,0 +1,51 @@
+package blockchain_parameters
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+var setRoundChangeTimeoutsMethod = contracts.NewRegisteredContractMethod(params.BlockchainParametersRegistryId, abis.BlockchainParameters, "setRoundChangeTimeouts", params.MaxGasForReadBlockchainParameter)
+
+func TestGetRoundChangeTimeoutsOrDefault(t *testing.T) {
+	defaults := &istanbul.RoundTimeouts{
+		RequestTimeout:              3000,
+		TimeoutBackoffFactor:        1000,
+		MinResendRoundChangeTimeout: 15 * 1000,
+		MaxResendRoundChangeTimeout: 2 * 60 * 1000,
+	}
+
+	t.Run("should return the defaults if the runner fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		g.Expect(GetRoundChangeTimeoutsOrDefault(testutil.FailingVmRunner{}, defaults)).To(Equal(defaults))
+	})
+
+	t.Run("should return the defaults for the timeouts not set", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		atlas := testutil.NewAtlasMock()
+		g.Expect(GetRoundChangeTimeoutsOrDefault(atlas.Runner, defaults)).To(Equal(defaults))
+	})
+
+	t.Run("should read back the timeouts set in the contract", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		atlas := testutil.NewAtlasMock()
+		err := setRoundChangeTimeoutsMethod.Execute(atlas.Runner, nil, common.Big0,
+			big.NewInt(5000), big.NewInt(2000), big.NewInt(0), big.NewInt(60*1000))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(GetRoundChangeTimeoutsOrDefault(atlas.Runner, defaults)).To(Equal(&istanbul.RoundTimeouts{
+			RequestTimeout:              5000,
+			TimeoutBackoffFactor:        2000,
+			MinResendRoundChangeTimeout: defaults.MinResendRoundChangeTimeout,
+			MaxResendRoundChangeTimeout: 60 * 1000,
+		}))
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package currency
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	getWhitelistMethod = contracts.NewRegisteredContractMethod(params.FeeCurrencyWhitelistRegistryId, abis.FeeCurrency, "getWhitelist", params.MaxGasForGetWhiteList)
+	medianRateMethod   = contracts.NewRegisteredContractMethod(params.SortedOraclesRegistryId, abis.SortedOracles, "medianRate", params.MaxGasForMedianRate)
+
+	balanceOfMethod     = contracts.NewMethod(abis.FeeCurrencyToken, "balanceOf", params.MaxGasToReadErc20Balance)
+	debitGasFeesMethod  = contracts.NewMethod(abis.FeeCurrencyToken, "debitGasFees", params.MaxGasForDebitGasFeesTransactions)
+	creditGasFeesMethod = contracts.NewMethod(abis.FeeCurrencyToken, "creditGasFees", params.MaxGasForCreditGasFeesTransactions)
+)
+
+// ExchangeRate is the price of MAP in a fee currency, as the amount of the
+// currency (numerator) worth denominator MAP.
+type ExchangeRate struct {
+	numerator   *big.Int
+	denominator *big.Int
+}
+
+// NewExchangeRate creates an exchange rate, both amounts must be positive.
+func NewExchangeRate(numerator, denominator *big.Int) (*ExchangeRate, error) {
+	if numerator.Sign() <= 0 || denominator.Sign() <= 0 {
+		return nil, contracts.ErrExchangeRateZero
+	}
+	return &ExchangeRate{numerator: numerator, denominator: denominator}, nil
+}
+
+// ToBase converts an amount of the fee currency to MAP.
+func (er *ExchangeRate) ToBase(amount *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(amount, er.denominator), er.numerator)
+}
+
+// FromBase converts an amount of MAP to the fee currency.
+func (er *ExchangeRate) FromBase(amount *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(amount, er.numerator), er.denominator)
+}
+
+// Manager caches the exchange rates of the fee currencies at a block, so that
+// transactions paying for gas in different currencies can be compared.
+type Manager struct {
+	vmRunner vm.EVMRunner
+
+	rates map[common.Address]*ExchangeRate
+	mu    sync.Mutex
+}
+
+// NewManager creates a manager reading the exchange rates through vmRunner.
+func NewManager(vmRunner vm.EVMRunner) *Manager {
+	return &Manager{
+		vmRunner: vmRunner,
+		rates:    make(map[common.Address]*ExchangeRate),
+	}
+}
+
+// GetExchangeRate returns the exchange rate of currency, nil means MAP.
+func (m *Manager) GetExchangeRate(currency *common.Address) (*ExchangeRate, error) {
+	if currency == nil {
+		return &ExchangeRate{numerator: common.Big1, denominator: common.Big1}, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rate, ok := m.rates[*currency]; ok {
+		return rate, nil
+	}
+	rate, err := GetExchangeRate(m.vmRunner, *currency)
+	if err != nil {
+		return nil, err
+	}
+	m.rates[*currency] = rate
+	return rate, nil
+}
+
+// ToBase converts amount of currency to MAP, an amount of a currency without
+// exchange rate is worth nothing.
+func (m *Manager) ToBase(amount *big.Int, currency *common.Address) *big.Int {
+	rate, err := m.GetExchangeRate(currency)
+	if err != nil {
+		log.Debug("Failed to get fee currency exchange rate", "currency", currency, "err", err)
+		return new(big.Int)
+	}
+	return rate.ToBase(amount)
+}
+
+// CmpValues compares val1 of currency1 with val2 of currency2 in MAP.
+func (m *Manager) CmpValues(val1 *big.Int, currency1 *common.Address, val2 *big.Int, currency2 *common.Address) int {
+	if currency1 == currency2 || (currency1 != nil && currency2 != nil && *currency1 == *currency2) {
+		return val1.Cmp(val2)
+	}
+	return m.ToBase(val1, currency1).Cmp(m.ToBase(val2, currency2))
+}
+
+// GetWhitelist returns the currencies transactions may pay for gas in.
+func GetWhitelist(vmRunner vm.EVMRunner) ([]common.Address, error) {
+	var whitelist []common.Address
+	err := getWhitelistMethod.Query(vmRunner, &whitelist)
+	return whitelist, err
+}
+
+// IsWhitelisted tells whether transactions may pay for gas in currency.
+func IsWhitelisted(vmRunner vm.EVMRunner, currency common.Address) bool {
+	whitelist, err := GetWhitelist(vmRunner)
+	if err != nil {
+		log.Warn("Failed to get fee currency whitelist", "err", err)
+		return false
+	}
+	for _, addr := range whitelist {
+		if addr == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExchangeRate returns the exchange rate of currency reported by the oracles.
+func GetExchangeRate(vmRunner vm.EVMRunner, currency common.Address) (*ExchangeRate, error) {
+	var rate [2]*big.Int
+	if err := medianRateMethod.Query(vmRunner, &rate, currency); err != nil {
+		return nil, err
+	}
+	return NewExchangeRate(rate[0], rate[1])
+}
+
+// GetBalanceOf returns the balance of account in currency.
+func GetBalanceOf(vmRunner vm.EVMRunner, account common.Address, currency common.Address) (*big.Int, error) {
+	var balance *big.Int
+	err := balanceOfMethod.Bind(currency).Query(vmRunner, &balance, account)
+	return balance, err
+}
+
+// DebitFees charges from the most a transaction can pay for gas, before it is
+// executed.
+func DebitFees(vmRunner vm.EVMRunner, currency common.Address, from common.Address, value *big.Int) error {
+	return debitGasFeesMethod.Bind(currency).Execute(vmRunner, nil, common.Big0, from, value)
+}
+
+// CreditFees refunds from the gas its transaction did not use and pays the tip
+// to feeRecipient. The currency decides what happens with the base fee.
+func CreditFees(vmRunner vm.EVMRunner, currency common.Address, from, feeRecipient common.Address, refund, tipTxFee, baseTxFee *big.Int) error {
+	return creditGasFeesMethod.Bind(currency).Execute(vmRunner, nil, common.Big0, from, feeRecipient, refund, tipTxFee, baseTxFee)
+}
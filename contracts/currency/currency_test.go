@@ -0,0 +1,111 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+var someCurrency = common.HexToAddress("0x0fc")
+
+type tokenMock struct {
+	testutil.ContractMock
+	balances map[common.Address]*big.Int
+	baseFees *big.Int
+}
+
+func newTokenMock() *tokenMock {
+	token := &tokenMock{balances: make(map[common.Address]*big.Int), baseFees: new(big.Int)}
+	token.ContractMock = testutil.NewContractMock(abis.FeeCurrencyToken, token)
+	return token
+}
+
+func (tm *tokenMock) BalanceOf(account common.Address) *big.Int {
+	if balance, ok := tm.balances[account]; ok {
+		return balance
+	}
+	return new(big.Int)
+}
+
+func (tm *tokenMock) DebitGasFees(from common.Address, value *big.Int) {
+	tm.balances[from] = new(big.Int).Sub(tm.BalanceOf(from), value)
+}
+
+func (tm *tokenMock) CreditGasFees(from, feeRecipient common.Address, refund, tipTxFee, baseTxFee *big.Int) {
+	tm.balances[from] = new(big.Int).Add(tm.BalanceOf(from), refund)
+	tm.balances[feeRecipient] = new(big.Int).Add(tm.BalanceOf(feeRecipient), tipTxFee)
+	tm.baseFees = new(big.Int).Add(tm.baseFees, baseTxFee)
+}
+
+func TestGetExchangeRate(t *testing.T) {
+	testutil.TestFailOnFailingRunner(t, GetExchangeRate, someCurrency)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetExchangeRate, someCurrency)
+
+	t.Run("should convert amounts at the median rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		vmrunner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", func(token common.Address) (*big.Int, *big.Int) {
+			g.Expect(token).To(Equal(someCurrency))
+			return big.NewInt(20), big.NewInt(10)
+		})
+
+		rate, err := GetExchangeRate(vmrunner, someCurrency)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(rate.ToBase(big.NewInt(100))).To(Equal(big.NewInt(50)))
+		g.Expect(rate.FromBase(big.NewInt(100))).To(Equal(big.NewInt(200)))
+	})
+
+	t.Run("should fail on a zero rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		vmrunner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", func(token common.Address) (*big.Int, *big.Int) {
+			return big.NewInt(0), big.NewInt(10)
+		})
+
+		_, err := GetExchangeRate(vmrunner, someCurrency)
+		g.Expect(err).To(Equal(contracts.ErrExchangeRateZero))
+	})
+}
+
+func TestIsWhitelisted(t *testing.T) {
+	t.Run("should be False if runner fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		g.Expect(IsWhitelisted(testutil.FailingVmRunner{}, someCurrency)).To(BeFalse())
+	})
+
+	t.Run("should tell whitelisted currencies", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		vmrunner := testutil.NewSingleMethodRunner(params.FeeCurrencyWhitelistRegistryId, "getWhitelist", func() []common.Address {
+			return []common.Address{common.HexToAddress("0x0fd"), someCurrency}
+		})
+
+		g.Expect(IsWhitelisted(vmrunner, someCurrency)).To(BeTrue())
+		g.Expect(IsWhitelisted(vmrunner, common.HexToAddress("0x0fe"))).To(BeFalse())
+	})
+}
+
+func TestDebitAndCreditFees(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var (
+		from     = common.HexToAddress("0x01")
+		coinbase = common.HexToAddress("0x02")
+		vmrunner = testutil.NewMockEVMRunner()
+		token    = newTokenMock()
+	)
+	vmrunner.RegisterContract(someCurrency, token)
+	token.balances[from] = big.NewInt(1000)
+
+	g.Expect(DebitFees(vmrunner, someCurrency, from, big.NewInt(300))).To(Succeed())
+	balance, err := GetBalanceOf(vmrunner, from, someCurrency)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(balance).To(Equal(big.NewInt(700)))
+
+	g.Expect(CreditFees(vmrunner, someCurrency, from, coinbase, big.NewInt(100), big.NewInt(50), big.NewInt(150))).To(Succeed())
+	g.Expect(token.BalanceOf(from)).To(Equal(big.NewInt(800)))
+	g.Expect(token.BalanceOf(coinbase)).To(Equal(big.NewInt(50)))
+	g.Expect(token.baseFees).To(Equal(big.NewInt(150)))
+}
@@ -32,10 +32,19 @@ var (
 	getElectableValidatorsMethod             = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getElectableValidators", params.MaxGasForGetElectableValidators)
 	electNValidatorSignersMethod             = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "electNValidatorSigners", params.MaxGasForElectNValidatorSigners)
 	getTotalVotesForEligibleValidatorsMethod = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getTotalVotesForEligibleValidators", params.MaxGasForGetEligibleValidatorsVoteTotals)
+	getActiveVotesForValidatorMethod         = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getActiveVotesForValidator", params.MaxGasForGetActiveVotesForValidator)
 	distributeEpochVotersRewardsMethod       = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "distributeEpochVotersRewards", params.MaxGasForDistributeVoterEpochRewards)
 
 	activeAllPendingMethod             = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "activeAllPending", params.MaxGasForActiveAllPending)
 	getPendingVotersForValidatorMethod = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getPendingVotersForValidator", params.MaxGasForActiveAllPending)
+
+	getPendingVotesForValidatorMethod          = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getPendingVotesForValidator", params.MaxGasForGetVotes)
+	getTotalVotesForValidatorMethod            = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getTotalVotesForValidator", params.MaxGasForGetVotes)
+	getPendingVotesForValidatorByAccountMethod = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getPendingVotesForValidatorByAccount", params.MaxGasForGetVotes)
+	getActiveVotesForValidatorByAccountMethod  = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getActiveVotesForValidatorByAccount", params.MaxGasForGetVotes)
+	getValidatorsVotedForByAccountMethod       = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getValidatorsVotedForByAccount", params.MaxGasForGetVotes)
+	getTotalVotesMethod                        = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getTotalVotes", params.MaxGasForGetVotes)
+	getValidatorEligibilityMethod              = contracts.NewRegisteredContractMethod(params.ElectionRegistryId, abis.Elections, "getValidatorEligibility", params.MaxGasForGetValidatorEligibility)
 )
 
 func GetElectedValidators(vmRunner vm.EVMRunner) ([]common.Address, error) {
@@ -45,14 +54,12 @@ func GetElectedValidators(vmRunner vm.EVMRunner) ([]common.Address, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newValSet, nil
+	return withoutJailedSigners(vmRunner, newValSet, 0)
 }
 
 func ElectNValidatorSigners(vmRunner vm.EVMRunner, additionalAboveMaxElectable int64) ([]common.Address, error) {
 	// Get the electable min and max
-	var minElectableValidators *big.Int
-	var maxElectableValidators *big.Int
-	err := getElectableValidatorsMethod.Query(vmRunner, &[]interface{}{&minElectableValidators, &maxElectableValidators})
+	minElectableValidators, maxElectableValidators, err := getElectableValidators(vmRunner)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +69,151 @@ func ElectNValidatorSigners(vmRunner vm.EVMRunner, additionalAboveMaxElectable i
 	if err != nil {
 		return nil, err
 	}
-	return electedValidators, nil
+	return withoutJailedSigners(vmRunner, electedValidators, additionalAboveMaxElectable)
+}
+
+func getElectableValidators(vmRunner vm.EVMRunner) (*big.Int, *big.Int, error) {
+	var minElectableValidators *big.Int
+	var maxElectableValidators *big.Int
+	err := getElectableValidatorsMethod.Query(vmRunner, &[]interface{}{&minElectableValidators, &maxElectableValidators})
+	if err != nil {
+		return nil, nil, err
+	}
+	return minElectableValidators, maxElectableValidators, nil
+}
+
+// withoutJailedSigners removes the jailed signers from the elected ones and runs
+// the election for as many more seats to fill theirs, up to maxElectable +
+// additionalAboveMaxElectable. The election is kept if every signer is jailed.
+func withoutJailedSigners(vmRunner vm.EVMRunner, elected []common.Address, additionalAboveMaxElectable int64) ([]common.Address, error) {
+	jailed, err := vm.ReadJailedSigners(vmRunner.GetStateDB())
+	if err != nil {
+		return nil, err
+	}
+	isJailed := make(map[common.Address]bool, len(jailed))
+	for _, signer := range jailed {
+		isJailed[signer] = true
+	}
+	anyJailed := false
+	for _, signer := range elected {
+		anyJailed = anyJailed || isJailed[signer]
+	}
+	if !anyJailed {
+		return elected, nil
+	}
+
+	minElectableValidators, maxElectableValidators, err := getElectableValidators(vmRunner)
+	if err != nil {
+		return nil, err
+	}
+	seats := maxElectableValidators.Int64() + additionalAboveMaxElectable
+	var candidates []common.Address
+	err = electNValidatorSignersMethod.Query(vmRunner, &candidates, minElectableValidators, big.NewInt(seats+int64(len(jailed))))
+	if err != nil {
+		return nil, err
+	}
+	unjailed := make([]common.Address, 0, len(candidates))
+	for _, signer := range candidates {
+		if !isJailed[signer] && int64(len(unjailed)) < seats {
+			unjailed = append(unjailed, signer)
+		}
+	}
+	if len(unjailed) == 0 {
+		log.Warn("Every elected validator is jailed, keeping the election", "elected", len(elected))
+		return elected, nil
+	}
+	return unjailed, nil
+}
+
+// GetActiveVotesForValidator returns the active votes received by the validator account
+func GetActiveVotesForValidator(vmRunner vm.EVMRunner, validator common.Address) (*big.Int, error) {
+	var votes *big.Int
+	err := getActiveVotesForValidatorMethod.Query(vmRunner, &votes, validator)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetPendingVotesForValidator returns the votes for the validator account that
+// are activated at the next epoch
+func GetPendingVotesForValidator(vmRunner vm.EVMRunner, validator common.Address) (*big.Int, error) {
+	var votes *big.Int
+	err := getPendingVotesForValidatorMethod.Query(vmRunner, &votes, validator)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetTotalVotesForValidator returns the pending and active votes for the validator account
+func GetTotalVotesForValidator(vmRunner vm.EVMRunner, validator common.Address) (*big.Int, error) {
+	var votes *big.Int
+	err := getTotalVotesForValidatorMethod.Query(vmRunner, &votes, validator)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetPendingVotersForValidator returns the accounts with pending votes for the validator account
+func GetPendingVotersForValidator(vmRunner vm.EVMRunner, validator common.Address) ([]common.Address, error) {
+	var voters []common.Address
+	err := getPendingVotersForValidatorMethod.Query(vmRunner, &voters, validator)
+	if err != nil {
+		return nil, err
+	}
+	return voters, nil
+}
+
+// GetPendingVotesForValidatorByAccount returns the pending votes of account for the validator account
+func GetPendingVotesForValidatorByAccount(vmRunner vm.EVMRunner, validator, account common.Address) (*big.Int, error) {
+	var votes *big.Int
+	err := getPendingVotesForValidatorByAccountMethod.Query(vmRunner, &votes, validator, account)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetActiveVotesForValidatorByAccount returns the active votes of account for the validator account
+func GetActiveVotesForValidatorByAccount(vmRunner vm.EVMRunner, validator, account common.Address) (*big.Int, error) {
+	var votes *big.Int
+	err := getActiveVotesForValidatorByAccountMethod.Query(vmRunner, &votes, validator, account)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetValidatorsVotedForByAccount returns the validator accounts account voted for
+func GetValidatorsVotedForByAccount(vmRunner vm.EVMRunner, account common.Address) ([]common.Address, error) {
+	var validators []common.Address
+	err := getValidatorsVotedForByAccountMethod.Query(vmRunner, &validators, account)
+	if err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
+
+// GetTotalVotes returns the pending and active votes for all the validators
+func GetTotalVotes(vmRunner vm.EVMRunner) (*big.Int, error) {
+	var votes *big.Int
+	err := getTotalVotesMethod.Query(vmRunner, &votes)
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetValidatorEligibility returns whether the validator account can receive votes and be elected
+func GetValidatorEligibility(vmRunner vm.EVMRunner, validator common.Address) (bool, error) {
+	var eligible bool
+	err := getValidatorEligibilityMethod.Query(vmRunner, &eligible, validator)
+	if err != nil {
+		return false, err
+	}
+	return eligible, nil
 }
 
 type voteTotal struct {
@@ -70,10 +221,20 @@ type voteTotal struct {
 	Value     *big.Int
 }
 
-func getTotalVotesForEligibleValidators(vmRunner vm.EVMRunner) ([]voteTotal, error) {
+// GetTotalVotesForEligibleValidators returns the eligible validator accounts and
+// their total votes, sorted by votes in descending order.
+func GetTotalVotesForEligibleValidators(vmRunner vm.EVMRunner) ([]common.Address, []*big.Int, error) {
 	var validators []common.Address
 	var values []*big.Int
 	err := getTotalVotesForEligibleValidatorsMethod.Query(vmRunner, &[]interface{}{&validators, &values})
+	if err != nil {
+		return nil, nil, err
+	}
+	return validators, values, nil
+}
+
+func getTotalVotesForEligibleValidators(vmRunner vm.EVMRunner) ([]voteTotal, error) {
+	validators, values, err := GetTotalVotesForEligibleValidators(vmRunner)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,47 @@
 package election
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mapprotocol/atlas/contracts"
 	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
 )
 
 func TestGetElectedValidators(t *testing.T) {
 	testutil.TestFailOnFailingRunner(t, GetElectedValidators)
 	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetElectedValidators)
 }
+
+func TestGetValidatorEligibility(t *testing.T) {
+	validator := common.HexToAddress("0x0a1")
+	testutil.TestFailOnFailingRunner(t, GetValidatorEligibility, validator)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetValidatorEligibility, validator)
+
+	g := NewGomegaWithT(t)
+	vmrunner := testutil.NewSingleMethodRunner(params.ElectionRegistryId, "getValidatorEligibility", func(v common.Address) bool {
+		return v == validator
+	})
+	eligible, err := GetValidatorEligibility(vmrunner, validator)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(eligible).To(BeTrue())
+}
+
+func TestGetPendingVotesForValidatorByAccount(t *testing.T) {
+	validator, account := common.HexToAddress("0x0a1"), common.HexToAddress("0x0b2")
+	testutil.TestFailOnFailingRunner(t, GetPendingVotesForValidatorByAccount, validator, account)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetPendingVotesForValidatorByAccount, validator, account)
+
+	g := NewGomegaWithT(t)
+	vmrunner := testutil.NewSingleMethodRunner(params.ElectionRegistryId, "getPendingVotesForValidatorByAccount", func(v, a common.Address) *big.Int {
+		g.Expect(v).To(Equal(validator))
+		g.Expect(a).To(Equal(account))
+		return big.NewInt(42)
+	})
+	votes, err := GetPendingVotesForValidatorByAccount(vmrunner, validator, account)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(votes).To(Equal(big.NewInt(42)))
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package jail
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	getJailStatusMethod    = contracts.NewBoundMethod(params.JailAddress, abis.Jail, "getJailStatus", params.MaxGasForGetJailStatus)
+	getJailedSignersMethod = contracts.NewBoundMethod(params.JailAddress, abis.Jail, "getJailedSigners", params.MaxGasForGetJailStatus)
+)
+
+// GetJailStatus returns whether the validator, given by its signer or its account,
+// is jailed and the epoch from which its account may unjail it.
+func GetJailStatus(vmRunner vm.EVMRunner, validator common.Address) (bool, *big.Int, error) {
+	var (
+		jailed       bool
+		releaseEpoch *big.Int
+	)
+	err := getJailStatusMethod.Query(vmRunner, &[]interface{}{&jailed, &releaseEpoch}, validator)
+	if err != nil {
+		return false, nil, err
+	}
+	return jailed, releaseEpoch, nil
+}
+
+// GetJailedSigners returns the signers of the jailed validators, they are not elected.
+func GetJailedSigners(vmRunner vm.EVMRunner) ([]common.Address, error) {
+	var signers []common.Address
+	if err := getJailedSignersMethod.Query(vmRunner, &signers); err != nil {
+		return nil, err
+	}
+	return signers, nil
+}
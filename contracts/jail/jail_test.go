@@ -0,0 +1,65 @@
+package jail
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+type jailMock struct {
+	releaseEpochs map[common.Address]*big.Int
+}
+
+func (j *jailMock) GetJailStatus(validator common.Address) (bool, *big.Int) {
+	if epoch, ok := j.releaseEpochs[validator]; ok {
+		return true, epoch
+	}
+	return false, common.Big0
+}
+
+func (j *jailMock) GetJailedSigners() []common.Address {
+	signers := make([]common.Address, 0, len(j.releaseEpochs))
+	for signer := range j.releaseEpochs {
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+func TestJail(t *testing.T) {
+	jailed := common.HexToAddress("0x01")
+	free := common.HexToAddress("0x02")
+
+	t.Run("should fail if the runner fails", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		_, _, err := GetJailStatus(testutil.FailingVmRunner{}, jailed)
+		g.Expect(err).To(MatchError(testutil.ErrFailingRunner))
+		_, err = GetJailedSigners(testutil.FailingVmRunner{})
+		g.Expect(err).To(MatchError(testutil.ErrFailingRunner))
+	})
+
+	t.Run("should read the jail status", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		runner := testutil.NewMockEVMRunner()
+		mock := &jailMock{releaseEpochs: map[common.Address]*big.Int{jailed: big.NewInt(7)}}
+		contract := testutil.NewContractMock(abis.Jail, mock)
+		runner.RegisterContract(params.JailAddress, &contract)
+
+		isJailed, releaseEpoch, err := GetJailStatus(runner, jailed)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(isJailed).To(BeTrue())
+		g.Expect(releaseEpoch).To(Equal(big.NewInt(7)))
+
+		isJailed, _, err = GetJailStatus(runner, free)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(isJailed).To(BeFalse())
+
+		signers, err := GetJailedSigners(runner)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(signers).To(Equal([]common.Address{jailed}))
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package locked_gold
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	getAccountNonvotingLockedGoldMethod = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "getAccountNonvotingLockedGold", params.MaxGasForGetNonvotingLockedGold)
+	getTotalLockedGoldMethod            = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "getTotalLockedGold", params.MaxGasForGetTotalLockedGold)
+	getNonvotingLockedGoldMethod        = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "getNonvotingLockedGold", params.MaxGasForGetNonvotingLockedGold)
+	slashMethod                         = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "slash", params.MaxGasForSlash)
+)
+
+// GetTotalLockedGold returns the MAP locked by all accounts.
+func GetTotalLockedGold(vmRunner vm.EVMRunner) (*big.Int, error) {
+	var amount *big.Int
+	if err := getTotalLockedGoldMethod.Query(vmRunner, &amount); err != nil {
+		return nil, err
+	}
+	return amount, nil
+}
+
+// GetNonvotingLockedGold returns the locked MAP of all accounts that is not used for votes.
+func GetNonvotingLockedGold(vmRunner vm.EVMRunner) (*big.Int, error) {
+	var amount *big.Int
+	if err := getNonvotingLockedGoldMethod.Query(vmRunner, &amount); err != nil {
+		return nil, err
+	}
+	return amount, nil
+}
+
+// GetAccountNonvotingLockedGold returns the locked MAP of account that is not used for votes.
+func GetAccountNonvotingLockedGold(vmRunner vm.EVMRunner, account common.Address) (*big.Int, error) {
+	var amount *big.Int
+	if err := getAccountNonvotingLockedGoldMethod.Query(vmRunner, &amount, account); err != nil {
+		return nil, err
+	}
+	return amount, nil
+}
+
+// Slash takes penalty from the locked MAP of account on behalf of slasher, which
+// has to be a whitelisted slasher, and pays reward out of it to reporter. The
+// penalty must not exceed the nonvoting locked MAP of account as no votes are
+// revoked.
+func Slash(vmRunner vm.EVMRunner, slasher, account common.Address, penalty *big.Int, reporter common.Address, reward *big.Int) error {
+	return slashMethod.ExecuteFrom(vmRunner, slasher, nil, common.Big0, account, penalty, reporter, reward,
+		[]common.Address{}, []common.Address{}, []*big.Int{})
+}
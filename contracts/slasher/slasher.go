@@ -0,0 +1,99 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package slasher
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/accounts"
+	"github.com/mapprotocol/atlas/contracts/locked_gold"
+	"github.com/mapprotocol/atlas/contracts/validators"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	registryOwnerMethod   = contracts.NewBoundMethod(params.RegistrySmartContractAddress, abis.Registry, "owner", params.MaxGasForGetOwner)
+	setAddressForMethod   = contracts.NewBoundMethod(params.RegistrySmartContractAddress, abis.Registry, "setAddressFor", params.MaxGasForSetAddressFor)
+	lockedGoldOwnerMethod = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "owner", params.MaxGasForGetOwner)
+	isSlasherMethod       = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "isSlasher", params.MaxGasForIsSlasher)
+	addSlasherMethod      = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "addSlasher", params.MaxGasForAddSlasher)
+)
+
+// RegisterSlasher registers slasher under name, which hashes to slasherId, in the
+// registry and whitelists it in LockedGold, both on behalf of the owners of the contracts. It enables a
+// slasher on chains whose genesis did not register it, the steps that were
+// already taken are skipped.
+func RegisterSlasher(vmRunner vm.EVMRunner, name string, slasherId common.Hash, slasher common.Address) error {
+	registered, err := contracts.GetRegisteredAddress(vmRunner, slasherId)
+	if err != nil && err != contracts.ErrSmartContractNotDeployed {
+		return err
+	}
+	if registered != slasher {
+		var owner common.Address
+		if err := registryOwnerMethod.Query(vmRunner, &owner); err != nil {
+			return err
+		}
+		if err := setAddressForMethod.ExecuteFrom(vmRunner, owner, nil, common.Big0, name, slasher); err != nil {
+			return err
+		}
+	}
+
+	var whitelisted bool
+	if err := isSlasherMethod.Query(vmRunner, &whitelisted, slasher); err != nil {
+		return err
+	}
+	if whitelisted {
+		return nil
+	}
+	var owner common.Address
+	if err := lockedGoldOwnerMethod.Query(vmRunner, &owner); err != nil {
+		return err
+	}
+	return addSlasherMethod.ExecuteFrom(vmRunner, owner, nil, common.Big0, name)
+}
+
+// SlashValidator slashes the account of the validator signer on behalf of the
+// slasher registered under slasherId. The penalty is capped by the nonvoting
+// locked MAP of the account and the reward paid to reporter by the penalty. The
+// slashing multiplier of the account is halved even if nothing could be taken.
+func SlashValidator(vmRunner vm.EVMRunner, slasherId common.Hash, signer, reporter common.Address, penalty, reward *big.Int) error {
+	slasher, err := contracts.GetRegisteredAddress(vmRunner, slasherId)
+	if err != nil {
+		return err
+	}
+	account, err := accounts.GetSignerToAccountMethod(vmRunner, signer)
+	if err != nil {
+		return err
+	}
+	nonvoting, err := locked_gold.GetAccountNonvotingLockedGold(vmRunner, account)
+	if err != nil {
+		return err
+	}
+	penalty = math.BigMin(penalty, nonvoting)
+	if penalty.Sign() > 0 {
+		reward = math.BigMin(reward, penalty)
+		if err := locked_gold.Slash(vmRunner, slasher, account, penalty, reporter, reward); err != nil {
+			return err
+		}
+	}
+	return validators.HalveSlashingMultiplier(vmRunner, slasher, account)
+}
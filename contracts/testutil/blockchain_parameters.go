@@ -14,6 +14,7 @@ type BlockchainParametersMock struct {
 	BlockGasLimitValue                         *big.Int
 	LookbackWindow                             *big.Int
 	IntrinsicGasForAlternativeFeeCurrencyValue *big.Int
+	RoundChangeTimeouts                        [4]*big.Int
 }
 
 func NewBlockchainParametersMock() *BlockchainParametersMock {
@@ -22,6 +23,7 @@ func NewBlockchainParametersMock() *BlockchainParametersMock {
 		BlockGasLimitValue: big.NewInt(20000000),
 		LookbackWindow:     big.NewInt(3),
 		IntrinsicGasForAlternativeFeeCurrencyValue: big.NewInt(10000),
+		RoundChangeTimeouts:                        [4]*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
 	}
 
 	contract := NewContractMock(abis.BlockchainParameters, mock)
@@ -41,3 +43,9 @@ func (bp *BlockchainParametersMock) GetUptimeLookbackWindow() *big.Int {
 func (bp *BlockchainParametersMock) IntrinsicGasForAlternativeFeeCurrency() *big.Int {
 	return bp.IntrinsicGasForAlternativeFeeCurrencyValue
 }
+func (bp *BlockchainParametersMock) GetRoundChangeTimeouts() (*big.Int, *big.Int, *big.Int, *big.Int) {
+	return bp.RoundChangeTimeouts[0], bp.RoundChangeTimeouts[1], bp.RoundChangeTimeouts[2], bp.RoundChangeTimeouts[3]
+}
+func (bp *BlockchainParametersMock) SetRoundChangeTimeouts(requestTimeout, timeoutBackoffFactor, minResendRoundChangeTimeout, maxResendRoundChangeTimeout *big.Int) {
+	bp.RoundChangeTimeouts = [4]*big.Int{requestTimeout, timeoutBackoffFactor, minResendRoundChangeTimeout, maxResendRoundChangeTimeout}
+}
@@ -30,12 +30,19 @@ import (
 	"github.com/mapprotocol/atlas/params"
 )
 
+// ValidatorContractData is the validator as returned by the Validators contract,
+// the score, commission and slash multiplier are fixidity fractions.
 type ValidatorContractData struct {
-	EcdsaPublicKey []byte
-	BlsPublicKey   []byte
-	Affiliation    common.Address
-	Score          *big.Int
-	Signer         common.Address
+	EcdsaPublicKey      []byte
+	BlsPublicKey        []byte
+	BlsG1PublicKey      []byte
+	Score               *big.Int
+	Signer              common.Address
+	Commission          *big.Int
+	NextCommission      *big.Int
+	NextCommissionBlock *big.Int
+	SlashMultiplier     *big.Int
+	LastSlashed         *big.Int
 }
 
 var (
@@ -50,6 +57,8 @@ var (
 	deRegisterValidatorsInPendingMethod        = contracts.NewRegisteredContractMethod(params.ValidatorsRegistryId, abis.Validators, "deRegisterAllValidatorsInPending", params.MaxGasForDeregisterPayment1)
 	getDeRegisteredValidatorsTMethod           = contracts.NewRegisteredContractMethod(params.ValidatorsRegistryId, abis.Validators, "getDeRegisteredValidatorsT", params.MaxGasForDistributeEpochPayment)
 	deRegisterValidatorsInPendingMethod2       = contracts.NewRegisteredContractMethod(params.ValidatorsRegistryId, abis.Validators, "deRegisterAllValidatorsInPending", params.MaxGasForDeregisterPayment)
+	halveSlashingMultiplierMethod              = contracts.NewRegisteredContractMethod(params.ValidatorsRegistryId, abis.Validators, "halveSlashingMultiplier", params.MaxGasForHalveSlashingMultiplier)
+	getTopValidatorsMethod                     = contracts.NewRegisteredContractMethod(params.ValidatorsRegistryId, abis.Validators, "getTopValidators", params.MaxGasForGetEligibleValidatorsVoteTotals)
 )
 
 func RetrieveRegisteredValidatorSigners(vmRunner vm.EVMRunner) ([]common.Address, error) {
@@ -167,3 +176,18 @@ func DeRegisterValidatorsInPending2(vmRunner vm.EVMRunner) (*[]common.Address, e
 	log.Info("new ", "Address", Address)
 	return &Address, err
 }
+
+// HalveSlashingMultiplier halves the reward multiplier of the validator account
+// on behalf of slasher, which has to be a whitelisted slasher.
+func HalveSlashingMultiplier(vmRunner vm.EVMRunner, slasher, account common.Address) error {
+	return halveSlashingMultiplierMethod.ExecuteFrom(vmRunner, slasher, nil, common.Big0, account)
+}
+
+// GetTopValidators returns the n eligible validator accounts with the most votes.
+func GetTopValidators(vmRunner vm.EVMRunner, n *big.Int) ([]common.Address, error) {
+	var top []common.Address
+	if err := getTopValidatorsMethod.Query(vmRunner, &top, n); err != nil {
+		return nil, err
+	}
+	return top, nil
+}
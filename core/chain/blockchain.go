@@ -196,6 +196,7 @@ type BlockChain struct {
 
 	currentBlock     atomic.Value // Current head of the block chain
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
+	mmr              *headerMMR   // Merkle Mountain Range over the canonical block hashes
 
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
 	bodyCache     *lru.Cache     // Cache for the most recent block bodies
@@ -216,6 +217,8 @@ type BlockChain struct {
 	processor  abstract.Processor // Block transaction processor abstract
 	vmConfig   vm.Config
 
+	systemTracers sync.Map // Tracers of the system calls made on a state, keyed by *state.StateDB
+
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
 }
 
@@ -264,6 +267,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		futureBlocks:   futureBlocks,
 		engine:         engine,
 		vmConfig:       vmConfig,
+		mmr:            newHeaderMMR(db, chainConfig.MMRBlock),
 	}
 	bc.validator = NewBlockValidator(chainConfig, bc, engine)
 	bc.prefetcher = NewStatePrefetcher(chainConfig, bc, engine)
@@ -463,6 +467,7 @@ func (bc *BlockChain) loadLastState() error {
 	// Everything seems to be fine, set as the head block
 	bc.currentBlock.Store(currentBlock)
 	headBlockGauge.Update(int64(currentBlock.NumberU64()))
+	bc.mmr.sync(currentBlock.Header())
 
 	// Restore the last known head header
 	currentHeader := currentBlock.Header()
@@ -670,6 +675,7 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	}
 	bc.currentBlock.Store(block)
 	headBlockGauge.Update(int64(block.NumberU64()))
+	bc.mmr.sync(block.Header())
 	bc.chainmu.Unlock()
 
 	// Destroy any existing state snapshot and regenerate it in the background,
@@ -832,6 +838,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	}
 	bc.currentBlock.Store(block)
 	headBlockGauge.Update(int64(block.NumberU64()))
+	bc.mmr.sync(block.Header())
 }
 
 // Genesis retrieves the chain's genesis block.
@@ -1452,9 +1459,20 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 
 // NewEVMRunner creates the System's EVMRunner for given header & sttate
 func (bc *BlockChain) NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner {
+	if tracer, ok := bc.systemTracers.Load(state); ok {
+		return vmcontext.NewTracedEVMRunner(bc, header, state, tracer.(vm.Tracer))
+	}
 	return vmcontext.NewEVMRunner(bc, header, state)
 }
 
+// TraceSystemCalls reports the calls of the EVM runners created for statedb,
+// such as the ones the consensus engine makes when finalizing a block, to
+// tracer until the returned function is called.
+func (bc *BlockChain) TraceSystemCalls(statedb *state.StateDB, tracer vm.Tracer) (stop func()) {
+	bc.systemTracers.Store(statedb, tracer)
+	return func() { bc.systemTracers.Delete(statedb) }
+}
+
 // WriteBlockWithState writes the block and all associated state to the database.
 func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) error {
 	if !bc.chainmu.TryLock() {
@@ -1534,6 +1552,11 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		// unlike all of the other saved data within this batch write
 		rawdb.WriteRandomCommitmentCache(blockBatch, randomCommitment, block.ParentHash())
 	}
+	if istEngine, isIstanbul := bc.engine.(consensus.Istanbul); isIstanbul {
+		if rewards := istEngine.EpochRewards(block.Hash()); rewards != nil {
+			rawdb.WriteEpochRewards(blockBatch, block.Hash(), rewards)
+		}
+	}
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
@@ -0,0 +1,222 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mapprotocol/atlas/consensus"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+var errMMRDisabled = errors.New("header mmr is not enabled")
+
+// headerMMR is a Merkle Mountain Range over the canonical block hashes starting
+// at the configured MMR block. Leaf i is the hash of block start+i. The perfect
+// subtrees are kept in the database so the mmr survives restarts.
+type headerMMR struct {
+	db    ethdb.Database
+	start *big.Int // nil if the mmr is disabled
+
+	mu     sync.RWMutex
+	leaves uint64        // number of leaves written to the database
+	peaks  []common.Hash // roots of the perfect subtrees holding the leaves
+}
+
+func newHeaderMMR(db ethdb.Database, start *big.Int) *headerMMR {
+	m := &headerMMR{
+		db:    db,
+		start: start,
+	}
+	if start == nil {
+		return m
+	}
+	m.leaves = rawdb.ReadHeaderMMRLeaves(db)
+	peaks, err := m.peaksAt(m.leaves)
+	if err != nil {
+		log.Warn("Rebuilding header mmr", "err", err)
+		m.leaves, peaks = 0, nil
+	}
+	m.peaks = peaks
+	return m
+}
+
+// node reads a perfect subtree of the mmr from the database.
+func (m *headerMMR) node(height uint, index uint64) (common.Hash, error) {
+	hash := rawdb.ReadHeaderMMRNode(m.db, height, index)
+	if hash == (common.Hash{}) {
+		return common.Hash{}, fmt.Errorf("missing header mmr node %d/%d", height, index)
+	}
+	return hash, nil
+}
+
+// peaksAt returns the roots of the perfect subtrees holding the first leaves
+// leaves, largest first.
+func (m *headerMMR) peaksAt(leaves uint64) ([]common.Hash, error) {
+	var (
+		peaks  []common.Hash
+		offset uint64
+	)
+	for height := uint(64); height > 0; height-- {
+		size := uint64(1) << (height - 1)
+		if leaves&size == 0 {
+			continue
+		}
+		peak, err := m.node(height-1, offset/size)
+		if err != nil {
+			return nil, err
+		}
+		peaks = append(peaks, peak)
+		offset += size
+	}
+	return peaks, nil
+}
+
+// pushLeaf appends hash as leaf number leaves to the mmr with the given peaks
+// and returns the new peaks. Every completed subtree is passed to write if set.
+func pushLeaf(peaks []common.Hash, leaves uint64, hash common.Hash, write func(height uint, index uint64, hash common.Hash)) []common.Hash {
+	if write != nil {
+		write(0, leaves, hash)
+	}
+	for height := uint(0); leaves>>height&1 == 1; height++ {
+		hash = mmr.MergeHash(peaks[len(peaks)-1], hash)
+		peaks = peaks[:len(peaks)-1]
+		if write != nil {
+			write(height+1, leaves>>(height+1), hash)
+		}
+	}
+	return append(peaks, hash)
+}
+
+// bagPeaks returns the root of the mmr with the given peaks.
+func bagPeaks(peaks []common.Hash) common.Hash {
+	if len(peaks) == 0 {
+		return common.Hash{}
+	}
+	root := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = mmr.MergeHash(peaks[i], root)
+	}
+	return root
+}
+
+// sync makes the mmr cover the canonical chain up to and including head. Leaves
+// that are no longer canonical are dropped before the new ones are pushed.
+func (m *headerMMR) sync(head *types.Header) {
+	if m.start == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start, number := m.start.Uint64(), head.Number.Uint64()
+	leaves := m.leaves
+	for leaves > 0 {
+		last := start + leaves - 1
+		if last <= number && rawdb.ReadCanonicalHash(m.db, last) == rawdb.ReadHeaderMMRNode(m.db, 0, leaves-1) {
+			break
+		}
+		leaves--
+	}
+	peaks := append([]common.Hash(nil), m.peaks...)
+	if leaves != m.leaves {
+		var err error
+		if peaks, err = m.peaksAt(leaves); err != nil {
+			log.Error("Failed to rewind header mmr", "leaves", leaves, "err", err)
+			return
+		}
+	}
+	batch := m.db.NewBatch()
+	write := func(height uint, index uint64, hash common.Hash) {
+		rawdb.WriteHeaderMMRNode(batch, height, index, hash)
+	}
+	for next := start + leaves; next <= number; next++ {
+		hash := rawdb.ReadCanonicalHash(m.db, next)
+		if hash == (common.Hash{}) {
+			log.Warn("Missing canonical hash for header mmr", "number", next)
+			break
+		}
+		peaks = pushLeaf(peaks, leaves, hash, write)
+		leaves++
+	}
+	rawdb.WriteHeaderMMRLeaves(batch, leaves)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write header mmr", "err", err)
+	}
+	m.leaves, m.peaks = leaves, peaks
+}
+
+// MMRRoot returns the root of the header mmr over the blocks from the MMR
+// block up to the parent of header. Ancestors of header that are not part of
+// the canonical chain are looked up in parents (ascending order) or the database.
+func (bc *BlockChain) MMRRoot(header *types.Header, parents []*types.Header) (common.Hash, error) {
+	m := bc.mmr
+	if m.start == nil {
+		return common.Hash{}, errMMRDisabled
+	}
+	start, number := m.start.Uint64(), header.Number.Uint64()
+	if number <= start {
+		return common.Hash{}, fmt.Errorf("block %d is not after the mmr block %d", number, start)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Walk back until the chain joins the leaves of the canonical mmr.
+	var (
+		side   []common.Hash
+		hash   = header.ParentHash
+		leaves = number - start
+	)
+	for leaves > 0 {
+		if leaves <= m.leaves && rawdb.ReadHeaderMMRNode(m.db, 0, leaves-1) == hash {
+			break
+		}
+		side = append(side, hash)
+		var parent *types.Header
+		if len(parents) > 0 && parents[len(parents)-1].Hash() == hash {
+			parent, parents = parents[len(parents)-1], parents[:len(parents)-1]
+		} else if parent = bc.GetHeader(hash, start+leaves-1); parent == nil {
+			return common.Hash{}, consensus.ErrUnknownAncestor
+		}
+		hash = parent.ParentHash
+		leaves--
+	}
+	peaks, err := m.peaksAt(leaves)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for i := len(side) - 1; i >= 0; i-- {
+		peaks = pushLeaf(peaks, leaves, side[i], nil)
+		leaves++
+	}
+	return bagPeaks(peaks), nil
+}
+
+// MMRProof returns the proof of the canonical block number against the mmr root
+// committed in the canonical block end, which covers the blocks before end.
+func (bc *BlockChain) MMRProof(number, end uint64) (*mmr.ProofInfo, error) {
+	m := bc.mmr
+	if m.start == nil {
+		return nil, errMMRDisabled
+	}
+	start := m.start.Uint64()
+	if number < start || number >= end {
+		return nil, fmt.Errorf("block %d is not covered by the mmr root in block %d", number, end)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if end-start > m.leaves {
+		return nil, fmt.Errorf("header mmr does not reach block %d", end-1)
+	}
+	return mmr.GenerateLeafProof(m.node, end-start, number-start)
+}
@@ -0,0 +1,183 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethparams "github.com/ethereum/go-ethereum/params"
+
+	"github.com/mapprotocol/atlas/consensus/consensustest"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func newMMRChain(t *testing.T, mmrBlock int64) (*BlockChain, *types.Block) {
+	config := *params.AllEthashProtocolChanges
+	config.MMRBlock = big.NewInt(mmrBlock)
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := (&Genesis{Config: &config, BaseFee: big.NewInt(ethparams.InitialBaseFee)}).MustCommit(db)
+	blockchain, err := NewBlockChain(db, nil, &config, consensustest.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	return blockchain, genesis
+}
+
+func newMMRLeaf(hash common.Hash) *mmr.Node {
+	return mmr.NewNode(hash, common.Big0, common.Big0, common.Big0, 0)
+}
+
+// expectedMMRRoot builds a fresh mmr over the given block hashes.
+func expectedMMRRoot(hashes []common.Hash) common.Hash {
+	m := mmr.NewMMR()
+	for _, hash := range hashes {
+		m.Push(newMMRLeaf(hash))
+	}
+	return m.GetRoot2()
+}
+
+func TestHeaderMMRProofs(t *testing.T) {
+	blockchain, genesis := newMMRChain(t, 1)
+	defer blockchain.Stop()
+
+	blocks := makeBlockChain(genesis, 10, consensustest.NewFaker(), blockchain.db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	hashes := make([]common.Hash, len(blocks))
+	for i, block := range blocks {
+		hashes[i] = block.Hash()
+	}
+
+	for end := uint64(2); end <= 10; end++ {
+		root, err := blockchain.MMRRoot(blocks[end-1].Header(), nil)
+		if err != nil {
+			t.Fatalf("root for block %d: %v", end, err)
+		}
+		if want := expectedMMRRoot(hashes[:end-1]); root != want {
+			t.Fatalf("root for block %d mismatch: have %x, want %x", end, root, want)
+		}
+		for number := uint64(1); number < end; number++ {
+			proof, err := blockchain.MMRProof(number, end)
+			if err != nil {
+				t.Fatalf("proof for block %d in %d: %v", number, end, err)
+			}
+			if err := proof.VerifyLeaf(root, number-1, hashes[number-1]); err != nil {
+				t.Errorf("proof for block %d in %d: %v", number, end, err)
+			}
+		}
+	}
+	if _, err := blockchain.MMRProof(10, 12); err == nil {
+		t.Error("expected error for a root beyond the head")
+	}
+	if _, err := blockchain.MMRProof(0, 5); err == nil {
+		t.Error("expected error for a block before the mmr block")
+	}
+}
+
+func TestHeaderMMRSideChain(t *testing.T) {
+	blockchain, genesis := newMMRChain(t, 1)
+	defer blockchain.Stop()
+
+	blocks := makeBlockChain(genesis, 10, consensustest.NewFaker(), blockchain.db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	fork := makeBlockChain(blocks[4], 6, consensustest.NewFaker(), blockchain.db, forkSeed)
+
+	var hashes []common.Hash
+	for _, block := range blocks[:5] {
+		hashes = append(hashes, block.Hash())
+	}
+	for _, block := range fork[:5] {
+		hashes = append(hashes, block.Hash())
+	}
+	// The fork blocks are not stored, they are passed in as parents.
+	parents := make([]*types.Header, 5)
+	for i, block := range fork[:5] {
+		parents[i] = block.Header()
+	}
+	root, err := blockchain.MMRRoot(fork[5].Header(), parents)
+	if err != nil {
+		t.Fatalf("side chain root: %v", err)
+	}
+	if want := expectedMMRRoot(hashes); root != want {
+		t.Fatalf("side chain root mismatch: have %x, want %x", root, want)
+	}
+	if _, err := blockchain.MMRRoot(fork[5].Header(), nil); err == nil {
+		t.Fatal("expected error for unknown side chain ancestors")
+	}
+
+	// Rewinding and importing the fork moves the mmr onto it.
+	if err := blockchain.SetHead(5); err != nil {
+		t.Fatalf("failed to rewind: %v", err)
+	}
+	if _, err := blockchain.MMRProof(3, 8); err == nil {
+		t.Error("expected error for a rewound root block")
+	}
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	proof, err := blockchain.MMRProof(7, 11)
+	if err != nil {
+		t.Fatalf("proof on fork: %v", err)
+	}
+	if err := proof.VerifyLeaf(root, 6, fork[1].Hash()); err != nil {
+		t.Errorf("proof on fork: %v", err)
+	}
+}
+
+func TestHeaderMMRPersisted(t *testing.T) {
+	blockchain, genesis := newMMRChain(t, 1)
+	blocks := makeBlockChain(genesis, 10, consensustest.NewFaker(), blockchain.db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	blockchain.Stop()
+
+	hashes := make([]common.Hash, len(blocks))
+	for i, block := range blocks {
+		hashes[i] = block.Hash()
+	}
+	// The mmr is loaded from its peaks, not rebuilt from the canonical chain.
+	m := newHeaderMMR(blockchain.db, blockchain.chainConfig.MMRBlock)
+	if m.leaves != 10 {
+		t.Fatalf("loaded %d leaves, want 10", m.leaves)
+	}
+	if root, want := bagPeaks(m.peaks), expectedMMRRoot(hashes); root != want {
+		t.Fatalf("loaded root mismatch: have %x, want %x", root, want)
+	}
+
+	restarted, err := NewBlockChain(blockchain.db, nil, blockchain.chainConfig, consensustest.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to restart chain: %v", err)
+	}
+	defer restarted.Stop()
+
+	more := makeBlockChain(blocks[9], 3, consensustest.NewFaker(), restarted.db, canonicalSeed)
+	if _, err := restarted.InsertChain(more); err != nil {
+		t.Fatalf("failed to extend chain: %v", err)
+	}
+	for _, block := range more {
+		hashes = append(hashes, block.Hash())
+	}
+	root, err := restarted.MMRRoot(more[2].Header(), nil)
+	if err != nil {
+		t.Fatalf("root after restart: %v", err)
+	}
+	if want := expectedMMRRoot(hashes[:12]); root != want {
+		t.Fatalf("root after restart mismatch: have %x, want %x", root, want)
+	}
+	proof, err := restarted.MMRProof(4, 13)
+	if err != nil {
+		t.Fatalf("proof after restart: %v", err)
+	}
+	if err := proof.VerifyLeaf(root, 3, hashes[3]); err != nil {
+		t.Errorf("proof after restart: %v", err)
+	}
+}
@@ -26,9 +26,12 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
 )
 
 var emptyCodeHash = crypto.Keccak256Hash(nil)
@@ -62,6 +65,11 @@ type StateTransition struct {
 	data       []byte
 	state      types.StateDB
 	evm        *vm.EVM
+
+	feeCurrency *common.Address // Currency the gas is paid in, nil for MAP
+	feeSnapshot int             // State before the gas was debited in the fee currency
+	baseFee     *big.Int        // Base fee of the block in the fee currency
+	vmRunner    vm.EVMRunner    // Runner of the fee currency calls
 }
 
 // Message represents a message sent to a contract.
@@ -72,6 +80,7 @@ type Message interface {
 	GasPrice() *big.Int
 	GasFeeCap() *big.Int
 	GasTipCap() *big.Int
+	FeeCurrency() *common.Address
 	Gas() uint64
 	Value() *big.Int
 
@@ -160,15 +169,17 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *core.GasPool) *StateTransition {
 	return &StateTransition{
-		gp:        gp,
-		evm:       evm,
-		msg:       msg,
-		gasPrice:  msg.GasPrice(),
-		gasFeeCap: msg.GasFeeCap(),
-		gasTipCap: msg.GasTipCap(),
-		value:     msg.Value(),
-		data:      msg.Data(),
-		state:     evm.StateDB,
+		gp:          gp,
+		evm:         evm,
+		msg:         msg,
+		gasPrice:    msg.GasPrice(),
+		gasFeeCap:   msg.GasFeeCap(),
+		gasTipCap:   msg.GasTipCap(),
+		value:       msg.Value(),
+		data:        msg.Data(),
+		state:       evm.StateDB,
+		feeCurrency: msg.FeeCurrency(),
+		baseFee:     evm.Context.BaseFee,
 	}
 }
 
@@ -194,6 +205,9 @@ func (st *StateTransition) to() common.Address {
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).SetUint64(st.msg.Gas())
 	mgval = mgval.Mul(mgval, st.gasPrice)
+	if st.feeCurrency != nil {
+		return st.buyGasInFeeCurrency(mgval)
+	}
 	balanceCheck := mgval
 	if st.gasFeeCap != nil {
 		balanceCheck = new(big.Int).SetUint64(st.msg.Gas())
@@ -213,6 +227,59 @@ func (st *StateTransition) buyGas() error {
 	return nil
 }
 
+// buyGasInFeeCurrency debits mgval from the balance of the sender in the fee
+// currency, the value is still transferred in MAP.
+func (st *StateTransition) buyGasInFeeCurrency(mgval *big.Int) error {
+	balanceCheck := new(big.Int).SetUint64(st.msg.Gas())
+	balanceCheck = balanceCheck.Mul(balanceCheck, st.gasFeeCap)
+	have, err := currency.GetBalanceOf(st.vmRunner, st.msg.From(), *st.feeCurrency)
+	if err != nil {
+		return err
+	}
+	if have.Cmp(balanceCheck) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v in %v", core.ErrInsufficientFunds, st.msg.From().Hex(), have, balanceCheck, st.feeCurrency.Hex())
+	}
+	if have, want := st.state.GetBalance(st.msg.From()), st.value; have.Cmp(want) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, st.msg.From().Hex(), have, want)
+	}
+	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
+		return err
+	}
+	st.feeSnapshot = st.state.Snapshot()
+	if err := currency.DebitFees(st.vmRunner, *st.feeCurrency, st.msg.From(), mgval); err != nil {
+		// The message is refused, its gas goes back to the block
+		st.gp.AddGas(st.msg.Gas())
+		return err
+	}
+	st.gas += st.msg.Gas()
+
+	st.initialGas = st.msg.Gas()
+	return nil
+}
+
+// prepareFeeCurrency checks the fee currency of the message and converts the
+// base fee of the block to it.
+func (st *StateTransition) prepareFeeCurrency() error {
+	st.vmRunner = vmcontext.NewEVMRunnerForEVM(st.evm)
+	// The gas of the fee currency calls is paid by the intrinsic gas
+	st.vmRunner.StopGasMetering()
+	if !currency.IsWhitelisted(st.vmRunner, *st.feeCurrency) {
+		return fmt.Errorf("%w: address %v, currency %v", core.ErrNonWhitelistedFeeCurrency, st.msg.From().Hex(), st.feeCurrency.Hex())
+	}
+	if st.baseFee == nil {
+		return nil
+	}
+	rate, err := currency.GetExchangeRate(st.vmRunner, *st.feeCurrency)
+	if err != nil {
+		return fmt.Errorf("%w: currency %v: %v", core.ErrFeeCurrencyRate, st.feeCurrency.Hex(), err)
+	}
+	st.baseFee = rate.FromBase(st.baseFee)
+	// The message can't know the base fee in the fee currency, so its gas
+	// price is the fee cap
+	st.gasPrice = cmath.BigMin(new(big.Int).Add(st.gasTipCap, st.baseFee), st.gasFeeCap)
+	return nil
+}
+
 func (st *StateTransition) preCheck() error {
 	// Only check transactions that are not fake
 	if !st.msg.IsFake() {
@@ -231,6 +298,15 @@ func (st *StateTransition) preCheck() error {
 				st.msg.From().Hex(), codeHash)
 		}
 	}
+	if st.feeCurrency != nil {
+		if !st.evm.ChainConfig().IsFeeCurrency(st.evm.Context.BlockNumber) {
+			return fmt.Errorf("%w: address %v, currency %v", core.ErrTxTypeNotSupported,
+				st.msg.From().Hex(), st.feeCurrency.Hex())
+		}
+		if err := st.prepareFeeCurrency(); err != nil {
+			return err
+		}
+	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
 		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
@@ -250,9 +326,9 @@ func (st *StateTransition) preCheck() error {
 			// This will panic if baseFee is nil, but basefee presence is verified
 			// as part of header validation.
 
-			if st.gasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 {
+			if st.gasFeeCap.Cmp(st.baseFee) < 0 {
 				return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", core.ErrFeeCapTooLow,
-					st.msg.From().Hex(), st.gasFeeCap, st.evm.Context.BaseFee)
+					st.msg.From().Hex(), st.gasFeeCap, st.baseFee)
 			}
 		}
 	}
@@ -302,6 +378,14 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if st.gas < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, st.gas, gas)
 	}
+	if st.feeCurrency != nil {
+		// Pay for the calls charging and refunding the gas in the fee currency
+		gasForAlternativeCurrency := blockchain_parameters.GetIntrinsicGasForAlternativeFeeCurrencyOrDefault(st.vmRunner)
+		if st.gas-gas < gasForAlternativeCurrency {
+			return nil, fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, st.gas, gas+gasForAlternativeCurrency)
+		}
+		gas += gasForAlternativeCurrency
+	}
 	st.gas -= gas
 
 	// Check clause 6
@@ -334,10 +418,15 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}
 	effectiveTip := st.gasPrice
 	if london {
-		effectiveTip = cmath.BigMin(st.gasTipCap, new(big.Int).Sub(st.gasFeeCap, st.evm.Context.BaseFee))
+		effectiveTip = cmath.BigMin(st.gasTipCap, new(big.Int).Sub(st.gasFeeCap, st.baseFee))
+	}
+	tipTxFee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip)
+	if st.feeCurrency == nil {
+		// burn all tips
+		st.state.AddBalance(st.evm.Context.Coinbase, tipTxFee)
+	} else if err := st.creditGasFees(tipTxFee); err != nil {
+		return nil, err
 	}
-	// burn all tips
-	st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -354,15 +443,35 @@ func (st *StateTransition) refundGas(refundQuotient uint64) {
 	}
 	st.gas += refund
 
-	// Return ETH for remaining gas, exchanged at the original rate.
-	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(st.msg.From(), remaining)
+	// Return ETH for remaining gas, exchanged at the original rate. The fee
+	// currency refunds it in creditGasFees.
+	if st.feeCurrency == nil {
+		remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
+		st.state.AddBalance(st.msg.From(), remaining)
+	}
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
 	st.gp.AddGas(st.gas)
 }
 
+// creditGasFees refunds the remaining gas in the fee currency and pays the tip
+// to the coinbase. If the currency contract fails to credit the fees, the whole
+// transaction is reverted to the state before its gas was debited and the gas
+// goes back to the block, the transaction is invalid then.
+func (st *StateTransition) creditGasFees(tipTxFee *big.Int) error {
+	refund := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
+	baseTxFee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+	baseTxFee.Sub(baseTxFee, tipTxFee)
+	if err := currency.CreditFees(st.vmRunner, *st.feeCurrency, st.msg.From(), st.evm.Context.Coinbase, refund, tipTxFee, baseTxFee); err != nil {
+		st.state.RevertToSnapshot(st.feeSnapshot)
+		st.gp.AddGas(st.gasUsed())
+		return fmt.Errorf("%w: address %v, currency %v: %v", core.ErrFeeCurrencyCredit,
+			st.msg.From().Hex(), st.feeCurrency.Hex(), err)
+	}
+	return nil
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
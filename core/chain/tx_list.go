@@ -256,15 +256,18 @@ type txList struct {
 
 	costcap *big.Int // Price of the highest costing transaction (reset only if exceeds balance)
 	gascap  uint64   // Gas limit of the highest spending transaction (reset only if exceeds block limit)
+
+	ctx *atomic.Value // Current block context (holds a txPoolContext)
 }
 
 // newTxList create a new transaction list for maintaining nonce-indexable fast,
 // gapped, sortable transaction lists.
-func newTxList(strict bool) *txList {
+func newTxList(strict bool, ctx *atomic.Value) *txList {
 	return &txList{
 		strict:  strict,
 		txs:     newTxSortedMap(),
 		costcap: new(big.Int),
+		ctx:     ctx,
 	}
 }
 
@@ -283,13 +286,21 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
 	if old != nil {
-		if old.GasFeeCapCmp(tx) >= 0 || old.GasTipCapCmp(tx) >= 0 {
+		oldFeeCap, oldTip := old.GasFeeCap(), old.GasTipCap()
+		feeCap, tip := tx.GasFeeCap(), tx.GasTipCap()
+		// Compare the fees in MAP if the replacement pays in another currency
+		if !sameFeeCurrency(old, tx) {
+			ctx := l.ctx.Load().(txPoolContext)
+			oldFeeCap, oldTip = ctx.ToBase(oldFeeCap, old.FeeCurrency()), ctx.ToBase(oldTip, old.FeeCurrency())
+			feeCap, tip = ctx.ToBase(feeCap, tx.FeeCurrency()), ctx.ToBase(tip, tx.FeeCurrency())
+		}
+		if oldFeeCap.Cmp(feeCap) >= 0 || oldTip.Cmp(tip) >= 0 {
 			return false, nil
 		}
 		// thresholdFeeCap = oldFC  * (100 + priceBump) / 100
 		a := big.NewInt(100 + int64(priceBump))
-		aFeeCap := new(big.Int).Mul(a, old.GasFeeCap())
-		aTip := a.Mul(a, old.GasTipCap())
+		aFeeCap := new(big.Int).Mul(a, oldFeeCap)
+		aTip := a.Mul(a, oldTip)
 
 		// thresholdTip    = oldTip * (100 + priceBump) / 100
 		b := big.NewInt(100)
@@ -299,7 +310,7 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 		// Have to ensure that either the new fee cap or tip is higher than the
 		// old ones as well as checking the percentage threshold to ensure that
 		// this is accurate for low (Wei-level) gas price replacements
-		if tx.GasFeeCapIntCmp(thresholdFeeCap) < 0 || tx.GasTipCapIntCmp(thresholdTip) < 0 {
+		if feeCap.Cmp(thresholdFeeCap) < 0 || tip.Cmp(thresholdTip) < 0 {
 			return false, nil
 		}
 	}
@@ -422,7 +433,8 @@ func (l *txList) LastElement() *types.Transaction {
 // then the heap is sorted based on the effective tip based on the given base fee.
 // If baseFee is nil then the sorting is based on gasFeeCap.
 type priceHeap struct {
-	baseFee *big.Int // heap should always be re-sorted after baseFee is changed
+	baseFee *big.Int      // heap should always be re-sorted after baseFee is changed
+	ctx     *atomic.Value // Current block context (holds a txPoolContext)
 	list    []*types.Transaction
 }
 
@@ -441,9 +453,12 @@ func (h *priceHeap) Less(i, j int) bool {
 }
 
 func (h *priceHeap) cmp(a, b *types.Transaction) int {
+	if !sameFeeCurrency(a, b) {
+		return h.cmpInBase(a, b)
+	}
 	if h.baseFee != nil {
 		// Compare effective tips if baseFee is specified
-		if c := a.EffectiveGasTipCmp(b, h.baseFee); c != 0 {
+		if c := h.effectiveGasTip(a).Cmp(h.effectiveGasTip(b)); c != 0 {
 			return c
 		}
 	}
@@ -455,6 +470,35 @@ func (h *priceHeap) cmp(a, b *types.Transaction) int {
 	return a.GasTipCapCmp(b)
 }
 
+// cmpInBase is cmp for transactions paying for gas in different currencies,
+// their fees are compared in MAP.
+func (h *priceHeap) cmpInBase(a, b *types.Transaction) int {
+	ctx := h.ctx.Load().(txPoolContext)
+	if h.baseFee != nil {
+		if c := ctx.CmpValues(h.effectiveGasTip(a), a.FeeCurrency(), h.effectiveGasTip(b), b.FeeCurrency()); c != 0 {
+			return c
+		}
+	}
+	if c := ctx.CmpValues(a.GasFeeCap(), a.FeeCurrency(), b.GasFeeCap(), b.FeeCurrency()); c != 0 {
+		return c
+	}
+	return ctx.CmpValues(a.GasTipCap(), a.FeeCurrency(), b.GasTipCap(), b.FeeCurrency())
+}
+
+// effectiveGasTip returns the effective tip of tx in its fee currency, the
+// base fee is converted to it at the current exchange rate.
+func (h *priceHeap) effectiveGasTip(tx *types.Transaction) *big.Int {
+	baseFee := h.baseFee
+	if feeCurrency := tx.FeeCurrency(); feeCurrency != nil {
+		rate, err := h.ctx.Load().(txPoolContext).GetExchangeRate(feeCurrency)
+		if err != nil {
+			return new(big.Int)
+		}
+		baseFee = rate.FromBase(baseFee)
+	}
+	return tx.EffectiveGasTipValue(baseFee)
+}
+
 func (h *priceHeap) Push(x interface{}) {
 	tx := x.(*types.Transaction)
 	h.list = append(h.list, tx)
@@ -499,9 +543,11 @@ const (
 )
 
 // newTxPricedList creates a new price-sorted transaction heap.
-func newTxPricedList(all *txLookup) *txPricedList {
+func newTxPricedList(all *txLookup, ctx *atomic.Value) *txPricedList {
 	return &txPricedList{
-		all: all,
+		all:      all,
+		urgent:   priceHeap{ctx: ctx},
+		floating: priceHeap{ctx: ctx},
 	}
 }
 
@@ -634,3 +680,9 @@ func (l *txPricedList) SetBaseFee(baseFee *big.Int) {
 	l.urgent.baseFee = baseFee
 	l.Reheap()
 }
+
+// sameFeeCurrency tells whether a and b pay for gas in the same currency.
+func sameFeeCurrency(a, b *types.Transaction) bool {
+	ca, cb := a.FeeCurrency(), b.FeeCurrency()
+	return (ca == nil && cb == nil) || (ca != nil && cb != nil && *ca == *cb)
+}
@@ -19,10 +19,15 @@ package chain
 import (
 	"math/big"
 	"math/rand"
+	"sync/atomic"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mapprotocol/atlas/contracts/currency"
+	"github.com/mapprotocol/atlas/contracts/testutil"
 	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
 )
 
 // Tests that transactions can be added to strict lists and list contents and
@@ -36,7 +41,7 @@ func TestStrictTxListAdd(t *testing.T) {
 		txs[i] = transaction(uint64(i), 0, key)
 	}
 	// Insert the transactions in a random order
-	list := newTxList(true)
+	list := newTxList(true, new(atomic.Value))
 	for _, v := range rand.Perm(len(txs)) {
 		list.Add(txs[v], DefaultTxPoolConfig.PriceBump)
 	}
@@ -51,6 +56,48 @@ func TestStrictTxListAdd(t *testing.T) {
 	}
 }
 
+// Tests that a transaction paying for gas in a fee currency only replaces one
+// paying in MAP if its fees are bumped enough once converted to MAP.
+func TestTxListFeeCurrencyReplacement(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	feeCurrency := common.HexToAddress("0x0fc")
+
+	// 2 units of the fee currency are worth 1 MAP
+	vmRunner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", func(token common.Address) (*big.Int, *big.Int) {
+		return big.NewInt(2), big.NewInt(1)
+	})
+	ctx := new(atomic.Value)
+	ctx.Store(txPoolContext{Manager: currency.NewManager(vmRunner)})
+
+	feeCurrencyTx := func(gasFee, tip int64) *types.Transaction {
+		tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(params.TestChainConfig.ChainID), &types.FeeCurrencyTx{
+			ChainID:     params.TestChainConfig.ChainID,
+			GasTipCap:   big.NewInt(tip),
+			GasFeeCap:   big.NewInt(gasFee),
+			Gas:         21000,
+			FeeCurrency: &feeCurrency,
+			To:          &common.Address{},
+			Value:       big.NewInt(100),
+		})
+		return tx
+	}
+	list := newTxList(true, ctx)
+	if inserted, _ := list.Add(dynamicFeeTx(0, 21000, big.NewInt(100), big.NewInt(10), key), DefaultTxPoolConfig.PriceBump); !inserted {
+		t.Fatalf("failed to insert original transaction")
+	}
+	// Higher fees in the fee currency, but worth less in MAP
+	if inserted, _ := list.Add(feeCurrencyTx(150, 15), DefaultTxPoolConfig.PriceBump); inserted {
+		t.Errorf("replacement worth less in MAP was inserted")
+	}
+	if inserted, old := list.Add(feeCurrencyTx(220, 22), DefaultTxPoolConfig.PriceBump); !inserted || old == nil {
+		t.Errorf("replacement bumped in MAP was not inserted")
+	}
+	// Fee currency transactions only cost their value in MAP
+	if drops, _ := list.Filter(big.NewInt(100), 21000); len(drops) != 0 {
+		t.Errorf("fee currency transaction filtered by MAP balance: %v", drops)
+	}
+}
+
 func BenchmarkTxListAdd(t *testing.B) {
 	// Generate a list of transactions to insert
 	key, _ := crypto.GenerateKey()
@@ -60,7 +107,7 @@ func BenchmarkTxListAdd(t *testing.B) {
 		txs[i] = transaction(uint64(i), 0, key)
 	}
 	// Insert the transactions in a random order
-	list := newTxList(true)
+	list := newTxList(true, new(atomic.Value))
 	priceLimit := big.NewInt(int64(DefaultTxPoolConfig.PriceLimit))
 	t.ResetTimer()
 	for _, v := range rand.Perm(len(txs)) {
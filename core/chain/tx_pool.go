@@ -33,6 +33,7 @@ import (
 
 	"github.com/mapprotocol/atlas/consensus/misc"
 	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
@@ -247,9 +248,10 @@ type TxPool struct {
 	signer      types.Signer
 	mu          sync.RWMutex
 
-	istanbul bool // Fork indicator whether we are in the istanbul stage.
-	eip2718  bool // Fork indicator whether we are using EIP-2718 type transactions.
-	eip1559  bool // Fork indicator whether we are using EIP-1559 type transactions.
+	istanbul    bool // Fork indicator whether we are in the istanbul stage.
+	eip2718     bool // Fork indicator whether we are using EIP-2718 type transactions.
+	eip1559     bool // Fork indicator whether we are using EIP-1559 type transactions.
+	feeCurrency bool // Fork indicator whether we are accepting fee currency transactions.
 
 	currentState *state.StateDB // Current state in the blockchain head
 	// todo ibft compare
@@ -315,8 +317,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pool.locals.add(addr)
 	}
 
-	//pool.priced = newTxPricedList(pool.all, &pool.currentCtx)
-	pool.priced = newTxPricedList(pool.all)
+	pool.priced = newTxPricedList(pool.all, &pool.currentCtx)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -605,6 +606,10 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
 		return core.ErrTxTypeNotSupported
 	}
+	// Reject fee currency transactions until the fee currency fork activates.
+	if !pool.feeCurrency && tx.Type() == types.FeeCurrencyTxType {
+		return core.ErrTxTypeNotSupported
+	}
 	// Reject transactions over defined size to prevent DOS attacks
 	if uint64(tx.Size()) > txMaxSize {
 		return ErrOversizedData
@@ -639,7 +644,20 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	//if !local && tx.Type() == types.LegacyTxType && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 	//	return ErrUnderpriced
 	//}
-	if tx.GasFeeCapIntCmp(pool.gasPrice) < 0 {
+	if feeCurrency := tx.FeeCurrency(); feeCurrency != nil {
+		if !currency.IsWhitelisted(pool.currentVMRunner, *feeCurrency) {
+			return core.ErrNonWhitelistedFeeCurrency
+		}
+		// The minimal gas price is in MAP, compare the fee cap at the current rate
+		rate, err := pool.ctx().GetExchangeRate(feeCurrency)
+		if err != nil {
+			log.Debug("validateTx error in getting fee currency exchange rate", "feeCurrency", feeCurrency, "error", err)
+			return core.ErrFeeCurrencyRate
+		}
+		if rate.ToBase(tx.GasFeeCap()).Cmp(pool.gasPrice) < 0 {
+			return ErrUnderpriced
+		}
+	} else if tx.GasFeeCapIntCmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -648,7 +666,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	}
 
 	// Transactor should have enough funds to cover the costs
-	err = ValidateTransactorBalanceCoversTx(tx, from, pool.currentState, pool.currentVMRunner, false)
+	err = ValidateTransactorBalanceCoversTx(tx, from, pool.currentState, pool.currentVMRunner)
 	if err != nil {
 		return err
 	}
@@ -663,6 +681,9 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err != nil {
 		return err
 	}
+	if tx.FeeCurrency() != nil {
+		intrGas += pool.ctx().gasForAlternativeCurrency
+	}
 	if tx.Gas() < intrGas {
 		return core.ErrIntrinsicGas
 	}
@@ -670,7 +691,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 }
 
 // ValidateTransactorBalanceCoversTx validates transactor has enough funds to cover transaction cost: V + GP * GL.
-func ValidateTransactorBalanceCoversTx(tx *types.Transaction, from common.Address, currentState *state.StateDB, currentVMRunner vm.EVMRunner, eHardfork bool) error {
+func ValidateTransactorBalanceCoversTx(tx *types.Transaction, from common.Address, currentState *state.StateDB, currentVMRunner vm.EVMRunner) error {
 	if tx.FeeCurrency() == nil && currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
 		log.Debug("Insufficient funds",
 			"from", from, "Transaction cost", tx.Cost(), "to", tx.To(),
@@ -678,23 +699,19 @@ func ValidateTransactorBalanceCoversTx(tx *types.Transaction, from common.Addres
 			"value", tx.Value(), "fee currency", tx.FeeCurrency(), "balance", currentState.GetBalance(from))
 		return errors.New("insufficient funds for gas * price + value + gatewayFee")
 	} else if tx.FeeCurrency() != nil {
-		//feeCurrencyBalance, err := currency.GetBalanceOf(currentVMRunner, from, *tx.FeeCurrency())
-		//
-		//if err != nil {
-		//	log.Debug("validateTx error in getting fee currency balance", "feeCurrency", tx.FeeCurrency(), "error", err)
-		//	return err
-		//}
+		feeCurrencyBalance, err := currency.GetBalanceOf(currentVMRunner, from, *tx.FeeCurrency())
 
-		// This is required to match the logic in canPayFee() state_transition.go
-		//   - Prior to E hardfork: we require the balance to be strictly greater than the fee,
-		//     which means we reject the transaction if balance <= fee
-		//   - After E hardfork: we require the balance to be greater than or equal to the fee,
-		//     which means we reject the transaction if balance < fee
-		//fee := tx.Fee()
-		//if (eHardfork && feeCurrencyBalance.Cmp(fee) < 0) || (!eHardfork && feeCurrencyBalance.Cmp(fee) <= 0) {
-		//	log.Debug("validateTx insufficient fee currency", "feeCurrency", tx.FeeCurrency(), "feeCurrencyBalance", feeCurrencyBalance)
-		//	return errors.New("insufficient funds for gas * price + value + gatewayFee")
-		//}
+		if err != nil {
+			log.Debug("validateTx error in getting fee currency balance", "feeCurrency", tx.FeeCurrency(), "error", err)
+			return err
+		}
+
+		// This is required to match the logic in buyGasInFeeCurrency() state_transition.go,
+		// the balance must be greater than or equal to the fee
+		if feeCurrencyBalance.Cmp(tx.Fee()) < 0 {
+			log.Debug("validateTx insufficient fee currency", "feeCurrency", tx.FeeCurrency(), "feeCurrencyBalance", feeCurrencyBalance)
+			return errors.New("insufficient funds for gas * price + value + gatewayFee")
+		}
 
 		if currentState.GetBalance(from).Cmp(tx.Value()) < 0 {
 			log.Debug("validateTx insufficient funds", "balance", currentState.GetBalance(from).String())
@@ -829,8 +846,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	if pool.queue[from] == nil {
 		// todo ibft compare
 		// todo ibft cancel
-		//pool.queue[from] = newTxList(false, &pool.currentCtx)
-		pool.queue[from] = newTxList(false)
+		pool.queue[from] = newTxList(false, &pool.currentCtx)
 	}
 	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
 	if !inserted {
@@ -884,8 +900,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	if pool.pending[addr] == nil {
 		// todo ibft compare
 		// todo ibft cancel
-		//pool.pending[addr] = newTxList(true, &pool.currentCtx)
-		pool.pending[addr] = newTxList(true)
+		pool.pending[addr] = newTxList(true, &pool.currentCtx)
 	}
 	list := pool.pending[addr]
 
@@ -1369,7 +1384,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// atomic store of the new txPoolContext
 	newCtx := txPoolContext{
 		NewBlockContext(pool.currentVMRunner),
-		//currency.NewManager(pool.currentVMRunner),
+		currency.NewManager(pool.currentVMRunner),
 	}
 	pool.currentCtx.Store(newCtx)
 
@@ -1383,6 +1398,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
 	pool.eip2718 = pool.chainconfig.IsBerlin(next)
 	pool.eip1559 = pool.chainconfig.IsLondon(next)
+	pool.feeCurrency = pool.chainconfig.IsFeeCurrency(next)
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1951,6 +1967,7 @@ func NewBlockContext(vmRunner vm.EVMRunner) BlockContext {
 
 type txPoolContext struct {
 	BlockContext
+	*currency.Manager
 }
 
 func (pool *TxPool) ctx() *txPoolContext {
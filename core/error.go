@@ -99,6 +99,18 @@ var (
 
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
+
+	// ErrNonWhitelistedFeeCurrency is returned if a transaction pays for gas in
+	// a currency that is not whitelisted.
+	ErrNonWhitelistedFeeCurrency = errors.New("fee currency not whitelisted")
+
+	// ErrFeeCurrencyRate is returned if the exchange rate of the fee currency
+	// of a transaction is not available.
+	ErrFeeCurrencyRate = errors.New("fee currency exchange rate not available")
+
+	// ErrFeeCurrencyCredit is returned if the fee currency of a transaction
+	// fails to refund the remaining gas and pay the fees after its execution.
+	ErrFeeCurrencyCredit = errors.New("fee currency fees not credited")
 )
 
 var (
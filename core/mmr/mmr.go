@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"math/bits"
 	"sort"
 	"strings"
 	"sync"
@@ -1037,6 +1038,164 @@ func VerifyRequiredBlocks2(info *ProofInfo) ([]*ProofBlock, error) {
 	return proof_blocks, nil
 }
 
+// VerifyLeaf checks that p, as produced by GenerateProof2, proves hash to be
+// the leaf at index leaf of the mmr committed to by root.
+func (p *ProofInfo) VerifyLeaf(root common.Hash, leaf uint64, hash common.Hash) error {
+	if p.RootHash != root {
+		return fmt.Errorf("mmr root mismatch: have %s, want %s", p.RootHash.Hex(), root.Hex())
+	}
+	if leaf >= p.LeafNumber {
+		return fmt.Errorf("leaf %d out of range, mmr has %d leaves", leaf, p.LeafNumber)
+	}
+	if len(p.Checked) != 1 || p.Checked[0] != leaf {
+		return fmt.Errorf("proof does not check leaf %d", leaf)
+	}
+	if len(p.Elems) == 0 {
+		return errors.New("empty mmr proof")
+	}
+	for _, elem := range p.Elems {
+		if elem == nil || elem.Res == nil {
+			return errors.New("malformed mmr proof element")
+		}
+	}
+	last := p.Elems[len(p.Elems)-1]
+	if last.Cat != 0 || last.Res.H != root || last.LeafNum != p.LeafNumber {
+		return errors.New("invalid mmr proof root element")
+	}
+	computed, rest, err := foldLeafProof(p.Elems[:len(p.Elems)-1], p.LeafNumber, leaf, hash)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("unexpected trailing mmr proof elements")
+	}
+	if computed != root {
+		return errors.New("invalid mmr proof")
+	}
+	return nil
+}
+
+// foldLeafProof walks the proof elements of a single leaf in the order
+// generateProofRecursive emits them and returns the hash of the subtree of
+// leafs leaves together with the unconsumed elements.
+func foldLeafProof(elems []*ProofElem, leafs, leaf uint64, hash common.Hash) (common.Hash, []*ProofElem, error) {
+	if len(elems) == 0 {
+		return common.Hash{}, nil, errors.New("truncated mmr proof")
+	}
+	if leafs == 1 {
+		if elems[0].Cat != 2 || elems[0].Res.H != hash {
+			return common.Hash{}, nil, errors.New("leaf hash mismatch")
+		}
+		return hash, elems[1:], nil
+	}
+	left := getLeftLeafNumber(leafs)
+	if leaf < left {
+		h, rest, err := foldLeafProof(elems, left, leaf, hash)
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+		if len(rest) == 0 || rest[0].Cat != 1 || !rest[0].Right {
+			return common.Hash{}, nil, errors.New("missing right sibling in mmr proof")
+		}
+		return merge2(h, rest[0].Res.H), rest[1:], nil
+	}
+	if elems[0].Cat != 1 || elems[0].Right {
+		return common.Hash{}, nil, errors.New("missing left sibling in mmr proof")
+	}
+	h, rest, err := foldLeafProof(elems[1:], leafs-left, leaf-left, hash)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	return merge2(elems[0].Res.H, h), rest, nil
+}
+
+// NodeReader returns the hash of the perfect subtree of the given height that
+// holds the leaves from index<<height on.
+type NodeReader func(height uint, index uint64) (common.Hash, error)
+
+// GenerateLeafProof builds the proof GenerateProof2 returns for leaf in an mmr
+// of leafs leaves, reading only the nodes next to the path of the leaf.
+func GenerateLeafProof(node NodeReader, leafs, leaf uint64) (*ProofInfo, error) {
+	if leaf >= leafs {
+		return nil, fmt.Errorf("leaf %d out of range, mmr has %d leaves", leaf, leafs)
+	}
+	elems, root, err := leafProofRecursive(node, 0, leafs, leaf, nil)
+	if err != nil {
+		return nil, err
+	}
+	elems = append(elems, &ProofElem{
+		Cat:     0,
+		LeafNum: leafs,
+		Res:     &ProofRes{H: root, TD: new(big.Int)},
+	})
+	return &ProofInfo{
+		RootHash:       root,
+		RootDifficulty: new(big.Int),
+		LeafNumber:     leafs,
+		Elems:          elems,
+		Checked:        []uint64{leaf},
+	}, nil
+}
+
+// leafProofRecursive appends the proof elements of leaf in the subtree of leafs
+// leaves starting at offset in the order generateProofRecursive emits them and
+// returns the root of the subtree.
+func leafProofRecursive(node NodeReader, offset, leafs, leaf uint64, elems []*ProofElem) ([]*ProofElem, common.Hash, error) {
+	if leafs == 1 {
+		h, err := node(0, offset)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		return append(elems, &ProofElem{Cat: 2, Res: &ProofRes{H: h, TD: new(big.Int)}}), h, nil
+	}
+	left := getLeftLeafNumber(leafs)
+	if leaf < offset+left {
+		elems, l, err := leafProofRecursive(node, offset, left, leaf, elems)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		r, err := SubtreeRoot(node, offset+left, leafs-left)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		elems = append(elems, &ProofElem{Cat: 1, Right: true, Res: &ProofRes{H: r, TD: new(big.Int)}})
+		return elems, merge2(l, r), nil
+	}
+	l, err := SubtreeRoot(node, offset, left)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	elems = append(elems, &ProofElem{Cat: 1, Res: &ProofRes{H: l, TD: new(big.Int)}})
+	elems, r, err := leafProofRecursive(node, offset+left, leafs-left, leaf, elems)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return elems, merge2(l, r), nil
+}
+
+// SubtreeRoot returns the root of the leafs leaves starting at offset, which
+// must be the first leaf of a subtree of the mmr.
+func SubtreeRoot(node NodeReader, offset, leafs uint64) (common.Hash, error) {
+	if IsPowerOfTwo(leafs) {
+		return node(uint(bits.TrailingZeros64(leafs)), offset/leafs)
+	}
+	left := getLeftLeafNumber(leafs)
+	l, err := SubtreeRoot(node, offset, left)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	r, err := SubtreeRoot(node, offset+left, leafs-left)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return merge2(l, r), nil
+}
+
+// MergeHash returns the hash of the parent of the nodes left and right.
+func MergeHash(left, right common.Hash) common.Hash {
+	return merge2(left, right)
+}
+
 ///////////////////////////////////////////////////////////////////////////////////////
 
 func (m *Mmr) GenerateProof(proofHeight, EndHeight uint64) *ProofInfo {
@@ -270,3 +270,74 @@ func TestO7(t *testing.T) {
 func Test08(t *testing.T) {
 
 }
+
+func TestVerifyLeaf(t *testing.T) {
+	for count := 1; count <= 70; count++ {
+		mmr := NewMMR()
+		hashes := make([]common.Hash, count)
+		for i := range hashes {
+			hashes[i] = RlpHash(uint64(i))
+			mmr.Push(NewNode(hashes[i], big.NewInt(0), big.NewInt(0), big.NewInt(0), 0))
+		}
+		root := mmr.GetRoot2()
+		for i := range hashes {
+			data, err := ProofInfoToBytes(mmr.GenerateProof2(uint64(i), 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, err := ProofInfoFromBytes(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := proof.VerifyLeaf(root, uint64(i), hashes[i]); err != nil {
+				t.Fatalf("count %d leaf %d: %v", count, i, err)
+			}
+			if err := proof.VerifyLeaf(root, uint64(i), common.Hash{1}); err == nil {
+				t.Fatalf("count %d leaf %d: wrong hash accepted", count, i)
+			}
+			if i > 0 {
+				proof.Checked = []uint64{uint64(i - 1)}
+				if err := proof.VerifyLeaf(root, uint64(i-1), hashes[i]); err == nil {
+					t.Fatalf("count %d leaf %d: wrong index accepted", count, i)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateLeafProof(t *testing.T) {
+	for count := 1; count <= 70; count++ {
+		mmr := NewMMR()
+		nodes := make(map[[2]uint64]common.Hash)
+		for i := 0; i < count; i++ {
+			hash := RlpHash(uint64(i))
+			mmr.Push(NewNode(hash, big.NewInt(0), big.NewInt(0), big.NewInt(0), 0))
+			nodes[[2]uint64{0, uint64(i)}] = hash
+			for height, index := uint64(0), uint64(i); index&1 == 1; height, index = height+1, index>>1 {
+				parent := merge2(nodes[[2]uint64{height, index - 1}], nodes[[2]uint64{height, index}])
+				nodes[[2]uint64{height + 1, index >> 1}] = parent
+			}
+		}
+		reader := func(height uint, index uint64) (common.Hash, error) {
+			hash, ok := nodes[[2]uint64{uint64(height), index}]
+			if !ok {
+				return common.Hash{}, fmt.Errorf("missing node %d/%d", height, index)
+			}
+			return hash, nil
+		}
+		if root, err := SubtreeRoot(reader, 0, uint64(count)); err != nil || root != mmr.GetRoot2() {
+			t.Fatalf("count %d: root = %x, %v, want %x", count, root, err, mmr.GetRoot2())
+		}
+		for i := 0; i < count; i++ {
+			proof, err := GenerateLeafProof(reader, uint64(count), uint64(i))
+			if err != nil {
+				t.Fatalf("count %d leaf %d: %v", count, i, err)
+			}
+			have, _ := ProofInfoToBytes(proof)
+			want, _ := ProofInfoToBytes(mmr.GenerateProof2(uint64(i), 0))
+			if !bytes.Equal(have, want) {
+				t.Fatalf("count %d leaf %d: proof mismatch", count, i)
+			}
+		}
+	}
+}
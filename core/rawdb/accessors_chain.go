@@ -1391,6 +1391,151 @@ func WriteAccumulatedEpochUptime(db ethdb.KeyValueWriter, epoch uint64, uptime *
 	}
 }
 
+// istanbulEvidenceKey = istanbulEvidencePrefix + evidence hash
+func istanbulEvidenceKey(hash common.Hash) []byte {
+	return append(istanbulEvidencePrefix, hash.Bytes()...)
+}
+
+// ReadAllIstanbulEvidence retrieves all stored double signing evidence.
+func ReadAllIstanbulEvidence(db ethdb.Iteratee) []types.IstanbulEvidence {
+	it := db.NewIterator(istanbulEvidencePrefix, nil)
+	defer it.Release()
+
+	var evidence []types.IstanbulEvidence
+	for it.Next() {
+		if len(it.Key()) != len(istanbulEvidencePrefix)+common.HashLength {
+			continue
+		}
+		var e types.IstanbulEvidence
+		if err := rlp.DecodeBytes(it.Value(), &e); err != nil {
+			log.Error("Invalid istanbul evidence RLP", "err", err)
+			continue
+		}
+		evidence = append(evidence, e)
+	}
+	return evidence
+}
+
+// HasIstanbulEvidence checks if the double signing evidence with the given hash is stored.
+func HasIstanbulEvidence(db ethdb.KeyValueReader, hash common.Hash) bool {
+	ok, _ := db.Has(istanbulEvidenceKey(hash))
+	return ok
+}
+
+// WriteIstanbulEvidence stores double signing evidence under its hash.
+func WriteIstanbulEvidence(db ethdb.KeyValueWriter, evidence *types.IstanbulEvidence) {
+	data, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Crit("Failed to RLP encode istanbul evidence", "err", err)
+	}
+	if err := db.Put(istanbulEvidenceKey(evidence.Hash()), data); err != nil {
+		log.Crit("Failed to store istanbul evidence", "err", err)
+	}
+}
+
+// DeleteIstanbulEvidence removes the double signing evidence with the given hash.
+func DeleteIstanbulEvidence(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(istanbulEvidenceKey(hash)); err != nil {
+		log.Crit("Failed to delete istanbul evidence", "err", err)
+	}
+}
+
+// epochRewardsKey = epochRewardsPrefix + num (uint64 big endian) + hash
+func epochRewardsKey(number uint64, hash common.Hash) []byte {
+	return append(append(epochRewardsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// ReadEpochRewards retrieves the rewards distributed by the last block of an
+// epoch with the given hash.
+func ReadEpochRewards(db ethdb.KeyValueReader, hash common.Hash, number uint64) *types.EpochRewards {
+	data, _ := db.Get(epochRewardsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	rewards := new(types.EpochRewards)
+	if err := rlp.DecodeBytes(data, rewards); err != nil {
+		log.Error("Invalid epoch rewards RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return rewards
+}
+
+// ReadAllEpochRewards retrieves the rewards of every epoch recorded for a
+// canonical block, in epoch order.
+func ReadAllEpochRewards(db ethdb.Database) []*types.EpochRewards {
+	it := db.NewIterator(epochRewardsPrefix, nil)
+	defer it.Release()
+
+	var all []*types.EpochRewards
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(epochRewardsPrefix)+8+common.HashLength {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(epochRewardsPrefix) : len(epochRewardsPrefix)+8])
+		if ReadCanonicalHash(db, number) != common.BytesToHash(key[len(epochRewardsPrefix)+8:]) {
+			continue
+		}
+		rewards := new(types.EpochRewards)
+		if err := rlp.DecodeBytes(it.Value(), rewards); err != nil {
+			log.Error("Invalid epoch rewards RLP", "number", number, "err", err)
+			continue
+		}
+		all = append(all, rewards)
+	}
+	return all
+}
+
+// WriteEpochRewards stores the rewards distributed by the last block of an
+// epoch with the given hash.
+func WriteEpochRewards(db ethdb.KeyValueWriter, hash common.Hash, rewards *types.EpochRewards) {
+	data, err := rlp.EncodeToBytes(rewards)
+	if err != nil {
+		log.Crit("Failed to RLP encode epoch rewards", "err", err)
+	}
+	if err := db.Put(epochRewardsKey(rewards.Number, hash), data); err != nil {
+		log.Crit("Failed to store epoch rewards", "err", err)
+	}
+}
+
+// headerMMRNodeKey = headerMMRNodePrefix + height (byte) + index (uint64 big endian)
+func headerMMRNodeKey(height uint, index uint64) []byte {
+	return append(append(headerMMRNodePrefix, byte(height)), encodeBlockNumber(index)...)
+}
+
+// ReadHeaderMMRLeaves retrieves the number of leaves in the header mmr.
+func ReadHeaderMMRLeaves(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(headerMMRLeavesKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteHeaderMMRLeaves stores the number of leaves in the header mmr.
+func WriteHeaderMMRLeaves(db ethdb.KeyValueWriter, leaves uint64) {
+	if err := db.Put(headerMMRLeavesKey, encodeBlockNumber(leaves)); err != nil {
+		log.Crit("Failed to store the header mmr leaves", "err", err)
+	}
+}
+
+// ReadHeaderMMRNode retrieves the hash of the perfect subtree of the header mmr
+// with the given height holding the leaves from index<<height on.
+func ReadHeaderMMRNode(db ethdb.KeyValueReader, height uint, index uint64) common.Hash {
+	data, _ := db.Get(headerMMRNodeKey(height, index))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteHeaderMMRNode stores the hash of a perfect subtree of the header mmr.
+func WriteHeaderMMRNode(db ethdb.KeyValueWriter, height uint, index uint64, hash common.Hash) {
+	if err := db.Put(headerMMRNodeKey(height, index), hash.Bytes()); err != nil {
+		log.Crit("Failed to store header mmr node", "err", err)
+	}
+}
+
 // uptimeKey = uptimePrefix + epoch number
 func uptimeKey(epoch uint64) []byte {
 	// abuse encodeBlockNumber for epochs
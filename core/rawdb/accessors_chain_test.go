@@ -875,3 +875,66 @@ func BenchmarkDecodeRLPLogs(b *testing.B) {
 		}
 	})
 }
+
+func TestIstanbulEvidenceStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	first := &types.IstanbulEvidence{First: []byte{0x01}, Second: []byte{0x02}}
+	second := &types.IstanbulEvidence{First: []byte{0x03}, Second: []byte{0x04}}
+	WriteIstanbulEvidence(db, first)
+	WriteIstanbulEvidence(db, second)
+	WriteIstanbulEvidence(db, first)
+
+	if !HasIstanbulEvidence(db, first.Hash()) {
+		t.Fatal("stored evidence not found")
+	}
+	if stored := ReadAllIstanbulEvidence(db); len(stored) != 2 {
+		t.Fatalf("have %d evidence, want 2", len(stored))
+	}
+	DeleteIstanbulEvidence(db, first.Hash())
+	stored := ReadAllIstanbulEvidence(db)
+	if len(stored) != 1 || stored[0].Hash() != second.Hash() {
+		t.Fatalf("unexpected evidence after delete: %v", stored)
+	}
+}
+
+func TestEpochRewardsStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if ReadEpochRewards(db, common.Hash{0x01}, 100) != nil {
+		t.Fatal("non existent epoch rewards returned")
+	}
+	rewards := func(epoch uint64, community int64) *types.EpochRewards {
+		return &types.EpochRewards{
+			Epoch:            epoch,
+			Number:           epoch * 100,
+			CommunityReward:  big.NewInt(community),
+			MaintainerReward: big.NewInt(0),
+			Validators: []types.ValidatorEpochReward{{
+				Account:         common.Address{0x01},
+				Signer:          common.Address{0x02},
+				Uptime:          big.NewInt(1e18),
+				ValidatorReward: big.NewInt(20),
+				VoterReward:     big.NewInt(30),
+			}},
+		}
+	}
+	// a sidechain block of epoch 2 keeps its own record
+	WriteEpochRewards(db, common.Hash{0x02}, rewards(2, 10))
+	WriteEpochRewards(db, common.Hash{0x01}, rewards(1, 10))
+	WriteEpochRewards(db, common.Hash{0x03}, rewards(2, 20))
+
+	if have, want := ReadEpochRewards(db, common.Hash{0x02}, 200), rewards(2, 10); !reflect.DeepEqual(have, want) {
+		t.Fatalf("epoch rewards mismatch: have %+v, want %+v", have, want)
+	}
+	if have, want := ReadEpochRewards(db, common.Hash{0x03}, 200), rewards(2, 20); !reflect.DeepEqual(have, want) {
+		t.Fatalf("epoch rewards mismatch: have %+v, want %+v", have, want)
+	}
+	// only the records of canonical blocks are listed
+	WriteCanonicalHash(db, common.Hash{0x01}, 100)
+	WriteCanonicalHash(db, common.Hash{0x03}, 200)
+	all := ReadAllEpochRewards(db)
+	if len(all) != 2 || all[0].Epoch != 1 || all[1].Epoch != 2 || all[1].CommunityReward.Int64() != 20 {
+		t.Fatalf("unexpected epoch rewards: %v", all)
+	}
+}
@@ -75,6 +75,20 @@ func DeleteCode(db ethdb.KeyValueWriter, hash common.Hash) {
 	}
 }
 
+// HasPOWStorageAccount reports whether the storage trie of the account holds
+// byte arrays written through SetPOWState.
+func HasPOWStorageAccount(db ethdb.KeyValueReader, addr common.Address) bool {
+	ok, _ := db.Has(powStorageAccountKey(addr))
+	return ok
+}
+
+// WritePOWStorageAccount flags the account as written through SetPOWState.
+func WritePOWStorageAccount(db ethdb.KeyValueWriter, addr common.Address) {
+	if err := db.Put(powStorageAccountKey(addr), []byte{1}); err != nil {
+		log.Crit("Failed to store POW storage account", "err", err)
+	}
+}
+
 // ReadTrieNode retrieves the trie node of the provided hash.
 func ReadTrieNode(db ethdb.KeyValueReader, hash common.Hash) []byte {
 	data, _ := db.Get(hash.Bytes())
@@ -75,6 +75,9 @@ var (
 	// badBlockKey tracks the list of bad blocks seen by local
 	badBlockKey = []byte("InvalidBlock")
 
+	// headerMMRLeavesKey tracks the number of leaves in the header mmr.
+	headerMMRLeavesKey = []byte("HeaderMMRLeaves")
+
 	// uncleanShutdownKey tracks the list of local crashes
 	uncleanShutdownKey = []byte("unclean-shutdown") // config prefix for the db
 
@@ -96,6 +99,11 @@ var (
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
+	istanbulEvidencePrefix  = []byte("istanbul-evidence-") // istanbulEvidencePrefix + evidence hash -> double signing evidence
+	epochRewardsPrefix      = []byte("epoch-rewards-")     // epochRewardsPrefix + num (uint64 big endian) + hash -> epoch rewards
+	headerMMRNodePrefix     = []byte("header-mmr-")        // headerMMRNodePrefix + height (byte) + index (uint64 big endian) -> mmr node hash
+	powStorageAccountPrefix = []byte("pow-storage-")       // powStorageAccountPrefix + address -> flag of an account written through SetPOWState
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -220,6 +228,11 @@ func codeKey(hash common.Hash) []byte {
 	return append(CodePrefix, hash.Bytes()...)
 }
 
+// powStorageAccountKey = powStorageAccountPrefix + address
+func powStorageAccountKey(addr common.Address) []byte {
+	return append(powStorageAccountPrefix, addr.Bytes()...)
+}
+
 // IsCodeKey reports whether the given byte slice is the key of contract code,
 // if so return the raw code hash as well.
 func IsCodeKey(key []byte) (bool, []byte) {
@@ -49,14 +49,16 @@ type DumpCollector interface {
 
 // DumpAccount represents an account in the state.
 type DumpAccount struct {
-	Balance   string                 `json:"balance"`
-	Nonce     uint64                 `json:"nonce"`
-	Root      hexutil.Bytes          `json:"root"`
-	CodeHash  hexutil.Bytes          `json:"codeHash"`
-	Code      hexutil.Bytes          `json:"code,omitempty"`
-	Storage   map[common.Hash]string `json:"storage,omitempty"`
-	Address   *common.Address        `json:"address,omitempty"` // Address only present in iterative (line-by-line) mode
-	SecureKey hexutil.Bytes          `json:"key,omitempty"`     // If we don't have address, we can output the key
+	Balance  string                 `json:"balance"`
+	Nonce    uint64                 `json:"nonce"`
+	Root     hexutil.Bytes          `json:"root"`
+	CodeHash hexutil.Bytes          `json:"codeHash"`
+	Code     hexutil.Bytes          `json:"code,omitempty"`
+	Storage  map[common.Hash]string `json:"storage,omitempty"`
+	// POWStorage holds the byte arrays written through SetPOWState
+	POWStorage map[common.Hash]hexutil.Bytes `json:"powStorage,omitempty"`
+	Address    *common.Address               `json:"address,omitempty"` // Address only present in iterative (line-by-line) mode
+	SecureKey  hexutil.Bytes                 `json:"key,omitempty"`     // If we don't have address, we can output the key
 
 }
 
@@ -101,14 +103,15 @@ type iterativeDump struct {
 // OnAccount implements DumpCollector interface
 func (d iterativeDump) OnAccount(addr common.Address, account DumpAccount) {
 	dumpAccount := &DumpAccount{
-		Balance:   account.Balance,
-		Nonce:     account.Nonce,
-		Root:      account.Root,
-		CodeHash:  account.CodeHash,
-		Code:      account.Code,
-		Storage:   account.Storage,
-		SecureKey: account.SecureKey,
-		Address:   nil,
+		Balance:    account.Balance,
+		Nonce:      account.Nonce,
+		Root:       account.Root,
+		CodeHash:   account.CodeHash,
+		Code:       account.Code,
+		Storage:    account.Storage,
+		POWStorage: account.POWStorage,
+		SecureKey:  account.SecureKey,
+		Address:    nil,
 	}
 	if addr != (common.Address{}) {
 		dumpAccount.Address = &addr
@@ -168,14 +171,23 @@ func (s *StateDB) DumpToCollector(c DumpCollector, conf *DumpConfig) (nextKey []
 		}
 		if !conf.SkipStorage {
 			account.Storage = make(map[common.Hash]string)
+			pow := s.IsPOWStorageAccount(addr)
 			storageIt := trie.NewIterator(obj.getTrie(s.db).NodeIterator(nil))
 			for storageIt.Next() {
+				key := common.BytesToHash(s.trie.GetKey(storageIt.Key))
+				if pow {
+					if account.POWStorage == nil {
+						account.POWStorage = make(map[common.Hash]hexutil.Bytes)
+					}
+					account.POWStorage[key] = common.CopyBytes(storageIt.Value)
+					continue
+				}
 				_, content, _, err := rlp.Split(storageIt.Value)
 				if err != nil {
 					log.Error("Failed to decode the value returned by iterator", "error", err)
 					continue
 				}
-				account.Storage[common.BytesToHash(s.trie.GetKey(storageIt.Key))] = common.Bytes2Hex(content)
+				account.Storage[key] = common.Bytes2Hex(content)
 			}
 		}
 		c.OnAccount(addr, account)
@@ -113,6 +113,7 @@ type stateObject struct {
 	// When an object is marked suicided it will be delete from the trie
 	// during the "update" phase of the state transition.
 	dirtyCode bool // true if the code was updated
+	dirtyPOW  bool // true if written through SetPOWState since the last commit
 	suicided  bool
 	deleted   bool
 }
@@ -324,7 +325,7 @@ func (s *stateObject) SetPOWState(db Database, key common.Hash, value []byte) {
 		prevalue: s.GetPOWState(db, key),
 	})
 	s.setStateByteArray(key, value)
-
+	s.dirtyPOW = true
 }
 
 func (s *stateObject) setStateByteArray(key common.Hash, value []byte) {
@@ -543,6 +544,7 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.pendingPOWStorage = s.pendingPOWStorage.Copy()
 	stateObject.suicided = s.suicided
 	stateObject.dirtyCode = s.dirtyCode
+	stateObject.dirtyPOW = s.dirtyPOW
 	stateObject.deleted = s.deleted
 	return stateObject
 }
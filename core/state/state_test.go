@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/mapprotocol/atlas/core/rawdb"
 )
 
@@ -248,3 +249,67 @@ func compareStateObjects(so0, so1 *stateObject, t *testing.T) {
 		}
 	}
 }
+
+func TestPOWStorageDumpAndProof(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	sdb, _ := New(common.Hash{}, NewDatabaseWithConfig(db, nil), nil)
+
+	var (
+		contract = common.BytesToAddress([]byte{0x01})
+		addr     = common.BytesToAddress([]byte{0x02})
+		slot     = common.BytesToHash([]byte("slot"))
+		powKey   = common.BytesToHash([]byte("eth2map-1"))
+		powValue = bytes.Repeat([]byte{0xc8}, 40)
+		// a byte array that is also a valid RLP encoded slot
+		flagKey   = common.BytesToHash([]byte("flag"))
+		flagValue = []byte{0x01}
+	)
+	sdb.SetState(contract, slot, common.BigToHash(big.NewInt(7)))
+	sdb.SetPOWState(addr, powKey, powValue)
+	sdb.SetPOWState(addr, flagKey, flagValue)
+	if !sdb.IsPOWStorageAccount(addr) || sdb.IsPOWStorageAccount(contract) {
+		t.Error("POW storage account not flagged before commit")
+	}
+	root, err := sdb.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdb, _ = New(root, sdb.Database(), nil)
+	if !sdb.IsPOWStorageAccount(addr) || sdb.IsPOWStorageAccount(contract) {
+		t.Error("POW storage account not flagged after commit")
+	}
+
+	dump := sdb.RawDump(nil)
+	if got := dump.Accounts[contract].Storage[slot]; got != "07" {
+		t.Errorf("dumped slot = %q, want %q", got, "07")
+	}
+	if len(dump.Accounts[contract].POWStorage) != 0 {
+		t.Error("slot dumped as POW storage")
+	}
+	account := dump.Accounts[addr]
+	if got := account.POWStorage[powKey]; !bytes.Equal(got, powValue) {
+		t.Errorf("dumped POW storage = %x, want %x", got, powValue)
+	}
+	if got := account.POWStorage[flagKey]; !bytes.Equal(got, flagValue) {
+		t.Errorf("dumped POW storage = %x, want %x", got, flagValue)
+	}
+	if len(account.Storage) != 0 {
+		t.Error("POW storage dumped as a slot")
+	}
+
+	proof, err := sdb.GetStorageProof(addr, powKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofDB := rawdb.NewMemoryDatabase()
+	for _, node := range proof {
+		proofDB.Put(crypto.Keccak256(node), node)
+	}
+	value, err := trie.VerifyProof(sdb.StorageTrie(addr).Hash(), crypto.Keccak256(powKey.Bytes()), proofDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, powValue) {
+		t.Errorf("proven POW storage = %x, want %x", value, powValue)
+	}
+}
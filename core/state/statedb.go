@@ -438,6 +438,15 @@ func (self *StateDB) SetPOWState(addr common.Address, key common.Hash, value []b
 	}
 }
 
+// IsPOWStorageAccount reports whether the storage trie of addr holds byte arrays
+// written through SetPOWState rather than slots.
+func (s *StateDB) IsPOWStorageAccount(addr common.Address) bool {
+	if obj := s.stateObjects[addr]; obj != nil && obj.dirtyPOW {
+		return true
+	}
+	return rawdb.HasPOWStorageAccount(s.db.TrieDB().DiskDB(), addr)
+}
+
 // SetStorage replaces the entire storage for the specified account with given
 // storage. This function should only be used for debugging.
 func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
@@ -956,6 +965,10 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 				rawdb.WriteCode(codeWriter, common.BytesToHash(obj.CodeHash()), obj.code)
 				obj.dirtyCode = false
 			}
+			if obj.dirtyPOW {
+				rawdb.WritePOWStorageAccount(codeWriter, addr)
+				obj.dirtyPOW = false
+			}
 			// Write any storage changes in the state object to its storage trie
 			committed, err := obj.CommitTrie(s.db)
 			if err != nil {
@@ -94,17 +94,18 @@ func (tx *AccessListTx) copy() TxData {
 }
 
 // accessors for innerTx.
-func (tx *AccessListTx) txType() byte           { return AccessListTxType }
-func (tx *AccessListTx) chainID() *big.Int      { return tx.ChainID }
-func (tx *AccessListTx) accessList() AccessList { return tx.AccessList }
-func (tx *AccessListTx) data() []byte           { return tx.Data }
-func (tx *AccessListTx) gas() uint64            { return tx.Gas }
-func (tx *AccessListTx) gasPrice() *big.Int     { return tx.GasPrice }
-func (tx *AccessListTx) gasTipCap() *big.Int    { return tx.GasPrice }
-func (tx *AccessListTx) gasFeeCap() *big.Int    { return tx.GasPrice }
-func (tx *AccessListTx) value() *big.Int        { return tx.Value }
-func (tx *AccessListTx) nonce() uint64          { return tx.Nonce }
-func (tx *AccessListTx) to() *common.Address    { return tx.To }
+func (tx *AccessListTx) txType() byte                 { return AccessListTxType }
+func (tx *AccessListTx) chainID() *big.Int            { return tx.ChainID }
+func (tx *AccessListTx) accessList() AccessList       { return tx.AccessList }
+func (tx *AccessListTx) data() []byte                 { return tx.Data }
+func (tx *AccessListTx) gas() uint64                  { return tx.Gas }
+func (tx *AccessListTx) gasPrice() *big.Int           { return tx.GasPrice }
+func (tx *AccessListTx) gasTipCap() *big.Int          { return tx.GasPrice }
+func (tx *AccessListTx) gasFeeCap() *big.Int          { return tx.GasPrice }
+func (tx *AccessListTx) feeCurrency() *common.Address { return nil }
+func (tx *AccessListTx) value() *big.Int              { return tx.Value }
+func (tx *AccessListTx) nonce() uint64                { return tx.Nonce }
+func (tx *AccessListTx) to() *common.Address          { return tx.To }
 
 func (tx *AccessListTx) rawSignatureValues() (v, r, s *big.Int) {
 	return tx.V, tx.R, tx.S
@@ -77,11 +77,33 @@ type IstanbulExtra struct {
 	AggregatedSeal IstanbulAggregatedSeal
 	// ParentAggregatedSeal contains and aggregated BLS signature for the previous block.
 	ParentAggregatedSeal IstanbulAggregatedSeal
+	// MmrRoot is the root of the header MMR over all blocks before this one, set only
+	// in the last block of an epoch once the MMR is enabled.
+	MmrRoot common.Hash
+	// Evidence holds the double signing evidence included by the proposer, the
+	// offenders are slashed when the block is finalized.
+	Evidence []IstanbulEvidence
+	// AddedValidatorsBLSCurve is the curve of the BLS public keys of the validators
+	// added in the block, zero for the BN256 keys.
+	AddedValidatorsBLSCurve uint8
+}
+
+// IstanbulEvidence is a pair of conflicting consensus messages signed by the
+// same validator for the same view. The messages are kept RLP encoded along
+// with their signatures.
+type IstanbulEvidence struct {
+	First  []byte
+	Second []byte
+}
+
+// Hash returns the keccak256 hash of the RLP encoding of e.
+func (e *IstanbulEvidence) Hash() common.Hash {
+	return rlpHash(e)
 }
 
 // EncodeRLP serializes ist into the Ethereum RLP format.
 func (ist *IstanbulExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		ist.AddedValidators,
 		ist.AddedValidatorsPublicKeys,
 		ist.AddedValidatorsG1PublicKeys,
@@ -89,7 +111,19 @@ func (ist *IstanbulExtra) EncodeRLP(w io.Writer) error {
 		ist.Seal,
 		&ist.AggregatedSeal,
 		&ist.ParentAggregatedSeal,
-	})
+	}
+	// The optional fields are left out when unset so extras without them keep
+	// their encoding.
+	if ist.MmrRoot != (common.Hash{}) || len(ist.Evidence) > 0 || ist.AddedValidatorsBLSCurve != 0 {
+		fields = append(fields, ist.MmrRoot)
+	}
+	if len(ist.Evidence) > 0 || ist.AddedValidatorsBLSCurve != 0 {
+		fields = append(fields, ist.Evidence)
+	}
+	if ist.AddedValidatorsBLSCurve != 0 {
+		fields = append(fields, ist.AddedValidatorsBLSCurve)
+	}
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the istanbul fields from a RLP stream.
@@ -102,11 +136,15 @@ func (ist *IstanbulExtra) DecodeRLP(s *rlp.Stream) error {
 		Seal                        []byte
 		AggregatedSeal              IstanbulAggregatedSeal
 		ParentAggregatedSeal        IstanbulAggregatedSeal
+		MmrRoot                     common.Hash        `rlp:"optional"`
+		Evidence                    []IstanbulEvidence `rlp:"optional"`
+		AddedValidatorsBLSCurve     uint8              `rlp:"optional"`
 	}
 	if err := s.Decode(&istanbulExtra); err != nil {
 		return err
 	}
 	ist.AddedValidators, ist.AddedValidatorsPublicKeys, ist.AddedValidatorsG1PublicKeys, ist.RemovedValidators, ist.Seal, ist.AggregatedSeal, ist.ParentAggregatedSeal = istanbulExtra.AddedValidators, istanbulExtra.AddedValidatorsPublicKeys, istanbulExtra.AddedValidatorsG1PublicKeys, istanbulExtra.RemovedValidators, istanbulExtra.Seal, istanbulExtra.AggregatedSeal, istanbulExtra.ParentAggregatedSeal
+	ist.MmrRoot, ist.Evidence, ist.AddedValidatorsBLSCurve = istanbulExtra.MmrRoot, istanbulExtra.Evidence, istanbulExtra.AddedValidatorsBLSCurve
 	return nil
 }
 
@@ -131,6 +169,27 @@ type IstanbulEpochValidatorSetSeal struct {
 	Signature []byte
 }
 
+// EpochRewards records the rewards distributed at the last block of an epoch.
+type EpochRewards struct {
+	Epoch            uint64   `json:"epoch"`
+	Number           uint64   `json:"number"`
+	CommunityReward  *big.Int `json:"communityReward"`
+	MaintainerReward *big.Int `json:"maintainerReward"`
+
+	Validators []ValidatorEpochReward `json:"validators"`
+}
+
+// ValidatorEpochReward is the reward of a validator account for the epoch it
+// signed blocks in. Ignored validators were no longer registered and are not paid.
+type ValidatorEpochReward struct {
+	Account         common.Address `json:"account"`
+	Signer          common.Address `json:"signer"`
+	Uptime          *big.Int       `json:"uptime"`
+	ValidatorReward *big.Int       `json:"validatorReward"`
+	VoterReward     *big.Int       `json:"voterReward"`
+	Ignored         bool           `json:"ignored"`
+}
+
 // Converts an array of addresses to an array of their hex strings.  Used for printing out an array of addresses
 func ConvertToStringSlice(addresses []common.Address) []string {
 	returnList := make([]string, len(addresses))
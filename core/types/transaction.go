@@ -46,6 +46,10 @@ const (
 	LegacyTxType = iota
 	AccessListTxType
 	DynamicFeeTxType
+
+	// FeeCurrencyTxType is out of the range of the Ethereum transaction types
+	// so that it can't collide with the ones to come.
+	FeeCurrencyTxType = 0x7c
 )
 
 // Transaction is an Ethereum transaction.
@@ -68,7 +72,7 @@ func NewTx(inner TxData) *Transaction {
 
 // TxData is the underlying data of a transaction.
 //
-// This is implemented by DynamicFeeTx, LegacyTx and AccessListTx.
+// This is implemented by DynamicFeeTx, LegacyTx, AccessListTx and FeeCurrencyTx.
 type TxData interface {
 	txType() byte // returns the type ID
 	copy() TxData // creates a deep copy and initializes all fields
@@ -80,6 +84,7 @@ type TxData interface {
 	gasPrice() *big.Int
 	gasTipCap() *big.Int
 	gasFeeCap() *big.Int
+	feeCurrency() *common.Address
 	value() *big.Int
 	nonce() uint64
 	to() *common.Address
@@ -187,6 +192,10 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		var inner DynamicFeeTx
 		err := rlp.DecodeBytes(b[1:], &inner)
 		return &inner, err
+	case FeeCurrencyTxType:
+		var inner FeeCurrencyTx
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -276,6 +285,12 @@ func (tx *Transaction) GasTipCap() *big.Int { return new(big.Int).Set(tx.inner.g
 // GasFeeCap returns the fee cap per gas of the transaction.
 func (tx *Transaction) GasFeeCap() *big.Int { return new(big.Int).Set(tx.inner.gasFeeCap()) }
 
+// FeeCurrency returns the currency the gas of the transaction is paid in,
+// nil means MAP.
+func (tx *Transaction) FeeCurrency() *common.Address {
+	return copyAddressPtr(tx.inner.feeCurrency())
+}
+
 // Value returns the ether amount of the transaction.
 func (tx *Transaction) Value() *big.Int { return new(big.Int).Set(tx.inner.value()) }
 
@@ -288,8 +303,13 @@ func (tx *Transaction) To() *common.Address {
 	return copyAddressPtr(tx.inner.to())
 }
 
-// Cost returns gas * gasPrice + value.
+// Cost returns gas * gasPrice + value, the MAP the transaction can spend. The
+// gas of a fee currency transaction is paid in that currency, so it only costs
+// its value.
 func (tx *Transaction) Cost() *big.Int {
+	if tx.FeeCurrency() != nil {
+		return tx.Value()
+	}
 	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 	total.Add(total, tx.Value())
 	return total
@@ -462,6 +482,11 @@ type TxWithMinerFee struct {
 // miner gasTipCap if a base fee is provided.
 // Returns error in case of a negative effective miner gasTipCap.
 func NewTxWithMinerFee(tx *Transaction, baseFee *big.Int) (*TxWithMinerFee, error) {
+	// The base fee is in MAP, the one of a fee currency transaction is checked
+	// when it is applied
+	if tx.FeeCurrency() != nil {
+		baseFee = nil
+	}
 	minerFee, err := tx.EffectiveGasTip(baseFee)
 	if err != nil {
 		return nil, err
@@ -571,51 +596,55 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 //
 // NOTE: In a future PR this will be removed.
 type Message struct {
-	to         *common.Address
-	from       common.Address
-	nonce      uint64
-	amount     *big.Int
-	gasLimit   uint64
-	gasPrice   *big.Int
-	gasFeeCap  *big.Int
-	gasTipCap  *big.Int
-	data       []byte
-	accessList AccessList
-	isFake     bool
-}
-
-func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice, gasFeeCap, gasTipCap *big.Int, data []byte, accessList AccessList, isFake bool) Message {
+	to          *common.Address
+	from        common.Address
+	nonce       uint64
+	amount      *big.Int
+	gasLimit    uint64
+	gasPrice    *big.Int
+	gasFeeCap   *big.Int
+	gasTipCap   *big.Int
+	feeCurrency *common.Address
+	data        []byte
+	accessList  AccessList
+	isFake      bool
+}
+
+func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice, gasFeeCap, gasTipCap *big.Int, feeCurrency *common.Address, data []byte, accessList AccessList, isFake bool) Message {
 	return Message{
-		from:       from,
-		to:         to,
-		nonce:      nonce,
-		amount:     amount,
-		gasLimit:   gasLimit,
-		gasPrice:   gasPrice,
-		gasFeeCap:  gasFeeCap,
-		gasTipCap:  gasTipCap,
-		data:       data,
-		accessList: accessList,
-		isFake:     isFake,
+		from:        from,
+		to:          to,
+		nonce:       nonce,
+		amount:      amount,
+		gasLimit:    gasLimit,
+		gasPrice:    gasPrice,
+		gasFeeCap:   gasFeeCap,
+		gasTipCap:   gasTipCap,
+		feeCurrency: feeCurrency,
+		data:        data,
+		accessList:  accessList,
+		isFake:      isFake,
 	}
 }
 
 // AsMessage returns the transaction as a core.Message.
 func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	msg := Message{
-		nonce:      tx.Nonce(),
-		gasLimit:   tx.Gas(),
-		gasPrice:   new(big.Int).Set(tx.GasPrice()),
-		gasFeeCap:  new(big.Int).Set(tx.GasFeeCap()),
-		gasTipCap:  new(big.Int).Set(tx.GasTipCap()),
-		to:         tx.To(),
-		amount:     tx.Value(),
-		data:       tx.Data(),
-		accessList: tx.AccessList(),
-		isFake:     false,
-	}
-	// If baseFee provided, set gasPrice to effectiveGasPrice.
-	if baseFee != nil {
+		nonce:       tx.Nonce(),
+		gasLimit:    tx.Gas(),
+		gasPrice:    new(big.Int).Set(tx.GasPrice()),
+		gasFeeCap:   new(big.Int).Set(tx.GasFeeCap()),
+		gasTipCap:   new(big.Int).Set(tx.GasTipCap()),
+		feeCurrency: tx.FeeCurrency(),
+		to:          tx.To(),
+		amount:      tx.Value(),
+		data:        tx.Data(),
+		accessList:  tx.AccessList(),
+		isFake:      false,
+	}
+	// If baseFee provided, set gasPrice to effectiveGasPrice. The base fee is
+	// denominated in MAP, the state transition converts it for the fee currency.
+	if baseFee != nil && msg.feeCurrency == nil {
 		msg.gasPrice = math.BigMin(msg.gasPrice.Add(msg.gasTipCap, baseFee), msg.gasFeeCap)
 	}
 	var err error
@@ -623,26 +652,27 @@ func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	return msg, err
 }
 
-func (m Message) From() common.Address               { return m.from }
-func (m Message) To() *common.Address                { return m.to }
-func (m Message) GasPrice() *big.Int                 { return m.gasPrice }
-func (m Message) GasFeeCap() *big.Int                { return m.gasFeeCap }
-func (m Message) GasTipCap() *big.Int                { return m.gasTipCap }
-func (m Message) Value() *big.Int                    { return m.amount }
-func (m Message) Gas() uint64                        { return m.gasLimit }
-func (m Message) Nonce() uint64                      { return m.nonce }
-func (m Message) Data() []byte                       { return m.data }
-func (m Message) AccessList() AccessList             { return m.accessList }
-func (m Message) IsFake() bool                       { return m.isFake }
-func (tx *Transaction) FeeCurrency() *common.Address { return nil }
+func (m Message) From() common.Address         { return m.from }
+func (m Message) To() *common.Address          { return m.to }
+func (m Message) GasPrice() *big.Int           { return m.gasPrice }
+func (m Message) GasFeeCap() *big.Int          { return m.gasFeeCap }
+func (m Message) GasTipCap() *big.Int          { return m.gasTipCap }
+func (m Message) Value() *big.Int              { return m.amount }
+func (m Message) Gas() uint64                  { return m.gasLimit }
+func (m Message) Nonce() uint64                { return m.nonce }
+func (m Message) Data() []byte                 { return m.data }
+func (m Message) AccessList() AccessList       { return m.accessList }
+func (m Message) FeeCurrency() *common.Address { return m.feeCurrency }
+func (m Message) IsFake() bool                 { return m.isFake }
+
+// Fee returns the most the transaction pays for gas, in its fee currency.
 func (tx *Transaction) Fee() *big.Int {
-	return Fee(tx.GasPrice(), tx.Gas(), nil)
+	return Fee(tx.GasPrice(), tx.Gas())
 }
 
-// Fee calculates the transaction fee (gasLimit * gasPrice + gatewayFee)
-func Fee(gasPrice *big.Int, gasLimit uint64, gatewayFee *big.Int) *big.Int {
-	gasFee := new(big.Int).Mul(gasPrice, big.NewInt(int64(gasLimit)))
-	return gasFee.Add(gasFee, gatewayFee)
+// Fee calculates the transaction fee (gasLimit * gasPrice)
+func Fee(gasPrice *big.Int, gasLimit uint64) *big.Int {
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
 }
 
 // copyAddressPtr copies an address.
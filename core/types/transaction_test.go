@@ -488,6 +488,67 @@ func TestTransactionCoding(t *testing.T) {
 	}
 }
 
+func TestFeeCurrencyTxCoding(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	var (
+		signer      = NewLondonSigner(common.Big1)
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		recipient   = common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87")
+		feeCurrency = common.HexToAddress("0x00000000000000000000000000000000000000fc")
+	)
+	txdata := &FeeCurrencyTx{
+		ChainID:     big.NewInt(1),
+		Nonce:       3,
+		To:          &recipient,
+		Gas:         123457,
+		GasTipCap:   big.NewInt(2),
+		GasFeeCap:   big.NewInt(10),
+		FeeCurrency: &feeCurrency,
+		Data:        []byte("abcdef"),
+	}
+	tx, err := SignNewTx(key, signer, txdata)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	// The fee currency is part of the signed payload
+	other := NewTx(&FeeCurrencyTx{ChainID: big.NewInt(1), Nonce: 3, To: &recipient, Gas: 123457, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(10), Data: []byte("abcdef")})
+	if signer.Hash(tx) == signer.Hash(other) {
+		t.Errorf("signing hash does not cover the fee currency")
+	}
+	for name, coding := range map[string]func(*Transaction) (*Transaction, error){
+		"rlp":  encodeDecodeBinary,
+		"json": encodeDecodeJSON,
+	} {
+		parsedTx, err := coding(tx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := assertEqual(parsedTx, tx); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if parsedTx.Type() != FeeCurrencyTxType {
+			t.Errorf("%s: type = %d, want %d", name, parsedTx.Type(), FeeCurrencyTxType)
+		}
+		if fc := parsedTx.FeeCurrency(); fc == nil || *fc != feeCurrency {
+			t.Errorf("%s: fee currency = %v, want %x", name, fc, feeCurrency)
+		}
+		if sender, err := Sender(signer, parsedTx); err != nil || sender != from {
+			t.Errorf("%s: sender = %x (%v), want %x", name, sender, err, from)
+		}
+	}
+	msg, err := tx.AsMessage(signer, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The base fee is converted to the fee currency when the message is applied
+	if msg.FeeCurrency() == nil || *msg.FeeCurrency() != feeCurrency || msg.GasPrice().Cmp(txdata.GasFeeCap) != 0 {
+		t.Errorf("message fee currency %v gas price %v, want %x and %v", msg.FeeCurrency(), msg.GasPrice(), feeCurrency, txdata.GasFeeCap)
+	}
+}
+
 func encodeDecodeJSON(tx *Transaction) (*Transaction, error) {
 	data, err := json.Marshal(tx)
 	if err != nil {
@@ -0,0 +1,188 @@
+package vm
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/mapprotocol/atlas/params"
+)
+
+// CIP-20 hash function selectors, the first input byte of a call.
+const (
+	cip20Sha3_256  byte = 0x00
+	cip20Sha3_512  byte = 0x01
+	cip20Keccak512 byte = 0x02
+	cip20Sha2_512  byte = 0x03
+	cip20Blake2s   byte = 0x10
+)
+
+var (
+	errCip20UnknownFunction  = errors.New("unsupported hash function")
+	errCip20InvalidConfig    = errors.New("invalid blake2s configuration")
+	errCip20KeyNotSupported  = errors.New("keyed blake2s is not supported")
+	errCip20InvalidDigestLen = errors.New("invalid blake2s digest length")
+)
+
+// cip20Function is a hash function exposed by the CIP-20 precompile.
+type cip20Function struct {
+	baseGas, perWordGas uint64
+	hash                func(input []byte) ([]byte, error)
+}
+
+var cip20Functions = map[byte]cip20Function{
+	cip20Sha3_256:  {params.Sha3_256BaseGas, params.Sha3_256PerWordGas, sumSha3_256},
+	cip20Sha3_512:  {params.Sha3_512BaseGas, params.Sha3_512PerWordGas, sumSha3_512},
+	cip20Keccak512: {params.Keccak512BaseGas, params.Keccak512PerWordGas, sumKeccak512},
+	cip20Sha2_512:  {params.Sha2_512BaseGas, params.Sha2_512PerWordGas, sumSha2_512},
+	cip20Blake2s:   {params.Blake2sBaseGas, params.Blake2sPerWordGas, sumBlake2s},
+}
+
+// cip20HashFunctions implements the CIP-20 extensible hash function precompile.
+// The first input byte selects the function, the rest is hashed by it.
+type cip20HashFunctions struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *cip20HashFunctions) RequiredGas(input []byte) uint64 {
+	if len(input) == 0 {
+		return params.InvalidCip20Gas
+	}
+	f, ok := cip20Functions[input[0]]
+	if !ok {
+		return params.InvalidCip20Gas
+	}
+	words := uint64(len(input)-1+31) / 32
+	return f.baseGas + words*f.perWordGas
+}
+
+func (c *cip20HashFunctions) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, ErrInputLength
+	}
+	f, ok := cip20Functions[input[0]]
+	if !ok {
+		return nil, errCip20UnknownFunction
+	}
+	return f.hash(input[1:])
+}
+
+func sumSha3_256(input []byte) ([]byte, error) {
+	h := sha3.Sum256(input)
+	return h[:], nil
+}
+
+func sumSha3_512(input []byte) ([]byte, error) {
+	h := sha3.Sum512(input)
+	return h[:], nil
+}
+
+func sumKeccak512(input []byte) ([]byte, error) {
+	h := sha3.NewLegacyKeccak512()
+	h.Write(input)
+	return h.Sum(nil), nil
+}
+
+func sumSha2_512(input []byte) ([]byte, error) {
+	h := sha512.Sum512(input)
+	return h[:], nil
+}
+
+// sumBlake2s hashes input[32:] with BLAKE2s, input[:32] is the RFC 7693
+// parameter block: digest length, key length, fanout, depth, leaf length,
+// node offset, node depth, inner length, salt and personalization. The
+// parameters other than the lengths are mixed into the initial state as is.
+func sumBlake2s(input []byte) ([]byte, error) {
+	if len(input) < 32 {
+		return nil, errCip20InvalidConfig
+	}
+	config, msg := input[:32], input[32:]
+	digestLen := int(config[0])
+	if digestLen == 0 || digestLen > 32 {
+		return nil, errCip20InvalidDigestLen
+	}
+	if config[1] != 0 {
+		return nil, errCip20KeyNotSupported
+	}
+
+	var h [8]uint32
+	for i := range h {
+		h[i] = blake2sIV[i] ^ binary.LittleEndian.Uint32(config[4*i:])
+	}
+	var counter uint64
+	for len(msg) > 64 {
+		counter += 64
+		blake2sCompress(&h, msg[:64], counter, false)
+		msg = msg[64:]
+	}
+	var last [64]byte
+	copy(last[:], msg)
+	counter += uint64(len(msg))
+	blake2sCompress(&h, last[:], counter, true)
+
+	var out [32]byte
+	for i, v := range h {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+	return out[:digestLen], nil
+}
+
+var blake2sIV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var blake2sSigma = [10][16]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// blake2sCompress is the BLAKE2s compression function F of RFC 7693.
+func blake2sCompress(h *[8]uint32, block []byte, counter uint64, final bool) {
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	var v [16]uint32
+	copy(v[:8], h[:])
+	copy(v[8:], blake2sIV[:])
+	v[12] ^= uint32(counter)
+	v[13] ^= uint32(counter >> 32)
+	if final {
+		v[14] = ^v[14]
+	}
+
+	g := func(a, b, c, d int, x, y uint32) {
+		v[a] = v[a] + v[b] + x
+		v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+		v[c] = v[c] + v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+		v[a] = v[a] + v[b] + y
+		v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+		v[c] = v[c] + v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+	}
+	for _, s := range blake2sSigma {
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
@@ -32,6 +32,9 @@ import (
 	params2 "github.com/mapprotocol/atlas/params"
 	"math/big"
 
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -95,6 +98,14 @@ type PrecompiledContract interface {
 	Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) // Run runs the precompiled contract
 }
 
+// statefulPrecompiledContract is implemented by atlas precompiles whose cost
+// also depends on the state they work on, StateGas is charged on top of
+// RequiredGas before the contract runs.
+type statefulPrecompiledContract interface {
+	PrecompiledContract
+	StateGas(evm *EVM, input []byte) uint64
+}
+
 // var HeaderStoreAddress common.Address = common.BytesToAddress([]byte("headerStoreAddress"))
 // PrecompiledContractsHomestead contains the default set of pre-compiled Ethereum
 // contracts used in the Frontier and Homestead releases.
@@ -233,7 +244,29 @@ var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
 	eth2VerifyUpdateAddress: &eth2VerifyLightClient{},
 }
 
+// PrecompiledContractsDonut contains the pre-compiled contracts added on top of
+// the active Ethereum release by the Donut fork.
+var PrecompiledContractsDonut = map[common.Address]PrecompiledContract{
+	b12_377G1AddAddress:      &bls12377G1Add{},
+	b12_377G1MulAddress:      &bls12377G1Mul{},
+	b12_377G1MultiExpAddress: &bls12377G1MultiExp{},
+	b12_377G2AddAddress:      &bls12377G2Add{},
+	b12_377G2MulAddress:      &bls12377G2Mul{},
+	b12_377G2MultiExpAddress: &bls12377G2MultiExp{},
+	b12_377PairingAddress:    &bls12377Pairing{},
+	cip20Address:             &cip20HashFunctions{},
+	cip26Address:             &getValidatorBLS{},
+}
+
+// PrecompiledContractsJail contains the pre-compiled contracts added by the
+// validator jail fork.
+var PrecompiledContractsJail = map[common.Address]PrecompiledContract{
+	params.JailAddress: &jail{},
+}
+
 var (
+	PrecompiledAddressesJail      []common.Address
+	PrecompiledAddressesDonut     []common.Address
 	PrecompiledAddressesBerlin    []common.Address
 	PrecompiledAddressesIstanbul  []common.Address
 	PrecompiledAddressesByzantium []common.Address
@@ -253,20 +286,34 @@ func init() {
 	for k := range PrecompiledContractsBerlin {
 		PrecompiledAddressesBerlin = append(PrecompiledAddressesBerlin, k)
 	}
+	for k := range PrecompiledContractsDonut {
+		PrecompiledAddressesDonut = append(PrecompiledAddressesDonut, k)
+	}
+	for k := range PrecompiledContractsJail {
+		PrecompiledAddressesJail = append(PrecompiledAddressesJail, k)
+	}
 }
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	var addresses []common.Address
 	switch {
 	case rules.IsBerlin:
-		return PrecompiledAddressesBerlin
+		addresses = PrecompiledAddressesBerlin
 	case rules.IsIstanbul:
-		return PrecompiledAddressesIstanbul
+		addresses = PrecompiledAddressesIstanbul
 	case rules.IsByzantium:
-		return PrecompiledAddressesByzantium
+		addresses = PrecompiledAddressesByzantium
 	default:
-		return PrecompiledAddressesHomestead
+		addresses = PrecompiledAddressesHomestead
 	}
+	if rules.IsDonut {
+		addresses = append(append([]common.Address{}, addresses...), PrecompiledAddressesDonut...)
+	}
+	if rules.IsJail {
+		addresses = append(append([]common.Address{}, addresses...), PrecompiledAddressesJail...)
+	}
+	return addresses
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
@@ -276,6 +323,9 @@ func ActivePrecompiles(rules params.Rules) []common.Address {
 // - any error that occurred
 func RunPrecompiledContract(evm *EVM, contract *Contract, p PrecompiledContract, input []byte, suppliedGas uint64) (ret []byte, remainingGas uint64, err error) {
 	gasCost := p.RequiredGas(input)
+	if sp, ok := p.(statefulPrecompiledContract); ok {
+		gasCost += sp.StateGas(evm, input)
+	}
 	if suppliedGas < gasCost {
 		return nil, 0, ErrOutOfGas
 	}
@@ -1172,6 +1222,366 @@ func (c *bls12381MapG2) Run(evm *EVM, contract *Contract, input []byte) ([]byte,
 	return g.EncodePoint(r), nil
 }
 
+var (
+	errBLS12377InvalidInputLength          = errors.New("invalid input length")
+	errBLS12377InvalidFieldElementTopBytes = errors.New("invalid field element top bytes")
+	errBLS12377InvalidFieldElement         = errors.New("invalid field element")
+	errBLS12377G1PointNotOnCurve           = errors.New("g1 point is not on curve")
+	errBLS12377G2PointNotOnCurve           = errors.New("g2 point is not on curve")
+	errBLS12377G1PointSubgroup             = errors.New("g1 point is not on correct subgroup")
+	errBLS12377G2PointSubgroup             = errors.New("g2 point is not on correct subgroup")
+)
+
+// bls12377G1Add implements the BLS12-377 G1Add precompile.
+type bls12377G1Add struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1Add) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G1AddGas
+}
+
+func (c *bls12377G1Add) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G1 addition call expects `256` bytes as an input that is interpreted as byte concatenation of two G1 points (`128` bytes each).
+	// Output is an encoding of addition operation result - single G1 point (`128` bytes).
+	if len(input) != 256 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	p0, err := decodeBLS12377G1Point(input[:128])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeBLS12377G1Point(input[128:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute r = p_0 + p_1
+	var r, t bls12377.G1Jac
+	r.FromAffine(p0)
+	r.AddAssign(t.FromAffine(p1))
+
+	return encodeBLS12377G1Point(new(bls12377.G1Affine).FromJacobian(&r)), nil
+}
+
+// bls12377G1Mul implements the BLS12-377 G1Mul precompile.
+type bls12377G1Mul struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1Mul) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G1MulGas
+}
+
+func (c *bls12377G1Mul) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G1 multiplication call expects `160` bytes as an input that is interpreted as byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
+	// Output is an encoding of multiplication operation result - single G1 point (`128` bytes).
+	if len(input) != 160 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	p0, err := decodeBLS12377G1Point(input[:128])
+	if err != nil {
+		return nil, err
+	}
+	// The endomorphism based multiplication is only correct in the prime order subgroup
+	if !p0.IsInSubGroup() {
+		return nil, errBLS12377G1PointSubgroup
+	}
+
+	// Compute r = e * p_0
+	r := new(bls12377.G1Affine).ScalarMultiplication(p0, decodeBLS12377Scalar(input[128:]))
+
+	return encodeBLS12377G1Point(r), nil
+}
+
+// bls12377G1MultiExp implements the BLS12-377 G1MultiExp precompile.
+type bls12377G1MultiExp struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1MultiExp) RequiredGas(input []byte) uint64 {
+	return bls12377MultiExpGas(len(input)/160, params2.Bls12377G1MulGas)
+}
+
+func (c *bls12377G1MultiExp) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G1 multiexponentiation call expects `160*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
+	// Output is an encoding of multiexponentiation operation result - single G1 point (`128` bytes).
+	k := len(input) / 160
+	if len(input) == 0 || len(input)%160 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+
+	// Compute r = e_0 * p_0 + e_1 * p_1 + ... + e_(k-1) * p_(k-1)
+	var r, t bls12377.G1Jac
+	r.FromAffine(new(bls12377.G1Affine))
+	for i := 0; i < k; i++ {
+		off := 160 * i
+		p, err := decodeBLS12377G1Point(input[off : off+128])
+		if err != nil {
+			return nil, err
+		}
+		if !p.IsInSubGroup() {
+			return nil, errBLS12377G1PointSubgroup
+		}
+		t.FromAffine(p)
+		r.AddAssign(t.ScalarMultiplication(&t, decodeBLS12377Scalar(input[off+128:off+160])))
+	}
+
+	return encodeBLS12377G1Point(new(bls12377.G1Affine).FromJacobian(&r)), nil
+}
+
+// bls12377G2Add implements the BLS12-377 G2Add precompile.
+type bls12377G2Add struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2Add) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G2AddGas
+}
+
+func (c *bls12377G2Add) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G2 addition call expects `512` bytes as an input that is interpreted as byte concatenation of two G2 points (`256` bytes each).
+	// Output is an encoding of addition operation result - single G2 point (`256` bytes).
+	if len(input) != 512 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	p0, err := decodeBLS12377G2Point(input[:256])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeBLS12377G2Point(input[256:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute r = p_0 + p_1
+	var r, t bls12377.G2Jac
+	r.FromAffine(p0)
+	r.AddAssign(t.FromAffine(p1))
+
+	return encodeBLS12377G2Point(new(bls12377.G2Affine).FromJacobian(&r)), nil
+}
+
+// bls12377G2Mul implements the BLS12-377 G2Mul precompile.
+type bls12377G2Mul struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2Mul) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G2MulGas
+}
+
+func (c *bls12377G2Mul) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G2 multiplication call expects `288` bytes as an input that is interpreted as byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
+	// Output is an encoding of multiplication operation result - single G2 point (`256` bytes).
+	if len(input) != 288 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	p0, err := decodeBLS12377G2Point(input[:256])
+	if err != nil {
+		return nil, err
+	}
+	// The endomorphism based multiplication is only correct in the prime order subgroup
+	if !p0.IsInSubGroup() {
+		return nil, errBLS12377G2PointSubgroup
+	}
+
+	// Compute r = e * p_0
+	r := new(bls12377.G2Affine).ScalarMultiplication(p0, decodeBLS12377Scalar(input[256:]))
+
+	return encodeBLS12377G2Point(r), nil
+}
+
+// bls12377G2MultiExp implements the BLS12-377 G2MultiExp precompile.
+type bls12377G2MultiExp struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2MultiExp) RequiredGas(input []byte) uint64 {
+	return bls12377MultiExpGas(len(input)/288, params2.Bls12377G2MulGas)
+}
+
+func (c *bls12377G2MultiExp) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// G2 multiexponentiation call expects `288*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
+	// Output is an encoding of multiexponentiation operation result - single G2 point (`256` bytes).
+	k := len(input) / 288
+	if len(input) == 0 || len(input)%288 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+
+	// Compute r = e_0 * p_0 + e_1 * p_1 + ... + e_(k-1) * p_(k-1)
+	var r, t bls12377.G2Jac
+	r.FromAffine(new(bls12377.G2Affine))
+	for i := 0; i < k; i++ {
+		off := 288 * i
+		p, err := decodeBLS12377G2Point(input[off : off+256])
+		if err != nil {
+			return nil, err
+		}
+		if !p.IsInSubGroup() {
+			return nil, errBLS12377G2PointSubgroup
+		}
+		t.FromAffine(p)
+		r.AddAssign(t.ScalarMultiplication(&t, decodeBLS12377Scalar(input[off+256:off+288])))
+	}
+
+	return encodeBLS12377G2Point(new(bls12377.G2Affine).FromJacobian(&r)), nil
+}
+
+// bls12377Pairing implements the BLS12-377 Pairing precompile.
+type bls12377Pairing struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377Pairing) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377PairingBaseGas + uint64(len(input)/384)*params2.Bls12377PairingPerPairGas
+}
+
+func (c *bls12377Pairing) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Pairing call expects `384*k` bytes as an inputs that is interpreted as byte concatenation of `k` slices. Each slice has the following structure:
+	// - `128` bytes of G1 point encoding
+	// - `256` bytes of G2 point encoding
+	// Output is a `32` bytes where last single byte is `0x01` if pairing result is equal to multiplicative identity in a pairing target field and `0x00` otherwise.
+	k := len(input) / 384
+	if len(input) == 0 || len(input)%384 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+
+	g1s := make([]bls12377.G1Affine, 0, k)
+	g2s := make([]bls12377.G2Affine, 0, k)
+	for i := 0; i < k; i++ {
+		off := 384 * i
+		p1, err := decodeBLS12377G1Point(input[off : off+128])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := decodeBLS12377G2Point(input[off+128 : off+384])
+		if err != nil {
+			return nil, err
+		}
+		if !p1.IsInSubGroup() {
+			return nil, errBLS12377G1PointSubgroup
+		}
+		if !p2.IsInSubGroup() {
+			return nil, errBLS12377G2PointSubgroup
+		}
+		// Pairs with a point at infinity do not change the product
+		if p1.IsInfinity() || p2.IsInfinity() {
+			continue
+		}
+		g1s = append(g1s, *p1)
+		g2s = append(g2s, *p2)
+	}
+
+	out := make([]byte, 32)
+	if len(g1s) == 0 {
+		out[31] = 1
+		return out, nil
+	}
+	ok, err := bls12377.PairingCheck(g1s, g2s)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		out[31] = 1
+	}
+	return out, nil
+}
+
+// bls12377MultiExpGas prices k scalar multiplications with the EIP-2537 discount.
+func bls12377MultiExpGas(k int, mulGas uint64) uint64 {
+	if k == 0 {
+		// Return 0 gas for small input length
+		return 0
+	}
+	var discount uint64
+	if dLen := len(ethparams.Bls12381MultiExpDiscountTable); k < dLen {
+		discount = ethparams.Bls12381MultiExpDiscountTable[k-1]
+	} else {
+		discount = ethparams.Bls12381MultiExpDiscountTable[dLen-1]
+	}
+	return (uint64(k) * mulGas * discount) / 1000
+}
+
+// decodeBLS12377FieldElement decodes a 64 byte BLS12-377 base field element,
+// the top 16 bytes must be zero and the value must be below the modulus.
+func decodeBLS12377FieldElement(in []byte) (fp.Element, error) {
+	var fe fp.Element
+	for i := 0; i < 16; i++ {
+		if in[i] != byte(0x00) {
+			return fe, errBLS12377InvalidFieldElementTopBytes
+		}
+	}
+	if new(big.Int).SetBytes(in[16:64]).Cmp(fp.Modulus()) >= 0 {
+		return fe, errBLS12377InvalidFieldElement
+	}
+	fe.SetBytes(in[16:64])
+	return fe, nil
+}
+
+func encodeBLS12377FieldElement(out []byte, fe *fp.Element) {
+	b := fe.Bytes()
+	copy(out[16:64], b[:])
+}
+
+// decodeBLS12377Scalar decodes a 32 byte scalar, scalars are taken modulo
+// the group order as the points are checked to be in the subgroup.
+func decodeBLS12377Scalar(in []byte) *big.Int {
+	return new(big.Int).Mod(new(big.Int).SetBytes(in), fr.Modulus())
+}
+
+// decodeBLS12377G1Point decodes a 128 byte G1 point, all zeroes is the point at infinity.
+func decodeBLS12377G1Point(in []byte) (*bls12377.G1Affine, error) {
+	var (
+		p   bls12377.G1Affine
+		err error
+	)
+	if p.X, err = decodeBLS12377FieldElement(in[:64]); err != nil {
+		return nil, err
+	}
+	if p.Y, err = decodeBLS12377FieldElement(in[64:128]); err != nil {
+		return nil, err
+	}
+	if !p.IsOnCurve() {
+		return nil, errBLS12377G1PointNotOnCurve
+	}
+	return &p, nil
+}
+
+func encodeBLS12377G1Point(p *bls12377.G1Affine) []byte {
+	out := make([]byte, 128)
+	encodeBLS12377FieldElement(out[:64], &p.X)
+	encodeBLS12377FieldElement(out[64:], &p.Y)
+	return out
+}
+
+// decodeBLS12377G2Point decodes a 256 byte G2 point, each coordinate is
+// encoded as c0 || c1. All zeroes is the point at infinity.
+func decodeBLS12377G2Point(in []byte) (*bls12377.G2Affine, error) {
+	var (
+		p   bls12377.G2Affine
+		err error
+	)
+	if p.X.A0, err = decodeBLS12377FieldElement(in[:64]); err != nil {
+		return nil, err
+	}
+	if p.X.A1, err = decodeBLS12377FieldElement(in[64:128]); err != nil {
+		return nil, err
+	}
+	if p.Y.A0, err = decodeBLS12377FieldElement(in[128:192]); err != nil {
+		return nil, err
+	}
+	if p.Y.A1, err = decodeBLS12377FieldElement(in[192:256]); err != nil {
+		return nil, err
+	}
+	if !p.IsOnCurve() {
+		return nil, errBLS12377G2PointNotOnCurve
+	}
+	return &p, nil
+}
+
+func encodeBLS12377G2Point(p *bls12377.G2Affine) []byte {
+	out := make([]byte, 256)
+	encodeBLS12377FieldElement(out[:64], &p.X.A0)
+	encodeBLS12377FieldElement(out[64:128], &p.X.A1)
+	encodeBLS12377FieldElement(out[128:192], &p.Y.A0)
+	encodeBLS12377FieldElement(out[192:], &p.Y.A1)
+	return out
+}
+
 const gasPerByte = 68
 
 type store struct{}
@@ -1186,8 +1596,11 @@ func (s *store) RequiredGas(input []byte) uint64 {
 		return baseGas
 	}
 
-	if method.Name == Save {
+	switch method.Name {
+	case Save, ResetLightClient, UpdateExecutionHeaders:
 		return uint64(len(input) * gasPerByte)
+	case UpdateLightClient:
+		return uint64(len(input)*gasPerByte) + params.VerifyEth2UpdateGas
 	}
 
 	if gas, ok := SyncGas[method.Name]; ok {
@@ -1196,10 +1609,49 @@ func (s *store) RequiredGas(input []byte) uint64 {
 	return baseGas
 }
 
+// StateGas charges setConfirmations for the headers it finalizes and, from the
+// relayer set fork on, setRelayer for the relayer sets it rewrites.
+func (s *store) StateGas(evm *EVM, input []byte) uint64 {
+	method, err := abiHeaderStore.MethodById(input)
+	if err != nil {
+		return 0
+	}
+	switch method.Name {
+	case SetConfirmations:
+		return setConfirmationsGas(evm, input[4:])
+	case SetRelayer:
+		if evm.chainConfig.IsRelayerSet(evm.Context.BlockNumber) {
+			return uint64(len(relayerChains())) * params.RelayerSetGas
+		}
+	}
+	return 0
+}
+
 func (s *store) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 	return RunHeaderStore(evm, contract, input)
 }
 
+type jail struct{}
+
+func (j *jail) RequiredGas(input []byte) uint64 {
+	var (
+		baseGas uint64 = 21000
+	)
+
+	method, err := abiJail.MethodById(input)
+	if err != nil {
+		return baseGas
+	}
+	if gas, ok := JailGas[method.Name]; ok {
+		return gas
+	}
+	return baseGas
+}
+
+func (j *jail) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	return RunJail(evm, contract, input)
+}
+
 type verify struct{}
 
 func (tv *verify) RequiredGas(input []byte) uint64 {
@@ -1212,6 +1664,10 @@ func (tv *verify) RequiredGas(input []byte) uint64 {
 		return baseGas
 	}
 
+	if method.Name == VerifyProofBatch {
+		return verifyProofBatchGas(input[4:])
+	}
+
 	if gas, ok := TxVerifyGas[method.Name]; ok {
 		return gas
 	}
@@ -1404,6 +1860,19 @@ func (c *proofOfPossession) Run(evm *EVM, contract *Contract, input []byte) ([]b
 	addressBytes := input[:common.AddressLength]
 
 	publicKeyBytes := input[common.AddressLength : common.AddressLength+blscrypto.PUBLICKEYBYTES]
+	// Validators elected from the BLS12-381 fork on sign with BLS12-381 keys, so
+	// only those can be registered from then on.
+	if evm.chainConfig.IsBLS12381(evm.Context.BlockNumber) {
+		var publicKey blscrypto.SerializedPublicKey
+		var g1PublicKey blscrypto.SerializedG1PublicKey
+		copy(publicKey[:], publicKeyBytes)
+		copy(g1PublicKey[:], input[common.AddressLength+blscrypto.PUBLICKEYBYTES:])
+		signatureBytes := input[common.AddressLength+blscrypto.PUBLICKEYBYTES+blscrypto.G1PUBLICKEYBYTES:]
+		if err := (blscrypto.BLS12381{}).VerifyProofOfPossession(publicKey, g1PublicKey, addressBytes, signatureBytes); err != nil {
+			return nil, err
+		}
+		return true32Byte, nil
+	}
 	publicKey, err := bls.UnmarshalPk(publicKeyBytes)
 	if err != nil {
 		return nil, err
@@ -1521,13 +1990,9 @@ func (c *getValidatorBLS) RequiredGas(input []byte) uint64 {
 	return params2.GetValidatorBLSGas
 }
 
-func copyBLSNumber(result []byte, offset int, uncompressedBytes []byte, offset2 int) {
-	for i := 0; i < 48; i++ {
-		result[63-i+offset] = uncompressedBytes[i+offset2]
-	}
-}
-
-// Return the validator BLS public key for the validator at given index. The public key is given in uncompressed format, 4*48 bytes.
+// Return the validator BLS public key for the validator at given index. The public key is the uncompressed G2
+// point of the curve of the validator, its four field elements in big endian 64 byte words: BN256 keys in the
+// EIP-197 order they are marshalled in, BLS12-381 keys in the EIP-2537 order.
 func (c *getValidatorBLS) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 	// input is comprised of two arguments:
 	//   index: 32 byte integer representing the index of the validator to get
@@ -1557,20 +2022,27 @@ func (c *getValidatorBLS) Run(evm *EVM, contract *Contract, input []byte) ([]byt
 
 	validator := validators[index.Uint64()]
 	uncompressedBytes := validator.BLSPublicKeyUncompressed()
-	if len(uncompressedBytes) != 192 {
-		return nil, ErrUnexpected
-	}
 
 	result := make([]byte, 256)
-	for i := 0; i < 256; i++ {
-		result[i] = 0
+	switch validator.BLSCurve() {
+	case blscrypto.BLS12381Curve:
+		if len(uncompressedBytes) != 192 {
+			return nil, ErrUnexpected
+		}
+		// The point is serialized as x.c1 || x.c0 || y.c1 || y.c0
+		copy(result[16:64], uncompressedBytes[48:96])
+		copy(result[80:128], uncompressedBytes[0:48])
+		copy(result[144:192], uncompressedBytes[144:192])
+		copy(result[208:256], uncompressedBytes[96:144])
+	default:
+		if len(uncompressedBytes) != 128 {
+			return nil, ErrUnexpected
+		}
+		for i := 0; i < 4; i++ {
+			copy(result[i*64+32:(i+1)*64], uncompressedBytes[i*32:(i+1)*32])
+		}
 	}
 
-	copyBLSNumber(result, 0, uncompressedBytes, 0)
-	copyBLSNumber(result, 64, uncompressedBytes, 48)
-	copyBLSNumber(result, 128, uncompressedBytes, 96)
-	copyBLSNumber(result, 192, uncompressedBytes, 144)
-
 	return result, nil
 }
 
@@ -26,9 +26,13 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/core/rawdb"
 	"github.com/mapprotocol/atlas/core/state"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
 	"github.com/mapprotocol/atlas/params"
 )
 
@@ -95,6 +99,14 @@ var allPrecompiles = map[common.Address]PrecompiledContract{
 	b12_381PairingAddress:    &bls12381Pairing{},
 	b12_381MapFpToG1Address:  &bls12381MapG1{},
 	b12_381MapFp2ToG2Address: &bls12381MapG2{},
+	b12_377G1AddAddress:      &bls12377G1Add{},
+	b12_377G1MulAddress:      &bls12377G1Mul{},
+	b12_377G1MultiExpAddress: &bls12377G1MultiExp{},
+	b12_377G2AddAddress:      &bls12377G2Add{},
+	b12_377G2MulAddress:      &bls12377G2Mul{},
+	b12_377G2MultiExpAddress: &bls12377G2MultiExp{},
+	b12_377PairingAddress:    &bls12377Pairing{},
+	cip20Address:             &cip20HashFunctions{},
 	cip26Address:             &getValidatorBLS{},
 }
 
@@ -450,6 +462,195 @@ func TestPrecompiledBLS12381PairingFail(t *testing.T)    { testJsonFail("blsPair
 func TestPrecompiledBLS12381MapG1Fail(t *testing.T)      { testJsonFail("blsMapG1", "11", t) }
 func TestPrecompiledBLS12381MapG2Fail(t *testing.T)      { testJsonFail("blsMapG2", "12", t) }
 
+func TestPrecompiledBLS12377G1Add(t *testing.T)      { testJson("bls377G1Add", "e9", t) }
+func TestPrecompiledBLS12377G1Mul(t *testing.T)      { testJson("bls377G1Mul", "e8", t) }
+func TestPrecompiledBLS12377G1MultiExp(t *testing.T) { testJson("bls377G1MultiExp", "e7", t) }
+func TestPrecompiledBLS12377G2Add(t *testing.T)      { testJson("bls377G2Add", "e6", t) }
+func TestPrecompiledBLS12377G2Mul(t *testing.T)      { testJson("bls377G2Mul", "e5", t) }
+func TestPrecompiledBLS12377G2MultiExp(t *testing.T) { testJson("bls377G2MultiExp", "e4", t) }
+func TestPrecompiledBLS12377Pairing(t *testing.T)    { testJson("bls377Pairing", "e3", t) }
+
+func BenchmarkPrecompiledBLS12377G1Add(b *testing.B)      { benchJson("bls377G1Add", "e9", b) }
+func BenchmarkPrecompiledBLS12377G1Mul(b *testing.B)      { benchJson("bls377G1Mul", "e8", b) }
+func BenchmarkPrecompiledBLS12377G1MultiExp(b *testing.B) { benchJson("bls377G1MultiExp", "e7", b) }
+func BenchmarkPrecompiledBLS12377G2Add(b *testing.B)      { benchJson("bls377G2Add", "e6", b) }
+func BenchmarkPrecompiledBLS12377G2Mul(b *testing.B)      { benchJson("bls377G2Mul", "e5", b) }
+func BenchmarkPrecompiledBLS12377G2MultiExp(b *testing.B) { benchJson("bls377G2MultiExp", "e4", b) }
+func BenchmarkPrecompiledBLS12377Pairing(b *testing.B)    { benchJson("bls377Pairing", "e3", b) }
+
+func TestPrecompiledBLS12377G1AddFail(t *testing.T)      { testJsonFail("bls377G1Add", "e9", t) }
+func TestPrecompiledBLS12377G1MulFail(t *testing.T)      { testJsonFail("bls377G1Mul", "e8", t) }
+func TestPrecompiledBLS12377G1MultiExpFail(t *testing.T) { testJsonFail("bls377G1MultiExp", "e7", t) }
+func TestPrecompiledBLS12377G2AddFail(t *testing.T)      { testJsonFail("bls377G2Add", "e6", t) }
+func TestPrecompiledBLS12377G2MulFail(t *testing.T)      { testJsonFail("bls377G2Mul", "e5", t) }
+func TestPrecompiledBLS12377G2MultiExpFail(t *testing.T) { testJsonFail("bls377G2MultiExp", "e4", t) }
+func TestPrecompiledBLS12377PairingFail(t *testing.T)    { testJsonFail("bls377Pairing", "e3", t) }
+
+// Tests the sample inputs from the extensible hash function precompile CIP 20
+func TestPrecompiledCip20(t *testing.T)      { testJson("cip20", "e2", t) }
+func TestPrecompiledCip20Fail(t *testing.T)  { testJsonFail("cip20", "e2", t) }
+func BenchmarkPrecompiledCip20(b *testing.B) { benchJson("cip20", "e2", b) }
+
+func TestPrecompiledDonutGas(t *testing.T) {
+	tests := []struct {
+		addr  string
+		input []byte
+		gas   uint64
+	}{
+		{"e9", make([]byte, 256), params.Bls12377G1AddGas},
+		{"e8", make([]byte, 160), params.Bls12377G1MulGas},
+		{"e7", nil, 0},
+		{"e7", make([]byte, 160), params.Bls12377G1MulGas * 1200 / 1000},
+		{"e7", make([]byte, 160*2), 2 * params.Bls12377G1MulGas * 888 / 1000},
+		{"e7", make([]byte, 160*200), 200 * params.Bls12377G1MulGas * 174 / 1000},
+		{"e6", make([]byte, 512), params.Bls12377G2AddGas},
+		{"e5", make([]byte, 288), params.Bls12377G2MulGas},
+		{"e4", make([]byte, 288*3), 3 * params.Bls12377G2MulGas * 764 / 1000},
+		{"e3", make([]byte, 384), params.Bls12377PairingBaseGas + params.Bls12377PairingPerPairGas},
+		{"e3", make([]byte, 384*4), params.Bls12377PairingBaseGas + 4*params.Bls12377PairingPerPairGas},
+		{"e2", nil, params.InvalidCip20Gas},
+		{"e2", []byte{0x20}, params.InvalidCip20Gas},
+		{"e2", []byte{0x00}, params.Sha3_256BaseGas},
+		{"e2", make([]byte, 1+33), params.Sha3_256BaseGas + 2*params.Sha3_256PerWordGas},
+		{"e2", append([]byte{0x01}, make([]byte, 64)...), params.Sha3_512BaseGas + 2*params.Sha3_512PerWordGas},
+		{"e2", append([]byte{0x02}, make([]byte, 1)...), params.Keccak512BaseGas + params.Keccak512PerWordGas},
+		{"e2", append([]byte{0x03}, make([]byte, 96)...), params.Sha2_512BaseGas + 3*params.Sha2_512PerWordGas},
+		{"e2", append([]byte{0x10}, make([]byte, 32+100)...), params.Blake2sBaseGas + 5*params.Blake2sPerWordGas},
+		{"e1", make([]byte, 64), params.GetValidatorBLSGas},
+	}
+	for i, test := range tests {
+		p := allPrecompiles[common.HexToAddress(test.addr)]
+		if gas := p.RequiredGas(test.input); gas != test.gas {
+			t.Errorf("test %d: %s RequiredGas(%d bytes) = %d, want %d", i, test.addr, len(test.input), gas, test.gas)
+		}
+	}
+}
+
+func TestPrecompiledDonutActivation(t *testing.T) {
+	config := *params.TestChainConfig
+	config.DonutBlock = big.NewInt(10)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	for _, addr := range []common.Address{b12_377G1AddAddress, b12_377PairingAddress, cip20Address, cip26Address} {
+		evm := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+		if _, ok := evm.precompile(addr); ok {
+			t.Errorf("precompile %x active before the Donut fork", addr)
+		}
+		evm = NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+		if _, ok := evm.precompile(addr); !ok {
+			t.Errorf("precompile %x not active at the Donut fork", addr)
+		}
+	}
+	before := ActivePrecompiles(config.Rules(big.NewInt(9)))
+	after := ActivePrecompiles(config.Rules(big.NewInt(10)))
+	if len(after) != len(before)+len(PrecompiledContractsDonut) {
+		t.Errorf("ActivePrecompiles() at the Donut fork = %d addresses, want %d", len(after), len(before)+len(PrecompiledContractsDonut))
+	}
+}
+
+func TestPrecompiledJailActivation(t *testing.T) {
+	config := *params.TestChainConfig
+	config.DonutBlock = big.NewInt(0)
+	config.JailBlock = big.NewInt(10)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+	if _, ok := evm.precompile(params.JailAddress); ok {
+		t.Error("jail precompile active before the jail fork")
+	}
+	evm = NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+	if _, ok := evm.precompile(params.JailAddress); !ok {
+		t.Error("jail precompile not active at the jail fork")
+	}
+	before := ActivePrecompiles(config.Rules(big.NewInt(9)))
+	after := ActivePrecompiles(config.Rules(big.NewInt(10)))
+	if len(after) != len(before)+len(PrecompiledContractsJail) {
+		t.Errorf("ActivePrecompiles() at the jail fork = %d addresses, want %d", len(after), len(before)+len(PrecompiledContractsJail))
+	}
+}
+
+func TestProofOfPossessionBLS12381(t *testing.T) {
+	config := *params.TestChainConfig
+	config.BLS12381Block = big.NewInt(10)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	key, _ := crypto.GenerateKey()
+	curve := blscrypto.BLS12381{}
+	priv, _ := curve.ECDSAToBLS(key)
+	pub, _ := curve.PrivateToPublic(priv)
+	g1, _ := curve.PrivateToG1Public(priv)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	pop, _ := curve.ProofOfPossession(priv, address.Bytes())
+
+	input := append(append(append(address.Bytes(), pub[:]...), g1[:]...), pop[:]...)
+	p := &proofOfPossession{}
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+	if res, err := p.Run(evm, nil, input); err != nil || !bytes.Equal(res, true32Byte) {
+		t.Errorf("BLS12-381 proof of possession at the fork = %x, %v", res, err)
+	}
+	evm = NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+	if _, err := p.Run(evm, nil, input); err == nil {
+		t.Errorf("BLS12-381 proof of possession accepted before the fork")
+	}
+	// the proof is bound to the address
+	other := append(common.HexToAddress("0x1").Bytes(), input[common.AddressLength:]...)
+	evm = NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+	if _, err := p.Run(evm, nil, other); err == nil {
+		t.Errorf("BLS12-381 proof of possession accepted for another address")
+	}
+}
+
+func TestGetValidatorBLS(t *testing.T) {
+	newKey := func(curve blscrypto.BLSCryptoSelector) blscrypto.SerializedPublicKey {
+		key, _ := crypto.GenerateKey()
+		priv, _ := curve.ECDSAToBLS(key)
+		pub, _ := curve.PrivateToPublic(priv)
+		return pub
+	}
+	bn256Key, bls12381Key := newKey(blscrypto.BN256{}), newKey(blscrypto.BLS12381{})
+	valSet := validator.NewSet([]istanbul.ValidatorData{
+		{Address: common.HexToAddress("0x1"), BLSPublicKey: bn256Key},
+		{Address: common.HexToAddress("0x2"), BLSPublicKey: bls12381Key, BLSCurve: blscrypto.BLS12381Curve},
+	})
+	getValidators := func(*big.Int, common.Hash) []istanbul.Validator { return valSet.List() }
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(10), GetValidators: getValidators}, TxContext{}, nil, params.TestChainConfig, Config{})
+	input := func(index int64) []byte {
+		return append(common.LeftPadBytes(big.NewInt(index).Bytes(), 32), common.LeftPadBytes(big.NewInt(10).Bytes(), 32)...)
+	}
+	p := &getValidatorBLS{}
+
+	// the BN256 key is returned in its marshalled order, one element per word
+	res, err := p.Run(evm, nil, input(0))
+	if err != nil {
+		t.Fatalf("BN256 key: %v", err)
+	}
+	uncompressed, _ := blscrypto.BN256{}.UncompressKey(bn256Key)
+	for i := 0; i < 4; i++ {
+		word := res[i*64 : (i+1)*64]
+		if !bytes.Equal(word, common.LeftPadBytes(uncompressed[i*32:(i+1)*32], 64)) {
+			t.Errorf("BN256 key word %d = %x, want %x", i, word, uncompressed[i*32:(i+1)*32])
+		}
+	}
+
+	// the x coordinate of the BLS12-381 key is the compressed key without its flags
+	res, err = p.Run(evm, nil, input(1))
+	if err != nil {
+		t.Fatalf("BLS12-381 key: %v", err)
+	}
+	x := common.CopyBytes(bls12381Key[:96])
+	x[0] &= 0x1f
+	if !bytes.Equal(res[0:64], common.LeftPadBytes(x[48:96], 64)) || !bytes.Equal(res[64:128], common.LeftPadBytes(x[0:48], 64)) {
+		t.Errorf("BLS12-381 key x = %x, want %x", res[0:128], x)
+	}
+	if bytes.Equal(res[128:256], make([]byte, 128)) {
+		t.Errorf("BLS12-381 key y is empty")
+	}
+
+	if _, err := p.Run(evm, nil, input(2)); err != ErrValidatorsOutOfBounds {
+		t.Errorf("out of bounds index error = %v, want %v", err, ErrValidatorsOutOfBounds)
+	}
+}
+
 func loadJson(name string) ([]precompiledTest, error) {
 	data, err := ioutil.ReadFile(fmt.Sprintf("testdata/precompiles/%v.json", name))
 	if err != nil {
@@ -71,6 +71,12 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		precompiles = PrecompiledContractsHomestead
 	}
 	p, ok := precompiles[addr]
+	if !ok && evm.chainRules.IsDonut {
+		p, ok = PrecompiledContractsDonut[addr]
+	}
+	if !ok && evm.chainRules.IsJail {
+		p, ok = PrecompiledContractsJail[addr]
+	}
 	return p, ok
 }
 
@@ -11,6 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/chains/interfaces"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/params"
@@ -23,8 +25,35 @@ const (
 	SetRelayer    = "setRelayer"
 	GetRelayer    = "getRelayer"
 	EventOfUpdate = "UpdateBlockHeader"
+
+	ResetLightClient       = "resetLightClient"
+	UpdateLightClient      = "updateLightClient"
+	UpdateExecutionHeaders = "updateExecutionHeaders"
+
+	SetConfirmations    = "setConfirmations"
+	FinalizedNbrAndHash = "finalizedNumberAndHash"
+
+	AddRelayer               = "addRelayer"
+	RemoveRelayer            = "removeRelayer"
+	SetRelayerQuota          = "setRelayerQuota"
+	GetRelayers              = "getRelayers"
+	RelayerSubmissions       = "relayerSubmissions"
+	EventOfRelayerSubmission = "RelayerSubmission"
 )
 
+// lightClientMethods are the header store methods added by the light client fork.
+var lightClientMethods = map[string]bool{
+	ResetLightClient:       true,
+	UpdateLightClient:      true,
+	UpdateExecutionHeaders: true,
+}
+
+// finalityMethods are the header store methods added by the finality fork.
+var finalityMethods = map[string]bool{
+	SetConfirmations:    true,
+	FinalizedNbrAndHash: true,
+}
+
 // HeaderStore contract ABI
 var (
 	abiHeaderStore, _ = abi.JSON(strings.NewReader(params.HeaderStoreABIJSON))
@@ -32,9 +61,16 @@ var (
 
 // SyncGas defines all method gas
 var SyncGas = map[string]uint64{
-	CurNbrAndHash: 42000,
-	SetRelayer:    2100,
-	GetRelayer:    0,
+	CurNbrAndHash:       42000,
+	FinalizedNbrAndHash: 42000,
+	SetConfirmations:    42000,
+	SetRelayer:          2100,
+	GetRelayer:          0,
+	AddRelayer:          params.RelayerSetGas,
+	RemoveRelayer:       params.RelayerSetGas,
+	SetRelayerQuota:     params.RelayerSetGas,
+	GetRelayers:         0,
+	RelayerSubmissions:  0,
 }
 
 // RunHeaderStore execute atlas header store contract
@@ -45,6 +81,21 @@ func RunHeaderStore(evm *EVM, contract *Contract, input []byte) (ret []byte, err
 		return nil, err
 	}
 
+	if relayerSetMethods[method.Name] && !evm.chainConfig.IsRelayerSet(evm.Context.BlockNumber) {
+		log.Warn("run header store contract failed, method before the relayer set fork", "method.name", method.Name)
+		return nil, errors.New("invalid method name")
+	}
+
+	if lightClientMethods[method.Name] && !evm.chainConfig.IsLightClient(evm.Context.BlockNumber) {
+		log.Warn("run header store contract failed, method before the light client fork", "method.name", method.Name)
+		return nil, errors.New("invalid method name")
+	}
+
+	if finalityMethods[method.Name] && !evm.chainConfig.IsFinality(evm.Context.BlockNumber) {
+		log.Warn("run header store contract failed, method before the finality fork", "method.name", method.Name)
+		return nil, errors.New("invalid method name")
+	}
+
 	data := input[4:]
 	switch method.Name {
 	case Save:
@@ -59,6 +110,26 @@ func RunHeaderStore(evm *EVM, contract *Contract, input []byte) (ret []byte, err
 		ret, err = setRelayer(evm, contract, data)
 	case GetRelayer:
 		ret, err = getRelayer(evm)
+	case ResetLightClient:
+		ret, err = resetLightClient(evm, contract, data)
+	case UpdateLightClient:
+		ret, err = updateLightClient(evm, contract, data)
+	case UpdateExecutionHeaders:
+		ret, err = updateExecutionHeaders(evm, contract, data)
+	case SetConfirmations:
+		ret, err = setConfirmations(evm, contract, data)
+	case FinalizedNbrAndHash:
+		ret, err = finalizedNumberAndHash(evm, contract, data)
+	case AddRelayer:
+		ret, err = addRelayer(evm, contract, data)
+	case RemoveRelayer:
+		ret, err = removeRelayer(evm, contract, data)
+	case SetRelayerQuota:
+		ret, err = setRelayerQuota(evm, contract, data)
+	case GetRelayers:
+		ret, err = getRelayers(evm, data)
+	case RelayerSubmissions:
+		ret, err = relayerSubmissions(evm, data)
 	default:
 		log.Warn("run header store contract failed, invalid method name", "method.name", method.Name)
 		return ret, errors.New("invalid method name")
@@ -82,10 +153,6 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 		Headers []byte
 	}{}
 
-	if err := validateRelayer(evm, contract.CallerAddress); err != nil {
-		return nil, err
-	}
-
 	method := abiHeaderStore.Methods[Save]
 	unpack, err := method.Inputs.Unpack(input)
 	if err != nil {
@@ -111,8 +178,11 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 	if !(chains.IsSupportedChain(fromChain) && chains.IsSupportedChain(toChain)) {
 		return nil, ErrNotSupportChain
 	}
+	if err := validateRelayer(evm, contract.CallerAddress, fromChain); err != nil {
+		return nil, err
+	}
 
-	group, err := chains.ChainType2ChainGroup(fromChain)
+	group, err := interfaces.ActiveChainGroup(evm.chainConfig, fromChain, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -132,9 +202,13 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 		return nil, err
 	}
 
-	// make event
+	emitUpdateBlockHeader(evm, contract, nums)
+	return nil, recordSubmission(evm, contract, fromChain, nums)
+}
+
+func emitUpdateBlockHeader(evm *EVM, contract *Contract, nums []*params.NumberHash) {
 	event := abiHeaderStore.Events[EventOfUpdate]
-	logData, err := event.Inputs.Pack()
+	logData, _ := event.Inputs.Pack()
 	for _, n := range nums {
 		topics := []common.Hash{
 			event.ID,
@@ -144,8 +218,6 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 		addLog(evm, contract, topics, logData)
 		log.Info("event produce", "height", n, "topics", topics, "event.ID", event.ID)
 	}
-
-	return nil, nil
 }
 
 func reset(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
@@ -179,7 +251,7 @@ func reset(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 		return nil, errors.New("current chainID does not match the from parameter")
 	}
 
-	group, err := chains.ChainType2ChainGroup(from)
+	group, err := interfaces.ActiveChainGroup(evm.chainConfig, from, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +266,108 @@ func reset(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 	return nil, nil
 }
 
+func resetLightClient(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	if !bytes.Equal(contract.CallerAddress.Bytes(), adminHash[12:]) {
+		return nil, errors.New("forbidden")
+	}
+
+	var state []byte
+	method := abiHeaderStore.Methods[ResetLightClient]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&state, unpack); err != nil {
+		return nil, err
+	}
+	if err := eth2.ResetLightClientStore(evm.StateDB, state); err != nil {
+		log.Error("failed to reset eth2 light client store", "error", err)
+		return nil, err
+	}
+	return nil, nil
+}
+
+func updateLightClient(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+		Update    []byte
+	}{}
+	method := abiHeaderStore.Methods[UpdateLightClient]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	chain, beaconChainID, err := lightClientChain(evm, args.ChainType)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRelayer(evm, contract.CallerAddress, chain); err != nil {
+		return nil, err
+	}
+	execution, err := eth2.ApplyLightClientUpdate(evm.StateDB, beaconChainID, args.Update)
+	if err != nil {
+		log.Error("failed to apply eth2 light client update", "error", err)
+		return nil, err
+	}
+
+	nums := []*params.NumberHash{{Number: execution.Number, Hash: execution.BlockHash}}
+	emitUpdateBlockHeader(evm, contract, nums)
+	return nil, recordSubmission(evm, contract, chain, nums)
+}
+
+func updateExecutionHeaders(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+		Headers   []byte
+	}{}
+	method := abiHeaderStore.Methods[UpdateExecutionHeaders]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	chain, _, err := lightClientChain(evm, args.ChainType)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRelayer(evm, contract.CallerAddress, chain); err != nil {
+		return nil, err
+	}
+	nums, err := ethereum.InsertExecutionHeaders(evm.StateDB, chain, args.Headers)
+	if err != nil {
+		log.Error("failed to insert execution headers", "error", err)
+		return nil, err
+	}
+
+	emitUpdateBlockHeader(evm, contract, nums)
+	return nil, recordSubmission(evm, contract, chain, nums)
+}
+
+// lightClientChain resolves an active source chain followed by the eth2 light
+// client and the beacon network of its store.
+func lightClientChain(evm *EVM, chainType *big.Int) (chains.ChainType, uint64, error) {
+	chain := chains.ChainType(chainType.Uint64())
+	if !chainType.IsUint64() || !chains.IsSupportedChain(chain) {
+		return 0, 0, ErrNotSupportChain
+	}
+	if _, err := interfaces.ActiveChainGroup(evm.chainConfig, chain, evm.Context.BlockNumber); err != nil {
+		return 0, 0, err
+	}
+	beaconChainID, ok := chains.ChainType2BeaconChainID(chain)
+	if !ok {
+		return 0, 0, errors.New("chain is not followed by the eth2 light client")
+	}
+	return chain, beaconChainID, nil
+}
+
 func currentNumberAndHash(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 	args := struct {
 		ChainID *big.Int
@@ -222,12 +396,114 @@ func currentNumberAndHash(evm *EVM, contract *Contract, input []byte) (ret []byt
 	return method.Outputs.Pack(new(big.Int).SetUint64(number), hash.Bytes())
 }
 
-func setRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+func finalityStore(evm *EVM, chainID *big.Int) (interfaces.IFinality, error) {
+	group, err := interfaces.ActiveChainGroup(evm.chainConfig, chains.ChainType(chainID.Uint64()), evm.Context.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	hs, err := interfaces.HeaderStoreFactory(group)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := hs.(interfaces.IFinality)
+	if !ok {
+		return nil, errors.New("header store does not support finality")
+	}
+	return f, nil
+}
+
+type setConfirmationsArgs struct {
+	ChainID       *big.Int
+	Confirmations *big.Int
+}
+
+func unpackSetConfirmations(input []byte) (*setConfirmationsArgs, error) {
+	args := new(setConfirmationsArgs)
+	method := abiHeaderStore.Methods[SetConfirmations]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.Confirmations.IsUint64() {
+		return nil, errors.New("invalid confirmations")
+	}
+	return args, nil
+}
+
+// setConfirmationsGas prices the headers setConfirmations walks to move the
+// finalized pointer, invalid calls are left to the run to reject.
+func setConfirmationsGas(evm *EVM, input []byte) uint64 {
+	if !evm.chainConfig.IsFinality(evm.Context.BlockNumber) {
+		return 0
+	}
+	args, err := unpackSetConfirmations(input)
+	if err != nil {
+		return 0
+	}
+	f, err := finalityStore(evm, args.ChainID)
+	if err != nil {
+		return 0
+	}
+	count, err := f.FinalizeCount(evm.StateDB, args.Confirmations.Uint64())
+	if err != nil {
+		return 0
+	}
+	return count * params.HeaderStoreFinalizeGas
+}
+
+func setConfirmations(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
 	if !bytes.Equal(contract.CallerAddress.Bytes(), adminHash[12:]) {
 		return nil, errors.New("forbidden")
 	}
 
+	args, err := unpackSetConfirmations(input)
+	if err != nil {
+		return nil, err
+	}
+	f, err := finalityStore(evm, args.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return nil, f.SetConfirmations(evm.StateDB, args.Confirmations.Uint64())
+}
+
+func finalizedNumberAndHash(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainID *big.Int
+	}{}
+	method := abiHeaderStore.Methods[FinalizedNbrAndHash]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	f, err := finalityStore(evm, args.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	number, hash, confirmations, err := f.GetFinalizedNumberAndHash(evm.StateDB)
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(new(big.Int).SetUint64(number), hash.Bytes(), new(big.Int).SetUint64(confirmations))
+}
+
+// setRelayer sets the relayer allowed to write header stores. From the relayer
+// set fork on it replaces the relayer previously set by setRelayer with the
+// given one in the relayer set of every source chain, so that it is subject to
+// the same quotas and attribution as relayers added by addRelayer.
+func setRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	if !isAdmin(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
 	args := struct {
 		Relayer common.Address
 	}{}
@@ -239,22 +515,25 @@ func setRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err err
 	if err := method.Inputs.Copy(&args, unpack); err != nil {
 		return nil, err
 	}
+
+	if evm.chainConfig.IsRelayerSet(evm.Context.BlockNumber) {
+		if err := replaceRelayer(evm.StateDB, ReadRelayer(evm.StateDB), args.Relayer); err != nil {
+			return nil, err
+		}
+	}
 	evm.StateDB.SetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]), args.Relayer.Bytes())
 	return nil, nil
 }
 
 func getRelayer(evm *EVM) (ret []byte, err error) {
 	method := abiHeaderStore.Methods[GetRelayer]
-	relayerBytes := evm.StateDB.GetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]))
-	return method.Outputs.Pack(common.BytesToAddress(relayerBytes))
+	return method.Outputs.Pack(ReadRelayer(evm.StateDB))
 }
 
-func validateRelayer(evm *EVM, caller common.Address) error {
-	adminAddrBytes := evm.StateDB.GetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]))
-	if !bytes.Equal(caller.Bytes(), adminAddrBytes) {
-		return errors.New("invalid relayer")
-	}
-	return nil
+// ReadRelayer returns the relayer last set by setRelayer, the zero address if
+// none was set.
+func ReadRelayer(db types.StateDB) common.Address {
+	return common.BytesToAddress(db.GetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:])))
 }
 
 func addLog(evm *EVM, contract *Contract, topics []common.Hash, data []byte) {
@@ -2,8 +2,16 @@ package vm
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
 )
 
 func headerStorePack(method string, args ...interface{}) []byte {
@@ -77,3 +85,59 @@ func TestCurrentNumberAndHash(t *testing.T) {
 		})
 	}
 }
+
+// forkGated reports whether the header store refused the method as not yet
+// available at the block of the evm.
+func forkGated(evm *EVM, caller common.Address, method string, args ...interface{}) bool {
+	_, err := runHeaderStoreAs(evm, caller, method, args...)
+	return err != nil && err.Error() == "invalid method name"
+}
+
+func TestLightClientFork(t *testing.T) {
+	admin := common.HexToAddress("0xad")
+	eth := big.NewInt(int64(chains.ChainTypeETH))
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, admin.Hash())
+	config := *params.TestChainConfig
+	config.LightClientBlock = big.NewInt(10)
+
+	calls := map[string][]interface{}{
+		ResetLightClient:       {[]byte{0x01}},
+		UpdateLightClient:      {eth, []byte{0x01}},
+		UpdateExecutionHeaders: {eth, []byte{0x01}},
+	}
+	for method, args := range calls {
+		before := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+		if !forkGated(before, admin, method, args...) {
+			t.Errorf("%s() expected to be refused before the fork", method)
+		}
+		after := NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+		if forkGated(after, admin, method, args...) {
+			t.Errorf("%s() refused from the fork block on", method)
+		}
+	}
+}
+
+func TestFinalityFork(t *testing.T) {
+	admin := common.HexToAddress("0xad")
+	eth := big.NewInt(int64(chains.ChainTypeETH))
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, admin.Hash())
+	config := *params.TestChainConfig
+	config.FinalityBlock = big.NewInt(10)
+
+	calls := map[string][]interface{}{
+		SetConfirmations:    {eth, big.NewInt(12)},
+		FinalizedNbrAndHash: {eth},
+	}
+	for method, args := range calls {
+		before := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+		if !forkGated(before, admin, method, args...) {
+			t.Errorf("%s() expected to be refused before the fork", method)
+		}
+		after := NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+		if forkGated(after, admin, method, args...) {
+			t.Errorf("%s() refused from the fork block on", method)
+		}
+	}
+}
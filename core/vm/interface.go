@@ -20,6 +20,8 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/core/types"
 )
 
 // CallContext provides a basic interface for the EVM calling conventions. The EVM
@@ -59,4 +61,7 @@ type EVMRunner interface {
 	// StartGasMetering backward compatibility method to start gas metering
 	// Deprecated. DO NOT USE
 	StartGasMetering()
+
+	// GetStateDB returns the state the runner executes on
+	GetStateDB() types.StateDB
 }
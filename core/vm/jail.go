@@ -0,0 +1,216 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	Unjail           = "unjail"
+	GetJailStatus    = "getJailStatus"
+	GetJailedSigners = "getJailedSigners"
+	EventOfUnjailed  = "Unjailed"
+)
+
+// Jail contract ABI
+var (
+	abiJail, _ = abi.JSON(strings.NewReader(params.JailABIJSON))
+)
+
+// JailGas defines all jail method gas
+var JailGas = map[string]uint64{
+	Unjail:           params.UnjailGas,
+	GetJailStatus:    0,
+	GetJailedSigners: 0,
+}
+
+var (
+	errNotJailed       = errors.New("validator is not jailed")
+	errJailTermNotOver = errors.New("jail term is not over")
+	errNoEpochSize     = errors.New("epoch size is not set")
+)
+
+// jailEntry is a signer excluded from the elections until the account that
+// registered it unjails it, which it may do from ReleaseEpoch on.
+type jailEntry struct {
+	Signer       common.Address
+	Account      common.Address
+	ReleaseEpoch uint64
+}
+
+var jailKey = common.BytesToHash([]byte("jailed"))
+
+func loadJail(db types.StateDB) ([]jailEntry, error) {
+	data := db.GetPOWState(params.JailAddress, jailKey)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var entries []jailEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("jail rlp decode failed", "err", err)
+		return nil, fmt.Errorf("jail rlp decode failed, error: %v", err)
+	}
+	return entries, nil
+}
+
+func storeJail(db types.StateDB, entries []jailEntry) error {
+	data, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		log.Error("Failed to RLP encode jail", "err", err)
+		return err
+	}
+	db.SetPOWState(params.JailAddress, jailKey, data)
+	return nil
+}
+
+// JailValidator jails signer, registered by account, until releaseEpoch. A
+// signer that is already jailed keeps the later of both release epochs.
+func JailValidator(db types.StateDB, signer, account common.Address, releaseEpoch uint64) error {
+	entries, err := loadJail(db)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].Signer == signer {
+			if entries[i].ReleaseEpoch < releaseEpoch {
+				entries[i].ReleaseEpoch = releaseEpoch
+			}
+			entries[i].Account = account
+			return storeJail(db, entries)
+		}
+	}
+	return storeJail(db, append(entries, jailEntry{Signer: signer, Account: account, ReleaseEpoch: releaseEpoch}))
+}
+
+// ReadJailedSigners returns the signers currently in jail.
+func ReadJailedSigners(db types.StateDB) ([]common.Address, error) {
+	entries, err := loadJail(db)
+	if err != nil {
+		return nil, err
+	}
+	signers := make([]common.Address, 0, len(entries))
+	for _, e := range entries {
+		signers = append(signers, e.Signer)
+	}
+	return signers, nil
+}
+
+// ReadJailStatus returns whether the validator, given by its signer or its
+// account, is jailed and the epoch it may be unjailed in.
+func ReadJailStatus(db types.StateDB, validator common.Address) (bool, uint64, error) {
+	entries, err := loadJail(db)
+	if err != nil {
+		return false, 0, err
+	}
+	for _, e := range entries {
+		if e.Signer == validator || e.Account == validator {
+			return true, e.ReleaseEpoch, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// RunJail execute atlas jail contract
+func RunJail(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	method, err := abiJail.MethodById(input)
+	if err != nil {
+		log.Error("get jail ABI method failed", "error", err)
+		return nil, err
+	}
+
+	data := input[4:]
+	switch method.Name {
+	case Unjail:
+		ret, err = unjail(evm, contract)
+	case GetJailStatus:
+		ret, err = getJailStatus(evm, data)
+	case GetJailedSigners:
+		ret, err = getJailedSigners(evm)
+	default:
+		log.Warn("run jail contract failed, invalid method name", "method.name", method.Name)
+		return ret, errors.New("invalid method name")
+	}
+
+	if err != nil {
+		log.Error("run jail contract failed", "method.name", method.Name, "error", err)
+	}
+
+	return ret, err
+}
+
+// unjail releases the signers of the caller whose jail term is over.
+func unjail(evm *EVM, contract *Contract) (ret []byte, err error) {
+	entries, err := loadJail(evm.StateDB)
+	if err != nil {
+		return nil, err
+	}
+	if evm.Context.EpochSize == 0 {
+		return nil, errNoEpochSize
+	}
+	epoch := istanbul.GetEpochNumber(evm.Context.BlockNumber.Uint64(), evm.Context.EpochSize)
+	var (
+		kept     = make([]jailEntry, 0, len(entries))
+		released []jailEntry
+	)
+	for _, e := range entries {
+		if e.Account != contract.CallerAddress {
+			kept = append(kept, e)
+			continue
+		}
+		if e.ReleaseEpoch > epoch {
+			return nil, fmt.Errorf("%w, signer: %s, release epoch: %d", errJailTermNotOver, e.Signer, e.ReleaseEpoch)
+		}
+		released = append(released, e)
+	}
+	if len(released) == 0 {
+		return nil, errNotJailed
+	}
+	if err := storeJail(evm.StateDB, kept); err != nil {
+		return nil, err
+	}
+
+	event := abiJail.Events[EventOfUnjailed]
+	for _, e := range released {
+		addLog(evm, contract, []common.Hash{event.ID, e.Signer.Hash(), e.Account.Hash()}, nil)
+	}
+	return nil, nil
+}
+
+func getJailStatus(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		Validator common.Address
+	}{}
+	method := abiJail.Methods[GetJailStatus]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	jailed, releaseEpoch, err := ReadJailStatus(evm.StateDB, args.Validator)
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(jailed, new(big.Int).SetUint64(releaseEpoch))
+}
+
+func getJailedSigners(evm *EVM) (ret []byte, err error) {
+	signers, err := ReadJailedSigners(evm.StateDB)
+	if err != nil {
+		return nil, err
+	}
+	return abiJail.Methods[GetJailedSigners].Outputs.Pack(signers)
+}
@@ -0,0 +1,102 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func runJailAs(evm *EVM, caller common.Address, method string, args ...interface{}) ([]byte, error) {
+	input, err := abiJail.Pack(method, args...)
+	if err != nil {
+		panic(err)
+	}
+	contract := NewContract(AccountRef(caller), AccountRef(params.JailAddress), big.NewInt(0), 0)
+	return RunJail(evm, contract, input)
+}
+
+func TestJail(t *testing.T) {
+	var (
+		signer1  = common.HexToAddress("0x01")
+		account1 = common.HexToAddress("0xa1")
+		signer2  = common.HexToAddress("0x02")
+		account2 = common.HexToAddress("0xa2")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	newEVM := func(number int64) *EVM {
+		return NewEVM(BlockContext{BlockNumber: big.NewInt(number), EpochSize: 10}, TxContext{}, statedb, params.TestChainConfig, Config{})
+	}
+
+	if err := JailValidator(statedb, signer1, account1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := JailValidator(statedb, signer2, account2, 2); err != nil {
+		t.Fatal(err)
+	}
+	// jailing again keeps the later release epoch
+	if err := JailValidator(statedb, signer1, account1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	evm := newEVM(15)
+	ret, err := runJailAs(evm, account1, GetJailedSigners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := abiJail.Methods[GetJailedSigners].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signers := out[0].([]common.Address); !reflect.DeepEqual(signers, []common.Address{signer1, signer2}) {
+		t.Errorf("getJailedSigners() = %x, want %x", signers, []common.Address{signer1, signer2})
+	}
+	for _, validator := range []common.Address{signer1, account1} {
+		ret, err := runJailAs(evm, account1, GetJailStatus, validator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := abiJail.Methods[GetJailStatus].Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if jailed, release := out[0].(bool), out[1].(*big.Int); !jailed || release.Uint64() != 3 {
+			t.Errorf("getJailStatus(%x) = %v, %d, want true, 3", validator, jailed, release)
+		}
+	}
+
+	// only the account may unjail its signer, once the jail term is over
+	if _, err := unjail(evm, NewContract(AccountRef(signer1), AccountRef(params.JailAddress), big.NewInt(0), 0)); !errors.Is(err, errNotJailed) {
+		t.Errorf("unjail() by the signer error = %v, want %v", err, errNotJailed)
+	}
+	if _, err := unjail(evm, NewContract(AccountRef(account1), AccountRef(params.JailAddress), big.NewInt(0), 0)); !errors.Is(err, errJailTermNotOver) {
+		t.Errorf("unjail() before the release epoch error = %v, want %v", err, errJailTermNotOver)
+	}
+	evm = newEVM(25)
+	if _, err := runJailAs(evm, account2, Unjail); err != nil {
+		t.Fatalf("unjail() error = %v", err)
+	}
+	if logs := statedb.Logs(); len(logs) != 1 || logs[0].Topics[1] != signer2.Hash() || logs[0].Topics[2] != account2.Hash() {
+		t.Errorf("unjail() logs = %v, want an Unjailed event of %x", logs, signer2)
+	}
+	if _, err := runJailAs(evm, account2, Unjail); err == nil {
+		t.Error("unjail() expected error for a released validator")
+	}
+
+	signers, err := ReadJailedSigners(statedb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(signers, []common.Address{signer1}) {
+		t.Errorf("ReadJailedSigners() = %x, want %x", signers, []common.Address{signer1})
+	}
+	if jailed, _, _ := ReadJailStatus(statedb, signer2); jailed {
+		t.Error("ReadJailStatus() of a released validator = jailed")
+	}
+}
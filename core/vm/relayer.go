@@ -0,0 +1,411 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// maxRelayers bounds the relayer set of a chain, every submission walks it.
+const maxRelayers = 32
+
+var (
+	errRelayerExists        = errors.New("relayer already exists")
+	errRelayerNotFound      = errors.New("relayer not found")
+	errTooManyRelayers      = fmt.Errorf("relayer set is limited to %d relayers", maxRelayers)
+	errRelayerQuotaExceeded = errors.New("relayer submission quota exceeded")
+)
+
+// relayerSetMethods are the header store methods added by the relayer set fork.
+var relayerSetMethods = map[string]bool{
+	AddRelayer:         true,
+	RemoveRelayer:      true,
+	SetRelayerQuota:    true,
+	GetRelayers:        true,
+	RelayerSubmissions: true,
+}
+
+// relayerSet holds the relayers of a source chain and the batches each of
+// them submitted in Epoch. Quota caps the batches per relayer and epoch, zero
+// means unlimited. setRelayer adds its relayer to the set of every source
+// chain.
+type relayerSet struct {
+	Relayers    []common.Address
+	Quota       uint64
+	Epoch       uint64
+	Submissions []uint64
+}
+
+func relayerSetKey(chain chains.ChainType) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("%s-%d", "relayers", chain)))
+}
+
+func loadRelayerSet(db types.StateDB, chain chains.ChainType) (*relayerSet, error) {
+	data := db.GetPOWState(params.NewRelayerAddress, relayerSetKey(chain))
+	if len(data) == 0 {
+		return new(relayerSet), nil
+	}
+	var rs relayerSet
+	if err := rlp.DecodeBytes(data, &rs); err != nil {
+		log.Error("relayer set rlp decode failed", "chain", chain, "err", err)
+		return nil, fmt.Errorf("relayer set rlp decode failed, error: %v", err)
+	}
+	return &rs, nil
+}
+
+func (rs *relayerSet) store(db types.StateDB, chain chains.ChainType) error {
+	data, err := rlp.EncodeToBytes(rs)
+	if err != nil {
+		log.Error("Failed to RLP encode relayer set", "err", err)
+		return err
+	}
+	db.SetPOWState(params.NewRelayerAddress, relayerSetKey(chain), data)
+	return nil
+}
+
+func (rs *relayerSet) indexOf(relayer common.Address) int {
+	for i, r := range rs.Relayers {
+		if r == relayer {
+			return i
+		}
+	}
+	return -1
+}
+
+func (rs *relayerSet) add(relayer common.Address) error {
+	if len(rs.Relayers) >= maxRelayers {
+		return errTooManyRelayers
+	}
+	if len(rs.Submissions) == len(rs.Relayers) {
+		rs.Submissions = append(rs.Submissions, 0)
+	}
+	rs.Relayers = append(rs.Relayers, relayer)
+	return nil
+}
+
+func (rs *relayerSet) remove(i int) {
+	if len(rs.Submissions) == len(rs.Relayers) {
+		rs.Submissions = append(rs.Submissions[:i], rs.Submissions[i+1:]...)
+	}
+	rs.Relayers = append(rs.Relayers[:i], rs.Relayers[i+1:]...)
+}
+
+// relayerChains returns the source chains that keep a relayer set.
+func relayerChains() []chains.ChainType {
+	var sources []chains.ChainType
+	for _, chain := range chains.ChainTypeList {
+		if _, err := chains.ChainType2ChainGroup(chain); err == nil {
+			sources = append(sources, chain)
+		}
+	}
+	return sources
+}
+
+// replaceRelayer replaces previous with relayer in the relayer set of every
+// source chain, the zero address adds or removes nothing.
+func replaceRelayer(db types.StateDB, previous, relayer common.Address) error {
+	for _, chain := range relayerChains() {
+		rs, err := loadRelayerSet(db, chain)
+		if err != nil {
+			return err
+		}
+		if previous != relayer {
+			if i := rs.indexOf(previous); i >= 0 {
+				rs.remove(i)
+			}
+		}
+		if relayer != (common.Address{}) && rs.indexOf(relayer) < 0 {
+			if err := rs.add(relayer); err != nil {
+				return err
+			}
+		}
+		if err := rs.store(db, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyRelayerSetFork copies the relayer set by setRelayer into the relayer set
+// of every source chain when number is the relayer set fork block, so that the
+// relayer that is live before the fork keeps relaying from it on. It runs
+// before the transactions of the block.
+func ApplyRelayerSetFork(config *params.ChainConfig, number *big.Int, db types.StateDB) error {
+	if config.RelayerSetBlock == nil || config.RelayerSetBlock.Cmp(number) != 0 {
+		return nil
+	}
+	relayer := ReadRelayer(db)
+	if relayer == (common.Address{}) {
+		return nil
+	}
+	return replaceRelayer(db, common.Address{}, relayer)
+}
+
+// submissions returns the batches of the relayer at index i in epoch.
+func (rs *relayerSet) submissions(i int, epoch uint64) uint64 {
+	if rs.Epoch != epoch || i >= len(rs.Submissions) {
+		return 0
+	}
+	return rs.Submissions[i]
+}
+
+// ReadRelayers returns the relayer set of the chain and its per epoch quota.
+func ReadRelayers(db types.StateDB, chain chains.ChainType) ([]common.Address, uint64, error) {
+	rs, err := loadRelayerSet(db, chain)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rs.Relayers, rs.Quota, nil
+}
+
+func relayerEpoch(evm *EVM) uint64 {
+	if evm.Context.EpochSize == 0 {
+		return 0
+	}
+	return istanbul.GetEpochNumber(evm.Context.BlockNumber.Uint64(), evm.Context.EpochSize)
+}
+
+// validateRelayer checks that the caller may submit headers of the chain and
+// has quota left in the current epoch. Before the relayer set fork only the
+// relayer set by setRelayer may submit headers.
+func validateRelayer(evm *EVM, caller common.Address, chain chains.ChainType) error {
+	if !evm.chainConfig.IsRelayerSet(evm.Context.BlockNumber) {
+		if caller != ReadRelayer(evm.StateDB) {
+			return errors.New("invalid relayer")
+		}
+		return nil
+	}
+	rs, err := loadRelayerSet(evm.StateDB, chain)
+	if err != nil {
+		return err
+	}
+	i := rs.indexOf(caller)
+	if i < 0 {
+		return errors.New("invalid relayer")
+	}
+	if rs.Quota > 0 && rs.submissions(i, relayerEpoch(evm)) >= rs.Quota {
+		return fmt.Errorf("%w, relayer: %s, quota: %d", errRelayerQuotaExceeded, caller, rs.Quota)
+	}
+	return nil
+}
+
+// recordSubmission counts an accepted batch against the caller and emits the
+// event attributing it, from the relayer set fork on.
+func recordSubmission(evm *EVM, contract *Contract, chain chains.ChainType, nums []*params.NumberHash) error {
+	if !evm.chainConfig.IsRelayerSet(evm.Context.BlockNumber) {
+		return nil
+	}
+	rs, err := loadRelayerSet(evm.StateDB, chain)
+	if err != nil {
+		return err
+	}
+	i := rs.indexOf(contract.CallerAddress)
+	if i < 0 || len(nums) == 0 {
+		return nil
+	}
+	epoch := relayerEpoch(evm)
+	if rs.Epoch != epoch || len(rs.Submissions) != len(rs.Relayers) {
+		rs.Epoch = epoch
+		rs.Submissions = make([]uint64, len(rs.Relayers))
+	}
+	rs.Submissions[i]++
+	if err := rs.store(evm.StateDB, chain); err != nil {
+		return err
+	}
+
+	event := abiHeaderStore.Events[EventOfRelayerSubmission]
+	logData, err := event.Inputs.NonIndexed().Pack(
+		new(big.Int).SetUint64(epoch),
+		new(big.Int).SetUint64(nums[0].Number),
+		new(big.Int).SetUint64(nums[len(nums)-1].Number),
+	)
+	if err != nil {
+		return err
+	}
+	topics := []common.Hash{
+		event.ID,
+		contract.CallerAddress.Hash(),
+		common.BigToHash(new(big.Int).SetUint64(uint64(chain))),
+	}
+	addLog(evm, contract, topics, logData)
+	return nil
+}
+
+func isAdmin(evm *EVM, caller common.Address) bool {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	return bytes.Equal(caller.Bytes(), adminHash[12:])
+}
+
+// relayerChain resolves a source chain whose headers are kept by a header store,
+// the atlas networks themselves have no relayer set.
+func relayerChain(chainType *big.Int) (chains.ChainType, error) {
+	if !chainType.IsUint64() {
+		return 0, ErrNotSupportChain
+	}
+	chain := chains.ChainType(chainType.Uint64())
+	if _, err := chains.ChainType2ChainGroup(chain); err != nil {
+		return 0, ErrNotSupportChain
+	}
+	return chain, nil
+}
+
+func addRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	if !isAdmin(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[AddRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	chain, err := relayerChain(args.ChainType)
+	if err != nil {
+		return nil, err
+	}
+	if args.Relayer == (common.Address{}) {
+		return nil, errors.New("relayer cannot be the zero address")
+	}
+	rs, err := loadRelayerSet(evm.StateDB, chain)
+	if err != nil {
+		return nil, err
+	}
+	if rs.indexOf(args.Relayer) >= 0 {
+		return nil, errRelayerExists
+	}
+	if err := rs.add(args.Relayer); err != nil {
+		return nil, err
+	}
+	return nil, rs.store(evm.StateDB, chain)
+}
+
+func removeRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	if !isAdmin(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[RemoveRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	chain := chains.ChainType(args.ChainType.Uint64())
+	rs, err := loadRelayerSet(evm.StateDB, chain)
+	if err != nil {
+		return nil, err
+	}
+	i := rs.indexOf(args.Relayer)
+	if i < 0 {
+		return nil, errRelayerNotFound
+	}
+	rs.remove(i)
+	return nil, rs.store(evm.StateDB, chain)
+}
+
+func setRelayerQuota(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	if !isAdmin(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		ChainType *big.Int
+		Quota     *big.Int
+	}{}
+	method := abiHeaderStore.Methods[SetRelayerQuota]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.Quota.IsUint64() {
+		return nil, errors.New("quota out of range")
+	}
+
+	chain, err := relayerChain(args.ChainType)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := loadRelayerSet(evm.StateDB, chain)
+	if err != nil {
+		return nil, err
+	}
+	rs.Quota = args.Quota.Uint64()
+	return nil, rs.store(evm.StateDB, chain)
+}
+
+func getRelayers(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+	}{}
+	method := abiHeaderStore.Methods[GetRelayers]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	relayers, quota, err := ReadRelayers(evm.StateDB, chains.ChainType(args.ChainType.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	if relayers == nil {
+		relayers = []common.Address{}
+	}
+	return method.Outputs.Pack(relayers, new(big.Int).SetUint64(quota))
+}
+
+func relayerSubmissions(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[RelayerSubmissions]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+
+	rs, err := loadRelayerSet(evm.StateDB, chains.ChainType(args.ChainType.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	epoch := relayerEpoch(evm)
+	var submissions uint64
+	if i := rs.indexOf(args.Relayer); i >= 0 {
+		submissions = rs.submissions(i, epoch)
+	}
+	return method.Outputs.Pack(new(big.Int).SetUint64(epoch), new(big.Int).SetUint64(submissions))
+}
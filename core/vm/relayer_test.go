@@ -0,0 +1,230 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func runHeaderStoreAs(evm *EVM, caller common.Address, method string, args ...interface{}) ([]byte, error) {
+	input, err := abiHeaderStore.Pack(method, args...)
+	if err != nil {
+		panic(err)
+	}
+	contract := NewContract(AccountRef(caller), AccountRef(params.HeaderStoreAddress), big.NewInt(0), 0)
+	return RunHeaderStore(evm, contract, input)
+}
+
+func TestRelayerSet(t *testing.T) {
+	var (
+		admin  = common.HexToAddress("0xad")
+		r1     = common.HexToAddress("0x01")
+		r2     = common.HexToAddress("0x02")
+		legacy = common.HexToAddress("0x03")
+		r4     = common.HexToAddress("0x04")
+		eth    = big.NewInt(int64(chains.ChainTypeETH))
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, admin.Hash())
+	config := *params.TestChainConfig
+	config.RelayerSetBlock = big.NewInt(0)
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(5), EpochSize: 10}, TxContext{}, statedb, &config, Config{})
+
+	if _, err := runHeaderStoreAs(evm, r1, AddRelayer, eth, r1); err == nil {
+		t.Fatal("addRelayer() expected error for a non admin caller")
+	}
+	for _, r := range []common.Address{r1, r2} {
+		if _, err := runHeaderStoreAs(evm, admin, AddRelayer, eth, r); err != nil {
+			t.Fatalf("addRelayer(%x) error = %v", r, err)
+		}
+	}
+	if _, err := runHeaderStoreAs(evm, admin, AddRelayer, eth, r1); !errors.Is(err, errRelayerExists) {
+		t.Fatalf("addRelayer() error = %v, want %v", err, errRelayerExists)
+	}
+	// relayer sets are only kept for the chains of a header store
+	overflow := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), eth)
+	for _, chain := range []*big.Int{big.NewInt(int64(chains.ChainTypeMAP)), overflow} {
+		if _, err := runHeaderStoreAs(evm, admin, AddRelayer, chain, r4); !errors.Is(err, ErrNotSupportChain) {
+			t.Errorf("addRelayer(%d) error = %v, want %v", chain, err, ErrNotSupportChain)
+		}
+		if _, err := runHeaderStoreAs(evm, admin, SetRelayerQuota, chain, big.NewInt(2)); !errors.Is(err, ErrNotSupportChain) {
+			t.Errorf("setRelayerQuota(%d) error = %v, want %v", chain, err, ErrNotSupportChain)
+		}
+	}
+	if _, err := runHeaderStoreAs(evm, admin, SetRelayer, legacy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runHeaderStoreAs(evm, admin, SetRelayerQuota, eth, big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := runHeaderStoreAs(evm, r1, GetRelayers, eth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := abiHeaderStore.Methods[GetRelayers].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relayers := out[0].([]common.Address); !reflect.DeepEqual(relayers, []common.Address{r1, r2, legacy}) {
+		t.Errorf("getRelayers() = %x, want %x", relayers, []common.Address{r1, r2, legacy})
+	}
+	if quota := out[1].(*big.Int); quota.Uint64() != 2 {
+		t.Errorf("getRelayers() quota = %d, want 2", quota)
+	}
+
+	// relayer sets are kept per chain
+	if err := validateRelayer(evm, r1, chains.ChainTypeBSC); err == nil {
+		t.Error("validateRelayer() expected error for another chain")
+	}
+	if err := validateRelayer(evm, legacy, chains.ChainTypeBSC); err != nil {
+		t.Errorf("validateRelayer() of the legacy relayer error = %v", err)
+	}
+
+	// the legacy relayer is subject to the quota of the chain
+	legacyContract := NewContract(AccountRef(legacy), AccountRef(params.HeaderStoreAddress), big.NewInt(0), 0)
+	for i := 0; i < 2; i++ {
+		if err := recordSubmission(evm, legacyContract, chains.ChainTypeETH, []*params.NumberHash{{Number: 99}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := validateRelayer(evm, legacy, chains.ChainTypeETH); !errors.Is(err, errRelayerQuotaExceeded) {
+		t.Errorf("validateRelayer() of the legacy relayer error = %v, want %v", err, errRelayerQuotaExceeded)
+	}
+
+	// setting another relayer replaces the previous one in every set
+	if _, err := runHeaderStoreAs(evm, admin, SetRelayer, r4); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRelayer(evm, legacy, chains.ChainTypeBSC); err == nil {
+		t.Error("validateRelayer() expected error for a replaced legacy relayer")
+	}
+	if relayers, _, _ := ReadRelayers(statedb, chains.ChainTypeETH); !reflect.DeepEqual(relayers, []common.Address{r1, r2, r4}) {
+		t.Errorf("ReadRelayers() = %x, want %x", relayers, []common.Address{r1, r2, r4})
+	}
+
+	contract := NewContract(AccountRef(r1), AccountRef(params.HeaderStoreAddress), big.NewInt(0), 0)
+	nums := []*params.NumberHash{{Number: 100}, {Number: 101}}
+	for i := 0; i < 2; i++ {
+		if err := validateRelayer(evm, r1, chains.ChainTypeETH); err != nil {
+			t.Fatalf("validateRelayer() submission %d error = %v", i, err)
+		}
+		if err := recordSubmission(evm, contract, chains.ChainTypeETH, nums); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := validateRelayer(evm, r1, chains.ChainTypeETH); !errors.Is(err, errRelayerQuotaExceeded) {
+		t.Fatalf("validateRelayer() error = %v, want %v", err, errRelayerQuotaExceeded)
+	}
+	if err := validateRelayer(evm, r2, chains.ChainTypeETH); err != nil {
+		t.Errorf("validateRelayer() of another relayer error = %v", err)
+	}
+	logs := statedb.Logs()[2:]
+	if len(logs) != 2 || logs[0].Topics[1] != r1.Hash() || logs[0].Topics[2] != common.BigToHash(eth) {
+		t.Fatalf("submission events = %v, want 2 attributed to %x", logs, r1)
+	}
+	data, err := abiHeaderStore.Events[EventOfRelayerSubmission].Inputs.NonIndexed().Unpack(logs[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[1].(*big.Int).Uint64() != 100 || data[2].(*big.Int).Uint64() != 101 {
+		t.Errorf("submission event range = %v..%v, want 100..101", data[1], data[2])
+	}
+
+	// removing a relayer keeps the counts of the others
+	if _, err := runHeaderStoreAs(evm, admin, RemoveRelayer, eth, r2); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRelayer(evm, r2, chains.ChainTypeETH); err == nil {
+		t.Error("validateRelayer() expected error for a removed relayer")
+	}
+	if err := validateRelayer(evm, r1, chains.ChainTypeETH); !errors.Is(err, errRelayerQuotaExceeded) {
+		t.Errorf("validateRelayer() error = %v, want %v", err, errRelayerQuotaExceeded)
+	}
+
+	// the quota is refilled in the next epoch
+	evm.Context.BlockNumber = big.NewInt(15)
+	if err := validateRelayer(evm, r1, chains.ChainTypeETH); err != nil {
+		t.Errorf("validateRelayer() in the next epoch error = %v", err)
+	}
+	ret, err = runHeaderStoreAs(evm, r1, RelayerSubmissions, eth, r1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = abiHeaderStore.Methods[RelayerSubmissions].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epoch, n := out[0].(*big.Int), out[1].(*big.Int); epoch.Uint64() != 2 || n.Sign() != 0 {
+		t.Errorf("relayerSubmissions() = %d, %d, want 2, 0", epoch, n)
+	}
+}
+
+func TestRelayerSetFork(t *testing.T) {
+	var (
+		admin   = common.HexToAddress("0xad")
+		relayer = common.HexToAddress("0x01")
+		other   = common.HexToAddress("0x02")
+		eth     = big.NewInt(int64(chains.ChainTypeETH))
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, admin.Hash())
+	config := *params.TestChainConfig
+	config.RelayerSetBlock = big.NewInt(10)
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(9), EpochSize: 10}, TxContext{}, statedb, &config, Config{})
+	setRelayer, err := abiHeaderStore.Pack(SetRelayer, relayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// before the fork only the relayer set by setRelayer submits headers
+	if gas := new(store).StateGas(evm, setRelayer); gas != 0 {
+		t.Errorf("StateGas(setRelayer) before the fork = %d, want 0", gas)
+	}
+	if _, err := runHeaderStoreAs(evm, admin, SetRelayer, relayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runHeaderStoreAs(evm, admin, AddRelayer, eth, other); err == nil {
+		t.Error("addRelayer() expected error before the fork")
+	}
+	if err := validateRelayer(evm, relayer, chains.ChainTypeBSC); err != nil {
+		t.Errorf("validateRelayer() before the fork error = %v", err)
+	}
+	if err := validateRelayer(evm, other, chains.ChainTypeBSC); err == nil {
+		t.Error("validateRelayer() expected error for another relayer before the fork")
+	}
+	if relayers, _, _ := ReadRelayers(statedb, chains.ChainTypeETH); len(relayers) != 0 {
+		t.Errorf("ReadRelayers() before the fork = %x, want none", relayers)
+	}
+
+	// the fork block moves the live relayer into the set of every chain
+	if err := ApplyRelayerSetFork(&config, big.NewInt(9), statedb); err != nil {
+		t.Fatal(err)
+	}
+	if relayers, _, _ := ReadRelayers(statedb, chains.ChainTypeETH); len(relayers) != 0 {
+		t.Errorf("ReadRelayers() after migrating before the fork = %x, want none", relayers)
+	}
+	if err := ApplyRelayerSetFork(&config, big.NewInt(10), statedb); err != nil {
+		t.Fatal(err)
+	}
+	evm.Context.BlockNumber = big.NewInt(10)
+	for _, chain := range relayerChains() {
+		if relayers, _, _ := ReadRelayers(statedb, chain); !reflect.DeepEqual(relayers, []common.Address{relayer}) {
+			t.Errorf("ReadRelayers(%d) at the fork = %x, want %x", chain, relayers, relayer)
+		}
+		if err := validateRelayer(evm, relayer, chain); err != nil {
+			t.Errorf("validateRelayer(%d) at the fork error = %v", chain, err)
+		}
+	}
+	if gas, want := new(store).StateGas(evm, setRelayer), uint64(len(relayerChains()))*params.RelayerSetGas; gas != want {
+		t.Errorf("StateGas(setRelayer) at the fork = %d, want %d", gas, want)
+	}
+}
@@ -12,12 +12,14 @@ import (
 
 	"github.com/mapprotocol/atlas/accounts/abi"
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/chains/interfaces"
 	"github.com/mapprotocol/atlas/params"
 )
 
 const (
-	VerifyProof = "verifyProofData"
+	VerifyProof      = "verifyProofData"
+	VerifyProofBatch = "verifyProofDataBatch"
 )
 
 // TxVerify contract ABI
@@ -37,10 +39,17 @@ func RunTxVerify(evm *EVM, contract *Contract, input []byte) (ret []byte, err er
 		return nil, err
 	}
 
+	if method.Name == VerifyProofBatch && !evm.chainConfig.IsProofBatch(evm.Context.BlockNumber) {
+		log.Warn("run tx verify contract failed, method before the proof batch fork", "method", method.Name)
+		return nil, errors.New("invalid method name")
+	}
+
 	data := input[4:]
 	switch method.Name {
 	case VerifyProof:
 		ret, err = verifyProofData(evm, contract, data)
+	case VerifyProofBatch:
+		ret, err = verifyProofDataBatch(evm, contract, data)
 	default:
 		log.Warn("run tx verify contract failed, invalid method", "method", method.Name)
 		return ret, errors.New("invalid method name")
@@ -61,13 +70,7 @@ func verifyProofData(evm *EVM, contract *Contract, input []byte) (ret []byte, er
 		logs         []byte
 		receiptProof []byte
 	)
-	args := struct {
-		Router   common.Address
-		Coin     common.Address
-		SrcChain *big.Int
-		DstChain *big.Int
-		TxProve  []byte
-	}{}
+	args := receiptProofArgs{}
 
 	verifyProof := abiTxVerify.Methods[VerifyProof]
 	defer func() {
@@ -108,12 +111,73 @@ func verifyProofData(evm *EVM, contract *Contract, input []byte) (ret []byte, er
 	if err = verifyProof.Inputs.Copy(&receiptProof, unpack); err != nil {
 		return nil, err
 	}
-	if err := rlp.DecodeBytes(receiptProof, &args); err != nil {
+	if err := args.decode(evm, receiptProof); err != nil {
 		log.Error("rlp decode receiptProof failed", "err", err)
 		return nil, err
 	}
-	log.Info("verifyProofData args", "router", args.Router, "coin", args.Coin, "srcChain", args.SrcChain, "dstChain", args.DstChain)
+	log.Info("verifyProofData args", "router", args.Router, "coin", args.Coin, "srcChain", args.SrcChain, "dstChain", args.DstChain, "eventHash", args.EventHash)
+
+	v, err := args.verifier(evm)
+	if err != nil {
+		return nil, err
+	}
+	logs, err = v.Verify(evm.StateDB, args.logFilter(evm), args.TxProve)
+	if err != nil {
+		log.Error("verify proof failed", "err", err.Error())
+		return nil, err
+	}
+	return nil, nil
+}
+
+type receiptProofArgs struct {
+	Router   common.Address
+	Coin     common.Address
+	SrcChain *big.Int
+	DstChain *big.Int
+	TxProve  []byte
+	// EventHash is the topic0 of the log to return, it defaults to the
+	// mapTransferOut event of the source chain verifier.
+	EventHash common.Hash `rlp:"optional"`
+}
+
+// legacyReceiptProofArgs is the receipt proof accepted before the log filter
+// fork, it has no EventHash.
+type legacyReceiptProofArgs struct {
+	Router   common.Address
+	Coin     common.Address
+	SrcChain *big.Int
+	DstChain *big.Int
+	TxProve  []byte
+}
+
+func (args *receiptProofArgs) decode(evm *EVM, enc []byte) error {
+	if evm.chainConfig.IsLogFilter(evm.Context.BlockNumber) {
+		return rlp.DecodeBytes(enc, args)
+	}
+	var legacy legacyReceiptProofArgs
+	if err := rlp.DecodeBytes(enc, &legacy); err != nil {
+		return err
+	}
+	*args = receiptProofArgs{
+		Router:   legacy.Router,
+		Coin:     legacy.Coin,
+		SrcChain: legacy.SrcChain,
+		DstChain: legacy.DstChain,
+		TxProve:  legacy.TxProve,
+	}
+	return nil
+}
+
+// logFilter returns the filter selecting the returned log, it is nil before the
+// log filter fork so that all the logs of the receipt are returned.
+func (args *receiptProofArgs) logFilter(evm *EVM) *chains.LogFilter {
+	if !evm.chainConfig.IsLogFilter(evm.Context.BlockNumber) {
+		return nil
+	}
+	return &chains.LogFilter{Emitter: args.Router, Topic0: args.EventHash}
+}
 
+func (args *receiptProofArgs) verifier(evm *EVM) (interfaces.IVerify, error) {
 	// params check
 	if bytes.Equal(args.Router.Bytes(), common.Address{}.Bytes()) {
 		return nil, errors.New("router address is empty")
@@ -124,19 +188,127 @@ func verifyProofData(evm *EVM, contract *Contract, input []byte) (ret []byte, er
 	if !chains.IsSupportedChain(chains.ChainType(args.SrcChain.Uint64())) {
 		return nil, ErrNotSupportChain
 	}
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(args.SrcChain.Uint64()))
+	srcChain := chains.ChainType(args.SrcChain.Uint64())
+	group, err := interfaces.ActiveChainGroup(evm.chainConfig, srcChain, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
+	return interfaces.VerifyFactory(group, srcChain)
+}
 
-	v, err := interfaces.VerifyFactory(group)
+// unpackReceiptProofBatch returns the rlp encoded receiptProofArgs of an abi
+// packed verifyProofDataBatch input.
+func unpackReceiptProofBatch(input []byte) ([]byte, error) {
+	var receiptProofs []byte
+	method := abiTxVerify.Methods[VerifyProofBatch]
+	unpack, err := method.Inputs.Unpack(input)
 	if err != nil {
 		return nil, err
 	}
-	logs, err = v.Verify(evm.StateDB, args.Router, args.TxProve)
+	if err := method.Inputs.Copy(&receiptProofs, unpack); err != nil {
+		return nil, err
+	}
+	return receiptProofs, nil
+}
+
+func decodeReceiptProofBatch(evm *EVM, input []byte) (*receiptProofArgs, error) {
+	receiptProofs, err := unpackReceiptProofBatch(input)
 	if err != nil {
-		log.Error("verify proof failed", "err", err.Error())
 		return nil, err
 	}
+	args := new(receiptProofArgs)
+	if err := args.decode(evm, receiptProofs); err != nil {
+		log.Error("rlp decode receiptProofs failed", "err", err)
+		return nil, err
+	}
+	return args, nil
+}
+
+func verifyProofDataBatch(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	var (
+		success  = true
+		message  = ""
+		results  = []bool{}
+		messages = []string{}
+		logs     = [][]byte{}
+	)
+
+	method := abiTxVerify.Methods[VerifyProofBatch]
+	defer func() {
+		var packErr error
+
+		if err != nil {
+			success, message = false, err.Error()
+			results, messages, logs = []bool{}, []string{}, [][]byte{}
+		}
+		ret, packErr = method.Outputs.Pack(success, message, results, messages, logs)
+		if packErr != nil {
+			log.Error("verify proof batch outputs pack failed", "error", packErr.Error())
+		}
+	}()
+
+	args, err := decodeReceiptProofBatch(evm, input)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("verifyProofDataBatch args", "router", args.Router, "coin", args.Coin, "srcChain", args.SrcChain, "dstChain", args.DstChain, "eventHash", args.EventHash)
+
+	v, err := args.verifier(evm)
+	if err != nil {
+		return nil, err
+	}
+	bv, ok := v.(interfaces.IBatchVerify)
+	if !ok {
+		return nil, errors.New("batch verification is not supported by the source chain")
+	}
+	receiptLogs, errs, err := bv.VerifyBatch(evm.StateDB, args.logFilter(evm), args.TxProve)
+	if err != nil {
+		log.Error("verify proof batch failed", "err", err.Error())
+		return nil, err
+	}
+
+	for i := range errs {
+		if errs[i] != nil {
+			results, messages, logs = append(results, false), append(messages, errs[i].Error()), append(logs, []byte{})
+			continue
+		}
+		results, messages, logs = append(results, true), append(messages, ""), append(logs, receiptLogs[i])
+	}
 	return nil, nil
 }
+
+// verifyProofBatchGas charges the batch for its receipts, its trie nodes and
+// their size. The input is only walked here, it is decoded once by the run.
+func verifyProofBatchGas(input []byte) uint64 {
+	txProve, err := batchTxProve(input)
+	if err != nil {
+		return params.TxVerifyBatchBaseGas
+	}
+	receipts, nodes, size, err := ethereum.BatchProofSize(txProve)
+	if err != nil {
+		return params.TxVerifyBatchBaseGas
+	}
+	return params.TxVerifyBatchBaseGas + uint64(receipts)*params.TxVerifyBatchReceiptGas +
+		uint64(nodes)*params.TxVerifyBatchNodeGas + uint64(size)*params.TxVerifyBatchByteGas
+}
+
+// batchTxProve returns the TxProve field of a verifyProofDataBatch input
+// without decoding the other fields of receiptProofArgs.
+func batchTxProve(input []byte) ([]byte, error) {
+	receiptProofs, err := unpackReceiptProofBatch(input)
+	if err != nil {
+		return nil, err
+	}
+	fields, _, err := rlp.SplitList(receiptProofs)
+	if err != nil {
+		return nil, err
+	}
+	// skip Router, Coin, SrcChain and DstChain
+	for i := 0; i < 4; i++ {
+		if _, _, fields, err = rlp.Split(fields); err != nil {
+			return nil, err
+		}
+	}
+	txProve, _, err := rlp.SplitString(fields)
+	return txProve, err
+}
@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -22,6 +23,8 @@ import (
 
 	"github.com/mapprotocol/atlas/accounts/abi"
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/chains/interfaces"
 	atlastypes "github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/params"
@@ -211,13 +214,13 @@ func TestReceiptsRootAndProof(t *testing.T) {
 	//set := flag.NewFlagSet("test", 0)
 	//chainsdb.NewStoreDb(cli.NewContext(nil, set, nil), 10, 2)
 
-	v, err := interfaces.VerifyFactory(group)
+	v, err := interfaces.VerifyFactory(group, chains.ChainType(srcChain.Uint64()))
 	if err != nil {
 		t.Fatal(err)
 	}
 	//db := rawdb.NewMemoryDatabase()
 	//sdb, _ := state.New(common.Hash{}, state.NewDatabase(db), nil)
-	if _, err := v.Verify(getStateDB(), router, getTxProve()); err != nil {
+	if _, err := v.Verify(getStateDB(), &chains.LogFilter{Emitter: router}, getTxProve()); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -261,3 +264,146 @@ func TestEventHash(t *testing.T) {
 	eventHash := crypto.Keccak256Hash([]byte(event))
 	t.Log("event hash: ", eventHash)
 }
+
+func TestVerifyProofBatchGas(t *testing.T) {
+	const blockNumber = 16_000_000
+	receipts := make(types.Receipts, 0, 4)
+	for i := 0; i < 4; i++ {
+		r := types.NewReceipt(nil, false, uint64(21000*(i+1)))
+		r.Logs = []*types.Log{{Address: routerAddr, Topics: []common.Hash{ethereum.EventHash}, Data: []byte{byte(i)}}}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+		receipts = append(receipts, r)
+	}
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range receipts {
+		var buf bytes.Buffer
+		receipts.EncodeIndex(i, &buf)
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, common.CopyBytes(buf.Bytes()))
+	}
+	proof := light.NewNodeSet()
+	batch := &ethereum.BatchTxProve{BlockNumber: blockNumber}
+	for _, idx := range []uint{1, 3} {
+		key, _ := rlp.EncodeToBytes(idx)
+		if err := tr.Prove(key, 0, proof); err != nil {
+			t.Fatal(err)
+		}
+		batch.Receipts = append(batch.Receipts, ethereum.ReceiptProve{Receipt: receipts[idx], TxIndex: idx})
+	}
+	batch.Prove = proof.NodeList()
+
+	pack := func(batch *ethereum.BatchTxProve) (txProve, input []byte) {
+		txProve, err := rlp.EncodeToBytes(batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := rlp.EncodeToBytes(&receiptProofArgs{Router: routerAddr, SrcChain: big.NewInt(1), DstChain: big.NewInt(22776), TxProve: txProve})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return txProve, PackInput(abiTxVerify, VerifyProofBatch, data)
+	}
+	encodedSize := func(v interface{}) uint64 {
+		enc, err := rlp.EncodeToBytes(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uint64(len(enc))
+	}
+
+	// the priced batch is one the verifier accepts
+	db := getStateDB()
+	lightClient := &eth2.LightClientStore{ChainID: 1, FinalizedHeader: &eth2.BeaconBlockHeader{}, FinalizedExecution: blockNumber}
+	if err := lightClient.Store(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := eth2.StoreExecutionBlock(db, &eth2.ExecutionBlock{Number: blockNumber, ReceiptsRoot: tr.Hash()}); err != nil {
+		t.Fatal(err)
+	}
+	v := ethereum.NewChainVerify(chains.ChainTypeETH)
+	txProve, input := pack(batch)
+	_, errs, err := v.VerifyBatch(db, &chains.LogFilter{Emitter: routerAddr}, txProve)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	for i := range errs {
+		if errs[i] != nil {
+			t.Fatalf("VerifyBatch() receipt %d: %v", i, errs[i])
+		}
+	}
+
+	var size uint64
+	for _, r := range batch.Receipts {
+		size += encodedSize(r)
+	}
+	for _, node := range batch.Prove {
+		size += encodedSize(node)
+	}
+	want := params.TxVerifyBatchBaseGas + 2*params.TxVerifyBatchReceiptGas +
+		uint64(len(batch.Prove))*params.TxVerifyBatchNodeGas + size*params.TxVerifyBatchByteGas
+	if gas := new(verify).RequiredGas(input); gas != want {
+		t.Errorf("RequiredGas() = %d, want %d", gas, want)
+	}
+
+	// a repeated node is paid for and refused by the verifier
+	dup := *batch
+	dup.Prove = append(append(light.NodeList{}, batch.Prove...), batch.Prove[0])
+	txProve, input = pack(&dup)
+	want += params.TxVerifyBatchNodeGas + encodedSize(batch.Prove[0])*params.TxVerifyBatchByteGas
+	if gas := new(verify).RequiredGas(input); gas != want {
+		t.Errorf("RequiredGas() with duplicated nodes = %d, want %d", gas, want)
+	}
+	if _, _, err := v.VerifyBatch(db, &chains.LogFilter{Emitter: routerAddr}, txProve); err == nil {
+		t.Error("VerifyBatch() expected error for duplicated nodes")
+	}
+
+	if gas := new(verify).RequiredGas(PackInput(abiTxVerify, VerifyProofBatch, []byte{0x01})); gas != params.TxVerifyBatchBaseGas {
+		t.Errorf("RequiredGas() of invalid input = %d, want %d", gas, params.TxVerifyBatchBaseGas)
+	}
+}
+
+func TestProofBatchFork(t *testing.T) {
+	config := *params.TestChainConfig
+	config.ProofBatchBlock = big.NewInt(10)
+	input := PackInput(abiTxVerify, VerifyProofBatch, []byte{0x01})
+	contract := NewContract(AccountRef(common.Address{}), AccountRef(params.TxVerifyAddress), big.NewInt(0), 0)
+
+	before := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, getStateDB(), &config, Config{})
+	if _, err := RunTxVerify(before, contract, input); err == nil || err.Error() != "invalid method name" {
+		t.Errorf("RunTxVerify() before the fork error = %v, want invalid method name", err)
+	}
+	after := NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, getStateDB(), &config, Config{})
+	if _, err := RunTxVerify(after, contract, input); err != nil && err.Error() == "invalid method name" {
+		t.Error("RunTxVerify() refused the batch from the fork block on")
+	}
+}
+
+func TestReceiptProofArgsLogFilterActivation(t *testing.T) {
+	config := *params.TestChainConfig
+	config.LogFilterBlock = big.NewInt(10)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	before := NewEVM(BlockContext{BlockNumber: big.NewInt(9)}, TxContext{}, statedb, &config, Config{})
+	after := NewEVM(BlockContext{BlockNumber: big.NewInt(10)}, TxContext{}, statedb, &config, Config{})
+
+	topic := common.HexToHash("0x01")
+	enc, err := rlp.EncodeToBytes(&receiptProofArgs{Router: routerAddr, SrcChain: big.NewInt(1), DstChain: big.NewInt(22776), EventHash: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(receiptProofArgs).decode(before, enc); err == nil {
+		t.Error("decode() accepted an event hash before the log filter fork")
+	}
+	args := new(receiptProofArgs)
+	if err := args.decode(after, enc); err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if args.logFilter(before) != nil {
+		t.Error("logFilter() filters the receipt logs before the log filter fork")
+	}
+	if filter := args.logFilter(after); filter == nil || filter.Emitter != routerAddr || filter.Topic0 != topic {
+		t.Errorf("logFilter() = %v, want emitter %v and topic0 %v", filter, routerAddr, topic)
+	}
+}
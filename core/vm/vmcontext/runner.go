@@ -29,14 +29,37 @@ type evmRunnerContext interface {
 }
 
 func NewEVMRunner(chain evmRunnerContext, header *types.Header, state types.StateDB) vm.EVMRunner {
+	return newEVMRunner(chain, header, state, *chain.GetVMConfig())
+}
+
+// NewTracedEVMRunner is like NewEVMRunner but reports the calls made through
+// the runner to tracer.
+func NewTracedEVMRunner(chain evmRunnerContext, header *types.Header, state types.StateDB, tracer vm.Tracer) vm.EVMRunner {
+	config := *chain.GetVMConfig()
+	config.Debug, config.Tracer = true, tracer
+	return newEVMRunner(chain, header, state, config)
+}
 
+func newEVMRunner(chain evmRunnerContext, header *types.Header, state types.StateDB, config vm.Config) vm.EVMRunner {
 	return &evmRunner{
 		state: state,
 		newEVM: func(from common.Address) *vm.EVM {
 			// The EVM Context requires a msg, but the actual field values don't really matter for this case.
 			// Putting in zero values for gas price and tx fee recipient
 			context := New(from, common.Big0, header, chain, nil)
-			return vm.NewEVM(context, vm.TxContext{}, state, chain.Config(), *chain.GetVMConfig())
+			return vm.NewEVM(context, vm.TxContext{}, state, chain.Config(), config)
+		},
+	}
+}
+
+// NewEVMRunnerForEVM creates an EVMRunner whose calls run on new, untraced
+// EVMs sharing the block context and the state of evm, such as the calls a
+// transaction makes to charge its gas in a fee currency.
+func NewEVMRunnerForEVM(evm *vm.EVM) vm.EVMRunner {
+	return &evmRunner{
+		state: evm.StateDB,
+		newEVM: func(from common.Address) *vm.EVM {
+			return vm.NewEVM(evm.Context, vm.TxContext{Origin: from, GasPrice: common.Big0}, evm.StateDB, evm.ChainConfig(), vm.Config{})
 		},
 	}
 }
@@ -13,6 +13,7 @@ require (
 	github.com/buraksezer/consistent v0.9.0
 	github.com/cespare/cp v1.1.1
 	github.com/cespare/xxhash/v2 v2.1.1
+	github.com/consensys/gnark-crypto v0.4.1-0.20210426202927-39ac3d4b3f1f
 	github.com/davecgh/go-spew v1.1.1
 	github.com/deckarep/golang-set v1.7.1
 	github.com/dop251/goja v0.0.0-20211011172007-d99e4b8cbf48
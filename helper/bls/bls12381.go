@@ -0,0 +1,292 @@
+package bls
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// BLS12381 implements BLSCryptoSelector on the BLS12-381 curve in the minimal
+// signature size variant: public keys are G2 points and signatures G1 points,
+// so that they fit SerializedPublicKey and SerializedSignature once compressed.
+// Both are laid out as in the zcash serialization format used by the Ethereum
+// BLS12-381 tooling, left aligned and zero padded to the fixed size.
+type BLS12381 struct{}
+
+const (
+	// BLS12381PublicKeyBytes is the size of a compressed BLS12-381 G2 public key
+	BLS12381PublicKeyBytes = blst.BLST_P2_COMPRESS_BYTES
+	// BLS12381G1PublicKeyBytes is the size of a compressed BLS12-381 G1 public key
+	BLS12381G1PublicKeyBytes = blst.BLST_P1_COMPRESS_BYTES
+	// BLS12381SignatureBytes is the size of a compressed BLS12-381 G1 signature
+	BLS12381SignatureBytes = blst.BLST_P1_COMPRESS_BYTES
+
+	// MODULUS381 is the order of the BLS12-381 groups
+	MODULUS381 = "52435875175126190479447740508185965837690552500527637822603658699938581184513"
+)
+
+// bls12381DST is the hash to curve domain separation tag of the proof of
+// possession scheme with signatures in G1, bls12381PopDST the one of the proofs.
+var (
+	bls12381DST    = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+	bls12381PopDST = []byte("BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+var (
+	errInvalidBLS12381PrivateKey = errors.New("invalid BLS12-381 private key")
+	errInvalidBLS12381PublicKey  = errors.New("invalid BLS12-381 public key")
+	errInvalidBLS12381Signature  = errors.New("invalid BLS12-381 signature")
+	errBLS12381Verification      = errors.New("BLS12-381 signature verification failed")
+	errBLS12381G1PublicKey       = errors.New("BLS12-381 G1 public key does not match the public key")
+)
+
+// ECDSAToBLS derives the BLS12-381 private key the same way as BN256 does, the
+// candidates are reduced to the group order of BLS12-381 instead.
+func (BLS12381) ECDSAToBLS(privateKeyECDSA *ecdsa.PrivateKey) ([]byte, error) {
+	modulus, ok := new(big.Int).SetString(MODULUS381, 10)
+	if !ok {
+		return nil, errors.New("can't parse modulus")
+	}
+	privateKeyECDSABytes := crypto.FromECDSA(privateKeyECDSA)
+	for i := 0; i < 256; i++ {
+		keyBytes := []byte("ecdsatobls")
+		keyBytes = append(keyBytes, uint8(i))
+		keyBytes = append(keyBytes, privateKeyECDSABytes...) // keyBytes = "ecdsatobls" || byte(i) || bytes(k)
+
+		privateKeyBLSBytes := crypto.Keccak256(keyBytes)
+		privateKeyBLSBig := new(big.Int).SetBytes(privateKeyBLSBytes)
+		if privateKeyBLSBig.Sign() == 0 || privateKeyBLSBig.Cmp(modulus) >= 0 {
+			continue
+		}
+		if _, err := bls12381SecretKey(privateKeyBLSBytes); err != nil {
+			return nil, err
+		}
+		return privateKeyBLSBytes, nil
+	}
+	return nil, errors.New("couldn't derive a BLS key from an ECDSA key")
+}
+
+func (BLS12381) PrivateToPublic(privateKeyBytes []byte) (SerializedPublicKey, error) {
+	pubKeyBytesFixed := SerializedPublicKey{}
+	sk, err := bls12381SecretKey(privateKeyBytes)
+	if err != nil {
+		return pubKeyBytesFixed, err
+	}
+	copy(pubKeyBytesFixed[:], new(blst.P2Affine).From(sk).Compress())
+	return pubKeyBytesFixed, nil
+}
+
+func (BLS12381) PrivateToG1Public(privateKeyBytes []byte) (SerializedG1PublicKey, error) {
+	pubKeyBytesFixed := SerializedG1PublicKey{}
+	sk, err := bls12381SecretKey(privateKeyBytes)
+	if err != nil {
+		return pubKeyBytesFixed, err
+	}
+	copy(pubKeyBytesFixed[:], new(blst.P1Affine).From(sk).Compress())
+	return pubKeyBytesFixed, nil
+}
+
+// Sign signs message and extraData hashed to G1 with the SSWU map, the only hasher
+// of BLS12-381, so shouldUseCompositeHasher and cip22 don't apply.
+func (BLS12381) Sign(privateKeyBytes []byte, message []byte, extraData []byte,
+	shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) (SerializedSignature, error) {
+	signature := SerializedSignature{}
+	sk, err := bls12381SecretKey(privateKeyBytes)
+	if err != nil {
+		return signature, err
+	}
+	sig := new(blst.P1Affine).Sign(sk, bls12381Message(message, extraData), bls12381DST)
+	if sig == nil {
+		return signature, errors.New("failed to sign BLS12-381 message")
+	}
+	copy(signature[:], sig.Compress())
+	return signature, nil
+}
+
+func (BLS12381) VerifyAggregatedSignature(publicKeys []SerializedPublicKey, message []byte, extraData []byte,
+	signature []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) error {
+	sig, err := bls12381Signature(signature)
+	if err != nil {
+		return err
+	}
+	pks := make([]*blst.P2Affine, 0, len(publicKeys))
+	for _, v := range publicKeys {
+		pk, err := bls12381PublicKey(v)
+		if err != nil {
+			return err
+		}
+		pks = append(pks, pk)
+	}
+	if !sig.FastAggregateVerify(true, pks, bls12381Message(message, extraData), bls12381DST) {
+		return errBLS12381Verification
+	}
+	return nil
+}
+
+func (BLS12381) AggregateSignatures(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+	sigs := make([]*blst.P1Affine, 0, len(signatures))
+	for _, signature := range signatures {
+		sig, err := bls12381Signature(signature)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	agg := new(blst.P1Aggregate)
+	if !agg.Aggregate(sigs, false) {
+		return nil, errInvalidBLS12381Signature
+	}
+	aggregated := make([]byte, SIGNATUREBYTES)
+	copy(aggregated, agg.ToAffine().Compress())
+	return aggregated, nil
+}
+
+func (BLS12381) VerifySignature(publicKey SerializedPublicKey, message []byte, extraData []byte,
+	signature []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) error {
+	sig, err := bls12381Signature(signature)
+	if err != nil {
+		return err
+	}
+	pk, err := bls12381PublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(true, pk, true, bls12381Message(message, extraData), bls12381DST) {
+		return errBLS12381Verification
+	}
+	return nil
+}
+
+// EncodeEpochSnarkData encodes the epoch data with the CIP-22 framing: the message
+// is blockHash entropy || parentHash entropy || maximumNonSigners (4 bytes) ||
+// maxValidators (4 bytes) || public keys and the extra data is epochIndex (2 bytes) ||
+// round (1 byte), all integers little endian. CIP-22 only defines the encoding of
+// BLS12-377 keys, the public keys are the 96 bytes compressed BLS12-381 G2 points.
+func (BLS12381) EncodeEpochSnarkData(newValSet []SerializedPublicKey, maximumNonSigners, maxValidators uint32, epochIndex uint16, round uint8, blockHash, parentHash EpochEntropy) ([]byte, []byte, error) {
+	message := make([]byte, 2*EPOCHENTROPYBYTES+8, 2*EPOCHENTROPYBYTES+8+len(newValSet)*BLS12381PublicKeyBytes)
+	copy(message[0:EPOCHENTROPYBYTES], blockHash[:])
+	copy(message[EPOCHENTROPYBYTES:2*EPOCHENTROPYBYTES], parentHash[:])
+	binary.LittleEndian.PutUint32(message[2*EPOCHENTROPYBYTES:], maximumNonSigners)
+	binary.LittleEndian.PutUint32(message[2*EPOCHENTROPYBYTES+4:], maxValidators)
+	for _, pk := range newValSet {
+		message = append(message, pk[:BLS12381PublicKeyBytes]...)
+	}
+
+	extraData := make([]byte, 3)
+	binary.LittleEndian.PutUint16(extraData[0:2], epochIndex)
+	extraData[2] = round
+	return message, extraData, nil
+}
+
+// UncompressKey returns the 192 bytes uncompressed G2 point of the public key.
+func (BLS12381) UncompressKey(serialized SerializedPublicKey) ([]byte, error) {
+	pk, err := bls12381PublicKey(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return pk.Serialize(), nil
+}
+
+// ProofOfPossession signs message, usually the address registering the key, with
+// the private key to prove its possession.
+func (BLS12381) ProofOfPossession(privateKeyBytes []byte, message []byte) (SerializedSignature, error) {
+	signature := SerializedSignature{}
+	sk, err := bls12381SecretKey(privateKeyBytes)
+	if err != nil {
+		return signature, err
+	}
+	sig := new(blst.P1Affine).Sign(sk, message, bls12381PopDST)
+	if sig == nil {
+		return signature, errors.New("failed to sign BLS12-381 proof of possession")
+	}
+	copy(signature[:], sig.Compress())
+	return signature, nil
+}
+
+// VerifyProofOfPossession checks that pop proves the possession of the private key
+// of publicKey for message, and that g1PublicKey belongs to the same private key.
+func (BLS12381) VerifyProofOfPossession(publicKey SerializedPublicKey, g1PublicKey SerializedG1PublicKey, message []byte, pop []byte) error {
+	sig, err := bls12381Signature(pop)
+	if err != nil {
+		return err
+	}
+	pk, err := bls12381PublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(true, pk, true, message, bls12381PopDST) {
+		return errBLS12381Verification
+	}
+	if !allZero(g1PublicKey[BLS12381G1PublicKeyBytes:]) {
+		return errBLS12381G1PublicKey
+	}
+	g1pk := new(blst.P1Affine).Uncompress(g1PublicKey[:BLS12381G1PublicKeyBytes])
+	if g1pk == nil || !g1pk.KeyValidate() {
+		return errBLS12381G1PublicKey
+	}
+	// e(g1pk, g2) == e(g1, pk) holds only for the keys of the same private key
+	if !blst.Fp12FinalVerify(blst.Fp12MillerLoop(blst.P2Generator().ToAffine(), g1pk), blst.Fp12MillerLoop(pk, blst.P1Generator().ToAffine())) {
+		return errBLS12381G1PublicKey
+	}
+	return nil
+}
+
+// bls12381Message returns the bytes signed for message and extraData.
+func bls12381Message(message, extraData []byte) []byte {
+	if len(extraData) == 0 {
+		return message
+	}
+	msg := make([]byte, 0, len(message)+len(extraData))
+	msg = append(msg, message...)
+	return append(msg, extraData...)
+}
+
+func bls12381SecretKey(privateKeyBytes []byte) (*blst.SecretKey, error) {
+	sk := new(blst.SecretKey).Deserialize(privateKeyBytes)
+	if sk == nil || !sk.Valid() {
+		return nil, errInvalidBLS12381PrivateKey
+	}
+	return sk, nil
+}
+
+func bls12381PublicKey(serialized SerializedPublicKey) (*blst.P2Affine, error) {
+	if !allZero(serialized[BLS12381PublicKeyBytes:]) {
+		return nil, errInvalidBLS12381PublicKey
+	}
+	pk := new(blst.P2Affine).Uncompress(serialized[:BLS12381PublicKeyBytes])
+	if pk == nil || !pk.KeyValidate() {
+		return nil, errInvalidBLS12381PublicKey
+	}
+	return pk, nil
+}
+
+func bls12381Signature(signature []byte) (*blst.P1Affine, error) {
+	if len(signature) != SIGNATUREBYTES {
+		return nil, fmt.Errorf("wrong length for serialized signature: expected %d, got %d", SIGNATUREBYTES, len(signature))
+	}
+	if !allZero(signature[BLS12381SignatureBytes:]) {
+		return nil, errInvalidBLS12381Signature
+	}
+	sig := new(blst.P1Affine).Uncompress(signature[:BLS12381SignatureBytes])
+	if sig == nil {
+		return nil, errInvalidBLS12381Signature
+	}
+	return sig, nil
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
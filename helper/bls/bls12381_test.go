@@ -0,0 +1,118 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func bls12381Keys(t *testing.T, n int) ([][]byte, []SerializedPublicKey) {
+	curve := BLS12381{}
+	var privs [][]byte
+	var pubs []SerializedPublicKey
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		priv, err := curve.ECDSAToBLS(key)
+		require.NoError(t, err)
+		pub, err := curve.PrivateToPublic(priv)
+		require.NoError(t, err)
+		privs = append(privs, priv)
+		pubs = append(pubs, pub)
+	}
+	return privs, pubs
+}
+
+func TestBLS12381SignVerify(t *testing.T) {
+	curve := BLS12381{}
+	privs, pubs := bls12381Keys(t, 2)
+	msg, extra := randomMessage(), []byte("extra")
+
+	sig, err := curve.Sign(privs[0], msg, extra, true, true, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, curve.VerifySignature(pubs[0], msg, extra, sig[:], true, true, nil, nil))
+
+	require.Error(t, curve.VerifySignature(pubs[0], msg, nil, sig[:], true, true, nil, nil))
+	require.Error(t, curve.VerifySignature(pubs[0], randomMessage(), extra, sig[:], true, true, nil, nil))
+	require.Error(t, curve.VerifySignature(pubs[1], msg, extra, sig[:], true, true, nil, nil))
+
+	uncompressed, err := curve.UncompressKey(pubs[0])
+	require.NoError(t, err)
+	require.Len(t, uncompressed, 2*BLS12381PublicKeyBytes)
+
+	g1, err := curve.PrivateToG1Public(privs[0])
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, G1PUBLICKEYBYTES-BLS12381G1PublicKeyBytes), g1[BLS12381G1PublicKeyBytes:])
+}
+
+func TestBLS12381Aggregate(t *testing.T) {
+	curve := BLS12381{}
+	privs, pubs := bls12381Keys(t, 4)
+	msg := randomMessage()
+
+	var sigs [][]byte
+	for _, priv := range privs {
+		sig, err := curve.Sign(priv, msg, nil, false, false, nil, nil)
+		require.NoError(t, err)
+		sigs = append(sigs, sig[:])
+	}
+	aggregated, err := curve.AggregateSignatures(sigs)
+	require.NoError(t, err)
+	require.Len(t, aggregated, SIGNATUREBYTES)
+	require.NoError(t, curve.VerifyAggregatedSignature(pubs, msg, nil, aggregated, false, false, nil, nil))
+
+	// union with more seals of the same signers
+	union, err := curve.AggregateSignatures([][]byte{aggregated[:], sigs[0]})
+	require.NoError(t, err)
+	require.Error(t, curve.VerifyAggregatedSignature(pubs, msg, nil, union, false, false, nil, nil))
+	require.Error(t, curve.VerifyAggregatedSignature(pubs[:3], msg, nil, aggregated, false, false, nil, nil))
+}
+
+func TestBLS12381EpochSnarkData(t *testing.T) {
+	curve := BLS12381{}
+	privs, pubs := bls12381Keys(t, 2)
+	blockHash, parentHash := EpochEntropy{1}, EpochEntropy{2}
+	message, extra, err := curve.EncodeEpochSnarkData(pubs, 1, 3, 7, 2, blockHash, parentHash)
+	require.NoError(t, err)
+	require.Len(t, message, 2*EPOCHENTROPYBYTES+8+2*BLS12381PublicKeyBytes)
+	require.Equal(t, blockHash[:], message[:EPOCHENTROPYBYTES])
+	require.Equal(t, parentHash[:], message[EPOCHENTROPYBYTES:2*EPOCHENTROPYBYTES])
+	require.Equal(t, []byte{1, 0, 0, 0, 3, 0, 0, 0}, message[2*EPOCHENTROPYBYTES:2*EPOCHENTROPYBYTES+8])
+	require.Equal(t, pubs[0][:BLS12381PublicKeyBytes], message[2*EPOCHENTROPYBYTES+8:2*EPOCHENTROPYBYTES+8+BLS12381PublicKeyBytes])
+	require.Equal(t, []byte{7, 0, 2}, extra)
+
+	sig, err := curve.Sign(privs[1], message, extra, true, true, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, curve.VerifySignature(pubs[1], message, extra, sig[:], true, true, nil, nil))
+}
+
+func TestBLS12381ProofOfPossession(t *testing.T) {
+	curve := BLS12381{}
+	privs, pubs := bls12381Keys(t, 2)
+	signer := common.HexToAddress("0x1")
+
+	pop, err := curve.ProofOfPossession(privs[0], signer.Bytes())
+	require.NoError(t, err)
+	g1, err := curve.PrivateToG1Public(privs[0])
+	require.NoError(t, err)
+	require.NoError(t, curve.VerifyProofOfPossession(pubs[0], g1, signer.Bytes(), pop[:]))
+
+	require.Error(t, curve.VerifyProofOfPossession(pubs[0], g1, common.HexToAddress("0x2").Bytes(), pop[:]))
+	require.Error(t, curve.VerifyProofOfPossession(pubs[1], g1, signer.Bytes(), pop[:]))
+	otherG1, err := curve.PrivateToG1Public(privs[1])
+	require.NoError(t, err)
+	require.Error(t, curve.VerifyProofOfPossession(pubs[0], otherG1, signer.Bytes(), pop[:]))
+
+	// a seal is no proof of possession
+	sig, err := curve.Sign(privs[0], signer.Bytes(), nil, false, false, nil, nil)
+	require.NoError(t, err)
+	require.Error(t, curve.VerifyProofOfPossession(pubs[0], g1, signer.Bytes(), sig[:]))
+}
+
+func TestCryptoTypeOf(t *testing.T) {
+	require.IsType(t, BN256{}, CryptoTypeOf(0))
+	require.IsType(t, BN256{}, CryptoTypeOf(BN256Curve))
+	require.IsType(t, BLS12381{}, CryptoTypeOf(BLS12381Curve))
+}
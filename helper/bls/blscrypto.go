@@ -107,10 +107,11 @@ type BLSCryptoSelector interface {
 	ECDSAToBLS(privateKeyECDSA *ecdsa.PrivateKey) ([]byte, error)
 	PrivateToPublic(privateKeyBytes []byte) (SerializedPublicKey, error)
 	PrivateToG1Public(privateKeyBytes []byte) (SerializedG1PublicKey, error)
+	Sign(privateKeyBytes []byte, message []byte, extraData []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) (SerializedSignature, error)
 	VerifyAggregatedSignature(publicKeys []SerializedPublicKey, message []byte, extraData []byte, signature []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) error
 	AggregateSignatures(signatures [][]byte) ([]byte, error)
 	VerifySignature(publicKey SerializedPublicKey, message []byte, extraData []byte, signature []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) error
-	EncodeEpochSnarkDataCIP22(newValSet []SerializedPublicKey, maximumNonSigners, maxValidators uint32, epochIndex uint16, round uint8, blockHash, parentHash EpochEntropy) ([]byte, []byte, error)
+	EncodeEpochSnarkData(newValSet []SerializedPublicKey, maximumNonSigners, maxValidators uint32, epochIndex uint16, round uint8, blockHash, parentHash EpochEntropy) ([]byte, []byte, error)
 	UncompressKey(serialized SerializedPublicKey) ([]byte, error)
 }
 
@@ -123,14 +124,36 @@ func CryptoType() BLSCryptoSelector {
 		//curve := BLS12377{}
 		return nil //curve
 	case BLS12381Curve:
-		//curve := BLS12381{}
-		return nil //curve
+		curve := BLS12381{}
+		return curve
 	default:
 		// Programming error.
 		panic(fmt.Sprintf("unknown bls crypto selection policy: %v", BLSCryptoType))
 	}
 }
 
+// CryptoTypeOf returns the BLS implementation of the keys of the given curve.
+// The zero curve stands for the BN256 keys validators registered before BLS12-381.
+func CryptoTypeOf(curve uint8) BLSCryptoSelector {
+	if curve == BLS12381Curve {
+		return BLS12381{}
+	}
+	return BN256{}
+}
+
+// CurveOfPublicKey returns the curve of a registered public key, tagged as in
+// CryptoTypeOf. BLS12-381 keys are told apart by their zero padded encoding, an
+// error is returned for the keys that are on neither curve.
+func CurveOfPublicKey(publicKey SerializedPublicKey) (uint8, error) {
+	if _, err := bls12381PublicKey(publicKey); err == nil {
+		return BLS12381Curve, nil
+	}
+	if _, err := UnmarshalPk(publicKey[:]); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
 type BN256 struct{}
 
 const (
@@ -207,6 +230,26 @@ func (BN256) PrivateToG1Public(privateKeyBytes []byte) (SerializedG1PublicKey, e
 	return pubKeyBytesFixed, err
 }
 
+func (BN256) Sign(privateKeyBytes []byte, message []byte, extraData []byte,
+	shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) (SerializedSignature, error) {
+	signature := SerializedSignature{}
+	privateKey, err := DeserializePrivateKey(privateKeyBytes)
+	if err != nil {
+		return signature, err
+	}
+	var sign *UnsafeSignature
+	if params.IsBN256Fork(fork, cur) {
+		sign, err = UnsafeSign2(privateKey, message)
+	} else {
+		sign, err = UnsafeSign(privateKey, message)
+	}
+	if err != nil {
+		return signature, err
+	}
+	copy(signature[:], sign.Marshal())
+	return signature, nil
+}
+
 func (BN256) VerifyAggregatedSignature(publicKeys []SerializedPublicKey, message []byte, extraData []byte,
 	signature []byte, shouldUseCompositeHasher, cip22 bool, fork, cur *big.Int) error {
 	sigma := UnsafeSignature{}
@@ -275,7 +318,9 @@ func (BN256) VerifySignature(publicKey SerializedPublicKey, message []byte, extr
 	return nil
 }
 
-func (BN256) EncodeEpochSnarkDataCIP22(newValSet []SerializedPublicKey, maximumNonSigners, maxValidators uint32, epochIndex uint16, round uint8, blockHash, parentHash EpochEntropy) ([]byte, []byte, error) {
+// EncodeEpochSnarkData RLP encodes the epoch data, split in the new validator set
+// and the entropy of the epoch.
+func (BN256) EncodeEpochSnarkData(newValSet []SerializedPublicKey, maximumNonSigners, maxValidators uint32, epochIndex uint16, round uint8, blockHash, parentHash EpochEntropy) ([]byte, []byte, error) {
 	type pack1 struct {
 		newValSet         []SerializedPublicKey
 		maximumNonSigners uint32
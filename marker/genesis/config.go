@@ -85,9 +85,19 @@ type Version struct {
 
 // BlockchainParameters are the initial configuration parameters for Blockchain
 type BlockchainParameters struct {
-	Version                 Version `json:"version"`
-	GasForNonGoldCurrencies uint64  `json:"gasForNonGoldCurrencies"`
-	BlockGasLimit           uint64  `json:"blockGasLimit"`
+	Version                 Version             `json:"version"`
+	GasForNonGoldCurrencies uint64              `json:"gasForNonGoldCurrencies"`
+	BlockGasLimit           uint64              `json:"blockGasLimit"`
+	RoundChangeTimeouts     RoundChangeTimeouts `json:"roundChangeTimeouts"`
+}
+
+// RoundChangeTimeouts are the istanbul round change timeouts in milliseconds, the
+// validators use their configured value for the timeouts left at zero
+type RoundChangeTimeouts struct {
+	RequestTimeout              uint64 `json:"requestTimeout"`
+	TimeoutBackoffFactor        uint64 `json:"timeoutBackoffFactor"`
+	MinResendRoundChangeTimeout uint64 `json:"minResendRoundChangeTimeout"`
+	MaxResendRoundChangeTimeout uint64 `json:"maxResendRoundChangeTimeout"`
 }
 
 // DoubleSigningSlasherParameters are the initial configuration parameters for DoubleSigningSlasher
@@ -108,6 +108,9 @@ func (ctx *deployContext) deploy() (chain.GenesisAlloc, error) {
 
 		// 10 Elect Validators
 		ctx.electValidators,
+
+		// 11 Slashers
+		ctx.registerSlashers,
 	}
 
 	logger := ctx.logger.New()
@@ -229,7 +232,7 @@ func (ctx *deployContext) deployRegistry() error {
 
 func (ctx *deployContext) deployBlockchainParameters() error {
 	return ctx.deployCoreContract("BlockchainParameters", func(contract *contract.EVMBackend) error {
-		return contract.SimpleCall("initialize",
+		err := contract.SimpleCall("initialize",
 			big.NewInt(ctx.genesisConfig.Blockchain.Version.Major),
 			big.NewInt(ctx.genesisConfig.Blockchain.Version.Minor),
 			big.NewInt(ctx.genesisConfig.Blockchain.Version.Patch),
@@ -237,6 +240,20 @@ func (ctx *deployContext) deployBlockchainParameters() error {
 			newBigInt(ctx.genesisConfig.Blockchain.BlockGasLimit),
 			newBigInt(ctx.genesisConfig.Istanbul.LookbackWindow),
 		)
+		if err != nil {
+			return err
+		}
+
+		timeouts := ctx.genesisConfig.Blockchain.RoundChangeTimeouts
+		if timeouts == (RoundChangeTimeouts{}) {
+			return nil
+		}
+		return contract.SimpleCall("setRoundChangeTimeouts",
+			newBigInt(timeouts.RequestTimeout),
+			newBigInt(timeouts.TimeoutBackoffFactor),
+			newBigInt(timeouts.MinResendRoundChangeTimeout),
+			newBigInt(timeouts.MaxResendRoundChangeTimeout),
+		)
 	})
 }
 
@@ -245,6 +262,22 @@ func (ctx *deployContext) addSlasher(slasherName string) error {
 	return ctx.contract("LockedGold").SimpleCall("addSlasher", slasherName)
 }
 
+// registerSlashers registers the addresses the consensus engine slashes validators
+// from and allows them to slash locked gold.
+func (ctx *deployContext) registerSlashers() error {
+	for _, name := range []string{"DoubleSigningSlasher", "DowntimeSlasher"} {
+		proxyAddress := env.MustProxyAddressFor(name)
+		ctx.logger.Info("Add entry to registry", "name", name, "address", proxyAddress)
+		if err := ctx.contract("Registry").SimpleCall("setAddressFor", name, proxyAddress); err != nil {
+			return err
+		}
+		if err := ctx.addSlasher(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ctx *deployContext) deployGoldToken() error {
 	err := ctx.deployCoreContract("GoldToken", func(contract *contract.EVMBackend) error {
 		return contract.SimpleCall("initialize", env.MustProxyAddressFor("Registry"))
@@ -162,6 +162,9 @@ func prepareBlock(w *worker) (*blockState, error) {
 		b.randomness = &types.Randomness{}
 	}
 
+	if err := vm.ApplyRelayerSetFork(w.chainConfig, header.Number, b.state); err != nil {
+		return nil, fmt.Errorf("failed to apply the relayer set fork: %w", err)
+	}
 	return b, nil
 }
 
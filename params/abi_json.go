@@ -11,15 +11,63 @@ type BlockHeader struct {
 
 contract HeaderStore {
     event UpdateBlockHeader(address indexed account, uint256 indexed blockHeight);
+    event RelayerSubmission(address indexed relayer, uint256 indexed chainType, uint256 epoch, uint256 first, uint256 last);
     function updateBlockHeader(bytes memory blockHeader) public {}
     function currentNumberAndHash(uint256 chainID) public returns (uint256 number, bytes memory hash) {}
     function setRelayer(address relayer) public {}
     function getRelayer() public returns (address relayer) {}
+    function addRelayer(uint256 chainType, address relayer) public {}
+    function removeRelayer(uint256 chainType, address relayer) public {}
+    function setRelayerQuota(uint256 chainType, uint256 quota) public {}
+    function getRelayers(uint256 chainType) public returns (address[] memory relayers, uint256 quota) {}
+    function relayerSubmissions(uint256 chainType, address relayer) public returns (uint256 epoch, uint256 submissions) {}
     function reset(uint256 from, uint256 td, bytes memory header) public {}
     function verifyProofData(bytes memory receiptProof) public returns(bool success, string memory message, bytes memory logs) {}
+    function resetLightClient(bytes memory state) public {}
+    function updateLightClient(uint256 chainType, bytes memory update) public {}
+    function updateExecutionHeaders(uint256 chainType, bytes memory headers) public {}
+    function setConfirmations(uint256 chainID, uint256 confirmations) public {}
+    function finalizedNumberAndHash(uint256 chainID) public returns (uint256 number, bytes memory hash, uint256 confirmations) {}
 }
 */
 const HeaderStoreABIJSON = `[
+	{
+	   "anonymous": false,
+	   "inputs": [
+		  {
+			 "indexed": true,
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  },
+		  {
+			 "indexed": true,
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "indexed": false,
+			 "internalType": "uint256",
+			 "name": "epoch",
+			 "type": "uint256"
+		  },
+		  {
+			 "indexed": false,
+			 "internalType": "uint256",
+			 "name": "first",
+			 "type": "uint256"
+		  },
+		  {
+			 "indexed": false,
+			 "internalType": "uint256",
+			 "name": "last",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "RelayerSubmission",
+	   "type": "event"
+	},
 	{
 	   "anonymous": false,
 	   "inputs": [
@@ -39,6 +87,24 @@ const HeaderStoreABIJSON = `[
 	   "name": "UpdateBlockHeader",
 	   "type": "event"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "addRelayer",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -63,6 +129,35 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainID",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "finalizedNumberAndHash",
+	   "outputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "number",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "bytes",
+			 "name": "hash",
+			 "type": "bytes"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "confirmations",
+			 "type": "uint256"
+		  }
+	   ],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [],
 	   "name": "getRelayer",
@@ -76,6 +171,77 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "getRelayers",
+	   "outputs": [
+		  {
+			 "internalType": "address[]",
+			 "name": "relayers",
+			 "type": "address[]"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "quota",
+			 "type": "uint256"
+		  }
+	   ],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "relayerSubmissions",
+	   "outputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "epoch",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "submissions",
+			 "type": "uint256"
+		  }
+	   ],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "removeRelayer",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -99,6 +265,37 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "bytes",
+			 "name": "state",
+			 "type": "bytes"
+		  }
+	   ],
+	   "name": "resetLightClient",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainID",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "confirmations",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "setConfirmations",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -112,6 +309,60 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "quota",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "setRelayerQuota",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "bytes",
+			 "name": "headers",
+			 "type": "bytes"
+		  }
+	   ],
+	   "name": "updateExecutionHeaders",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "bytes",
+			 "name": "update",
+			 "type": "bytes"
+		  }
+	   ],
+	   "name": "updateLightClient",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -160,11 +411,17 @@ const HeaderStoreABIJSON = `[
 /*
 
 type ReceiptProof struct{
-	Router   common.Address
-	Coin     common.Address
-	SrcChain *big.Int
-	DstChain *big.Int
-	TxProve  []byte
+	Router    common.Address // emitter of the returned log
+	Coin      common.Address
+	SrcChain  *big.Int
+	DstChain  *big.Int
+	TxProve   []byte
+	EventHash common.Hash `rlp:"optional"` // topic0 of the returned log
+}
+
+type MatchedLog struct { // rlp encoded in the logs output
+	Index uint // index of the log in the receipt
+	Log   *ethtypes.Log
 }
 
 type TxProve struct {
@@ -174,8 +431,16 @@ type TxProve struct {
 	TxIndex     uint
 }
 
+type BatchTxProve struct {
+	BlockNumber uint64
+	Receipts    []ReceiptProve // {Receipt *ethtypes.Receipt, TxIndex uint}
+	Prove       light.NodeList // deduplicated union of the receipt proofs
+}
+
 contract TxVerify {
     function verifyProofData(bytes memory receiptProof) public returns(bool success, string memory message, bytes memory logs) {}
+    // receiptProofs is a ReceiptProof whose TxProve holds a BatchTxProve
+    function verifyProofDataBatch(bytes memory receiptProofs) public returns(bool success, string memory message, bool[] memory results, string[] memory messages, bytes[] memory logs) {}
 }
 */
 const TxVerifyABIJSON = `[
@@ -207,5 +472,128 @@ const TxVerifyABIJSON = `[
 		],
 		"stateMutability": "nonpayable",
 		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "bytes",
+				"name": "receiptProofs",
+				"type": "bytes"
+			}
+		],
+		"name": "verifyProofDataBatch",
+		"outputs": [
+			{
+				"internalType": "bool",
+				"name": "success",
+				"type": "bool"
+			},
+			{
+				"internalType": "string",
+				"name": "message",
+				"type": "string"
+			},
+			{
+				"internalType": "bool[]",
+				"name": "results",
+				"type": "bool[]"
+			},
+			{
+				"internalType": "string[]",
+				"name": "messages",
+				"type": "string[]"
+			},
+			{
+				"internalType": "bytes[]",
+				"name": "logs",
+				"type": "bytes[]"
+			}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+// JailABIJSON  jail abi json
+/*
+
+type jailEntry struct {
+	Signer       common.Address
+	Account      common.Address
+	ReleaseEpoch uint64 // first epoch the account may unjail the signer in
+}
+
+contract Jail {
+    event Unjailed(address indexed signer, address indexed account);
+    // unjail releases the signer of the calling account once its jail term is over
+    function unjail() public {}
+    // validator is either the signer or the account of a validator
+    function getJailStatus(address validator) public view returns (bool jailed, uint256 releaseEpoch) {}
+    function getJailedSigners() public view returns (address[] memory signers) {}
+}
+*/
+const JailABIJSON = `[
+	{
+		"anonymous": false,
+		"inputs": [
+			{
+				"indexed": true,
+				"internalType": "address",
+				"name": "signer",
+				"type": "address"
+			},
+			{
+				"indexed": true,
+				"internalType": "address",
+				"name": "account",
+				"type": "address"
+			}
+		],
+		"name": "Unjailed",
+		"type": "event"
+	},
+	{
+		"inputs": [],
+		"name": "unjail",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "address",
+				"name": "validator",
+				"type": "address"
+			}
+		],
+		"name": "getJailStatus",
+		"outputs": [
+			{
+				"internalType": "bool",
+				"name": "jailed",
+				"type": "bool"
+			},
+			{
+				"internalType": "uint256",
+				"name": "releaseEpoch",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "getJailedSigners",
+		"outputs": [
+			{
+				"internalType": "address[]",
+				"name": "signers",
+				"type": "address[]"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
 	}
 ]`
@@ -21,6 +21,11 @@ var (
 	NewRelayerAddress  = common.BytesToAddress([]byte("relayerAddress"))
 	HeaderStoreAddress = common.BytesToAddress([]byte("headerstoreAddress"))
 	TxVerifyAddress    = common.BytesToAddress([]byte("txVerifyAddress"))
+	SlashingAddress    = common.BytesToAddress([]byte("slashingAddress"))
+	// ProposerVotesAddress keeps the validator votes used by the stake weighted proposer policy
+	ProposerVotesAddress = common.BytesToAddress([]byte("proposerVotesAddress"))
+	// JailAddress keeps the jailed validators and serves unjail requests
+	JailAddress = common.BytesToAddress([]byte("jailAddress"))
 )
 
 const (
@@ -67,11 +72,33 @@ var (
 	GovernanceRegistryId           = makeRegistryId("Governance")
 	LockedGoldRegistryId           = makeRegistryId("LockedGold")
 	RandomRegistryId               = makeRegistryId("Random")
+	SortedOraclesRegistryId        = makeRegistryId("SortedOracles")
 
 	//TransferWhitelistRegistryId    = makeRegistryId("TransferWhitelist")
 	ValidatorsRegistryId = makeRegistryId("Validators")
 	AccountsId           = makeRegistryId("Accounts")
 
+	// DoubleSigningSlasherRegistryId names the account the consensus engine
+	// slashes double signing validators from, it is whitelisted in LockedGold.
+	DoubleSigningSlasherRegistryId = makeRegistryId("DoubleSigningSlasher")
+	DoubleSigningPenalty           = new(big.Int).Mul(big.NewInt(9000), big.NewInt(1e18)) // 9000 MAP
+	DoubleSigningReward            = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)) // 1000 MAP, paid to the reporting proposer
+	// DoubleSigningSlasherAddress is registered as DoubleSigningSlasher at the double
+	// signing fork block on chains whose genesis did not register it, it is the
+	// address of the DoubleSigningSlasher proxy of new genesis.
+	DoubleSigningSlasherAddress = common.HexToAddress("0x000000000000000000000000000000000000d020")
+
+	// DowntimeSlasherRegistryId names the account the consensus engine slashes
+	// validators from that missed SlashableDowntimeWindows consecutive lookback windows.
+	DowntimeSlasherRegistryId = makeRegistryId("DowntimeSlasher")
+	DowntimePenalty           = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18)) // 100 MAP
+	DowntimeReward            = new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))  // 10 MAP, paid to the proposer of the last block of the epoch
+	SlashableDowntimeWindows  = uint64(720)
+
+	// JailEpochs is the number of epochs a slashed validator stays jailed
+	// before its account may unjail it.
+	JailEpochs = uint64(3)
+
 	// Function is "getOrComputeTobinTax()"
 	// selector is first 4 bytes of keccak256 of "getOrComputeTobinTax()"
 	// Source:
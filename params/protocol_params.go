@@ -24,6 +24,7 @@ const (
 	MaxGasForElectValidators                       uint64 = 5000 * million
 	MaxGasForElectNValidatorSigners                uint64 = 5000 * million
 	MaxGasForActiveAllPending                      uint64 = 5000 * million
+	MaxGasForGetActiveVotesForValidator            uint64 = 100 * thousand
 	MaxGasForGetAddressFor                         uint64 = 1000 * million
 	MaxGasForGetElectableValidators                uint64 = 1000 * million
 	MaxGasForGetEligibleValidatorsVoteTotals       uint64 = 1000 * million
@@ -49,6 +50,17 @@ const (
 	MaxGasForIsReserveLow                          uint64 = 1 * million
 	MaxGasForGetCommunityPartnerSettingPartner     uint64 = 100 * thousand
 	MaxGasForGetMgrMaintainerAddress               uint64 = 100 * thousand
+	MaxGasForGetNonvotingLockedGold                uint64 = 100 * thousand
+	MaxGasForGetTotalLockedGold                    uint64 = 100 * thousand
+	MaxGasForSlash                                 uint64 = 20 * million
+	MaxGasForHalveSlashingMultiplier               uint64 = 1 * million
+	MaxGasForGetJailStatus                         uint64 = 1 * million
+	MaxGasForGetOwner                              uint64 = 100 * thousand
+	MaxGasForSetAddressFor                         uint64 = 1 * million
+	MaxGasForIsSlasher                             uint64 = 1 * million
+	MaxGasForAddSlasher                            uint64 = 1 * million
+	MaxGasForGetVotes                              uint64 = 100 * thousand
+	MaxGasForGetValidatorEligibility               uint64 = 100 * thousand
 
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	CallValueTransferGas uint64 = 9000  // Paid for CALL when the value transfer is non-zero.
@@ -116,6 +128,15 @@ const (
 	Bls12381MapG2Gas          uint64 = 75000 // Gas price for BLS12-381 mapping field element to G2 operation
 
 	VerifyEth2UpdateGas uint64 = 50000 // Cost of verifying the eth2.0 light client update
+
+	TxVerifyBatchBaseGas    uint64 = 42000 // Base cost of a receipt batch proof, covers the receipts root lookup
+	TxVerifyBatchReceiptGas uint64 = 5000  // Per receipt cost of a receipt batch proof
+	TxVerifyBatchNodeGas    uint64 = 800   // Per trie node cost of a receipt batch proof
+	TxVerifyBatchByteGas    uint64 = 3     // Per byte cost of the receipts and trie nodes of a receipt batch proof
+
+	HeaderStoreFinalizeGas uint64 = 5000  // Per header cost of moving the finalized pointer of a header store, covers pruning the ring slot
+	RelayerSetGas          uint64 = 25000 // Cost of rewriting the relayer set of a source chain, priced like a new storage slot
+	UnjailGas              uint64 = 21500 // Cost of unjailing a validator, priced like a storage write plus the three topic Unjailed log
 	////////////////////////////////////////////////////////////////////////////////////////////////
 
 	MaxCodeSize        = 49152              // Maximum bytecode to permit for a contract
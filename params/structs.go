@@ -87,6 +87,38 @@ type ChainConfig struct {
 	LondonBlock         *big.Int `json:"londonBlock,omitempty"`         // London switch block (nil = no fork, 0 = already on london)
 
 	DonutBlock *big.Int `json:"donutBlock,omitempty"` // Donut switch block (nil = no fork, 0 = already activated)
+	MMRBlock   *big.Int `json:"mmrBlock,omitempty"`   // First block accumulated in the header MMR (nil = no MMR)
+	// First block electing validators with BLS12-381 keys instead of BN256 ones, they
+	// seal from the following epoch on (nil = no fork)
+	BLS12381Block *big.Int `json:"bls12381Block,omitempty"`
+	// First block recording the validator votes the stake weighted proposer policy draws from (nil = no fork)
+	StakeWeightedBlock *big.Int `json:"stakeWeightedBlock,omitempty"`
+	// First block slashing and jailing the validators missing consecutive uptime windows (nil = no fork)
+	DowntimeSlashingBlock *big.Int `json:"downtimeSlashingBlock,omitempty"`
+	// First block accepting transactions paying their gas in a whitelisted fee currency (nil = no fork)
+	FeeCurrencyBlock *big.Int `json:"feeCurrencyBlock,omitempty"`
+	// First block returning the receipt log matching the emitter and topic0 of a tx
+	// verify proof instead of all the logs of the receipt (nil = no fork)
+	LogFilterBlock *big.Int `json:"logFilterBlock,omitempty"`
+	// First block accepting header store submissions from the per-chain relayer sets
+	// instead of the relayer set by setRelayer, which is copied into the sets at this
+	// block (nil = no fork)
+	RelayerSetBlock *big.Int `json:"relayerSetBlock,omitempty"`
+	// First block including double signing evidence in the istanbul extra-data and
+	// slashing its offenders, the slasher is registered at this block (nil = no fork)
+	DoubleSigningBlock *big.Int `json:"doubleSigningBlock,omitempty"`
+	// First block accepting the eth2 light client and execution header submissions
+	// of the header store (nil = no fork)
+	LightClientBlock *big.Int `json:"lightClientBlock,omitempty"`
+	// First block accepting the confirmations of the finalized headers and serving
+	// them from the header store (nil = no fork)
+	FinalityBlock *big.Int `json:"finalityBlock,omitempty"`
+	// First block accepting batches of receipt proofs by the tx verify contract
+	// (nil = no fork)
+	ProofBatchBlock *big.Int `json:"proofBatchBlock,omitempty"`
+	// First block jailing slashed validators and serving unjail requests from the jail
+	// contract (nil = no fork)
+	JailBlock *big.Int `json:"jailBlock,omitempty"`
 
 	//YoloV3Block   *big.Int `json:"yoloV3Block,omitempty"`   // YOLO v3: Gas repricings TODO @holiman add EIP references
 	EWASMBlock    *big.Int `json:"ewasmBlock,omitempty"`    // EWASM switch block (nil = no fork, 0 = already activated)
@@ -102,6 +134,10 @@ type ChainConfig struct {
 	EnableRewardBlock *big.Int `json:"rewardblock,omitempty"`
 	DeregisterBlock   *big.Int `json:"deregisterblock,omitempty"`
 	CalcBaseBlock     *big.Int `json:"calcbaseblock,omitempty"`
+	// ChainGroupBlocks overrides the block from which a cross-chain group
+	// (see chains.ChainGroup) is accepted by the header store and tx verify
+	// contracts. Groups that are not listed use their registered default.
+	ChainGroupBlocks map[uint64]*big.Int `json:"chainGroupBlocks,omitempty"`
 	// This does not belong here but passing it to every function is not possible since that breaks
 	// some implemented interfaces and introduces churn across the geth codebase.
 	FullHeaderChainAvailable bool // False for lightest Sync mode, true otherwise
@@ -259,6 +295,98 @@ func (c *ChainConfig) IsCalc(num *big.Int) bool {
 	return isForked(c.CalcBaseBlock, num)
 }
 
+// IsDonut returns whether num is either equal to the Donut fork block or greater.
+func (c *ChainConfig) IsDonut(num *big.Int) bool {
+	return isForked(c.DonutBlock, num)
+}
+
+// IsBLS12381 returns whether num is either equal to the BLS12-381 fork block or greater.
+func (c *ChainConfig) IsBLS12381(num *big.Int) bool {
+	return isForked(c.BLS12381Block, num)
+}
+
+// IsMMR returns whether num is either equal to the first block of the header
+// MMR or greater.
+func (c *ChainConfig) IsMMR(num *big.Int) bool {
+	return isForked(c.MMRBlock, num)
+}
+
+// IsStakeWeighted returns whether num is either equal to the stake weighted
+// proposer fork block or greater.
+func (c *ChainConfig) IsStakeWeighted(num *big.Int) bool {
+	return isForked(c.StakeWeightedBlock, num)
+}
+
+// IsDowntimeSlashing returns whether num is either equal to the downtime
+// slashing fork block or greater.
+func (c *ChainConfig) IsDowntimeSlashing(num *big.Int) bool {
+	return isForked(c.DowntimeSlashingBlock, num)
+}
+
+// IsFeeCurrency returns whether num is either equal to the fee currency fork
+// block or greater.
+func (c *ChainConfig) IsFeeCurrency(num *big.Int) bool {
+	return isForked(c.FeeCurrencyBlock, num)
+}
+
+// IsLogFilter returns whether num is either equal to the receipt log filter
+// fork block or greater.
+func (c *ChainConfig) IsLogFilter(num *big.Int) bool {
+	return isForked(c.LogFilterBlock, num)
+}
+
+// IsRelayerSet returns whether num is either equal to the per-chain relayer set
+// fork block or greater.
+func (c *ChainConfig) IsRelayerSet(num *big.Int) bool {
+	return isForked(c.RelayerSetBlock, num)
+}
+
+// IsDoubleSigning returns whether num is either equal to the double signing
+// slashing fork block or greater.
+func (c *ChainConfig) IsDoubleSigning(num *big.Int) bool {
+	return isForked(c.DoubleSigningBlock, num)
+}
+
+// IsLightClient returns whether num is either equal to the eth2 light client
+// fork block or greater.
+func (c *ChainConfig) IsLightClient(num *big.Int) bool {
+	return isForked(c.LightClientBlock, num)
+}
+
+// IsFinality returns whether num is either equal to the finalized header fork
+// block or greater.
+func (c *ChainConfig) IsFinality(num *big.Int) bool {
+	return isForked(c.FinalityBlock, num)
+}
+
+// IsProofBatch returns whether num is either equal to the receipt proof batch
+// fork block or greater.
+func (c *ChainConfig) IsProofBatch(num *big.Int) bool {
+	return isForked(c.ProofBatchBlock, num)
+}
+
+// IsJail returns whether num is either equal to the validator jail fork block or
+// greater.
+func (c *ChainConfig) IsJail(num *big.Int) bool {
+	return isForked(c.JailBlock, num)
+}
+
+// ChainGroupBlock returns the configured activation block of a cross-chain
+// group and whether the config sets one at all.
+func (c *ChainConfig) ChainGroupBlock(group uint64) (*big.Int, bool) {
+	block, ok := c.ChainGroupBlocks[group]
+	return block, ok
+}
+
+// IsChainGroupActive returns whether num is at or past the activation block
+// of a cross-chain group, falling back to def when the config does not set one.
+func (c *ChainConfig) IsChainGroupActive(group uint64, def, num *big.Int) bool {
+	if block, ok := c.ChainGroupBlock(group); ok {
+		return isForked(block, num)
+	}
+	return isForked(def, num)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -371,6 +499,55 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.DonutBlock, newcfg.DonutBlock, head) {
+		return newCompatError("Donut fork block", c.DonutBlock, newcfg.DonutBlock)
+	}
+	if isForkIncompatible(c.MMRBlock, newcfg.MMRBlock, head) {
+		return newCompatError("MMR block", c.MMRBlock, newcfg.MMRBlock)
+	}
+	if isForkIncompatible(c.BLS12381Block, newcfg.BLS12381Block, head) {
+		return newCompatError("BLS12-381 fork block", c.BLS12381Block, newcfg.BLS12381Block)
+	}
+	if isForkIncompatible(c.StakeWeightedBlock, newcfg.StakeWeightedBlock, head) {
+		return newCompatError("stake weighted fork block", c.StakeWeightedBlock, newcfg.StakeWeightedBlock)
+	}
+	if isForkIncompatible(c.DowntimeSlashingBlock, newcfg.DowntimeSlashingBlock, head) {
+		return newCompatError("downtime slashing fork block", c.DowntimeSlashingBlock, newcfg.DowntimeSlashingBlock)
+	}
+	if isForkIncompatible(c.FeeCurrencyBlock, newcfg.FeeCurrencyBlock, head) {
+		return newCompatError("fee currency fork block", c.FeeCurrencyBlock, newcfg.FeeCurrencyBlock)
+	}
+	if isForkIncompatible(c.LogFilterBlock, newcfg.LogFilterBlock, head) {
+		return newCompatError("log filter fork block", c.LogFilterBlock, newcfg.LogFilterBlock)
+	}
+	if isForkIncompatible(c.RelayerSetBlock, newcfg.RelayerSetBlock, head) {
+		return newCompatError("relayer set fork block", c.RelayerSetBlock, newcfg.RelayerSetBlock)
+	}
+	if isForkIncompatible(c.DoubleSigningBlock, newcfg.DoubleSigningBlock, head) {
+		return newCompatError("double signing fork block", c.DoubleSigningBlock, newcfg.DoubleSigningBlock)
+	}
+	if isForkIncompatible(c.LightClientBlock, newcfg.LightClientBlock, head) {
+		return newCompatError("light client fork block", c.LightClientBlock, newcfg.LightClientBlock)
+	}
+	if isForkIncompatible(c.FinalityBlock, newcfg.FinalityBlock, head) {
+		return newCompatError("finality fork block", c.FinalityBlock, newcfg.FinalityBlock)
+	}
+	if isForkIncompatible(c.ProofBatchBlock, newcfg.ProofBatchBlock, head) {
+		return newCompatError("proof batch fork block", c.ProofBatchBlock, newcfg.ProofBatchBlock)
+	}
+	if isForkIncompatible(c.JailBlock, newcfg.JailBlock, head) {
+		return newCompatError("jail fork block", c.JailBlock, newcfg.JailBlock)
+	}
+	for group, block := range c.ChainGroupBlocks {
+		if isForkIncompatible(block, newcfg.ChainGroupBlocks[group], head) {
+			return newCompatError(fmt.Sprintf("chain group %d fork block", group), block, newcfg.ChainGroupBlocks[group])
+		}
+	}
+	for group, block := range newcfg.ChainGroupBlocks {
+		if isForkIncompatible(c.ChainGroupBlocks[group], block, head) {
+			return newCompatError(fmt.Sprintf("chain group %d fork block", group), c.ChainGroupBlocks[group], block)
+		}
+	}
 	return nil
 }
 
@@ -438,7 +615,8 @@ type Rules struct {
 	ChainID                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
-	IsBerlin, IsLondon, IsCatalyst                          bool
+	IsBerlin, IsLondon, IsCatalyst, IsDonut                 bool
+	IsJail                                                  bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -460,6 +638,8 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsBerlin:         c.IsBerlin(num),
 		IsLondon:         c.IsLondon(num),
 		IsCatalyst:       c.IsCatalyst(num),
+		IsDonut:          c.IsDonut(num),
+		IsJail:           c.IsJail(num),
 	}
 }
 